@@ -1,6 +1,8 @@
 package api
 
 import (
+	"time"
+
 	"github.com/GeneralTask/task-manager/backend/database"
 	"github.com/GeneralTask/task-manager/backend/jobs"
 	"github.com/gin-gonic/gin"
@@ -25,5 +27,12 @@ func (api *API) DashboardFetch(c *gin.Context) {
 		Handle500(c)
 		return
 	}
+	// tracked time only feeds the focus time graph, so it is updated in the background without holding up the fetch
+	go func(now time.Time) {
+		err := jobs.UpdateTimeTrackingTeamData(userID, now, jobs.DEFAULT_LOOKBACK_DAYS)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to update time tracking team data")
+		}
+	}(api.GetCurrentTime())
 	c.JSON(200, bson.M{})
 }
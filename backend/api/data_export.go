@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// download links are short lived so that a shared link stops working soon after, while the export itself can be polled for a fresh one
+const DataExportDownloadURLExpiration = time.Hour
+
+type DataExportResult struct {
+	ID          primitive.ObjectID `json:"id"`
+	Status      string             `json:"status"`
+	CreatedAt   string             `json:"created_at"`
+	CompletedAt string             `json:"completed_at,omitempty"`
+	ExpiresAt   string             `json:"expires_at,omitempty"`
+	SizeBytes   int64              `json:"size_bytes,omitempty"`
+	DownloadURL string             `json:"download_url,omitempty"`
+}
+
+// DataExportCreate godoc
+// @Summary      Requests a copy of all of the user's data
+// @Description  The export is built in the background, and an unfinished export is returned instead of starting another
+// @Tags         data export
+// @Produce      json
+// @Success      201 {object} DataExportResult
+// @Success      200 {object} DataExportResult "an export is already in progress"
+// @Failure      500 {object} string "internal server error"
+// @Router       /data_exports/create/ [post]
+func (api *API) DataExportCreate(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	dataExport, isCreated, err := database.GetOrCreateDataExport(api.DB, userID, api.GetCurrentTime())
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to create data export")
+		Handle500(c)
+		return
+	}
+	result, err := api.getDataExportResult(dataExport)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to sign data export download link")
+		Handle500(c)
+		return
+	}
+	statusCode := 200
+	if isCreated {
+		statusCode = 201
+	}
+	c.JSON(statusCode, result)
+}
+
+// DataExportGet godoc
+// @Summary      Returns the status of a data export
+// @Description  Once the export is complete, a signed download link is included which expires after an hour
+// @Tags         data export
+// @Produce      json
+// @Param        export_id  path      string  true  "Data Export ID"
+// @Success      200 {object} DataExportResult
+// @Failure      404 {object} string "export not found"
+// @Failure      500 {object} string "internal server error"
+// @Router       /data_exports/{export_id}/ [get]
+func (api *API) DataExportGet(c *gin.Context) {
+	exportID, err := primitive.ObjectIDFromHex(c.Param("export_id"))
+	if err != nil {
+		Handle404(c)
+		return
+	}
+	userID := getUserIDFromContext(c)
+	dataExport, err := database.GetDataExport(api.DB, exportID, userID)
+	if err == mongo.ErrNoDocuments {
+		Handle404(c)
+		return
+	} else if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch data export")
+		Handle500(c)
+		return
+	}
+	result, err := api.getDataExportResult(dataExport)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to sign data export download link")
+		Handle500(c)
+		return
+	}
+	c.JSON(200, result)
+}
+
+// DataExportDownload godoc
+// @Summary      Downloads the ZIP file of a data export
+// @Description  Doesn't require authentication, as the link is signed when the status of the export is fetched
+// @Tags         data export
+// @Produce      application/zip
+// @Param        export_id  path      string  true  "Data Export ID"
+// @Param        expires    query     string  true  "unix time the link expires at"
+// @Param        signature  query     string  true  "signature of the link"
+// @Success      200 {file} file
+// @Failure      403 {object} string "invalid or expired link"
+// @Failure      404 {object} string "export not found"
+// @Failure      500 {object} string "internal server error"
+// @Router       /data_exports/{export_id}/download/ [get]
+func (api *API) DataExportDownload(c *gin.Context) {
+	exportID, err := primitive.ObjectIDFromHex(c.Param("export_id"))
+	if err != nil {
+		Handle404(c)
+		return
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(403, gin.H{"detail": "invalid download link"})
+		return
+	}
+	signature, err := getDataExportSignature(exportID, expires)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("unable to verify data export download link")
+		c.JSON(403, gin.H{"detail": "invalid download link"})
+		return
+	}
+	if !hmac.Equal([]byte(signature), []byte(c.Query("signature"))) {
+		c.JSON(403, gin.H{"detail": "invalid download link"})
+		return
+	}
+	now := api.GetCurrentTime()
+	if now.After(time.Unix(expires, 0)) {
+		c.JSON(403, gin.H{"detail": "download link has expired"})
+		return
+	}
+
+	var dataExport database.DataExport
+	err = database.GetDataExportCollection(api.DB).FindOne(context.Background(), bson.M{"_id": exportID}).Decode(&dataExport)
+	if err == mongo.ErrNoDocuments {
+		Handle404(c)
+		return
+	} else if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch data export")
+		Handle500(c)
+		return
+	}
+	if dataExport.Status != database.DataExportStatusComplete || now.After(dataExport.ExpiresAt.Time()) {
+		Handle404(c)
+		return
+	}
+
+	bucket, err := database.GetDataExportFileBucket(api.DB)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to open data export files")
+		Handle500(c)
+		return
+	}
+	downloadStream, err := bucket.OpenDownloadStream(dataExport.FileID)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to open data export file")
+		Handle500(c)
+		return
+	}
+	defer downloadStream.Close()
+	c.DataFromReader(200, dataExport.SizeBytes, "application/zip", downloadStream, map[string]string{
+		"Content-Disposition": fmt.Sprintf(`attachment; filename="general_task_export_%s.zip"`, dataExport.CreatedAt.Time().UTC().Format("2006-01-02")),
+	})
+}
+
+func (api *API) getDataExportResult(dataExport *database.DataExport) (DataExportResult, error) {
+	result := DataExportResult{
+		ID:        dataExport.ID,
+		Status:    dataExport.Status,
+		CreatedAt: dataExport.CreatedAt.Time().UTC().Format(time.RFC3339),
+		SizeBytes: dataExport.SizeBytes,
+	}
+	if dataExport.Status != database.DataExportStatusComplete {
+		return result, nil
+	}
+	result.CompletedAt = dataExport.CompletedAt.Time().UTC().Format(time.RFC3339)
+	result.ExpiresAt = dataExport.ExpiresAt.Time().UTC().Format(time.RFC3339)
+	expiresAt := api.GetCurrentTime().Add(DataExportDownloadURLExpiration)
+	if expiresAt.After(dataExport.ExpiresAt.Time()) {
+		expiresAt = dataExport.ExpiresAt.Time()
+	}
+	downloadURL, err := getDataExportDownloadURL(dataExport.ID, expiresAt)
+	if err != nil {
+		return result, err
+	}
+	result.DownloadURL = downloadURL
+	return result, nil
+}
+
+func getDataExportDownloadURL(exportID primitive.ObjectID, expiresAt time.Time) (string, error) {
+	expires := expiresAt.Unix()
+	signature, err := getDataExportSignature(exportID, expires)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%sdata_exports/%s/download/?expires=%d&signature=%s", config.GetConfigValue("SERVER_URL"), exportID.Hex(), expires, signature), nil
+}
+
+func getDataExportSignature(exportID primitive.ObjectID, expires int64) (string, error) {
+	// download links are not authenticated, so anyone who knows the secret could download any export
+	secret := config.GetConfigValue("DATA_EXPORT_SIGNING_SECRET")
+	if !config.IsSecretConfigured(secret) {
+		return "", errors.New("data export signing secret is not configured")
+	}
+	hash := hmac.New(sha256.New, []byte(secret))
+	hash.Write([]byte(fmt.Sprintf("%s:%d", exportID.Hex(), expires)))
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
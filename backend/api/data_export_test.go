@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDataExport(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_data_export@generaltask.com", "")
+	now := time.Date(2023, 3, 1, 16, 0, 0, 0, time.UTC)
+	api.OverrideTime = &now
+	// the placeholder secret from .env is refused
+	assert.NoError(t, os.Setenv("DATA_EXPORT_SIGNING_SECRET", "test_data_export_secret"))
+	defer os.Unsetenv("DATA_EXPORT_SIGNING_SECRET")
+
+	sendRequest := func(method string, path string, authToken string) (int, []byte) {
+		request, _ := http.NewRequest(method, path, nil)
+		if authToken != "" {
+			request.Header.Add("Authorization", "Bearer "+authToken)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, body
+	}
+	var exportID primitive.ObjectID
+
+	UnauthorizedTest(t, "POST", "/data_exports/create/", nil)
+	UnauthorizedTest(t, "GET", "/data_exports/"+primitive.NewObjectID().Hex()+"/", nil)
+	t.Run("Create", func(t *testing.T) {
+		code, body := sendRequest("POST", "/data_exports/create/", authToken)
+		assert.Equal(t, http.StatusCreated, code)
+		var result DataExportResult
+		assert.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, database.DataExportStatusPending, result.Status)
+		assert.Equal(t, "", result.DownloadURL)
+		exportID = result.ID
+
+		// an unfinished export is returned rather than starting another
+		code, body = sendRequest("POST", "/data_exports/create/", authToken)
+		assert.Equal(t, http.StatusOK, code)
+		assert.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, exportID, result.ID)
+	})
+	t.Run("GetNotFound", func(t *testing.T) {
+		code, _ := sendRequest("GET", "/data_exports/"+primitive.NewObjectID().Hex()+"/", authToken)
+		assert.Equal(t, http.StatusNotFound, code)
+		otherAuthToken := login("test_data_export_other@generaltask.com", "")
+		code, _ = sendRequest("GET", "/data_exports/"+exportID.Hex()+"/", otherAuthToken)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("Download", func(t *testing.T) {
+		bucket, err := database.GetDataExportFileBucket(api.DB)
+		assert.NoError(t, err)
+		fileID, err := bucket.UploadFromStream("data_export.zip", bytes.NewReader([]byte("zip")))
+		assert.NoError(t, err)
+		_, err = database.GetDataExportCollection(api.DB).UpdateOne(
+			context.Background(),
+			bson.M{"_id": exportID},
+			bson.M{"$set": bson.M{
+				"status":       database.DataExportStatusComplete,
+				"file_id":      fileID,
+				"size_bytes":   3,
+				"completed_at": primitive.NewDateTimeFromTime(now),
+				"expires_at":   primitive.NewDateTimeFromTime(now.Add(24 * time.Hour)),
+			}},
+		)
+		assert.NoError(t, err)
+
+		code, body := sendRequest("GET", "/data_exports/"+exportID.Hex()+"/", authToken)
+		assert.Equal(t, http.StatusOK, code)
+		var result DataExportResult
+		assert.NoError(t, json.Unmarshal(body, &result))
+		assert.Equal(t, database.DataExportStatusComplete, result.Status)
+		assert.Equal(t, int64(3), result.SizeBytes)
+		downloadURL, err := url.Parse(result.DownloadURL)
+		assert.NoError(t, err)
+		assert.Equal(t, "/data_exports/"+exportID.Hex()+"/download/", downloadURL.Path)
+		assert.Equal(t, fmt.Sprint(now.Add(DataExportDownloadURLExpiration).Unix()), downloadURL.Query().Get("expires"))
+
+		// the signed link doesn't need the user to be logged in
+		code, body = sendRequest("GET", downloadURL.RequestURI(), "")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "zip", string(body))
+
+		query := downloadURL.Query()
+		query.Set("expires", fmt.Sprint(now.Add(2*DataExportDownloadURLExpiration).Unix()))
+		code, body = sendRequest("GET", downloadURL.Path+"?"+query.Encode(), "")
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, `{"detail":"invalid download link"}`, string(body))
+
+		later := now.Add(2 * DataExportDownloadURLExpiration)
+		api.OverrideTime = &later
+		defer func() { api.OverrideTime = &now }()
+		code, body = sendRequest("GET", downloadURL.RequestURI(), "")
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, `{"detail":"download link has expired"}`, string(body))
+	})
+	t.Run("PlaceholderSecret", func(t *testing.T) {
+		assert.NoError(t, os.Setenv("DATA_EXPORT_SIGNING_SECRET", config.PlaceholderSecret))
+		defer os.Setenv("DATA_EXPORT_SIGNING_SECRET", "test_data_export_secret")
+
+		// anyone could sign a link with the placeholder
+		expires := now.Add(DataExportDownloadURLExpiration).Unix()
+		hash := hmac.New(sha256.New, []byte(config.PlaceholderSecret))
+		hash.Write([]byte(fmt.Sprintf("%s:%d", exportID.Hex(), expires)))
+		code, body := sendRequest("GET", fmt.Sprintf("/data_exports/%s/download/?expires=%d&signature=%s", exportID.Hex(), expires, hex.EncodeToString(hash.Sum(nil))), "")
+		assert.Equal(t, http.StatusForbidden, code)
+		assert.Equal(t, `{"detail":"invalid download link"}`, string(body))
+
+		// and no links are signed with it
+		code, _ = sendRequest("GET", "/data_exports/"+exportID.Hex()+"/", authToken)
+		assert.Equal(t, http.StatusInternalServerError, code)
+	})
+	t.Run("DownloadExpiredExport", func(t *testing.T) {
+		expiresAt := now.Add(time.Hour)
+		_, err := database.GetDataExportCollection(api.DB).UpdateOne(
+			context.Background(),
+			bson.M{"_id": exportID},
+			bson.M{"$set": bson.M{"status": database.DataExportStatusExpired}},
+		)
+		assert.NoError(t, err)
+		signedURL, err := getDataExportDownloadURL(exportID, expiresAt)
+		assert.NoError(t, err)
+		downloadURL, err := url.Parse(signedURL)
+		assert.NoError(t, err)
+		code, _ := sendRequest("GET", downloadURL.RequestURI(), "")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type GithubEnterpriseConfigurationParams struct {
+	BaseURL           string `json:"base_url" binding:"required"`
+	OauthClientID     string `json:"oauth_client_id" binding:"required"`
+	OauthClientSecret string `json:"oauth_client_secret" binding:"required"`
+}
+
+// GithubEnterpriseConfigurationUpdate godoc
+// @Summary      Sets the GitHub Enterprise instance to link
+// @Description  Stores the base URL and OAuth app of the user's instance, which is used by /link/github_enterprise/
+// @Tags         github enterprise
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      GithubEnterpriseConfigurationParams  true  "GitHub Enterprise configuration"
+// @Success      200 {object} string "success"
+// @Failure      400 {object} string "invalid params"
+// @Failure      500 {object} string "internal server error"
+// @Router       /github_enterprise/configuration/ [post]
+func (api *API) GithubEnterpriseConfigurationUpdate(c *gin.Context) {
+	var params GithubEnterpriseConfigurationParams
+	err := c.BindJSON(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing parameter"})
+		return
+	}
+	baseURL, err := url.Parse(params.BaseURL)
+	if err != nil || baseURL.Scheme != "https" || baseURL.Host == "" {
+		c.JSON(400, gin.H{"detail": "'base_url' must be an https URL"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	_, err = database.GetGithubEnterpriseConfigurationCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"user_id": userID},
+		bson.M{"$set": database.GithubEnterpriseConfiguration{
+			UserID: userID,
+			// only the scheme and host are kept, as the API and OAuth paths are the same on every instance
+			BaseURL:           baseURL.Scheme + "://" + baseURL.Host,
+			OauthClientID:     params.OauthClientID,
+			OauthClientSecret: params.OauthClientSecret,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to update github enterprise configuration")
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubEnterpriseConfigurationUpdate(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	authToken := login("test_github_enterprise_configuration@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	UnauthorizedTest(t, "POST", "/github_enterprise/configuration/", nil)
+	t.Run("MissingParams", func(t *testing.T) {
+		ServeRequest(t, authToken, "POST", "/github_enterprise/configuration/", bytes.NewBuffer([]byte(`{"base_url": "https://github.example.com"}`)), http.StatusBadRequest, api)
+	})
+	t.Run("InvalidBaseURL", func(t *testing.T) {
+		body := ServeRequest(t, authToken, "POST", "/github_enterprise/configuration/", bytes.NewBuffer([]byte(`{"base_url": "http://github.example.com", "oauth_client_id": "id", "oauth_client_secret": "secret"}`)), http.StatusBadRequest, api)
+		assert.Equal(t, `{"detail":"'base_url' must be an https URL"}`, string(body))
+	})
+	t.Run("Success", func(t *testing.T) {
+		ServeRequest(t, authToken, "POST", "/github_enterprise/configuration/", bytes.NewBuffer([]byte(`{"base_url": "https://github.example.com/", "oauth_client_id": "id", "oauth_client_secret": "secret"}`)), http.StatusOK, api)
+		ServeRequest(t, authToken, "POST", "/github_enterprise/configuration/", bytes.NewBuffer([]byte(`{"base_url": "https://github.example.org/", "oauth_client_id": "new_id", "oauth_client_secret": "new_secret"}`)), http.StatusOK, api)
+
+		configuration, err := database.GetGithubEnterpriseConfiguration(api.DB, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "https://github.example.org", configuration.BaseURL)
+		assert.Equal(t, "new_id", configuration.OauthClientID)
+		assert.Equal(t, "new_secret", configuration.OauthClientSecret)
+	})
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/gin-gonic/gin"
+	"github.com/google/go-github/v45/github"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GithubWebhook godoc
+// @Summary      Updates pull requests affected by a GitHub webhook event
+// @Description  Handles pull_request, pull_request_review, check_run and issue_comment events, and label changes from issues events
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} string "success"
+// @Failure      400 {object} string "invalid params"
+// @Failure      401 {object} string "invalid signature"
+// @Router       /github/webhook/ [post]
+func (api *API) GithubWebhook(c *gin.Context) {
+	api.handleGithubWebhook(c, api.ExternalConfig.Github)
+}
+
+// GithubEnterpriseWebhook godoc
+// @Summary      Updates pull requests affected by a GitHub Enterprise webhook event
+// @Description  Handles the same events as /github/webhook/, signed with the GitHub Enterprise webhook secret
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Success      200 {object} string "success"
+// @Failure      400 {object} string "invalid params"
+// @Failure      401 {object} string "invalid signature"
+// @Router       /github_enterprise/webhook/ [post]
+func (api *API) GithubEnterpriseWebhook(c *gin.Context) {
+	api.handleGithubWebhook(c, api.ExternalConfig.GithubEnterprise)
+}
+
+func (api *API) handleGithubWebhook(c *gin.Context, githubConfig external.GithubConfig) {
+	// go-github skips signature validation entirely for an empty secret
+	if !config.IsSecretConfigured(githubConfig.WebhookSecret) {
+		api.Logger.Error().Msgf("%s webhook secret is not configured", githubConfig.ServiceID)
+		c.JSON(401, gin.H{"detail": "invalid signature"})
+		return
+	}
+	payload, err := github.ValidatePayload(c.Request, []byte(githubConfig.WebhookSecret))
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("invalid signature for github webhook")
+		c.JSON(401, gin.H{"detail": "invalid signature"})
+		return
+	}
+	event, err := github.ParseWebHook(github.WebHookType(c.Request), payload)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("unable to process github webhook payload")
+		c.JSON(400, gin.H{"detail": "unable to process github webhook payload"})
+		return
+	}
+
+	if issuesEvent, ok := event.(*github.IssuesEvent); ok && issuesEvent.Issue != nil && !issuesEvent.GetIssue().IsPullRequest() {
+		go api.updateGithubIssueLabelsFromWebhook(issuesEvent.GetIssue())
+	}
+	repository, pullRequestNumbers := getPullRequestsFromGithubWebhookEvent(event)
+	if repository != nil {
+		// GitHub stops waiting after 10 seconds, which is not enough to refresh a pull request for every user
+		go api.refreshPullRequestsFromGithubWebhook(external.GithubService{Config: githubConfig}, repository, pullRequestNumbers)
+	}
+	c.JSON(200, gin.H{})
+}
+
+// issues are only added by the regular fetch, so this just keeps the labels of existing issue tasks in sync
+func (api *API) updateGithubIssueLabelsFromWebhook(issue *github.Issue) {
+	tasks, err := database.GetTasksBySourceExternalIDWithoutUser(api.DB, fmt.Sprint(issue.GetID()), external.TASK_SOURCE_ID_GITHUB_ISSUE)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch github issues for github webhook")
+		return
+	}
+	externalLabels := external.GetGithubIssueExternalLabels(issue)
+	for _, task := range *tasks {
+		// issue IDs are only unique within a GitHub instance, so the link tells github.com and Enterprise issues apart
+		if task.Deeplink != issue.GetHTMLURL() {
+			continue
+		}
+		// for implicit memory aliasing
+		tempTask := task
+		tempTask.ExternalLabels = &externalLabels
+		err = api.UpdateTaskInDBWithError(&tempTask, tempTask.UserID, &database.Task{ExternalLabels: &externalLabels})
+		if err == nil {
+			err = api.importExternalLabels(tempTask.UserID, &[]*database.Task{&tempTask})
+		}
+		if err != nil {
+			// keep going so that one failed update does not block updates for other users
+			api.Logger.Error().Err(err).Msg("failed to update github issue labels from github webhook")
+		}
+	}
+}
+
+// refreshes the pull requests for every user with the repository, so that newly opened pull requests are created too.
+// the base URL of GitHub Enterprise instances comes from each user's linked account
+func (api *API) refreshPullRequestsFromGithubWebhook(githubService external.GithubService, repository *github.Repository, pullRequestNumbers []int) {
+	repositoryID := githubService.GetRepositoryID(repository)
+	accountIDs := make(map[primitive.ObjectID]string)
+	repositories, err := database.GetRepositoriesByRepositoryIDWithoutUser(api.DB, repositoryID)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch repositories for github webhook")
+		return
+	}
+	for _, repository := range *repositories {
+		if repository.AccountID != "" {
+			accountIDs[repository.UserID] = repository.AccountID
+		}
+	}
+
+	githubPRSource := external.GithubPRSource{Github: githubService}
+	for _, number := range pullRequestNumbers {
+		pullRequests, err := database.GetPullRequestsByRepositoryWithoutUser(api.DB, repositoryID, number)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to fetch pull requests for github webhook")
+			return
+		}
+		userAccountIDs := make(map[primitive.ObjectID]string)
+		for userID, accountID := range accountIDs {
+			userAccountIDs[userID] = accountID
+		}
+		for _, pullRequest := range *pullRequests {
+			userAccountIDs[pullRequest.UserID] = pullRequest.SourceAccountID
+		}
+		for userID, accountID := range userAccountIDs {
+			_, err = githubPRSource.RefreshPullRequest(api.DB, userID, accountID, repository, number)
+			if err != nil {
+				// keep going so that one bad token does not block updates for other users
+				api.Logger.Error().Err(err).Msg("failed to refresh pull request from github webhook")
+			}
+		}
+	}
+}
+
+// returns a nil repository if the event is not tied to any pull requests
+func getPullRequestsFromGithubWebhookEvent(event interface{}) (*github.Repository, []int) {
+	switch event := event.(type) {
+	case *github.PullRequestEvent:
+		if event.PullRequest == nil {
+			return nil, nil
+		}
+		return event.GetRepo(), []int{event.GetPullRequest().GetNumber()}
+	case *github.PullRequestReviewEvent:
+		if event.PullRequest == nil {
+			return nil, nil
+		}
+		return event.GetRepo(), []int{event.GetPullRequest().GetNumber()}
+	case *github.CheckRunEvent:
+		var numbers []int
+		for _, pullRequest := range event.GetCheckRun().PullRequests {
+			numbers = append(numbers, pullRequest.GetNumber())
+		}
+		if len(numbers) == 0 {
+			return nil, nil
+		}
+		return event.GetRepo(), numbers
+	case *github.IssueCommentEvent:
+		if !event.GetIssue().IsPullRequest() {
+			return nil, nil
+		}
+		return event.GetRepo(), []int{event.GetIssue().GetNumber()}
+	}
+	return nil, nil
+}
@@ -0,0 +1,246 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/testutils"
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const githubWebhookTestSecret = "example_secret"
+
+func newGithubWebhookRequest(eventType string, payload string, secret string) *http.Request {
+	return newGithubWebhookRequestWithPath("/github/webhook/", eventType, payload, secret)
+}
+
+func newGithubWebhookRequestWithPath(path string, eventType string, payload string, secret string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	request, _ := http.NewRequest("POST", path, bytes.NewBuffer([]byte(payload)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(github.EventTypeHeader, eventType)
+	request.Header.Set(github.SHA256SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return request
+}
+
+func TestGithubWebhook(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	api.ExternalConfig.Github.WebhookSecret = githubWebhookTestSecret
+	router := GetRouter(api)
+
+	t.Run("MissingSecret", func(t *testing.T) {
+		api, dbCleanup := GetAPIWithDBCleanup()
+		defer dbCleanup()
+		api.ExternalConfig.Github.WebhookSecret = ""
+		recorder := httptest.NewRecorder()
+		GetRouter(api).ServeHTTP(recorder, newGithubWebhookRequest("ping", `{"zen": "hello"}`, ""))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+	t.Run("PlaceholderSecret", func(t *testing.T) {
+		api, dbCleanup := GetAPIWithDBCleanup()
+		defer dbCleanup()
+		api.ExternalConfig.Github.WebhookSecret = config.PlaceholderSecret
+		recorder := httptest.NewRecorder()
+		GetRouter(api).ServeHTTP(recorder, newGithubWebhookRequest("ping", `{"zen": "hello"}`, "dummy_value"))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+	t.Run("GithubEnterprise", func(t *testing.T) {
+		api, dbCleanup := GetAPIWithDBCleanup()
+		defer dbCleanup()
+		api.ExternalConfig.Github.WebhookSecret = githubWebhookTestSecret
+		api.ExternalConfig.GithubEnterprise.WebhookSecret = "enterprise_secret"
+		router := GetRouter(api)
+
+		// each endpoint only accepts its own secret
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newGithubWebhookRequestWithPath("/github_enterprise/webhook/", "ping", `{"zen": "hello"}`, githubWebhookTestSecret))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, newGithubWebhookRequest("ping", `{"zen": "hello"}`, "enterprise_secret"))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, newGithubWebhookRequestWithPath("/github_enterprise/webhook/", "ping", `{"zen": "hello"}`, "enterprise_secret"))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+	t.Run("InvalidSignature", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newGithubWebhookRequest("ping", `{"zen": "hello"}`, "wrong_secret"))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"detail\":\"invalid signature\"}", string(body))
+	})
+	t.Run("InvalidPayload", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newGithubWebhookRequest("pull_request", `"uhoh"`, githubWebhookTestSecret))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"detail\":\"unable to process github webhook payload\"}", string(body))
+	})
+	t.Run("IgnoredEvent", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newGithubWebhookRequest("ping", `{"zen": "hello"}`, githubWebhookTestSecret))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		githubCompareServer := testutils.GetMockAPIServer(t, 200, testutils.CompareResponsePayload)
+		defer githubCompareServer.Close()
+		githubUserServer := testutils.GetMockAPIServer(t, 200, testutils.UserResponsePayload)
+		defer githubUserServer.Close()
+		githubPullRequestServer := testutils.GetMockAPIServer(t, 200, testutils.PullRequestPayload)
+		defer githubPullRequestServer.Close()
+		githubPullRequestReviewersServer := testutils.GetMockAPIServer(t, 200, testutils.EmptyPullRequestReviewersPayload)
+		defer githubPullRequestReviewersServer.Close()
+		githubEmptyListServer := testutils.GetMockAPIServer(t, 200, `[]`)
+		defer githubEmptyListServer.Close()
+		githubListCheckRunsForRefServer := testutils.GetMockAPIServer(t, 200, testutils.EmptyCheckRunsForRefPayload)
+		defer githubListCheckRunsForRefServer.Close()
+
+		fetchExternalAPIToken := false
+		api.ExternalConfig.Github.ConfigValues = external.GithubConfigValues{
+			FetchExternalAPIToken:       &fetchExternalAPIToken,
+			CompareURL:                  &githubCompareServer.URL,
+			GetUserURL:                  &githubUserServer.URL,
+			GetPullRequestURL:           &githubPullRequestServer.URL,
+			ListPullRequestCommentsURL:  &githubEmptyListServer.URL,
+			ListIssueCommentsURL:        &githubEmptyListServer.URL,
+			ListPullRequestReviewURL:    &githubEmptyListServer.URL,
+			ListPullRequestReviewersURL: &githubPullRequestReviewersServer.URL,
+			ListCheckRunsForRefURL:      &githubListCheckRunsForRefServer.URL,
+			ListUserTeamsURL:            &githubEmptyListServer.URL,
+		}
+		defer func() { api.ExternalConfig.Github.ConfigValues = external.GithubConfigValues{} }()
+
+		userID := primitive.NewObjectID()
+		isCompleted := false
+		_, err := database.GetPullRequestCollection(api.DB).InsertOne(context.Background(), database.PullRequest{
+			UserID:          userID,
+			IDExternal:      "1",
+			SourceID:        external.TASK_SOURCE_ID_GITHUB_PR,
+			SourceAccountID: "exampleAccountID",
+			IsCompleted:     &isCompleted,
+			RepositoryID:    "98765",
+			Number:          420,
+			Title:           "something cached in the db",
+			RequiredAction:  external.ActionNoneNeeded,
+		})
+		assert.NoError(t, err)
+
+		// users who have the repository but not the pull request yet, e.g. for newly opened pull requests
+		newUserID := primitive.NewObjectID()
+		_, err = database.GetRepositoryCollection(api.DB).InsertOne(context.Background(), database.Repository{
+			UserID:       newUserID,
+			AccountID:    "exampleAccountID",
+			RepositoryID: "98765",
+		})
+		assert.NoError(t, err)
+
+		// pull requests are refreshed in the background, so the refresh is checked directly
+		payload := `{"action": "submitted", "pull_request": {"id": 1, "number": 420}, "repository": {"id": 98765, "name": "ExampleRepository", "full_name": "dankmemes/ExampleRepository", "owner": {"login": "gigaChad123"}}}`
+		event, err := github.ParseWebHook("pull_request_review", []byte(payload))
+		assert.NoError(t, err)
+		repository, numbers := getPullRequestsFromGithubWebhookEvent(event)
+		api.refreshPullRequestsFromGithubWebhook(external.GithubService{Config: api.ExternalConfig.Github}, repository, numbers)
+
+		for _, id := range []primitive.ObjectID{userID, newUserID} {
+			pullRequest, err := database.GetPullRequestByExternalID(api.DB, "1", id)
+			assert.NoError(t, err)
+			assert.Equal(t, "Fix big oopsie", pullRequest.Title)
+			assert.Equal(t, external.ActionAddReviewers, pullRequest.RequiredAction)
+		}
+	})
+}
+
+func TestUpdateGithubIssueLabelsFromWebhook(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	issueURL := "https://github.com/dankmemes/ExampleRepository/issues/7"
+	insertResult, err := database.GetTaskCollection(api.DB).InsertMany(context.Background(), []interface{}{
+		database.Task{UserID: userID, IDExternal: "1001", SourceID: external.TASK_SOURCE_ID_GITHUB_ISSUE, Deeplink: issueURL},
+		// an issue with the same ID on a GitHub Enterprise instance
+		database.Task{UserID: userID, IDExternal: "1001", SourceID: external.TASK_SOURCE_ID_GITHUB_ISSUE, Deeplink: "https://github.example.com/dankmemes/ExampleRepository/issues/7"},
+	})
+	assert.NoError(t, err)
+
+	payload := `{"action": "labeled", "issue": {"id": 1001, "number": 7, "html_url": "` + issueURL + `", "labels": [{"id": 208045946, "name": "bug", "color": "f29513"}]}, "repository": {"id": 98765}}`
+	event, err := github.ParseWebHook("issues", []byte(payload))
+	assert.NoError(t, err)
+	api.updateGithubIssueLabelsFromWebhook(event.(*github.IssuesEvent).GetIssue())
+
+	labelIDs, err := database.GetExternalLabelIDs(api.DB, userID, external.TASK_SOURCE_ID_GITHUB_ISSUE)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(labelIDs))
+	task, err := database.GetTask(api.DB, insertResult.InsertedIDs[0].(primitive.ObjectID), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, labelIDs, *task.LabelIDs)
+	assert.Equal(t, "bug", (*task.ExternalLabels)[0].Name)
+	task, err = database.GetTask(api.DB, insertResult.InsertedIDs[1].(primitive.ObjectID), userID)
+	assert.NoError(t, err)
+	assert.Nil(t, task.LabelIDs)
+}
+
+func TestGetPullRequestsFromGithubWebhookEvent(t *testing.T) {
+	repository := &github.Repository{ID: github.Int64(1234)}
+	t.Run("PullRequest", func(t *testing.T) {
+		resultRepository, numbers := getPullRequestsFromGithubWebhookEvent(&github.PullRequestEvent{
+			Repo:        repository,
+			PullRequest: &github.PullRequest{Number: github.Int(7)},
+		})
+		assert.Equal(t, repository, resultRepository)
+		assert.Equal(t, []int{7}, numbers)
+	})
+	t.Run("PullRequestReview", func(t *testing.T) {
+		resultRepository, numbers := getPullRequestsFromGithubWebhookEvent(&github.PullRequestReviewEvent{
+			Repo:        repository,
+			PullRequest: &github.PullRequest{Number: github.Int(7)},
+		})
+		assert.Equal(t, repository, resultRepository)
+		assert.Equal(t, []int{7}, numbers)
+	})
+	t.Run("CheckRun", func(t *testing.T) {
+		resultRepository, numbers := getPullRequestsFromGithubWebhookEvent(&github.CheckRunEvent{
+			Repo:     repository,
+			CheckRun: &github.CheckRun{PullRequests: []*github.PullRequest{{Number: github.Int(7)}, {Number: github.Int(8)}}},
+		})
+		assert.Equal(t, repository, resultRepository)
+		assert.Equal(t, []int{7, 8}, numbers)
+	})
+	t.Run("CheckRunWithoutPullRequests", func(t *testing.T) {
+		resultRepository, _ := getPullRequestsFromGithubWebhookEvent(&github.CheckRunEvent{Repo: repository, CheckRun: &github.CheckRun{}})
+		assert.Nil(t, resultRepository)
+	})
+	t.Run("IssueComment", func(t *testing.T) {
+		resultRepository, numbers := getPullRequestsFromGithubWebhookEvent(&github.IssueCommentEvent{
+			Repo:  repository,
+			Issue: &github.Issue{Number: github.Int(7), PullRequestLinks: &github.PullRequestLinks{}},
+		})
+		assert.Equal(t, repository, resultRepository)
+		assert.Equal(t, []int{7}, numbers)
+	})
+	t.Run("IssueCommentOnIssue", func(t *testing.T) {
+		resultRepository, _ := getPullRequestsFromGithubWebhookEvent(&github.IssueCommentEvent{Repo: repository, Issue: &github.Issue{Number: github.Int(7)}})
+		assert.Nil(t, resultRepository)
+	})
+	t.Run("OtherEvent", func(t *testing.T) {
+		resultRepository, _ := getPullRequestsFromGithubWebhookEvent(&github.PingEvent{})
+		assert.Nil(t, resultRepository)
+	})
+}
@@ -0,0 +1,225 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const JIRAIssueCreatedEvent = "jira:issue_created"
+const JIRAIssueUpdatedEvent = "jira:issue_updated"
+const JIRAIssueDeletedEvent = "jira:issue_deleted"
+const JIRACommentCreatedEvent = "comment_created"
+const JIRACommentUpdatedEvent = "comment_updated"
+const JIRACommentDeletedEvent = "comment_deleted"
+
+type JIRAWebhookPayload struct {
+	WebhookEvent string            `json:"webhookEvent"`
+	Issue        *JIRAWebhookIssue `json:"issue"`
+}
+
+type JIRAWebhookIssue struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type jiraWebhookTokenHeader struct {
+	Algorithm string `json:"alg"`
+}
+
+type jiraWebhookTokenClaims struct {
+	ExpiresAt       int64  `json:"exp"`
+	QueryStringHash string `json:"qsh"`
+}
+
+// JIRAWebhook godoc
+// @Summary      Updates tasks affected by a Jira webhook event
+// @Description  Handles issue created, updated and deleted events and comment events for a Jira site
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        cloud_id  path      string  true  "Atlassian cloud ID of the site"
+// @Success      200 {object} string "success"
+// @Failure      400 {object} string "invalid params"
+// @Failure      401 {object} string "invalid signature"
+// @Router       /jira/webhook/{cloud_id}/ [post]
+func (api *API) JIRAWebhook(c *gin.Context) {
+	err := verifyJIRAWebhookToken(c.GetHeader("Authorization"), api.ExternalConfig.Atlassian.WebhookSecret, getJIRAQueryStringHash(c.Request.Method, c.Request.URL), time.Now())
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("invalid signature for jira webhook")
+		c.JSON(401, gin.H{"detail": "invalid signature"})
+		return
+	}
+
+	var webhookPayload JIRAWebhookPayload
+	err = c.BindJSON(&webhookPayload)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("unable to process jira webhook payload")
+		c.JSON(400, gin.H{"detail": "unable to process jira webhook payload"})
+		return
+	}
+	if webhookPayload.Issue == nil || webhookPayload.Issue.ID == "" || !isValidJIRAWebhookEvent(webhookPayload.WebhookEvent) {
+		c.JSON(400, gin.H{"detail": "unrecognized jira payload format"})
+		return
+	}
+
+	// Jira expects a quick response, and each site configuration needs the issue to be re-fetched
+	go api.processJIRAWebhook(c.Param("cloud_id"), webhookPayload)
+	c.JSON(200, gin.H{})
+}
+
+func (api *API) processJIRAWebhook(cloudID string, webhookPayload JIRAWebhookPayload) {
+	siteConfigurations, err := database.GetAtlassianSiteConfigurationsByCloudID(api.DB, cloudID)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch site configurations for jira webhook")
+		return
+	}
+	taskSourceResult, err := api.ExternalConfig.GetSourceResult(external.TASK_SOURCE_ID_JIRA)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("unable to get task source result for jira")
+		return
+	}
+	jiraSource := taskSourceResult.Source.(external.JIRASource)
+
+	for _, siteConfiguration := range *siteConfigurations {
+		if webhookPayload.WebhookEvent == JIRAIssueDeletedEvent {
+			err = api.deleteJIRATask(siteConfiguration, webhookPayload.Issue.ID)
+		} else {
+			// webhook payloads use the v2 API format, so the issue and its comments are re-fetched instead
+			var task *database.Task
+			task, err = jiraSource.RefreshTask(api.DB, siteConfiguration.UserID, siteConfiguration.CloudID, webhookPayload.Issue.ID)
+			if err == nil && task != nil {
+				err = api.importExternalLabels(siteConfiguration.UserID, &[]*database.Task{task})
+			}
+		}
+		if err != nil {
+			// keep going so that one bad token does not block updates for other users
+			api.Logger.Error().Err(err).Msg("failed to process jira webhook")
+		}
+	}
+}
+
+func isValidJIRAWebhookEvent(webhookEvent string) bool {
+	switch webhookEvent {
+	case JIRAIssueCreatedEvent, JIRAIssueUpdatedEvent, JIRAIssueDeletedEvent, JIRACommentCreatedEvent, JIRACommentUpdatedEvent, JIRACommentDeletedEvent:
+		return true
+	}
+	return false
+}
+
+func (api *API) deleteJIRATask(siteConfiguration database.AtlassianSiteConfiguration, issueID string) error {
+	task, err := database.GetTaskBySourceExternalID(api.DB, issueID, external.TASK_SOURCE_ID_JIRA, siteConfiguration.UserID)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	isDeleted := true
+	updateTask := database.Task{
+		IsDeleted: &isDeleted,
+		DeletedAt: primitive.NewDateTimeFromTime(time.Now()),
+	}
+	return api.UpdateTaskInDBWithError(task, siteConfiguration.UserID, &updateTask)
+}
+
+// Jira signs webhooks for OAuth 2.0 apps with an HS256 JWT using the app's client secret,
+// and the qsh claim binds the token to the request so that it can't be replayed against another URL
+func verifyJIRAWebhookToken(authorizationHeader string, secret string, queryStringHash string, now time.Time) error {
+	if secret == "" {
+		return errors.New("jira webhook secret is not configured")
+	}
+	token := strings.TrimPrefix(authorizationHeader, "Bearer ")
+	parts := strings.Split(token, ".")
+	if token == authorizationHeader || len(parts) != 3 {
+		return errors.New("malformed jira webhook token")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return err
+	}
+	var header jiraWebhookTokenHeader
+	err = json.Unmarshal(headerBytes, &header)
+	if err != nil {
+		return err
+	}
+	if header.Algorithm != "HS256" {
+		return errors.New("unsupported jira webhook token algorithm")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return errors.New("jira webhook token signature mismatch")
+	}
+
+	claimsBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims jiraWebhookTokenClaims
+	err = json.Unmarshal(claimsBytes, &claims)
+	if err != nil {
+		return err
+	}
+	if claims.ExpiresAt == 0 || now.Unix() > claims.ExpiresAt {
+		return errors.New("jira webhook token has expired")
+	}
+	if !hmac.Equal([]byte(claims.QueryStringHash), []byte(queryStringHash)) {
+		return errors.New("jira webhook token query string hash mismatch")
+	}
+	return nil
+}
+
+// see https://developer.atlassian.com/cloud/jira/platform/understanding-jwt-for-connect-apps/#qsh
+func getJIRAQueryStringHash(method string, requestURL *url.URL) string {
+	path := strings.TrimSuffix(requestURL.EscapedPath(), "/")
+	if path == "" {
+		path = "/"
+	}
+	path = strings.ReplaceAll(path, "&", "%26")
+
+	query := requestURL.Query()
+	keys := []string{}
+	for key := range query {
+		if key != "jwt" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	params := []string{}
+	for _, key := range keys {
+		values := []string{}
+		for _, value := range query[key] {
+			values = append(values, escapeJIRAQueryStringHashValue(value))
+		}
+		sort.Strings(values)
+		params = append(params, escapeJIRAQueryStringHashValue(key)+"="+strings.Join(values, ","))
+	}
+
+	hash := sha256.Sum256([]byte(strings.ToUpper(method) + "&" + path + "&" + strings.Join(params, "&")))
+	return hex.EncodeToString(hash[:])
+}
+
+// names and values are percent encoded per RFC 3986, so spaces are %20 rather than +
+func escapeJIRAQueryStringHashValue(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const jiraWebhookTestSecret = "example_secret"
+
+func getJIRAWebhookToken(secret string, expiresAt time.Time, queryStringHash string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	claims := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iss":"example","exp":%d,"qsh":"%s"}`, expiresAt.Unix(), queryStringHash)))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(header + "." + claims))
+	return header + "." + claims + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func getJIRAWebhookPathQueryStringHash(cloudID string) string {
+	return getJIRAQueryStringHash("POST", &url.URL{Path: "/jira/webhook/" + cloudID + "/"})
+}
+
+func newJIRAWebhookRequest(cloudID string, payload string, secret string) *http.Request {
+	request, _ := http.NewRequest("POST", "/jira/webhook/"+cloudID+"/", bytes.NewBuffer([]byte(payload)))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+getJIRAWebhookToken(secret, time.Now().Add(time.Minute), getJIRAWebhookPathQueryStringHash(cloudID)))
+	return request
+}
+
+func TestJIRAWebhook(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	api.ExternalConfig.Atlassian.WebhookSecret = jiraWebhookTestSecret
+	router := GetRouter(api)
+
+	t.Run("InvalidSignature", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newJIRAWebhookRequest("sample_cloud_id", `{}`, "wrong_secret"))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"detail\":\"invalid signature\"}", string(body))
+	})
+	t.Run("OtherRequestToken", func(t *testing.T) {
+		// a token signed for another site isn't accepted
+		request := newJIRAWebhookRequest("other_cloud_id", `{}`, jiraWebhookTestSecret)
+		request.URL.Path = "/jira/webhook/sample_cloud_id/"
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+	t.Run("InvalidPayload", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newJIRAWebhookRequest("sample_cloud_id", `"uhoh"`, jiraWebhookTestSecret))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"detail\":\"unable to process jira webhook payload\"}", string(body))
+	})
+	t.Run("MissingIssue", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newJIRAWebhookRequest("sample_cloud_id", `{"webhookEvent": "jira:issue_updated"}`, jiraWebhookTestSecret))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"detail\":\"unrecognized jira payload format\"}", string(body))
+	})
+	t.Run("UnrecognizedEvent", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newJIRAWebhookRequest("sample_cloud_id", `{"webhookEvent": "sprint_started", "issue": {"id": "42069"}}`, jiraWebhookTestSecret))
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+	t.Run("UnknownSite", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, newJIRAWebhookRequest(primitive.NewObjectID().Hex(), `{"webhookEvent": "jira:issue_updated", "issue": {"id": "42069"}}`, jiraWebhookTestSecret))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+	t.Run("IssueDeleted", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		cloudID := primitive.NewObjectID().Hex()
+		_, err := database.GetJiraSitesCollection(api.DB).InsertOne(context.Background(), database.AtlassianSiteConfiguration{
+			UserID:  userID,
+			CloudID: cloudID,
+		})
+		assert.NoError(t, err)
+		isDeleted := false
+		_, err = database.GetOrCreateTask(api.DB, userID, "42069", external.TASK_SOURCE_ID_JIRA, &database.Task{
+			UserID:          userID,
+			IDExternal:      "42069",
+			SourceID:        external.TASK_SOURCE_ID_JIRA,
+			SourceAccountID: cloudID,
+			IsDeleted:       &isDeleted,
+		})
+		assert.NoError(t, err)
+
+		api.processJIRAWebhook(cloudID, JIRAWebhookPayload{
+			WebhookEvent: JIRAIssueDeletedEvent,
+			Issue:        &JIRAWebhookIssue{ID: "42069", Key: "MOON-1969"},
+		})
+
+		task, err := database.GetTaskByExternalID(api.DB, "42069", userID)
+		assert.NoError(t, err)
+		assert.True(t, *task.IsDeleted)
+		assert.NotEqual(t, primitive.DateTime(0), task.DeletedAt)
+	})
+}
+
+func TestVerifyJIRAWebhookToken(t *testing.T) {
+	now := time.Now()
+	qsh := getJIRAWebhookPathQueryStringHash("sample_cloud_id")
+	t.Run("MissingSecret", func(t *testing.T) {
+		assert.Error(t, verifyJIRAWebhookToken("Bearer "+getJIRAWebhookToken("", now.Add(time.Minute), qsh), "", qsh, now))
+	})
+	t.Run("MissingBearer", func(t *testing.T) {
+		assert.Error(t, verifyJIRAWebhookToken(getJIRAWebhookToken(jiraWebhookTestSecret, now.Add(time.Minute), qsh), jiraWebhookTestSecret, qsh, now))
+	})
+	t.Run("Malformed", func(t *testing.T) {
+		assert.Error(t, verifyJIRAWebhookToken("Bearer abc.def", jiraWebhookTestSecret, qsh, now))
+	})
+	t.Run("WrongSecret", func(t *testing.T) {
+		assert.Error(t, verifyJIRAWebhookToken("Bearer "+getJIRAWebhookToken("wrong_secret", now.Add(time.Minute), qsh), jiraWebhookTestSecret, qsh, now))
+	})
+	t.Run("Expired", func(t *testing.T) {
+		assert.Error(t, verifyJIRAWebhookToken("Bearer "+getJIRAWebhookToken(jiraWebhookTestSecret, now.Add(-time.Minute), qsh), jiraWebhookTestSecret, qsh, now))
+	})
+	t.Run("WrongQueryStringHash", func(t *testing.T) {
+		assert.Error(t, verifyJIRAWebhookToken("Bearer "+getJIRAWebhookToken(jiraWebhookTestSecret, now.Add(time.Minute), qsh), jiraWebhookTestSecret, getJIRAWebhookPathQueryStringHash("other_cloud_id"), now))
+	})
+	t.Run("Success", func(t *testing.T) {
+		assert.NoError(t, verifyJIRAWebhookToken("Bearer "+getJIRAWebhookToken(jiraWebhookTestSecret, now.Add(time.Minute), qsh), jiraWebhookTestSecret, qsh, now))
+	})
+}
+
+func TestGetJIRAQueryStringHash(t *testing.T) {
+	requestURL, err := url.Parse("/jira/webhook/sample_cloud_id/?b=two%20words&a=1&jwt=token")
+	assert.NoError(t, err)
+	hash := sha256.Sum256([]byte("POST&/jira/webhook/sample_cloud_id&a=1&b=two%20words"))
+	assert.Equal(t, hex.EncodeToString(hash[:]), getJIRAQueryStringHash("post", requestURL))
+
+	rootURL, err := url.Parse("/")
+	assert.NoError(t, err)
+	hash = sha256.Sum256([]byte("GET&/&"))
+	assert.Equal(t, hex.EncodeToString(hash[:]), getJIRAQueryStringHash("GET", rootURL))
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slices"
+)
+
+const LabelIDQueryParameter = "label_id"
+
+type LabelCreateParams struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color"`
+}
+
+type LabelModifyParams struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type LabelResult struct {
+	ID       primitive.ObjectID `json:"id"`
+	Name     string             `json:"name"`
+	Color    string             `json:"color,omitempty"`
+	SourceID string             `json:"source_id,omitempty"`
+}
+
+func (api *API) LabelList(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	labels, err := database.GetLabels(api.DB, userID)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	labelResults := []LabelResult{}
+	for _, label := range *labels {
+		labelResults = append(labelResults, LabelResult{
+			ID:       label.ID,
+			Name:     label.Name,
+			Color:    label.Color,
+			SourceID: label.SourceID,
+		})
+	}
+	c.JSON(200, labelResults)
+}
+
+func (api *API) LabelAdd(c *gin.Context) {
+	var params LabelCreateParams
+	err := c.BindJSON(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing 'name' parameter"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	mongoResult, err := database.GetLabelCollection(api.DB).InsertOne(
+		context.Background(),
+		&database.Label{
+			UserID: userID,
+			Name:   params.Name,
+			Color:  params.Color,
+		},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to insert label")
+		Handle500(c)
+		return
+	}
+	c.JSON(201, gin.H{"id": mongoResult.InsertedID.(primitive.ObjectID).Hex()})
+}
+
+func (api *API) LabelModify(c *gin.Context) {
+	labelID, err := primitive.ObjectIDFromHex(c.Param("label_id"))
+	if err != nil {
+		// This means the label ID is improperly formatted
+		Handle404(c)
+		return
+	}
+	var params LabelModifyParams
+	err = c.BindJSON(&params)
+	if err != nil || params == (LabelModifyParams{}) {
+		c.JSON(400, gin.H{"detail": "invalid or missing label modify parameter"})
+		return
+	}
+
+	updateFields := bson.M{}
+	if params.Name != "" {
+		updateFields["name"] = params.Name
+	}
+	if params.Color != "" {
+		updateFields["color"] = params.Color
+	}
+	userID := getUserIDFromContext(c)
+	res, err := database.GetLabelCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": labelID},
+			{"user_id": userID},
+		}},
+		bson.M{"$set": updateFields},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to update label")
+		Handle500(c)
+		return
+	}
+	if res.MatchedCount != 1 {
+		Handle404(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+func (api *API) LabelDelete(c *gin.Context) {
+	labelID, err := primitive.ObjectIDFromHex(c.Param("label_id"))
+	if err != nil {
+		// This means the label ID is improperly formatted
+		Handle404(c)
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	res, err := database.GetLabelCollection(api.DB).DeleteOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": labelID},
+			{"user_id": userID},
+		}},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to delete label")
+		Handle500(c)
+		return
+	}
+	if res.DeletedCount != 1 {
+		Handle404(c)
+		return
+	}
+
+	_, err = database.GetTaskCollection(api.DB).UpdateMany(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"label_ids": labelID},
+		}},
+		bson.M{"$pull": bson.M{"label_ids": labelID}},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to remove label from tasks")
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+// getLabelIDs validates that the labels belong to the user
+func (api *API) getLabelIDs(userID primitive.ObjectID, labelIDHexes []string) ([]primitive.ObjectID, error) {
+	labelIDs := []primitive.ObjectID{}
+	for _, labelIDHex := range labelIDHexes {
+		labelID, err := primitive.ObjectIDFromHex(labelIDHex)
+		if err != nil {
+			return nil, errors.New("invalid label ID")
+		}
+		if !slices.Contains(labelIDs, labelID) {
+			labelIDs = append(labelIDs, labelID)
+		}
+	}
+	if len(labelIDs) == 0 {
+		return labelIDs, nil
+	}
+	count, err := database.GetLabelCollection(api.DB).CountDocuments(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": bson.M{"$in": labelIDs}},
+			{"user_id": userID},
+		}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if int(count) != len(labelIDs) {
+		return nil, errors.New("label not found")
+	}
+	return labelIDs, nil
+}
+
+// getLabelFilter reads the optional label filter from the query parameters
+func getLabelFilter(c *gin.Context) (*primitive.ObjectID, error) {
+	labelIDHex, exists := c.GetQuery(LabelIDQueryParameter)
+	if !exists {
+		return nil, nil
+	}
+	labelID, err := primitive.ObjectIDFromHex(labelIDHex)
+	if err != nil {
+		return nil, errors.New("invalid 'label_id' parameter")
+	}
+	return &labelID, nil
+}
+
+func hasLabel(labelIDs *[]primitive.ObjectID, labelID primitive.ObjectID) bool {
+	return labelIDs != nil && slices.Contains(*labelIDs, labelID)
+}
+
+// filterTasksByLabel keeps the tasks with the label, along with the subtasks at every level below those tasks
+func filterTasksByLabel(tasks []database.Task, labelID primitive.ObjectID) []database.Task {
+	parentTaskIDs := make(map[primitive.ObjectID]primitive.ObjectID)
+	labeledTaskIDs := make(map[primitive.ObjectID]bool)
+	for _, task := range tasks {
+		parentTaskIDs[task.ID] = task.ParentTaskID
+		if task.ParentTaskID == primitive.NilObjectID && hasLabel(task.LabelIDs, labelID) {
+			labeledTaskIDs[task.ID] = true
+		}
+	}
+	filteredTasks := []database.Task{}
+	for _, task := range tasks {
+		// walks up to the top level task, which is the one that carries the label
+		topLevelTaskID := task.ID
+		for depth := 0; depth < len(tasks) && parentTaskIDs[topLevelTaskID] != primitive.NilObjectID; depth++ {
+			topLevelTaskID = parentTaskIDs[topLevelTaskID]
+		}
+		if labeledTaskIDs[topLevelTaskID] {
+			filteredTasks = append(filteredTasks, task)
+		}
+	}
+	return filteredTasks
+}
+
+// importExternalLabels adds the labels from external sources to the user's labels, and replaces the labels of the fetched tasks
+// which came from their source so that labels removed in the source are removed here too. labels added by the user are kept
+func (api *API) importExternalLabels(userID primitive.ObjectID, tasks *[]*database.Task) error {
+	for _, task := range *tasks {
+		// sources which don't support labels leave them unset, while an empty list means every label was removed
+		if task.ExternalLabels == nil {
+			continue
+		}
+		labelIDs := []primitive.ObjectID{}
+		for _, externalLabel := range *task.ExternalLabels {
+			label, err := database.UpdateOrCreateExternalLabel(api.DB, userID, task.SourceID, externalLabel)
+			if err != nil {
+				return err
+			}
+			labelIDs = append(labelIDs, label.ID)
+		}
+		sourceLabelIDs, err := database.GetExternalLabelIDs(api.DB, userID, task.SourceID)
+		if err != nil {
+			return err
+		}
+		removedLabelIDs := []primitive.ObjectID{}
+		for _, sourceLabelID := range sourceLabelIDs {
+			if !slices.Contains(labelIDs, sourceLabelID) {
+				removedLabelIDs = append(removedLabelIDs, sourceLabelID)
+			}
+		}
+		taskFilter := bson.M{"$and": []bson.M{
+			{"_id": task.ID},
+			{"user_id": userID},
+		}}
+		_, err = database.GetTaskCollection(api.DB).UpdateOne(
+			context.Background(),
+			taskFilter,
+			bson.M{"$pull": bson.M{"label_ids": bson.M{"$in": removedLabelIDs}}},
+		)
+		if err != nil {
+			return err
+		}
+		_, err = database.GetTaskCollection(api.DB).UpdateOne(
+			context.Background(),
+			taskFilter,
+			bson.M{"$addToSet": bson.M{"label_ids": bson.M{"$each": labelIDs}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
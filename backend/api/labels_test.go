@@ -0,0 +1,269 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLabels(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_labels@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	createLabel := func(payload string) (int, string) {
+		request, _ := http.NewRequest("POST", "/labels/create/", bytes.NewBuffer([]byte(payload)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, string(body)
+	}
+	listLabels := func() []LabelResult {
+		request, _ := http.NewRequest("GET", "/labels/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var result []LabelResult
+		err := json.NewDecoder(recorder.Body).Decode(&result)
+		assert.NoError(t, err)
+		return result
+	}
+
+	UnauthorizedTest(t, "GET", "/labels/", nil)
+	UnauthorizedTest(t, "POST", "/labels/create/", nil)
+	UnauthorizedTest(t, "PATCH", "/labels/modify/123/", nil)
+	UnauthorizedTest(t, "DELETE", "/labels/delete/123/", nil)
+	t.Run("MissingName", func(t *testing.T) {
+		code, body := createLabel(`{"color": "red"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"invalid or missing 'name' parameter"}`, body)
+	})
+	t.Run("Success", func(t *testing.T) {
+		code, _ := createLabel(`{"name": "urgent", "color": "red"}`)
+		assert.Equal(t, http.StatusCreated, code)
+		labels := listLabels()
+		assert.Equal(t, 1, len(labels))
+		assert.Equal(t, "urgent", labels[0].Name)
+		assert.Equal(t, "red", labels[0].Color)
+
+		request, _ := http.NewRequest("PATCH", "/labels/modify/"+labels[0].ID.Hex()+"/", bytes.NewBuffer([]byte(`{"name": "important"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		labels = listLabels()
+		assert.Equal(t, "important", labels[0].Name)
+		assert.Equal(t, "red", labels[0].Color)
+
+		request, _ = http.NewRequest("DELETE", "/labels/delete/"+labels[0].ID.Hex()+"/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, 0, len(listLabels()))
+	})
+	t.Run("ModifyNotFound", func(t *testing.T) {
+		request, _ := http.NewRequest("PATCH", "/labels/modify/"+primitive.NewObjectID().Hex()+"/", bytes.NewBuffer([]byte(`{"name": "important"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+	t.Run("TaskLabels", func(t *testing.T) {
+		code, body := createLabel(`{"name": "errands"}`)
+		assert.Equal(t, http.StatusCreated, code)
+		var labelResponse struct {
+			ID string `json:"id"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(body), &labelResponse))
+
+		notCompleted := false
+		title := "buy milk"
+		insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:      userID,
+			SourceID:    external.TASK_SOURCE_ID_GT_TASK,
+			Title:       &title,
+			IsCompleted: &notCompleted,
+		})
+		assert.NoError(t, err)
+		taskID := insertResult.InsertedID.(primitive.ObjectID)
+		otherTitle := "other task"
+		_, err = database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:      userID,
+			SourceID:    external.TASK_SOURCE_ID_GT_TASK,
+			Title:       &otherTitle,
+			IsCompleted: &notCompleted,
+		})
+		assert.NoError(t, err)
+
+		// labels of other users cannot be attached
+		request, _ := http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(`{"label_ids": ["`+primitive.NewObjectID().Hex()+`"]}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		request, _ = http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(`{"label_ids": ["`+labelResponse.ID+`"]}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		request, _ = http.NewRequest("GET", "/tasks/v4/?label_id="+labelResponse.ID, nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var tasks []TaskResultV4
+		assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&tasks))
+		assert.Equal(t, 1, len(tasks))
+		assert.Equal(t, taskID, tasks[0].ID)
+		assert.Equal(t, labelResponse.ID, tasks[0].LabelIDs[0].Hex())
+
+		// removing all labels
+		request, _ = http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(`{"label_ids": []}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		task, err := database.GetTask(api.DB, taskID, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, *task.LabelIDs)
+	})
+	t.Run("InvalidFilter", func(t *testing.T) {
+		request, _ := http.NewRequest("GET", "/tasks/v4/?label_id=uhoh", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestImportExternalLabels(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	existingLabelID := primitive.NewObjectID()
+	insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+		UserID:   userID,
+		SourceID: external.TASK_SOURCE_ID_LINEAR,
+		LabelIDs: &[]primitive.ObjectID{existingLabelID},
+	})
+	assert.NoError(t, err)
+	task := &database.Task{
+		ID:       insertResult.InsertedID.(primitive.ObjectID),
+		SourceID: external.TASK_SOURCE_ID_LINEAR,
+		ExternalLabels: &[]database.ExternalLabel{
+			{ExternalID: "bug-id", Name: "Bug", Color: "#ff0000"},
+		},
+	}
+
+	// importing twice reuses the same label
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, api.importExternalLabels(userID, &[]*database.Task{task}))
+	}
+	labels, err := database.GetLabels(api.DB, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*labels))
+	assert.Equal(t, "Bug", (*labels)[0].Name)
+	assert.Equal(t, external.TASK_SOURCE_ID_LINEAR, (*labels)[0].SourceID)
+
+	dbTask, err := database.GetTask(api.DB, task.ID, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []primitive.ObjectID{existingLabelID, (*labels)[0].ID}, *dbTask.LabelIDs)
+	bugLabelID := (*labels)[0].ID
+
+	t.Run("LabelReplaced", func(t *testing.T) {
+		task.ExternalLabels = &[]database.ExternalLabel{{ExternalID: "feature-id", Name: "Feature"}}
+		assert.NoError(t, api.importExternalLabels(userID, &[]*database.Task{task}))
+		labelIDs, err := database.GetExternalLabelIDs(api.DB, userID, external.TASK_SOURCE_ID_LINEAR)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(labelIDs))
+		featureLabelID := labelIDs[1]
+
+		// the label added by the user is kept
+		dbTask, err := database.GetTask(api.DB, task.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{existingLabelID, featureLabelID}, *dbTask.LabelIDs)
+		assert.NotContains(t, *dbTask.LabelIDs, bugLabelID)
+	})
+	t.Run("AllLabelsRemoved", func(t *testing.T) {
+		task.ExternalLabels = &[]database.ExternalLabel{}
+		assert.NoError(t, api.importExternalLabels(userID, &[]*database.Task{task}))
+		dbTask, err := database.GetTask(api.DB, task.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{existingLabelID}, *dbTask.LabelIDs)
+	})
+	t.Run("LabelsNotSupported", func(t *testing.T) {
+		task.ExternalLabels = nil
+		assert.NoError(t, api.importExternalLabels(userID, &[]*database.Task{task}))
+		dbTask, err := database.GetTask(api.DB, task.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{existingLabelID}, *dbTask.LabelIDs)
+	})
+}
+
+func TestMergeTasksV4LabelFilter(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	labelID := primitive.NewObjectID()
+	notCompleted := false
+	completed := true
+	blockerTaskID := primitive.NewObjectID()
+	labeledTaskID := primitive.NewObjectID()
+	subtaskID := primitive.NewObjectID()
+	activeTasks := []database.Task{
+		// the blocker and the dependent task don't have the label, but still count for the labeled task
+		{ID: blockerTaskID, IsCompleted: &notCompleted},
+		{ID: labeledTaskID, IsCompleted: &notCompleted, LabelIDs: &[]primitive.ObjectID{labelID}, BlockedByTaskIDs: []primitive.ObjectID{blockerTaskID}},
+		{ID: subtaskID, ParentTaskID: labeledTaskID, IsCompleted: &notCompleted},
+		{ID: primitive.NewObjectID(), IsCompleted: &notCompleted, BlockedByTaskIDs: []primitive.ObjectID{labeledTaskID}},
+	}
+	completedTasks := []database.Task{
+		{ID: primitive.NewObjectID(), ParentTaskID: labeledTaskID, IsCompleted: &completed},
+	}
+
+	taskResults, err := api.mergeTasksV4(api.DB, &activeTasks, &completedTasks, &[]database.Task{}, userID, &labelID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(taskResults))
+	assert.Equal(t, labeledTaskID, taskResults[0].ID)
+	assert.True(t, taskResults[0].IsBlocked)
+	assert.Equal(t, []primitive.ObjectID{activeTasks[3].ID}, taskResults[0].BlockingTaskIDs)
+	assert.Equal(t, 2, taskResults[0].SubtaskRollup.SubtaskCount)
+	assert.Equal(t, 1, taskResults[0].SubtaskRollup.CompletedSubtaskCount)
+	assert.Equal(t, subtaskID, taskResults[1].ID)
+	assert.Equal(t, completedTasks[0].ID, taskResults[2].ID)
+}
+
+func TestFilterTasksByLabel(t *testing.T) {
+	labelID := primitive.NewObjectID()
+	labeledTaskID := primitive.NewObjectID()
+	subtaskID := primitive.NewObjectID()
+	tasks := []database.Task{
+		{ID: labeledTaskID, LabelIDs: &[]primitive.ObjectID{labelID}},
+		{ID: subtaskID, ParentTaskID: labeledTaskID},
+		{ID: primitive.NewObjectID(), LabelIDs: &[]primitive.ObjectID{primitive.NewObjectID()}},
+		{ID: primitive.NewObjectID()},
+		{ID: primitive.NewObjectID(), ParentTaskID: subtaskID},
+	}
+	filteredTasks := filterTasksByLabel(tasks, labelID)
+	assert.Equal(t, 3, len(filteredTasks))
+	assert.Equal(t, tasks[0].ID, filteredTasks[0].ID)
+	assert.Equal(t, tasks[1].ID, filteredTasks[1].ID)
+	assert.Equal(t, tasks[4].ID, filteredTasks[2].ID)
+}
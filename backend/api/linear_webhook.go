@@ -3,10 +3,12 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io"
-	"strings"
 	"time"
 
 	"github.com/GeneralTask/task-manager/backend/constants"
@@ -25,16 +27,25 @@ const CommentType = "Comment"
 const CreateAction = "create"
 const UpdateAction = "update"
 const RemoveAction = "remove"
-const ValidLinearIP1 = "35.231.147.226"
-const ValidLinearIP2 = "35.243.134.228"
+const LinearSignatureHeader = "Linear-Signature"
+
+// deliveries outside of this window are rejected to prevent replays
+const LinearWebhookTimestampTolerance = time.Minute
+
+// how long the previous secret is still accepted after a rotation, to allow for the secret to be updated in Linear
+const LinearWebhookSecretGracePeriod = time.Hour
+
+var errLinearWebhookUserNotVerified = errors.New("linear webhook was not signed with the secret of the task owner")
 
 type LinearWebhookPayload struct {
-	Action      string           `json:"action"`
-	Type        string           `json:"type"`
-	CreatedAt   string           `json:"createdAt"`
-	RawData     *json.RawMessage `json:"data"`
-	UpdatedFrom *json.RawMessage `json:"updatedFrom"`
-	Url         string           `json:"url"`
+	Action           string           `json:"action"`
+	Type             string           `json:"type"`
+	CreatedAt        string           `json:"createdAt"`
+	RawData          *json.RawMessage `json:"data"`
+	UpdatedFrom      *json.RawMessage `json:"updatedFrom"`
+	Url              string           `json:"url"`
+	OrganizationID   string           `json:"organizationId"`
+	WebhookTimestamp int64            `json:"webhookTimestamp"`
 }
 
 type LinearIssuePayload struct {
@@ -48,6 +59,14 @@ type LinearIssuePayload struct {
 	DueDate     string      `json:"dueDate,omitempty"`
 	CreatedAt   string      `json:"createdAt,omitempty"`
 	UpdatedAt   string      `json:"updatedAt,omitempty"`
+	// a pointer so that the payload can still be compared, and so a missing list isn't taken as every label being removed
+	Labels *[]LinearLabelPayload `json:"labels,omitempty"`
+}
+
+type LinearLabelPayload struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
 }
 
 type LinearState struct {
@@ -71,13 +90,6 @@ type LinearCommentPayload struct {
 }
 
 func (api *API) LinearWebhook(c *gin.Context) {
-	requestIP := c.Request.Header.Get("X-Forwarded-For")
-	if !strings.Contains(requestIP, ValidLinearIP1) && !strings.Contains(requestIP, ValidLinearIP2) {
-		api.Logger.Error().Msg("incorrect IP for linear webhook: " + requestIP)
-		c.JSON(400, gin.H{"detail": "invalid request format"})
-		return
-	}
-
 	// make request body readable
 	body, err := io.ReadAll(c.Request.Body)
 	if err != nil {
@@ -97,6 +109,12 @@ func (api *API) LinearWebhook(c *gin.Context) {
 		c.JSON(400, gin.H{"detail": "unable to process linear webhook payload"})
 		return
 	}
+	verifiedUserIDs, err := api.verifyLinearWebhookSignature(c.GetHeader(LinearSignatureHeader), body, webhookPayload, time.Now())
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("invalid signature for linear webhook")
+		c.JSON(401, gin.H{"detail": "invalid signature"})
+		return
+	}
 
 	switch webhookPayload.Type {
 	case IssueType:
@@ -105,8 +123,11 @@ func (api *API) LinearWebhook(c *gin.Context) {
 			c.JSON(400, gin.H{"detail": "unable to unmarshal linear issue object"})
 			return
 		}
-		err = api.processLinearIssueWebhook(c, webhookPayload, issuePayload)
-		if err != nil {
+		err = api.processLinearIssueWebhook(c, webhookPayload, issuePayload, verifiedUserIDs)
+		if err == errLinearWebhookUserNotVerified {
+			c.JSON(401, gin.H{"detail": "invalid signature"})
+			return
+		} else if err != nil {
 			c.JSON(400, gin.H{"detail": "unable to process linear issue webhook"})
 			return
 		}
@@ -116,8 +137,11 @@ func (api *API) LinearWebhook(c *gin.Context) {
 			c.JSON(400, gin.H{"detail": "unable to unmarshal linear issue object"})
 			return
 		}
-		err = api.processLinearCommentWebhook(c, webhookPayload, commentPayload)
-		if err != nil {
+		err = api.processLinearCommentWebhook(c, webhookPayload, commentPayload, verifiedUserIDs)
+		if err == errLinearWebhookUserNotVerified {
+			c.JSON(401, gin.H{"detail": "invalid signature"})
+			return
+		} else if err != nil {
 			c.JSON(400, gin.H{"detail": "unable to process linear comment webhook"})
 			return
 		}
@@ -129,17 +153,71 @@ func (api *API) LinearWebhook(c *gin.Context) {
 	c.JSON(200, gin.H{})
 }
 
-func (api *API) processLinearIssueWebhook(c *gin.Context, webhookPayload LinearWebhookPayload, issuePayload LinearIssuePayload) error {
+// returns the users whose own secret signed the delivery, as only their tasks may be changed by it
+func (api *API) verifyLinearWebhookSignature(signature string, body []byte, webhookPayload LinearWebhookPayload, now time.Time) ([]primitive.ObjectID, error) {
+	webhookSecrets, err := database.GetLinearWebhookSecrets(api.DB, webhookPayload.OrganizationID)
+	if err != nil || len(*webhookSecrets) == 0 {
+		return nil, errors.New("no webhook secret registered for linear organization")
+	}
+	verifiedUserIDs := []primitive.ObjectID{}
+	for _, webhookSecret := range *webhookSecrets {
+		if isValidLinearSignature(signature, body, webhookSecret.Secret) {
+			verifiedUserIDs = append(verifiedUserIDs, webhookSecret.UserID)
+			continue
+		}
+		rotatedAt := webhookSecret.RotatedAt.Time()
+		if webhookSecret.PreviousSecret != "" && !now.After(rotatedAt.Add(LinearWebhookSecretGracePeriod)) && isValidLinearSignature(signature, body, webhookSecret.PreviousSecret) {
+			verifiedUserIDs = append(verifiedUserIDs, webhookSecret.UserID)
+		}
+	}
+	if len(verifiedUserIDs) == 0 {
+		return nil, errors.New("linear webhook signature mismatch")
+	}
+
+	// the timestamp is part of the signed body, so it can only be checked once the signature is verified
+	timestamp := time.UnixMilli(webhookPayload.WebhookTimestamp)
+	if timestamp.Before(now.Add(-LinearWebhookTimestampTolerance)) || timestamp.After(now.Add(LinearWebhookTimestampTolerance)) {
+		return nil, errors.New("linear webhook timestamp outside of allowed window")
+	}
+	return verifiedUserIDs, nil
+}
+
+func isLinearWebhookUserVerified(userID primitive.ObjectID, verifiedUserIDs []primitive.ObjectID) bool {
+	for _, verifiedUserID := range verifiedUserIDs {
+		if verifiedUserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidLinearSignature(signature string, body []byte, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(signatureBytes, mac.Sum(nil))
+}
+
+func (api *API) processLinearIssueWebhook(c *gin.Context, webhookPayload LinearWebhookPayload, issuePayload LinearIssuePayload, verifiedUserIDs []primitive.ObjectID) error {
 	token, err := database.GetExternalTokenByExternalID(api.DB, issuePayload.AssigneeID, external.TASK_SERVICE_ID_LINEAR, false)
 	if err != nil {
 		// if the owner of the task is not found, we must check if the task exists
 		// if the task does exist, we must delete as we do not want it to show on the user's list
-		api.removeTaskOwnerIfExists(issuePayload)
+		api.removeTaskOwnerIfExists(issuePayload, verifiedUserIDs)
 		return err
 	}
 
 	userID := token.UserID
 	accountID := token.AccountID
+	if !isLinearWebhookUserVerified(userID, verifiedUserIDs) {
+		return errLinearWebhookUserNotVerified
+	}
 
 	switch webhookPayload.Action {
 	case CreateAction:
@@ -165,7 +243,7 @@ func (api *API) createOrModifyIssueFromPayload(userID primitive.ObjectID, accoun
 	}
 
 	task.AllStatuses = statuses
-	task.CompletedStatus = getCompletedLinearStatus(task.AllStatuses)
+	task.CompletedStatus = external.GetCompletedLinearStatus(task.AllStatuses)
 
 	if task.Status.Type == external.LinearCompletedType || task.Status.Type == external.LinearCanceledType {
 		isCompleted := true
@@ -182,7 +260,7 @@ func (api *API) createOrModifyIssueFromPayload(userID primitive.ObjectID, accoun
 		task.IDTaskSection = dbTask.IDTaskSection
 	}
 
-	_, err = database.UpdateOrCreateTask(
+	dbTask, err = database.UpdateOrCreateTaskWithActor(
 		api.DB,
 		userID,
 		task.IDExternal,
@@ -190,11 +268,17 @@ func (api *API) createOrModifyIssueFromPayload(userID primitive.ObjectID, accoun
 		nil,
 		task,
 		nil,
+		database.TaskHistoryActorWebhook,
 	)
 	if err != nil {
 		logger.Error().Err(err).Msg("could not create or update task")
 		return err
 	}
+	task.ID = dbTask.ID
+	err = api.importExternalLabels(userID, &[]*database.Task{task})
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to import external labels")
+	}
 	return nil
 }
 
@@ -213,7 +297,7 @@ func (api *API) removeIssueFromPayload(userID primitive.ObjectID, issuePayload L
 	return api.UpdateTaskInDBWithError(task, userID, &updateTask)
 }
 
-func (api *API) processLinearCommentWebhook(c *gin.Context, webhookPayload LinearWebhookPayload, commentPayload LinearCommentPayload) error {
+func (api *API) processLinearCommentWebhook(c *gin.Context, webhookPayload LinearWebhookPayload, commentPayload LinearCommentPayload, verifiedUserIDs []primitive.ObjectID) error {
 	var err error
 	logger := logging.GetSentryLogger()
 	task, err := database.GetTaskByExternalIDWithoutUser(api.DB, commentPayload.IssueID, false)
@@ -229,6 +313,9 @@ func (api *API) processLinearCommentWebhook(c *gin.Context, webhookPayload Linea
 	userIDExternal := commentPayload.UserID
 	userID := token.UserID
 	accountID := token.AccountID
+	if !isLinearWebhookUserVerified(userID, verifiedUserIDs) {
+		return errLinearWebhookUserNotVerified
+	}
 
 	switch webhookPayload.Action {
 	case CreateAction:
@@ -363,18 +450,21 @@ func populateLinearTask(userID primitive.ObjectID, accountID string, webhookPayl
 	priorityFloat := (float64)(issuePayload.Priority)
 
 	task := &database.Task{
-		UserID:             userID,
-		IDExternal:         issuePayload.ID,
-		Deeplink:           webhookPayload.Url,
-		IDTaskSection:      constants.IDTaskSectionDefault,
-		SourceID:           external.TASK_SOURCE_ID_LINEAR,
-		Title:              &issuePayload.Title,
-		Body:               &issuePayload.Description,
-		SourceAccountID:    accountID,
-		CreatedAtExternal:  primitive.NewDateTimeFromTime(issueCreatedAt),
-		IsCompleted:        &_false,
-		IsDeleted:          &_false,
-		PriorityNormalized: &priorityFloat,
+		UserID:                userID,
+		IDExternal:            issuePayload.ID,
+		Deeplink:              webhookPayload.Url,
+		IDTaskSection:         constants.IDTaskSectionDefault,
+		SourceID:              external.TASK_SOURCE_ID_LINEAR,
+		Title:                 &issuePayload.Title,
+		Body:                  &issuePayload.Description,
+		SearchBody:            &issuePayload.Description,
+		SourceAccountID:       accountID,
+		CreatedAtExternal:     primitive.NewDateTimeFromTime(issueCreatedAt),
+		IsCompleted:           &_false,
+		IsDeleted:             &_false,
+		PriorityNormalized:    &priorityFloat,
+		ExternalPriority:      external.GetLinearPriorityFromNormalized(priorityFloat),
+		AllExternalPriorities: external.GetLinearPriorities(),
 		Status: &database.ExternalTaskStatus{
 			ExternalID: issuePayload.State.ID,
 			State:      issuePayload.State.Name,
@@ -382,6 +472,14 @@ func populateLinearTask(userID primitive.ObjectID, accountID string, webhookPayl
 		},
 	}
 
+	if issuePayload.Labels != nil {
+		externalLabels := []database.ExternalLabel{}
+		for _, label := range *issuePayload.Labels {
+			externalLabels = append(externalLabels, database.ExternalLabel{ExternalID: label.ID, Name: label.Name, Color: label.Color})
+		}
+		task.ExternalLabels = &externalLabels
+	}
+
 	if issuePayload.DueDate != "" {
 		dueDate, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, string(issuePayload.DueDate))
 		primitiveDueDate := primitive.NewDateTimeFromTime(dueDate)
@@ -394,19 +492,9 @@ func populateLinearTask(userID primitive.ObjectID, accountID string, webhookPayl
 	return task
 }
 
-// TODO increase test coverage of the below code
-func getCompletedLinearStatus(teamStatuses []*database.ExternalTaskStatus) *database.ExternalTaskStatus {
-	for _, status := range teamStatuses {
-		if status.Type == external.LinearCompletedType {
-			return status
-		}
-	}
-	return nil
-}
-
-func (api *API) removeTaskOwnerIfExists(issuePayload LinearIssuePayload) {
+func (api *API) removeTaskOwnerIfExists(issuePayload LinearIssuePayload, verifiedUserIDs []primitive.ObjectID) {
 	task, err := database.GetTaskByExternalIDWithoutUser(api.DB, issuePayload.ID, false)
-	if err != nil {
+	if err != nil || !isLinearWebhookUserVerified(task.UserID, verifiedUserIDs) {
 		// don't log this error because could be a task that shouldn't exist in GT
 		return
 	}
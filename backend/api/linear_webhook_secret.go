@@ -0,0 +1,77 @@
+package api
+
+import (
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/gin-gonic/gin"
+)
+
+type LinearWebhookSecretParams struct {
+	Secret    string `json:"secret"`
+	AccountID string `json:"account_id"`
+}
+
+// LinearWebhookSecretRotate godoc
+// @Summary      Registers or rotates the signing secret of the user's own webhook in their Linear workspace
+// @Description  Deliveries signed with it can only change the user's tasks. The previous secret remains valid for a grace period so the new secret can be updated in Linear
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      LinearWebhookSecretParams  true  "webhook signing secret from Linear"
+// @Success      200 {object} string "success"
+// @Failure      400 {object} string "invalid params"
+// @Failure      500 {object} string "internal server error"
+// @Router       /linear/webhook_secret/ [post]
+func (api *API) LinearWebhookSecretRotate(c *gin.Context) {
+	var params LinearWebhookSecretParams
+	err := c.BindJSON(&params)
+	if err != nil || params.Secret == "" {
+		c.JSON(400, gin.H{"detail": "invalid or missing 'secret' parameter."})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	tokens, err := database.GetExternalTokens(api.DB, userID, external.TASK_SERVICE_ID_LINEAR)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	if len(*tokens) == 0 {
+		c.JSON(400, gin.H{"detail": "no linked linear account"})
+		return
+	}
+	// users can link several Linear workspaces, and the secret belongs to the webhook of only one of them
+	isLinkedAccount := false
+	for _, token := range *tokens {
+		if token.AccountID == params.AccountID {
+			isLinkedAccount = true
+			break
+		}
+	}
+	if !isLinkedAccount {
+		c.JSON(400, gin.H{"detail": "invalid or missing 'account_id' parameter."})
+		return
+	}
+
+	client, err := external.GetLinearClient(api.ExternalConfig.Linear.ConfigValues.UserInfoURL, api.DB, userID, params.AccountID)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("unable to create linear client")
+		Handle500(c)
+		return
+	}
+	organizationID, err := external.GetLinearOrganizationID(client)
+	if err != nil || organizationID == "" {
+		api.Logger.Error().Err(err).Msg("unable to fetch linear organization")
+		Handle500(c)
+		return
+	}
+
+	err = database.RotateLinearWebhookSecret(api.DB, userID, organizationID, params.Secret, time.Now())
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLinearWebhookSecretRotate(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	organizationID := primitive.NewObjectID().Hex()
+	organizationServer := testutils.GetMockAPIServer(t, 200, `{"data": {"organization": {"id": "`+organizationID+`"}}}`)
+	defer organizationServer.Close()
+	api.ExternalConfig.Linear.ConfigValues.UserInfoURL = &organizationServer.URL
+	router := GetRouter(api)
+
+	UnauthorizedTest(t, "POST", "/linear/webhook_secret/", nil)
+	t.Run("MissingSecret", func(t *testing.T) {
+		authToken := login("linear_webhook_secret_missing@generaltask.com", "")
+		request, _ := http.NewRequest("POST", "/linear/webhook_secret/", bytes.NewBuffer([]byte(`{"secret": ""}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"detail\":\"invalid or missing 'secret' parameter.\"}", string(body))
+	})
+	t.Run("NoLinkedAccount", func(t *testing.T) {
+		authToken := login("linear_webhook_secret_unlinked@generaltask.com", "")
+		request, _ := http.NewRequest("POST", "/linear/webhook_secret/", bytes.NewBuffer([]byte(`{"secret": "example_secret", "account_id": "example account ID"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"detail\":\"no linked linear account\"}", string(body))
+	})
+	t.Run("InvalidAccountID", func(t *testing.T) {
+		authToken := login("linear_webhook_secret_invalid_account@generaltask.com", "")
+		userID := getUserIDFromAuthToken(t, api.DB, authToken)
+		_, err := database.GetExternalTokenCollection(api.DB).InsertOne(context.Background(), database.ExternalAPIToken{
+			UserID:    userID,
+			ServiceID: external.TASK_SERVICE_ID_LINEAR,
+			AccountID: "linked account ID",
+		})
+		assert.NoError(t, err)
+
+		for _, payload := range []string{
+			`{"secret": "example_secret"}`,
+			`{"secret": "example_secret", "account_id": "someone else's account ID"}`,
+		} {
+			request, _ := http.NewRequest("POST", "/linear/webhook_secret/", bytes.NewBuffer([]byte(payload)))
+			request.Header.Add("Authorization", "Bearer "+authToken)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+			body, err := io.ReadAll(recorder.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, "{\"detail\":\"invalid or missing 'account_id' parameter.\"}", string(body))
+		}
+	})
+	t.Run("Success", func(t *testing.T) {
+		authToken := login("linear_webhook_secret_success@generaltask.com", "")
+		userID := getUserIDFromAuthToken(t, api.DB, authToken)
+		_, err := database.GetExternalTokenCollection(api.DB).InsertOne(context.Background(), database.ExternalAPIToken{
+			UserID:    userID,
+			ServiceID: external.TASK_SERVICE_ID_LINEAR,
+			AccountID: "example account ID",
+		})
+		assert.NoError(t, err)
+
+		for _, secret := range []string{"old_secret", "new_secret"} {
+			request, _ := http.NewRequest("POST", "/linear/webhook_secret/", bytes.NewBuffer([]byte(`{"secret": "`+secret+`", "account_id": "example account ID"}`)))
+			request.Header.Add("Authorization", "Bearer "+authToken)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		}
+
+		webhookSecret, err := database.GetLinearWebhookSecret(api.DB, userID, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, webhookSecret.UserID)
+		assert.Equal(t, "new_secret", webhookSecret.Secret)
+		assert.Equal(t, "old_secret", webhookSecret.PreviousSecret)
+	})
+	t.Run("OtherUserInOrganization", func(t *testing.T) {
+		authToken := login("linear_webhook_secret_other@generaltask.com", "")
+		userID := getUserIDFromAuthToken(t, api.DB, authToken)
+		_, err := database.GetExternalTokenCollection(api.DB).InsertOne(context.Background(), database.ExternalAPIToken{
+			UserID:    userID,
+			ServiceID: external.TASK_SERVICE_ID_LINEAR,
+			AccountID: "other account ID",
+		})
+		assert.NoError(t, err)
+
+		request, _ := http.NewRequest("POST", "/linear/webhook_secret/", bytes.NewBuffer([]byte(`{"secret": "other_secret", "account_id": "other account ID"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		// the secret registered by the first user is not replaced
+		webhookSecrets, err := database.GetLinearWebhookSecrets(api.DB, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(*webhookSecrets))
+		webhookSecret, err := database.GetLinearWebhookSecret(api.DB, userID, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, "other_secret", webhookSecret.Secret)
+		assert.Equal(t, "", webhookSecret.PreviousSecret)
+	})
+}
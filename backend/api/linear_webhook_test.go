@@ -3,10 +3,15 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/GeneralTask/task-manager/backend/database"
 	"github.com/GeneralTask/task-manager/backend/external"
@@ -17,23 +22,86 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+const linearWebhookTestOrganizationID = "572f6728-59c0-4844-96b1-34b5e77b704e"
+const linearWebhookTestSecret = "example_secret"
+const linearWebhookTestPayload = `{"action":"invalid","createdAt":"2022-10-05T18:36:25.922Z","data":{"id":"e17bd25c-fa0b-49a0-8658-82fbec96427f"},"type":"InvalidType","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`
+
+// stamps the payload with the current time before signing it, as Linear does for every delivery
+func signLinearWebhookRequest(t *testing.T, request *http.Request, secret string) {
+	body, err := io.ReadAll(request.Body)
+	assert.NoError(t, err)
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) == nil {
+		if _, exists := payload["webhookTimestamp"]; !exists {
+			payload["webhookTimestamp"] = time.Now().UnixMilli()
+		}
+		body, err = json.Marshal(payload)
+		assert.NoError(t, err)
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	request.Body = io.NopCloser(bytes.NewBuffer(body))
+	request.ContentLength = int64(len(body))
+	request.Header.Set(LinearSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func registerLinearWebhookTestSecret(t *testing.T, db *mongo.Database, userID primitive.ObjectID) {
+	err := database.RotateLinearWebhookSecret(db, userID, linearWebhookTestOrganizationID, linearWebhookTestSecret, time.Now())
+	assert.NoError(t, err)
+}
+
 func TestWebhookHandler(t *testing.T) {
 	api, dbCleanup := GetAPIWithDBCleanup()
 	defer dbCleanup()
+	registerLinearWebhookTestSecret(t, api.DB, primitive.NewObjectID())
 	router := GetRouter(api)
 
-	t.Run("InvalidIP", func(t *testing.T) {
+	t.Run("MissingSignature", func(t *testing.T) {
 		request, _ := http.NewRequest(
 			"POST",
 			"/linear/webhook/",
-			nil)
+			bytes.NewBuffer([]byte(linearWebhookTestPayload)),
+		)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
-		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
 
 		body, err := io.ReadAll(recorder.Body)
 		assert.NoError(t, err)
-		assert.Equal(t, "{\"detail\":\"invalid request format\"}", string(body))
+		assert.Equal(t, "{\"detail\":\"invalid signature\"}", string(body))
+	})
+	t.Run("InvalidSignature", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/linear/webhook/",
+			bytes.NewBuffer([]byte(linearWebhookTestPayload)),
+		)
+		signLinearWebhookRequest(t, request, "wrong_secret")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+	t.Run("UnknownOrganization", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/linear/webhook/",
+			bytes.NewBuffer([]byte(`{"action":"invalid","type":"InvalidType","organizationId":"unknown"}`)),
+		)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+	t.Run("ExpiredTimestamp", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/linear/webhook/",
+			bytes.NewBuffer([]byte(`{"action":"invalid","type":"InvalidType","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e","webhookTimestamp":1665000000000}`)),
+		)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
 	})
 
 	t.Run("InvalidFormat", func(t *testing.T) {
@@ -42,7 +110,7 @@ func TestWebhookHandler(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`"uhoh"`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -57,7 +125,7 @@ func TestWebhookHandler(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"remove","createdAt":"2022-10-05T18:36:25.922Z","data":{"id":"e17bd25c-fa0b-49a0-8658-82fbec96427f","createdAt":"2022-10-05T18:12:23.127Z","updatedAt":"2022-10-05T18:18:54.049Z","body":"here we","issueId":"7ca5cb7c-9038-4f72-b880-f4209e1d1466","userId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","editedAt":"2022-10-05T18:18:54.049Z","issue":{"id":"7ca5cb7c-9038-4f72-b880-f4209e1d1466","title":"New issue for modification purposes"},"user":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"}},"type":"InvalidType","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -87,6 +155,7 @@ func TestProcessComments(t *testing.T) {
 	api, dbCleanup := GetAPIWithDBCleanup()
 	defer dbCleanup()
 	api.ExternalConfig.Linear.ConfigValues.UserInfoURL = &userInfoServerSuccess.URL
+	registerLinearWebhookTestSecret(t, api.DB, userID)
 	router := GetRouter(api)
 
 	taskCollection := database.GetTaskCollection(db)
@@ -110,7 +179,7 @@ func TestProcessComments(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"invalid","createdAt":"2022-10-05T18:36:25.922Z","data":{"id":"e17bd25c-fa0b-49a0-8658-82fbec96427f","createdAt":"2022-10-05T18:12:23.127Z","updatedAt":"2022-10-05T18:18:54.049Z","body":"here we","issueId":"7ca5cb7c-9038-4f72-b880-f4209e1d1466","userId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","editedAt":"2022-10-05T18:18:54.049Z","issue":{"id":"7ca5cb7c-9038-4f72-b880-f4209e1d1466","title":"New issue for modification purposes"},"user":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"}},"type":"Comment","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -125,7 +194,7 @@ func TestProcessComments(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"invalid","createdAt":"2022-10-05T18:36:25.922Z","data":{"id":"e17bd25c-fa0b-49a0-8658-82fbec96427f","createdAt":"2022-10-05T18:12:23.127Z","updatedAt":"2022-10-05T18:18:54.049Z","body":"here we","issueId":"externalID","userId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","editedAt":"2022-10-05T18:18:54.049Z","issue":{"id":"7ca5cb7c-9038-4f72-b880-f4209e1d1466","title":"New issue for modification purposes"},"user":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"}},"type":"Comment","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -140,7 +209,7 @@ func TestProcessComments(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"create","createdAt":"2022-10-05T19:00:34.481Z","data":{"id":"ce5fc6ad-14f4-4b52-8a34-613b3ee5c9f1","createdAt":"2022-10-05T19:00:34.481Z","updatedAt":"2022-10-05T19:00:34.481Z","body":"here's a new one!","issueId":"externalID","userId":"userIDExternal","issue":{"id":"externalID","title":"New issue for modification purposes"},"user":{"id":"userIDExternal","name":"Julian Christensen"}},"url":"https://linear.app/general-task/issue/BACK-317#comment-ce5fc6ad","type":"Comment","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -155,7 +224,7 @@ func TestProcessComments(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"create","createdAt":"2022-10-05T19:00:34.481Z","data":{"id":"ce5fc6ad-14f4-4b52-8a34-613b3ee5c9f1","createdAt":"2022-10-05T19:00:34.481Z","updatedAt":"2022-10-05T19:00:34.481Z","body":"here's a new one!","issueId":"externalID","userId":"userIDExternal","issue":{"id":"externalID","title":"New issue for modification purposes"},"user":{"id":"userIDExternal","name":"Julian Christensen"}},"url":"https://linear.app/general-task/issue/BACK-317#comment-ce5fc6ad","type":"Comment","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -170,7 +239,7 @@ func TestProcessComments(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"create","createdAt":"2022-10-05T19:00:34.481Z","data":{"id":"new comment id","createdAt":"2022-10-05T19:00:34.481Z","updatedAt":"2022-10-05T19:00:34.481Z","body":"here's a w one!","issueId":"externalID","userId":"userIDExternal","issue":{"id":"externalID","title":"New issue for modification purposes"},"user":{"id":"userIDExternal","name":"Julian Christensen"}},"url":"https://linear.app/general-task/issue/BACK-317#comment-ce5fc6ad","type":"Comment","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -185,7 +254,7 @@ func TestProcessComments(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"update","createdAt":"2022-10-05T19:00:34.481Z","data":{"id":"ce5fc6ad-14f4-4b52-8a34-613b3ee5c9f1","createdAt":"2022-10-05T19:00:34.481Z","updatedAt":"2022-10-05T19:00:34.481Z","body":"modified text","issueId":"externalID","userId":"userIDExternal","issue":{"id":"externalID","title":"New issue for modification purposes"},"user":{"id":"userIDExternal","name":"Julian Christensen"}},"url":"https://linear.app/general-task/issue/BACK-317#comment-ce5fc6ad","type":"Comment","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -201,7 +270,7 @@ func TestProcessComments(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"remove","createdAt":"2022-10-05T19:00:34.481Z","data":{"id":"ce5fc6ad-14f4-4b52-8a34-613b3ee5c9f1","createdAt":"2022-10-05T19:00:34.481Z","updatedAt":"2022-10-05T19:00:34.481Z","body":"here's a new one!","issueId":"externalID","userId":"userIDExternal","issue":{"id":"externalID","title":"New issue for modification purposes"},"user":{"id":"userIDExternal","name":"Julian Christensen"}},"url":"https://linear.app/general-task/issue/BACK-317#comment-ce5fc6ad","type":"Comment","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -254,6 +323,7 @@ func TestProcessIssue(t *testing.T) {
 	api, dbCleanup := GetAPIWithDBCleanup()
 	defer dbCleanup()
 	api.ExternalConfig.Linear.ConfigValues.StatusFetchURL = &linearStatusServerSuccess.URL
+	registerLinearWebhookTestSecret(t, api.DB, userID)
 	router := GetRouter(api)
 
 	externalAPICollection := database.GetExternalTokenCollection(db)
@@ -270,7 +340,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"invalid","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -285,7 +355,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"create","createdAt":"2022-10-06T20:16:30.266Z","data":{"BABABABA":123},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -294,13 +364,30 @@ func TestProcessIssue(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, "{\"detail\":\"unable to unmarshal linear issue object\"}", string(body))
 	})
+	t.Run("OtherUserSecret", func(t *testing.T) {
+		// another user in the organization can't change this user's tasks with their own secret
+		err := database.RotateLinearWebhookSecret(api.DB, primitive.NewObjectID(), linearWebhookTestOrganizationID, "other_secret", time.Now())
+		assert.NoError(t, err)
+		request, _ := http.NewRequest(
+			"POST",
+			"/linear/webhook/",
+			bytes.NewBuffer([]byte(`{"action":"create","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
+		)
+		signLinearWebhookRequest(t, request, "other_secret")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+		_, err = database.GetTaskByExternalIDWithoutUser(db, "aaad850c-8df6-482f-90b0-82725bd54155", false)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
 	t.Run("CreateIssueSuccess", func(t *testing.T) {
 		request, _ := http.NewRequest(
 			"POST",
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"create","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -317,7 +404,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"update","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"invalid","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -335,7 +422,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"create","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -357,7 +444,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"update","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there 2.0!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder = httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -374,7 +461,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"update","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there 2.0!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069422","name":"Done","color":"#e2e2e2","type":"completed"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -392,7 +479,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"remove","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"oopsie","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -407,7 +494,7 @@ func TestProcessIssue(t *testing.T) {
 			"/linear/webhook/",
 			bytes.NewBuffer([]byte(`{"action":"remove","createdAt":"2022-10-06T20:16:30.266Z","data":{"id":"aaad850c-8df6-482f-90b0-82725bd54155","createdAt":"2022-10-06T20:16:30.266Z","updatedAt":"2022-10-06T20:16:30.266Z","number":326,"title":"Hello there!","description":"As title!","priority":0,"boardOrder":0,"sortOrder":-130039,"teamId":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","cycleId":"4361a9d5-d18f-479e-a942-bcb4bac207ac","previousIdentifiers":[],"creatorId":"c4665594-0dc5-4913-8102-cbfa03ec8a69","assigneeId":"userIDExternal","stateId":"94594b14-e584-4635-bcf6-7e4c4a401f63","priorityLabel":"No priority","subscriberIds":["c4665594-0dc5-4913-8102-cbfa03ec8a69"],"labelIds":[],"assignee":{"id":"c4665594-0dc5-4913-8102-cbfa03ec8a69","name":"Julian Christensen"},"cycle":{"id":"4361a9d5-d18f-479e-a942-bcb4bac207ac","number":25,"startsAt":"2022-10-03T07:00:00.000Z","endsAt":"2022-10-10T07:00:00.000Z"},"state":{"id":"6942069420","name":"Todo","color":"#e2e2e2","type":"unstarted"},"team":{"id":"83abfaf9-ded6-4a55-93e9-81a181a1ac0a","name":"Backend","key":"BACK"}},"url":"https://linear.app/general-task/issue/BACK-326/hello-there","type":"Issue","organizationId":"572f6728-59c0-4844-96b1-34b5e77b704e"}`)),
 		)
-		request.Header.Add("X-Forwarded-For", ValidLinearIP1)
+		signLinearWebhookRequest(t, request, linearWebhookTestSecret)
 		recorder := httptest.NewRecorder()
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusOK, recorder.Code)
@@ -417,3 +504,76 @@ func TestProcessIssue(t *testing.T) {
 		assert.Equal(t, true, *task.IsDeleted)
 	})
 }
+
+func TestPopulateLinearTaskLabels(t *testing.T) {
+	userID := primitive.NewObjectID()
+	task := populateLinearTask(userID, "example account ID", LinearWebhookPayload{}, LinearIssuePayload{ID: "issue-id"})
+	assert.Nil(t, task.ExternalLabels)
+
+	task = populateLinearTask(userID, "example account ID", LinearWebhookPayload{}, LinearIssuePayload{
+		ID:     "issue-id",
+		Labels: &[]LinearLabelPayload{{ID: "bug-id", Name: "Bug", Color: "#ff0000"}},
+	})
+	assert.Equal(t, &[]database.ExternalLabel{{ExternalID: "bug-id", Name: "Bug", Color: "#ff0000"}}, task.ExternalLabels)
+}
+
+func TestVerifyLinearWebhookSignature(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	organizationID := primitive.NewObjectID().Hex()
+	now := time.Now()
+	body := []byte(`{"type":"Issue"}`)
+	payload := LinearWebhookPayload{OrganizationID: organizationID, WebhookTimestamp: now.UnixMilli()}
+	getSignature := func(secret string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	t.Run("NoSecretRegistered", func(t *testing.T) {
+		_, err := api.verifyLinearWebhookSignature(getSignature("old_secret"), body, payload, now)
+		assert.Error(t, err)
+	})
+	userID := primitive.NewObjectID()
+	otherUserID := primitive.NewObjectID()
+	err := database.RotateLinearWebhookSecret(api.DB, userID, organizationID, "old_secret", now)
+	assert.NoError(t, err)
+	err = database.RotateLinearWebhookSecret(api.DB, userID, organizationID, "new_secret", now)
+	assert.NoError(t, err)
+	err = database.RotateLinearWebhookSecret(api.DB, otherUserID, organizationID, "other_secret", now)
+	assert.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		verifiedUserIDs, err := api.verifyLinearWebhookSignature(getSignature("new_secret"), body, payload, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{userID}, verifiedUserIDs)
+		verifiedUserIDs, err = api.verifyLinearWebhookSignature(getSignature("other_secret"), body, payload, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{otherUserID}, verifiedUserIDs)
+	})
+	t.Run("PreviousSecretWithinGracePeriod", func(t *testing.T) {
+		verifiedUserIDs, err := api.verifyLinearWebhookSignature(getSignature("old_secret"), body, payload, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{userID}, verifiedUserIDs)
+	})
+	t.Run("PreviousSecretAfterGracePeriod", func(t *testing.T) {
+		later := now.Add(LinearWebhookSecretGracePeriod + time.Minute)
+		laterPayload := LinearWebhookPayload{OrganizationID: organizationID, WebhookTimestamp: later.UnixMilli()}
+		_, err := api.verifyLinearWebhookSignature(getSignature("old_secret"), body, laterPayload, later)
+		assert.Error(t, err)
+		_, err = api.verifyLinearWebhookSignature(getSignature("new_secret"), body, laterPayload, later)
+		assert.NoError(t, err)
+	})
+	t.Run("InvalidSignature", func(t *testing.T) {
+		_, err := api.verifyLinearWebhookSignature("not hex", body, payload, now)
+		assert.Error(t, err)
+		_, err = api.verifyLinearWebhookSignature(getSignature("wrong_secret"), body, payload, now)
+		assert.Error(t, err)
+	})
+	t.Run("TimestampOutsideWindow", func(t *testing.T) {
+		_, err := api.verifyLinearWebhookSignature(getSignature("new_secret"), body, payload, now.Add(2*LinearWebhookTimestampTolerance))
+		assert.Error(t, err)
+		_, err = api.verifyLinearWebhookSignature(getSignature("new_secret"), body, payload, now.Add(-2*LinearWebhookTimestampTolerance))
+		assert.Error(t, err)
+	})
+}
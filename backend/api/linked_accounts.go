@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type SupportedAccountType struct {
@@ -30,6 +31,16 @@ type linkedAccount struct {
 
 func (api *API) SupportedAccountTypesList(c *gin.Context) {
 	serverURL := config.GetConfigValue("SERVER_URL")
+	// GitHub Enterprise can only be linked once the user has configured their instance
+	isGithubEnterpriseConfigured := true
+	_, err := database.GetGithubEnterpriseConfiguration(api.DB, getUserIDFromContext(c))
+	if err == mongo.ErrNoDocuments {
+		isGithubEnterpriseConfigured = false
+	} else if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch github enterprise configuration")
+		Handle500(c)
+		return
+	}
 	nameToService := api.ExternalConfig.GetNameToService()
 	supportedAccountTypes := []SupportedAccountType{}
 	for serviceName, service := range nameToService {
@@ -38,6 +49,9 @@ func (api *API) SupportedAccountTypesList(c *gin.Context) {
 		if !service.Details.IsLinkable || serviceName == external.TASK_SERVICE_ID_SLACK_APP {
 			continue
 		}
+		if serviceName == external.TASK_SERVICE_ID_GITHUB_ENTERPRISE && !isGithubEnterpriseConfigured {
+			continue
+		}
 		supportedAccountTypes = append(supportedAccountTypes, SupportedAccountType{
 			Name:             service.Details.Name,
 			Logo:             service.Details.Logo,
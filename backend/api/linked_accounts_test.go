@@ -33,6 +33,24 @@ func TestSupportedAccountTypesList(t *testing.T) {
 		assert.Equal(t, 1, strings.Count(string(body), "{\"name\":\"Slack\",\"logo\":\"/images/slack.svg\",\"logo_v2\":\"slack\",\"authorization_url\":\"http://localhost:8080/link/slack/\"}"))
 		assert.Equal(t, 1, strings.Count(string(body), "{\"name\":\"Jira\",\"logo\":\"/images/jira.svg\",\"logo_v2\":\"jira\",\"authorization_url\":\"http://localhost:8080/link/atlassian/\"}"))
 	})
+	t.Run("GithubEnterprise", func(t *testing.T) {
+		authToken := login("supported_types_github_enterprise@generaltask.com", "")
+		api, dbCleanup := GetAPIWithDBCleanup()
+		defer dbCleanup()
+		githubEnterpriseType := "{\"name\":\"GitHub Enterprise\",\"logo\":\"/images/github.svg\",\"logo_v2\":\"github\",\"authorization_url\":\"http://localhost:8080/link/github_enterprise/\"}"
+
+		// hidden until the user has configured their instance
+		body := ServeRequest(t, authToken, "GET", "/linked_accounts/supported_types/", nil, http.StatusOK, api)
+		assert.Equal(t, 0, strings.Count(string(body), githubEnterpriseType))
+
+		_, err := database.GetGithubEnterpriseConfigurationCollection(api.DB).InsertOne(context.Background(), database.GithubEnterpriseConfiguration{
+			UserID:  getUserIDFromAuthToken(t, api.DB, authToken),
+			BaseURL: "https://github.example.com",
+		})
+		assert.NoError(t, err)
+		body = ServeRequest(t, authToken, "GET", "/linked_accounts/supported_types/", nil, http.StatusOK, api)
+		assert.Equal(t, 1, strings.Count(string(body), githubEnterpriseType))
+	})
 	UnauthorizedTest(t, "GET", "/linked_accounts/supported_types/", nil)
 }
 
@@ -178,6 +178,7 @@ func createNewUserTasks(userID primitive.ObjectID, db *mongo.Database) error {
 			SourceID:          external.TASK_SOURCE_ID_GT_TASK,
 			Title:             &tempTitle,
 			Body:              &body,
+			SearchBody:        &body,
 			SourceAccountID:   external.GeneralTaskDefaultAccountID,
 			IsCompleted:       &completed,
 			IsDeleted:         &deleted,
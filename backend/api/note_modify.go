@@ -96,6 +96,9 @@ func (api *API) NoteModify(c *gin.Context) {
 			UpdatedAt:    primitive.NewDateTimeFromTime(time.Now()),
 			CreatedAt:    note.CreatedAt,
 		}
+		if updatedNote.IsDeleted != nil && *updatedNote.IsDeleted {
+			updatedNote.DeletedAt = primitive.NewDateTimeFromTime(time.Now())
+		}
 
 		api.UpdateNoteInDB(c, note, userID, &updatedNote)
 	}
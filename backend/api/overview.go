@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"golang.org/x/exp/slices"
@@ -95,6 +96,11 @@ func (api *API) OverviewViewsList(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	labelID, err := getLabelFilter(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
 
 	userID := getUserIDFromContext(c)
 	_, err = database.GetUser(api.DB, userID)
@@ -137,6 +143,9 @@ func (api *API) OverviewViewsList(c *gin.Context) {
 		Handle500(c)
 		return
 	}
+	if labelID != nil {
+		filterOverviewResultsByLabel(result, *labelID)
+	}
 	sort.SliceStable(result, func(i, j int) bool {
 		return result[i].GetOrderingID() < result[j].GetOrderingID()
 	})
@@ -159,6 +168,8 @@ func (api *API) GetOverviewResults(views []database.View, userID primitive.Objec
 			singleOverviewResult, err = api.GetSlackOverviewResult(view, userID, timezoneOffset)
 		case string(constants.ViewGithub):
 			singleOverviewResult, err = api.GetGithubOverviewResult(view, userID, timezoneOffset)
+		case string(constants.ViewGithubIssue):
+			singleOverviewResult, err = api.GetGithubIssueOverviewResult(view, userID, timezoneOffset)
 		case string(constants.ViewMeetingPreparation):
 			singleOverviewResult, err = api.GetMeetingPreparationOverviewResult(view, userID, timezoneOffset, showMovedOrDeleted, ignoreMeetingPreparation)
 		case string(constants.ViewDueToday):
@@ -177,6 +188,24 @@ func (api *API) GetOverviewResults(views []database.View, userID primitive.Objec
 	return result, nil
 }
 
+// filterOverviewResultsByLabel keeps only the tasks with the label in each task view
+func filterOverviewResultsByLabel(results []OrderingIDGetter, labelID primitive.ObjectID) {
+	for _, result := range results {
+		taskView, ok := result.(*OverviewResult[TaskResult])
+		if !ok {
+			continue
+		}
+		viewItems := []*TaskResult{}
+		for _, task := range taskView.ViewItems {
+			if slices.Contains(task.LabelIDs, labelID) {
+				viewItems = append(viewItems, task)
+			}
+		}
+		taskView.ViewItems = viewItems
+		taskView.ViewItemIDs = GetTaskSectionViewItemIDs(viewItems)
+	}
+}
+
 func (api *API) GetTaskSectionOverviewResult(view database.View, userID primitive.ObjectID, timezoneOffset time.Duration) (*OverviewResult[TaskResult], error) {
 	if view.UserID != userID {
 		return nil, errors.New("invalid user")
@@ -192,6 +221,7 @@ func (api *API) GetTaskSectionOverviewResult(view database.View, userID primitiv
 
 	tasks, err := database.GetTasks(api.DB, userID, &[]bson.M{
 		{"is_deleted": bson.M{"$ne": true}},
+		database.GetNotSnoozedFilter(time.Now()),
 		{"id_task_section": view.TaskSectionID},
 	}, nil)
 	if err != nil {
@@ -326,6 +356,8 @@ func (api *API) UpdateViewsLinkedStatus(views *[]database.View, userID primitive
 		} else if view.Type == string(constants.ViewSlack) {
 			serviceID = external.TaskServiceSlack.ID
 		} else if view.Type == string(constants.ViewGithub) {
+			serviceID = getServiceIDForRepository(view.GithubID)
+		} else if view.Type == string(constants.ViewGithubIssue) {
 			serviceID = external.TaskServiceGithub.ID
 		} else {
 			return errors.New("invalid view type")
@@ -382,6 +414,7 @@ func (api *API) GetJiraOverviewResult(view database.View, userID primitive.Objec
 	jiraTasks, err := database.GetTasks(api.DB, userID, &[]bson.M{
 		{"is_completed": false},
 		{"is_deleted": bson.M{"$ne": true}},
+		database.GetNotSnoozedFilter(time.Now()),
 		{"source_id": external.TASK_SOURCE_ID_JIRA},
 	}, nil)
 	if err != nil {
@@ -430,6 +463,7 @@ func (api *API) GetLinearOverviewResult(view database.View, userID primitive.Obj
 	linearTasks, err := database.GetTasks(api.DB, userID, &[]bson.M{
 		{"is_completed": false},
 		{"is_deleted": bson.M{"$ne": true}},
+		database.GetNotSnoozedFilter(time.Now()),
 		{"source_id": external.TASK_SOURCE_ID_LINEAR},
 	}, nil)
 	if err != nil {
@@ -478,6 +512,7 @@ func (api *API) GetSlackOverviewResult(view database.View, userID primitive.Obje
 	slackTasks, err := database.GetTasks(api.DB, userID, &[]bson.M{
 		{"is_completed": false},
 		{"is_deleted": bson.M{"$ne": true}},
+		database.GetNotSnoozedFilter(time.Now()),
 		{"source_id": external.TASK_SOURCE_ID_SLACK_SAVED},
 	}, nil)
 	if err != nil {
@@ -496,15 +531,69 @@ func (api *API) GetSlackOverviewResult(view database.View, userID primitive.Obje
 	return &result, nil
 }
 
-func (api *API) GetGithubOverviewResult(view database.View, userID primitive.ObjectID, timezoneOffset time.Duration) (*OverviewResult[PullRequestResult], error) {
+func (api *API) GetGithubIssueOverviewResult(view database.View, userID primitive.ObjectID, timezoneOffset time.Duration) (*OverviewResult[TaskResult], error) {
 	if view.UserID != userID {
 		return nil, errors.New("invalid user")
 	}
 	authURL := config.GetAuthorizationURL(external.TASK_SERVICE_ID_GITHUB)
+	result := OverviewResult[TaskResult]{
+		ID:       view.ID,
+		Name:     constants.ViewGithubIssueName,
+		Logo:     external.TaskServiceGithub.LogoV2,
+		Type:     constants.ViewGithubIssue,
+		IsLinked: view.IsLinked,
+		Sources: []SourcesResult{
+			{
+				Name:             constants.ViewGithubIssueSourceName,
+				AuthorizationURL: &authURL,
+			},
+		},
+		TaskSectionID: view.TaskSectionID,
+		IsReorderable: view.IsReorderable,
+		IDOrdering:    view.IDOrdering,
+		ViewItems:     []*TaskResult{},
+		ViewItemIDs:   []string{},
+	}
+	if !view.IsLinked {
+		return &result, nil
+	}
+
+	githubIssueTasks, err := database.GetTasks(api.DB, userID, &[]bson.M{
+		{"is_completed": false},
+		{"is_deleted": bson.M{"$ne": true}},
+		database.GetNotSnoozedFilter(time.Now()),
+		{"source_id": external.TASK_SOURCE_ID_GITHUB_ISSUE},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	taskResults := api.taskListToTaskResultList(githubIssueTasks, userID)
+
+	timeNow := api.GetCurrentLocalizedTime(timezoneOffset)
+	timeStartOfDay := time.Date(timeNow.Year(), timeNow.Month(), timeNow.Day(), 0, 0, 0, 0, time.FixedZone("", 0))
+	taskCompletedInLastDay := api.getCompletedInLastDay(database.GetTaskCollection(api.DB), userID, timeStartOfDay, &[]bson.M{{"source_id": external.TASK_SOURCE_ID_GITHUB_ISSUE}})
+
+	result.IsLinked = view.IsLinked
+	result.ViewItems = taskResults
+	result.ViewItemIDs = GetTaskSectionViewItemIDs(taskResults)
+	result.HasTasksCompletedToday = taskCompletedInLastDay
+	return &result, nil
+}
+
+func (api *API) GetGithubOverviewResult(view database.View, userID primitive.ObjectID, timezoneOffset time.Duration) (*OverviewResult[PullRequestResult], error) {
+	if view.UserID != userID {
+		return nil, errors.New("invalid user")
+	}
+	serviceID := getServiceIDForRepository(view.GithubID)
+	logo := external.TaskServiceGithub.LogoV2
+	if serviceID == external.TASK_SERVICE_ID_GITLAB {
+		logo = external.TaskServiceGitlab.LogoV2
+	}
+	authURL := config.GetAuthorizationURL(serviceID)
 	result := OverviewResult[PullRequestResult]{
 		ID:       view.ID,
 		Name:     "Github PRs",
-		Logo:     external.TaskServiceGithub.LogoV2,
+		Logo:     logo,
 		Type:     constants.ViewGithub,
 		IsLinked: view.IsLinked,
 		Sources: []SourcesResult{
@@ -552,7 +641,11 @@ func (api *API) GetGithubOverviewResult(view database.View, userID primitive.Obj
 	timeStartOfDay := time.Date(timeNow.Year(), timeNow.Month(), timeNow.Day(), 0, 0, 0, 0, time.FixedZone("", 0))
 	taskCompletedInLastDay := api.getCompletedInLastDay(database.GetPullRequestCollection(api.DB), userID, timeStartOfDay, &[]bson.M{{"repository_id": view.GithubID}})
 
-	result.Name = fmt.Sprintf("GitHub PRs from %s", repository.FullName)
+	if serviceID == external.TASK_SERVICE_ID_GITLAB {
+		result.Name = fmt.Sprintf("GitLab MRs from %s", repository.FullName)
+	} else {
+		result.Name = fmt.Sprintf("GitHub PRs from %s", repository.FullName)
+	}
 	result.ViewItems = pullResults
 	result.ViewItemIDs = GetPullRequestViewItemsIDs(pullResults)
 	result.HasTasksCompletedToday = taskCompletedInLastDay
@@ -679,6 +772,7 @@ func (api *API) GetDueTodayOverviewResult(view database.View, userID primitive.O
 	dueTasks, err := database.GetTasks(api.DB, userID, &[]bson.M{
 		{"is_completed": false},
 		{"is_deleted": bson.M{"$ne": true}},
+		database.GetNotSnoozedFilter(time.Now()),
 		{"due_date": bson.M{"$lte": primitive.NewDateTimeFromTime(timeEndOfDay)}},
 		{"due_date": bson.M{"$ne": primitive.NewDateTimeFromTime(time.Time{})}},
 		{"due_date": bson.M{"$ne": primitive.NewDateTimeFromTime(time.Unix(0, 0))}},
@@ -972,7 +1066,7 @@ func (api *API) OverviewViewAdd(c *gin.Context) {
 	} else if viewCreateParams.Type == string(constants.ViewLinear) {
 		serviceID = external.TASK_SERVICE_ID_LINEAR
 	} else if viewCreateParams.Type == string(constants.ViewGithub) {
-		serviceID = external.TASK_SERVICE_ID_GITHUB
+		serviceID = getServiceIDForRepository(*viewCreateParams.GithubID)
 		isValidGithubRepository, err := isValidGithubRepository(api.DB, userID, *viewCreateParams.GithubID)
 		if err != nil {
 			api.Logger.Error().Err(err).Msg("error checking that github repository is valid")
@@ -984,6 +1078,8 @@ func (api *API) OverviewViewAdd(c *gin.Context) {
 			return
 		}
 		githubID = *viewCreateParams.GithubID
+	} else if viewCreateParams.Type == string(constants.ViewGithubIssue) {
+		serviceID = external.TASK_SERVICE_ID_GITHUB
 	} else if viewCreateParams.Type != string(constants.ViewJira) && viewCreateParams.Type != string(constants.ViewLinear) && viewCreateParams.Type != string(constants.ViewSlack) && viewCreateParams.Type != string(constants.ViewMeetingPreparation) && viewCreateParams.Type != string(constants.ViewDueToday) {
 		c.JSON(400, gin.H{"detail": "unsupported 'type'"})
 		return
@@ -1038,7 +1134,7 @@ func (api *API) ViewDoesExist(db *mongo.Database, userID primitive.ObjectID, par
 			return false, errors.New("'github_id' is required for github type views")
 		}
 		dbQuery["$and"] = append(dbQuery["$and"].([]bson.M), bson.M{"github_id": *params.GithubID})
-	} else if params.Type != string(constants.ViewLinear) && params.Type != string(constants.ViewSlack) && params.Type != string(constants.ViewJira) && params.Type != string(constants.ViewMeetingPreparation) && params.Type != string(constants.ViewDueToday) {
+	} else if params.Type != string(constants.ViewLinear) && params.Type != string(constants.ViewSlack) && params.Type != string(constants.ViewJira) && params.Type != string(constants.ViewMeetingPreparation) && params.Type != string(constants.ViewDueToday) && params.Type != string(constants.ViewGithubIssue) {
 		return false, errors.New("unsupported view type")
 	}
 	count, err := viewCollection.CountDocuments(context.Background(), dbQuery)
@@ -1308,6 +1404,20 @@ func (api *API) OverviewSupportedViewsList(c *gin.Context) {
 			AuthorizationURL: githubAuthURL,
 			Views:            supportedGithubViews,
 		},
+		{
+			Type:             constants.ViewGithubIssue,
+			Name:             "GitHub Issues",
+			Logo:             "github",
+			IsNested:         false,
+			IsLinked:         isGithubLinked,
+			AuthorizationURL: githubAuthURL,
+			Views: []SupportedViewItem{
+				{
+					Name:    "GitHub Issues View",
+					IsAdded: true,
+				},
+			},
+		},
 	}
 	err = api.updateIsAddedForSupportedViews(api.DB, userID, &supportedViews)
 	if err != nil {
@@ -1398,7 +1508,7 @@ func (api *API) getViewFromSupportedView(db *mongo.Database, userID primitive.Ob
 		return api.getView(db, userID, viewType, &[]bson.M{
 			{"task_section_id": view.TaskSectionID},
 		})
-	} else if slices.Contains([]constants.ViewType{constants.ViewJira, constants.ViewLinear, constants.ViewSlack, constants.ViewMeetingPreparation, constants.ViewDueToday}, viewType) {
+	} else if slices.Contains([]constants.ViewType{constants.ViewJira, constants.ViewLinear, constants.ViewSlack, constants.ViewMeetingPreparation, constants.ViewDueToday, constants.ViewGithubIssue}, viewType) {
 		return api.getView(db, userID, viewType, nil)
 	} else if viewType == constants.ViewGithub {
 		return api.getView(db, userID, viewType, &[]bson.M{
@@ -1438,6 +1548,17 @@ func (api *API) getView(db *mongo.Database, userID primitive.ObjectID, viewType
 	return &view, nil
 }
 
+// github views also hold GitLab projects and GitHub Enterprise repositories, whose repository IDs are prefixed to avoid collisions
+func getServiceIDForRepository(repositoryID string) string {
+	if strings.HasPrefix(repositoryID, external.GitlabRepositoryIDPrefix) {
+		return external.TASK_SERVICE_ID_GITLAB
+	}
+	if strings.HasPrefix(repositoryID, external.GithubEnterpriseRepositoryIDPrefix) {
+		return external.TASK_SERVICE_ID_GITHUB_ENTERPRISE
+	}
+	return external.TASK_SERVICE_ID_GITHUB
+}
+
 func isValidGithubRepository(db *mongo.Database, userID primitive.ObjectID, repositoryID string) (bool, error) {
 	repositoryCollection := database.GetRepositoryCollection(db)
 	count, err := repositoryCollection.CountDocuments(context.Background(), bson.M{"user_id": userID, "repository_id": repositoryID})
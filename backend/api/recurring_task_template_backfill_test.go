@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/GeneralTask/task-manager/backend/constants"
 	"github.com/GeneralTask/task-manager/backend/database"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -29,7 +30,7 @@ func TestRecurringTaskTemplateBackfill(t *testing.T) {
 		title := "hello!"
 		enabled := true
 		deleted := false
-		recurrenceRate := Daily
+		recurrenceRate := constants.RecurrenceRateDaily
 
 		// 10:00:10
 		creationTimeSeconds := 60*60*10 + 60*0 + 10
@@ -74,7 +75,7 @@ func TestRecurringTaskTemplateBackfill(t *testing.T) {
 		title := "hello!"
 		enabled := true
 		deleted := false
-		recurrenceRate := WeekDaily
+		recurrenceRate := constants.RecurrenceRateWeekDaily
 
 		// 11:00:10
 		creationTimeSeconds := 60*60*11 + 60*0 + 10
@@ -119,7 +120,7 @@ func TestRecurringTaskTemplateBackfill(t *testing.T) {
 		title := "hello!"
 		enabled := true
 		deleted := false
-		recurrenceRate := Weekly
+		recurrenceRate := constants.RecurrenceRateWeekly
 		// 10:00:10
 		creationTimeSeconds := 60*60*10 + 60*0 + 10
 		creationDay := int(time.Monday)
@@ -166,7 +167,7 @@ func TestRecurringTaskTemplateBackfill(t *testing.T) {
 		enabled := true
 		deleted := false
 		replace := true
-		recurrenceRate := Weekly
+		recurrenceRate := constants.RecurrenceRateWeekly
 		// 10:00:10
 		creationTimeSeconds := 60*60*10 + 60*0 + 10
 		creationDay := int(time.Monday)
@@ -213,7 +214,7 @@ func TestRecurringTaskTemplateBackfill(t *testing.T) {
 		title := "hello!"
 		enabled := true
 		deleted := false
-		recurrenceRate := Monthly
+		recurrenceRate := constants.RecurrenceRateMonthly
 		// 10:00:10
 		creationTimeSeconds := 60*60*10 + 60*0 + 10
 		creationDay := 14
@@ -258,7 +259,7 @@ func TestRecurringTaskTemplateBackfill(t *testing.T) {
 		title := "hello!"
 		enabled := true
 		deleted := false
-		recurrenceRate := Annually
+		recurrenceRate := constants.RecurrenceRateAnnually
 		// 10:00:10
 		creationTimeSeconds := 60*60*10 + 60*0 + 10
 		creationDay := 14
@@ -2,22 +2,29 @@ package api
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type RecurringTaskTemplateCreateParams struct {
-	Title                        *string  `json:"title,omitempty" binding:"required"`
-	Body                         *string  `json:"body,omitempty"`
-	IDTaskSection                *string  `json:"id_task_section,omitempty"`
-	PriorityNormalized           *float64 `json:"priority_normalized,omitempty"`
-	RecurrenceRate               *int     `json:"recurrence_rate,omitempty" binding:"required"`
-	TimeOfDaySecondsToCreateTask *int     `json:"time_of_day_seconds_to_create_task,omitempty" binding:"required"`
-	DayToCreateTask              *int     `json:"day_to_create_task,omitempty"`
-	MonthToCreateTask            *int     `json:"month_to_create_task,omitempty"`
-	ReplaceExisting              *bool    `json:"replace_existing,omitempty"`
+	Title                        *string                                 `json:"title,omitempty" binding:"required"`
+	Body                         *string                                 `json:"body,omitempty"`
+	IDTaskSection                *string                                 `json:"id_task_section,omitempty"`
+	PriorityNormalized           *float64                                `json:"priority_normalized,omitempty"`
+	RecurrenceRate               *int                                    `json:"recurrence_rate,omitempty"`
+	TimeOfDaySecondsToCreateTask *int                                    `json:"time_of_day_seconds_to_create_task,omitempty" binding:"required"`
+	DayToCreateTask              *int                                    `json:"day_to_create_task,omitempty"`
+	MonthToCreateTask            *int                                    `json:"month_to_create_task,omitempty"`
+	RecurrenceRule               *string                                 `json:"recurrence_rule,omitempty"`
+	ReplaceExisting              *bool                                   `json:"replace_existing,omitempty"`
+	DueDateOffsetSeconds         *int                                    `json:"due_date_offset_seconds,omitempty"`
+	TimeDuration                 *int                                    `json:"time_duration,omitempty"`
+	Subtasks                     []database.RecurringTaskTemplateSubtask `json:"subtasks,omitempty"`
 }
 
 func (api *API) RecurringTaskTemplateCreate(c *gin.Context) {
@@ -27,6 +34,29 @@ func (api *API) RecurringTaskTemplateCreate(c *gin.Context) {
 		c.JSON(400, gin.H{"detail": "invalid or missing parameter"})
 		return
 	}
+	// the recurrence rate is shorthand for a recurrence rule, so one of the two is required
+	if templateCreateParams.RecurrenceRate == nil && templateCreateParams.RecurrenceRule == nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing parameter"})
+		return
+	}
+	if templateCreateParams.RecurrenceRule != nil {
+		_, err = utils.ParseRecurrence(*templateCreateParams.RecurrenceRule)
+		if err != nil {
+			c.JSON(400, gin.H{"detail": "invalid recurrence rule: " + err.Error()})
+			return
+		}
+	}
+
+	timeAllocation, err := getTemplateTimeAllocation(templateCreateParams.TimeDuration)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": err.Error()})
+		return
+	}
+	err = validateTemplateSubtasks(templateCreateParams.Subtasks)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": err.Error()})
+		return
+	}
 
 	userID := getUserIDFromContext(c)
 
@@ -54,6 +84,10 @@ func (api *API) RecurringTaskTemplateCreate(c *gin.Context) {
 		TimeOfDaySecondsToCreateTask: templateCreateParams.TimeOfDaySecondsToCreateTask,
 		DayToCreateTask:              templateCreateParams.DayToCreateTask,
 		MonthToCreateTask:            templateCreateParams.MonthToCreateTask,
+		RecurrenceRule:               templateCreateParams.RecurrenceRule,
+		DueDateOffsetSeconds:         templateCreateParams.DueDateOffsetSeconds,
+		TimeAllocation:               timeAllocation,
+		Subtasks:                     templateCreateParams.Subtasks,
 		LastBackfillDatetime:         primitive.NewDateTimeFromTime(api.GetCurrentTime()),
 		CreatedAt:                    primitive.NewDateTimeFromTime(api.GetCurrentTime()),
 		UpdatedAt:                    primitive.NewDateTimeFromTime(api.GetCurrentTime()),
@@ -69,3 +103,23 @@ func (api *API) RecurringTaskTemplateCreate(c *gin.Context) {
 
 	c.JSON(200, gin.H{"template_id": insertID.InsertedID.(primitive.ObjectID)})
 }
+
+func getTemplateTimeAllocation(timeDuration *int) (*int64, error) {
+	if timeDuration == nil {
+		return nil, nil
+	}
+	if *timeDuration < 0 {
+		return nil, errors.New("time duration cannot be negative")
+	}
+	timeAllocation := (time.Duration(*timeDuration) * time.Second).Nanoseconds()
+	return &timeAllocation, nil
+}
+
+func validateTemplateSubtasks(subtasks []database.RecurringTaskTemplateSubtask) error {
+	for _, subtask := range subtasks {
+		if subtask.Title == "" {
+			return errors.New("subtask title cannot be empty")
+		}
+	}
+	return nil
+}
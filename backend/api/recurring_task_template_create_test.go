@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -68,6 +69,20 @@ func TestRecurringTaskTemplateCreate(t *testing.T) {
 		router.ServeHTTP(recorder, request)
 		assert.Equal(t, http.StatusBadRequest, recorder.Code)
 	})
+	t.Run("InvalidRecurrenceRule", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/recurring_task_templates/create/",
+			bytes.NewBuffer([]byte(`{"title": "hello!", "recurrence_rule": "FREQ=HOURLY", "time_of_day_seconds_to_create_task": 0}`)),
+		)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"detail":"invalid recurrence rule: unsupported FREQ: HOURLY"}`, string(body))
+	})
 	t.Run("Success", func(t *testing.T) {
 		request, _ := http.NewRequest(
 			"POST",
@@ -85,4 +100,72 @@ func TestRecurringTaskTemplateCreate(t *testing.T) {
 		assert.Equal(t, "hello!", *(templates[0].Title))
 		assert.Equal(t, primitive.NewDateTimeFromTime(currentTime), templates[0].CreatedAt)
 	})
+	t.Run("SuccessRecurrenceRule", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/recurring_task_templates/create/",
+			bytes.NewBuffer([]byte(`{"title": "every other tuesday", "recurrence_rule": "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "time_of_day_seconds_to_create_task": 0}`)),
+		)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var templates []database.RecurringTaskTemplate
+		err = database.FindWithCollection(database.GetRecurringTaskTemplateCollection(api.DB), userID, &[]bson.M{{"title": "every other tuesday"}}, &templates, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(templates))
+		assert.Nil(t, templates[0].RecurrenceRate)
+		assert.Equal(t, "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", *templates[0].RecurrenceRule)
+	})
+	t.Run("NegativeTimeDuration", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/recurring_task_templates/create/",
+			bytes.NewBuffer([]byte(`{"title": "hello!", "recurrence_rate": 0, "time_of_day_seconds_to_create_task": 0, "time_duration": -1}`)),
+		)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"detail":"time duration cannot be negative"}`, string(body))
+	})
+	t.Run("EmptySubtaskTitle", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/recurring_task_templates/create/",
+			bytes.NewBuffer([]byte(`{"title": "hello!", "recurrence_rate": 0, "time_of_day_seconds_to_create_task": 0, "subtasks": [{"title": ""}]}`)),
+		)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"detail":"subtask title cannot be empty"}`, string(body))
+	})
+	t.Run("SuccessSubtasks", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/recurring_task_templates/create/",
+			bytes.NewBuffer([]byte(`{"title": "release checklist", "recurrence_rate": 2, "day_to_create_task": 1, "time_of_day_seconds_to_create_task": 0, "due_date_offset_seconds": 86400, "time_duration": 1800, "subtasks": [{"title": "cut branch"}, {"title": "write notes", "body": "include migrations"}]}`)),
+		)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var templates []database.RecurringTaskTemplate
+		err = database.FindWithCollection(database.GetRecurringTaskTemplateCollection(api.DB), userID, &[]bson.M{{"title": "release checklist"}}, &templates, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(templates))
+		assert.Equal(t, 86400, *templates[0].DueDateOffsetSeconds)
+		assert.Equal(t, (30 * time.Minute).Nanoseconds(), *templates[0].TimeAllocation)
+		assert.Equal(t, []database.RecurringTaskTemplateSubtask{
+			{Title: "cut branch"},
+			{Title: "write notes", Body: "include migrations"},
+		}, templates[0].Subtasks)
+	})
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/utils"
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,17 +12,21 @@ import (
 )
 
 type RecurringTaskTemplateModifyParams struct {
-	Title                        *string  `json:"title,omitempty"`
-	Body                         *string  `json:"body,omitempty"`
-	IDTaskSection                *string  `json:"id_task_section,omitempty"`
-	PriorityNormalized           *float64 `json:"priority_normalized,omitempty"`
-	RecurrenceRate               *int     `json:"recurrence_rate,omitempty"`
-	TimeOfDaySecondsToCreateTask *int     `json:"time_of_day_seconds_to_create_task,omitempty"`
-	DayToCreateTask              *int     `json:"day_to_create_task,omitempty"`
-	MonthToCreateTask            *int     `json:"month_to_create_task,omitempty"`
-	IsEnabled                    *bool    `json:"is_enabled,omitempty"`
-	IsDeleted                    *bool    `json:"is_deleted,omitempty"`
-	ReplaceExisting              *bool    `json:"replace_existing,omitempty"`
+	Title                        *string                                  `json:"title,omitempty"`
+	Body                         *string                                  `json:"body,omitempty"`
+	IDTaskSection                *string                                  `json:"id_task_section,omitempty"`
+	PriorityNormalized           *float64                                 `json:"priority_normalized,omitempty"`
+	RecurrenceRate               *int                                     `json:"recurrence_rate,omitempty"`
+	TimeOfDaySecondsToCreateTask *int                                     `json:"time_of_day_seconds_to_create_task,omitempty"`
+	DayToCreateTask              *int                                     `json:"day_to_create_task,omitempty"`
+	MonthToCreateTask            *int                                     `json:"month_to_create_task,omitempty"`
+	RecurrenceRule               *string                                  `json:"recurrence_rule,omitempty"`
+	IsEnabled                    *bool                                    `json:"is_enabled,omitempty"`
+	IsDeleted                    *bool                                    `json:"is_deleted,omitempty"`
+	ReplaceExisting              *bool                                    `json:"replace_existing,omitempty"`
+	DueDateOffsetSeconds         *int                                     `json:"due_date_offset_seconds,omitempty"`
+	TimeDuration                 *int                                     `json:"time_duration,omitempty"`
+	Subtasks                     *[]database.RecurringTaskTemplateSubtask `json:"subtasks,omitempty"`
 }
 
 func (api *API) RecurringTaskTemplateModify(c *gin.Context) {
@@ -39,6 +44,29 @@ func (api *API) RecurringTaskTemplateModify(c *gin.Context) {
 		c.JSON(400, gin.H{"detail": "parameter missing or malformatted"})
 		return
 	}
+	// an empty recurrence rule reverts the template to its recurrence rate
+	if modifyParams.RecurrenceRule != nil && *modifyParams.RecurrenceRule != "" {
+		_, err = utils.ParseRecurrence(*modifyParams.RecurrenceRule)
+		if err != nil {
+			c.JSON(400, gin.H{"detail": "invalid recurrence rule: " + err.Error()})
+			return
+		}
+	}
+
+	timeAllocation, err := getTemplateTimeAllocation(modifyParams.TimeDuration)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": err.Error()})
+		return
+	}
+	var subtasks []database.RecurringTaskTemplateSubtask
+	if modifyParams.Subtasks != nil {
+		subtasks = *modifyParams.Subtasks
+	}
+	err = validateTemplateSubtasks(subtasks)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": err.Error()})
+		return
+	}
 
 	userID := getUserIDFromContext(c)
 
@@ -75,11 +103,20 @@ func (api *API) RecurringTaskTemplateModify(c *gin.Context) {
 		TimeOfDaySecondsToCreateTask: modifyParams.TimeOfDaySecondsToCreateTask,
 		DayToCreateTask:              modifyParams.DayToCreateTask,
 		MonthToCreateTask:            modifyParams.MonthToCreateTask,
+		RecurrenceRule:               modifyParams.RecurrenceRule,
+		DueDateOffsetSeconds:         modifyParams.DueDateOffsetSeconds,
+		TimeAllocation:               timeAllocation,
+		Subtasks:                     subtasks,
 		IDTaskSection:                taskSection,
 		ReplaceExisting:              modifyParams.ReplaceExisting,
 		UpdatedAt:                    primitive.NewDateTimeFromTime(api.GetCurrentTime()),
 	}
 
+	update := bson.M{"$set": updateTemplate}
+	if modifyParams.Subtasks != nil && len(subtasks) == 0 {
+		// an empty list is omitted from the $set, so the subtasks have to be removed explicitly
+		update["$unset"] = bson.M{"subtasks": ""}
+	}
 	mongoResult := database.GetRecurringTaskTemplateCollection(api.DB).FindOneAndUpdate(
 		context.Background(),
 		bson.M{
@@ -88,7 +125,7 @@ func (api *API) RecurringTaskTemplateModify(c *gin.Context) {
 				{"user_id": userID},
 			},
 		},
-		bson.M{"$set": updateTemplate},
+		update,
 		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	)
 	if mongoResult.Err() != nil {
@@ -100,6 +100,54 @@ func TestRecurringTaskTemplateModify(t *testing.T) {
 		assert.True(t, *templates[0].ReplaceExisting)
 		assert.Equal(t, primitive.NewDateTimeFromTime(currentTime), templates[0].UpdatedAt)
 	})
+	t.Run("InvalidRecurrenceRule", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"PATCH",
+			"/recurring_task_templates/modify/"+templateID.Hex()+"/",
+			bytes.NewBuffer([]byte(`{"recurrence_rule": "FREQ=WEEKLY;BYMONTHDAY=1"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+	t.Run("SuccessRecurrenceRule", func(t *testing.T) {
+		request, _ := http.NewRequest(
+			"PATCH",
+			"/recurring_task_templates/modify/"+templateID.Hex()+"/",
+			bytes.NewBuffer([]byte(`{"recurrence_rule": "RRULE:FREQ=MONTHLY;BYDAY=-1FR\nEXDATE;VALUE=DATE:20231229"}`)),
+		)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		var template database.RecurringTaskTemplate
+		err = database.FindOneWithCollection(database.GetRecurringTaskTemplateCollection(api.DB), userID, templateID).Decode(&template)
+		assert.NoError(t, err)
+		assert.Equal(t, "RRULE:FREQ=MONTHLY;BYDAY=-1FR\nEXDATE;VALUE=DATE:20231229", *template.RecurrenceRule)
+	})
+	t.Run("SuccessSubtasks", func(t *testing.T) {
+		modify := func(payload string) []database.RecurringTaskTemplateSubtask {
+			request, _ := http.NewRequest(
+				"PATCH",
+				"/recurring_task_templates/modify/"+templateID.Hex()+"/",
+				bytes.NewBuffer([]byte(payload)),
+			)
+			request.Header.Add("Authorization", "Bearer "+authToken)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, http.StatusOK, recorder.Code)
+
+			var template database.RecurringTaskTemplate
+			err = database.FindOneWithCollection(database.GetRecurringTaskTemplateCollection(api.DB), userID, templateID).Decode(&template)
+			assert.NoError(t, err)
+			return template.Subtasks
+		}
+		assert.Equal(t, []database.RecurringTaskTemplateSubtask{{Title: "subtask"}}, modify(`{"subtasks": [{"title": "subtask"}]}`))
+		// other changes leave the subtasks alone
+		assert.Equal(t, []database.RecurringTaskTemplateSubtask{{Title: "subtask"}}, modify(`{"time_duration": 60}`))
+		assert.Empty(t, modify(`{"subtasks": []}`))
+	})
 	t.Run("Delete", func(t *testing.T) {
 		template2Title := "whats up!"
 		insertResult, err := templateCollection.InsertOne(context.Background(), database.RecurringTaskTemplate{
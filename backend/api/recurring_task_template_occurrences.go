@@ -0,0 +1,79 @@
+package api
+
+import (
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/jobs"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const DefaultRecurringTaskTemplateOccurrenceCount = 5
+const MaxRecurringTaskTemplateOccurrenceCount = 50
+
+type RecurringTaskTemplateOccurrencesParams struct {
+	Count *int `form:"count"`
+}
+
+type RecurringTaskTemplateOccurrence struct {
+	Datetime  string `json:"datetime"`
+	Date      string `json:"date"`
+	IsSkipped bool   `json:"is_skipped"`
+}
+
+func (api *API) RecurringTaskTemplateOccurrences(c *gin.Context) {
+	templateID, err := primitive.ObjectIDFromHex(c.Param("template_id"))
+	if err != nil {
+		// This means the template ID is improperly formatted
+		Handle404(c)
+		return
+	}
+
+	var params RecurringTaskTemplateOccurrencesParams
+	err = c.BindQuery(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing parameter"})
+		return
+	}
+	count := DefaultRecurringTaskTemplateOccurrenceCount
+	if params.Count != nil {
+		count = *params.Count
+	}
+	if count < 1 || count > MaxRecurringTaskTemplateOccurrenceCount {
+		c.JSON(400, gin.H{"detail": "'count' must be between 1 and 50"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	var template database.RecurringTaskTemplate
+	err = database.FindOneWithCollection(database.GetRecurringTaskTemplateCollection(api.DB), userID, templateID).Decode(&template)
+	if err != nil {
+		c.JSON(404, gin.H{"detail": "template not found", "templateID": templateID})
+		return
+	}
+
+	currentTime, err := api.getCurrentTimeForRecurringTasks(c)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("unable to get localized time")
+		Handle500(c)
+		return
+	}
+	occurrences, err := jobs.GetUpcomingRecurringTaskTemplateOccurrences(template, currentTime, count)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to compute recurring task template occurrences")
+		Handle500(c)
+		return
+	}
+
+	results := []RecurringTaskTemplateOccurrence{}
+	for _, occurrence := range occurrences {
+		results = append(results, RecurringTaskTemplateOccurrence{
+			Datetime:  occurrence.Format(time.RFC3339),
+			Date:      occurrence.Format(constants.YEAR_MONTH_DAY_FORMAT),
+			IsSkipped: jobs.IsRecurringTaskTemplateOccurrenceSkipped(template, occurrence),
+		})
+	}
+	c.JSON(200, results)
+}
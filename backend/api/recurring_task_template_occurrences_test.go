@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRecurringTaskTemplateOccurrences(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	// 10:10:10 on Tuesday November 15, 2022
+	overrideDate := time.Date(2022, time.November, 15, 10, 10, 10, 0, time.UTC)
+	api.OverrideTime = &overrideDate
+	router := GetRouter(api)
+
+	authToken := login("recurring_task_template_occurrences@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	title := "every other tuesday"
+	rule := "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"
+	creationTimeSeconds := 60*60*9 + 30*60
+	insertResult, err := database.GetRecurringTaskTemplateCollection(api.DB).InsertOne(context.Background(), database.RecurringTaskTemplate{
+		UserID:                       userID,
+		Title:                        &title,
+		RecurrenceRule:               &rule,
+		TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+		SkippedDates:                 []string{"2022-11-29"},
+		CreatedAt:                    primitive.NewDateTimeFromTime(time.Date(2022, time.November, 1, 8, 0, 0, 0, time.UTC)),
+	})
+	assert.NoError(t, err)
+	templateID := insertResult.InsertedID.(primitive.ObjectID)
+
+	getOccurrences := func(url string) (int, string) {
+		request, _ := http.NewRequest("GET", url, nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		request.Header.Set("Timezone-Offset", "0")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, string(body)
+	}
+
+	UnauthorizedTest(t, "GET", "/recurring_task_templates/occurrences/"+templateID.Hex()+"/", nil)
+	t.Run("InvalidTemplateID", func(t *testing.T) {
+		code, _ := getOccurrences("/recurring_task_templates/occurrences/invalid/")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("WrongTemplate", func(t *testing.T) {
+		code, _ := getOccurrences("/recurring_task_templates/occurrences/" + primitive.NewObjectID().Hex() + "/")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("InvalidCount", func(t *testing.T) {
+		code, body := getOccurrences("/recurring_task_templates/occurrences/" + templateID.Hex() + "/?count=51")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"'count' must be between 1 and 50"}`, body)
+	})
+	t.Run("Success", func(t *testing.T) {
+		code, body := getOccurrences("/recurring_task_templates/occurrences/" + templateID.Hex() + "/?count=2")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, `[{"datetime":"2022-11-29T09:30:00Z","date":"2022-11-29","is_skipped":true},{"datetime":"2022-12-13T09:30:00Z","date":"2022-12-13","is_skipped":false}]`, body)
+	})
+	t.Run("SuccessTimezoneOffset", func(t *testing.T) {
+		request, _ := http.NewRequest("GET", "/recurring_task_templates/occurrences/"+templateID.Hex()+"/?count=1", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		// 8 hours behind UTC, where it is still 02:10 on November 15
+		request.Header.Set("Timezone-Offset", "480")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `[{"datetime":"2022-11-15T09:30:00-08:00","date":"2022-11-15","is_skipped":false}]`, string(body))
+	})
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/jobs"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type RecurringTaskTemplateSkipParams struct {
+	Date      string `json:"date" binding:"required"`
+	IsSkipped *bool  `json:"is_skipped"`
+}
+
+func (api *API) RecurringTaskTemplateSkip(c *gin.Context) {
+	templateID, err := primitive.ObjectIDFromHex(c.Param("template_id"))
+	if err != nil {
+		// This means the template ID is improperly formatted
+		Handle404(c)
+		return
+	}
+
+	var params RecurringTaskTemplateSkipParams
+	err = c.BindJSON(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing parameter"})
+		return
+	}
+	date, err := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, params.Date)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "'date' must be formatted as YYYY-MM-DD"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	var template database.RecurringTaskTemplate
+	err = database.FindOneWithCollection(database.GetRecurringTaskTemplateCollection(api.DB), userID, templateID).Decode(&template)
+	if err != nil {
+		c.JSON(404, gin.H{"detail": "template not found", "templateID": templateID})
+		return
+	}
+
+	// unskipping is always allowed, so that dates which no longer match a modified template can be cleaned up
+	update := bson.M{"$pull": bson.M{"skipped_dates": params.Date}}
+	if params.IsSkipped == nil || *params.IsSkipped {
+		currentTime, err := api.getCurrentTimeForRecurringTasks(c)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("unable to get localized time")
+			Handle500(c)
+			return
+		}
+		dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, currentTime.Location())
+		occurrences, err := jobs.GetRecurringTaskTemplateOccurrences(template, dayStart, dayStart.AddDate(0, 0, 1))
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to compute recurring task template occurrences")
+			Handle500(c)
+			return
+		}
+		if len(occurrences) == 0 && !jobs.IsRecurringTaskTemplateOccurrenceSkipped(template, dayStart) {
+			c.JSON(400, gin.H{"detail": "template has no occurrence on 'date'"})
+			return
+		}
+		update = bson.M{"$addToSet": bson.M{"skipped_dates": params.Date}}
+	}
+
+	_, err = database.GetRecurringTaskTemplateCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": templateID},
+			{"user_id": userID},
+		}},
+		update,
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to update recurring task template skipped dates")
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRecurringTaskTemplateSkip(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	// 10:10:10 on Tuesday November 15, 2022
+	overrideDate := time.Date(2022, time.November, 15, 10, 10, 10, 0, time.UTC)
+	api.OverrideTime = &overrideDate
+	router := GetRouter(api)
+
+	authToken := login("recurring_task_template_skip@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	title := "every monday"
+	recurrenceRate := constants.RecurrenceRateWeekly
+	creationDay := int(time.Monday)
+	creationTimeSeconds := 60 * 60 * 9
+	insertResult, err := database.GetRecurringTaskTemplateCollection(api.DB).InsertOne(context.Background(), database.RecurringTaskTemplate{
+		UserID:                       userID,
+		Title:                        &title,
+		RecurrenceRate:               &recurrenceRate,
+		DayToCreateTask:              &creationDay,
+		TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+	})
+	assert.NoError(t, err)
+	templateID := insertResult.InsertedID.(primitive.ObjectID)
+
+	skip := func(templateIDHex string, payload string) (int, string) {
+		request, _ := http.NewRequest("POST", "/recurring_task_templates/skip/"+templateIDHex+"/", bytes.NewBuffer([]byte(payload)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		request.Header.Set("Timezone-Offset", "0")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, string(body)
+	}
+	getSkippedDates := func() []string {
+		var template database.RecurringTaskTemplate
+		err := database.FindOneWithCollection(database.GetRecurringTaskTemplateCollection(api.DB), userID, templateID).Decode(&template)
+		assert.NoError(t, err)
+		return template.SkippedDates
+	}
+
+	UnauthorizedTest(t, "POST", "/recurring_task_templates/skip/"+templateID.Hex()+"/", nil)
+	t.Run("WrongTemplate", func(t *testing.T) {
+		code, _ := skip(primitive.NewObjectID().Hex(), `{"date": "2022-11-21"}`)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("MissingDate", func(t *testing.T) {
+		code, body := skip(templateID.Hex(), `{}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"invalid or missing parameter"}`, body)
+	})
+	t.Run("InvalidDate", func(t *testing.T) {
+		code, body := skip(templateID.Hex(), `{"date": "11/21/2022"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"'date' must be formatted as YYYY-MM-DD"}`, body)
+	})
+	t.Run("NoOccurrence", func(t *testing.T) {
+		code, body := skip(templateID.Hex(), `{"date": "2022-11-22"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"template has no occurrence on 'date'"}`, body)
+	})
+	t.Run("Success", func(t *testing.T) {
+		code, _ := skip(templateID.Hex(), `{"date": "2022-11-21"}`)
+		assert.Equal(t, http.StatusOK, code)
+		// skipping twice is a no-op
+		code, _ = skip(templateID.Hex(), `{"date": "2022-11-21"}`)
+		assert.Equal(t, http.StatusOK, code)
+		code, _ = skip(templateID.Hex(), `{"date": "2022-11-28"}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"2022-11-21", "2022-11-28"}, getSkippedDates())
+	})
+	t.Run("SuccessUnskip", func(t *testing.T) {
+		code, _ := skip(templateID.Hex(), `{"date": "2022-11-21", "is_skipped": false}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{"2022-11-28"}, getSkippedDates())
+	})
+}
@@ -45,6 +45,12 @@ func GetRouter(handlers *API) *gin.Engine {
 	router.POST("/tasks/create_external/slack/", handlers.SlackTaskCreate)
 
 	router.POST("/linear/webhook/", handlers.LinearWebhook)
+	router.POST("/jira/webhook/:cloud_id/", handlers.JIRAWebhook)
+	router.POST("/github/webhook/", handlers.GithubWebhook)
+	router.POST("/github_enterprise/webhook/", handlers.GithubEnterpriseWebhook)
+
+	// data export downloads are authorized by the signature in the link
+	router.GET("/data_exports/:export_id/download/", handlers.DataExportDownload)
 
 	// Slack App (Workspace level) endpoint for oauth verification
 	// We need this as we don't actually use the token provided, but still need to access it to
@@ -75,6 +81,7 @@ func GetRouter(handlers *API) *gin.Engine {
 	router.GET("/linked_accounts/", handlers.LinkedAccountsList)
 	router.GET("/linked_accounts/supported_types/", handlers.SupportedAccountTypesList)
 	router.DELETE("/linked_accounts/:account_id/", handlers.DeleteLinkedAccount)
+	router.POST("/github_enterprise/configuration/", handlers.GithubEnterpriseConfigurationUpdate)
 
 	router.GET("/calendars/", handlers.CalendarsList)
 	router.GET("/events/", handlers.EventsList)
@@ -83,28 +90,48 @@ func GetRouter(handlers *API) *gin.Engine {
 	router.DELETE("/events/delete/:event_id/", handlers.EventDelete)
 	router.PATCH("/events/modify/:event_id/", handlers.EventModify)
 
+	router.GET("/search/", handlers.Search)
 	router.GET("/tasks/fetch/", handlers.TasksFetch)
 	router.GET("/tasks/v3/", handlers.TasksListV3)
 	router.GET("/tasks/v4/", handlers.TasksListV4)
 	router.POST("/tasks/create/:source_id/", handlers.TaskCreate)
 	router.PATCH("/tasks/modify/:task_id/", handlers.TaskModify)
+	router.PATCH("/tasks/bulk_modify/", handlers.TaskBulkModify)
+	router.POST("/tasks/import/", handlers.TaskImport)
 	router.GET("/tasks/detail/:task_id/", handlers.TaskDetail)
 	router.POST("/tasks/:task_id/comments/add/", handlers.TaskAddComment)
+	router.PATCH("/tasks/:task_id/comments/:comment_id/", handlers.TaskModifyComment)
+	router.DELETE("/tasks/:task_id/comments/:comment_id/", handlers.TaskDeleteComment)
+	router.POST("/tasks/:task_id/dependencies/add/", handlers.TaskAddDependency)
+	router.DELETE("/tasks/:task_id/dependencies/:blocked_by_task_id/", handlers.TaskDeleteDependency)
+	router.GET("/tasks/:task_id/history/", handlers.TaskHistory)
+	router.POST("/tasks/restore/:task_id/", handlers.TaskRestore)
+	router.POST("/tasks/:task_id/timer/start/", handlers.TaskTimerStart)
+	router.GET("/timer/", handlers.TimerGet)
+	router.POST("/timer/stop/", handlers.TimerStop)
+	router.GET("/time_tracking/report/", handlers.TimeTrackingReport)
+	router.POST("/shareable_tasks/:task_id/comments/add/", handlers.ShareableTaskAddComment)
 
 	router.GET("/recurring_task_templates/", handlers.RecurringTaskTemplateList)
 	router.GET("/recurring_task_templates/v2/", handlers.RecurringTaskTemplateListV2)
 	router.GET("/recurring_task_templates/backfill_tasks/", handlers.RecurringTaskTemplateBackfillTasks)
 	router.POST("/recurring_task_templates/create/", handlers.RecurringTaskTemplateCreate)
 	router.PATCH("/recurring_task_templates/modify/:template_id/", handlers.RecurringTaskTemplateModify)
+	router.GET("/recurring_task_templates/occurrences/:template_id/", handlers.RecurringTaskTemplateOccurrences)
+	router.POST("/recurring_task_templates/skip/:template_id/", handlers.RecurringTaskTemplateSkip)
 
 	router.GET("/notes/", handlers.NotesList)
 	router.PATCH("/notes/modify/:note_id/", handlers.NoteModify)
 	router.POST("/notes/create/", handlers.NoteCreate)
+	router.POST("/notes/restore/:note_id/", handlers.NoteRestore)
+	router.GET("/trash/", handlers.TrashList)
 
 	router.GET("/ping_authed/", handlers.Ping)
 
 	router.GET("/settings/", handlers.SettingsList)
 	router.PATCH("/settings/", handlers.SettingsModify)
+	router.POST("/data_exports/create/", handlers.DataExportCreate)
+	router.GET("/data_exports/:export_id/", handlers.DataExportGet)
 
 	router.POST("/log_events/", handlers.LogEventAdd)
 	router.POST("/feedback/", handlers.FeedbackAdd)
@@ -112,6 +139,10 @@ func GetRouter(handlers *API) *gin.Engine {
 	router.GET("/user_info/", handlers.UserInfoGet)
 	router.PATCH("/user_info/", handlers.UserInfoUpdate)
 
+	router.GET("/labels/", handlers.LabelList)
+	router.POST("/labels/create/", handlers.LabelAdd)
+	router.PATCH("/labels/modify/:label_id/", handlers.LabelModify)
+	router.DELETE("/labels/delete/:label_id/", handlers.LabelDelete)
 	router.GET("/sections/", handlers.SectionList)
 	router.GET("/sections/v2/", handlers.SectionListV2)
 	router.POST("/sections/create/", handlers.SectionAdd)
@@ -135,6 +166,8 @@ func GetRouter(handlers *API) *gin.Engine {
 	router.GET("/pull_requests/", handlers.PullRequestsList)
 	router.GET("/pull_requests/fetch/", handlers.PullRequestsFetch)
 
+	router.POST("/linear/webhook_secret/", handlers.LinearWebhookSecretRotate)
+
 	router.GET("/daily_task_completion/", handlers.DailyTaskCompletionList)
 
 	// Add business middleware. Endpoints below this require business mode to be enabled
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+	// every page re-fetches all of the results before it, so deep pages are not allowed
+	MaxSearchPage = 10
+
+	SearchResultTypeTask        = "task"
+	SearchResultTypeNote        = "note"
+	SearchResultTypePullRequest = "pull_request"
+	SearchResultTypeEvent       = "event"
+)
+
+type SearchParams struct {
+	Query         string     `form:"query" binding:"required"`
+	SourceID      *string    `form:"source_id"`
+	IsCompleted   *bool      `form:"is_completed"`
+	DatetimeStart *time.Time `form:"datetime_start"`
+	DatetimeEnd   *time.Time `form:"datetime_end"`
+	database.Pagination
+}
+
+type SearchResult struct {
+	Type        string             `json:"type"`
+	ID          primitive.ObjectID `json:"id"`
+	Title       string             `json:"title"`
+	Body        string             `json:"body"`
+	SourceID    string             `json:"source_id,omitempty"`
+	Deeplink    string             `json:"deeplink,omitempty"`
+	IsCompleted bool               `json:"is_completed"`
+	Datetime    string             `json:"datetime,omitempty"`
+	Score       float64            `json:"score"`
+}
+
+type SearchResponse struct {
+	Results []SearchResult `json:"results"`
+	Page    int            `json:"page"`
+	Limit   int            `json:"limit"`
+	HasMore bool           `json:"has_more"`
+}
+
+// Search godoc
+// @Summary      Searches the user's tasks, notes, pull requests and events
+// @Description  Results are ranked by relevance across all types, and filters only match the types which have the filtered field
+// @Tags         search
+// @Produce      json
+// @Param        query           query     string  true   "text to search for"
+// @Param        source_id       query     string  false  "only include results from this source"
+// @Param        is_completed    query     bool    false  "only include tasks and pull requests with this completion state"
+// @Param        datetime_start  query     string  false  "only include results created or starting at or after this time"
+// @Param        datetime_end    query     string  false  "only include results created or starting before this time"
+// @Param        page            query     int     false  "page of results, from 1 to 10"
+// @Param        limit           query     int     false  "results per page"
+// @Success      200 {object} SearchResponse
+// @Failure      400 {object} string "invalid params"
+// @Failure      500 {object} string "internal server error"
+// @Router       /search/ [get]
+func (api *API) Search(c *gin.Context) {
+	var params SearchParams
+	err := c.BindQuery(&params)
+	if err != nil || params.Query == "" {
+		c.JSON(400, gin.H{"detail": "invalid or missing 'query' parameter"})
+		return
+	}
+	page := 1
+	if params.Page != nil {
+		page = *params.Page
+	}
+	limit := DefaultSearchLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if page < 1 || page > MaxSearchPage {
+		c.JSON(400, gin.H{"detail": "'page' must be between 1 and 10"})
+		return
+	}
+	if limit < 1 || limit > MaxSearchLimit {
+		c.JSON(400, gin.H{"detail": "'limit' must be between 1 and 100"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	// fetching one extra result from each type is enough to know whether there is another page
+	offset := (page - 1) * limit
+	results, err := api.searchAllTypes(userID, params, int64(offset+limit+1))
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to search")
+		Handle500(c)
+		return
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	response := SearchResponse{
+		Results: []SearchResult{},
+		Page:    page,
+		Limit:   limit,
+		HasMore: len(results) > offset+limit,
+	}
+	if offset < len(results) {
+		end := offset + limit
+		if end > len(results) {
+			end = len(results)
+		}
+		response.Results = results[offset:end]
+	}
+	c.JSON(200, response)
+}
+
+func (api *API) searchAllTypes(userID primitive.ObjectID, params SearchParams, limit int64) ([]SearchResult, error) {
+	results := []SearchResult{}
+
+	taskFilters := append(getSearchFilters(params, "created_at_external"), bson.M{"is_deleted": bson.M{"$ne": true}})
+	tasks, err := database.SearchWithCollection[database.Task](database.GetTaskCollection(api.DB), userID, params.Query, &taskFilters, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range *tasks {
+		result := SearchResult{
+			Type:        SearchResultTypeTask,
+			ID:          task.Item.ID,
+			SourceID:    task.Item.SourceID,
+			Deeplink:    task.Item.Deeplink,
+			IsCompleted: task.Item.IsCompleted != nil && *task.Item.IsCompleted,
+			Datetime:    task.Item.CreatedAtExternal.Time().UTC().Format(time.RFC3339),
+			Score:       task.Score,
+		}
+		if task.Item.Title != nil {
+			result.Title = *task.Item.Title
+		}
+		if task.Item.Body != nil {
+			result.Body = *task.Item.Body
+		}
+		results = append(results, result)
+	}
+
+	pullRequestFilters := getSearchFilters(params, "created_at_external")
+	pullRequests, err := database.SearchWithCollection[database.PullRequest](database.GetPullRequestCollection(api.DB), userID, params.Query, &pullRequestFilters, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, pullRequest := range *pullRequests {
+		results = append(results, SearchResult{
+			Type:        SearchResultTypePullRequest,
+			ID:          pullRequest.Item.ID,
+			Title:       pullRequest.Item.Title,
+			Body:        pullRequest.Item.Body,
+			SourceID:    pullRequest.Item.SourceID,
+			Deeplink:    pullRequest.Item.Deeplink,
+			IsCompleted: pullRequest.Item.IsCompleted != nil && *pullRequest.Item.IsCompleted,
+			Datetime:    pullRequest.Item.CreatedAtExternal.Time().UTC().Format(time.RFC3339),
+			Score:       pullRequest.Score,
+		})
+	}
+
+	// events can't be completed
+	if params.IsCompleted == nil {
+		eventFilters := getSearchFilters(params, "datetime_start")
+		events, err := database.SearchWithCollection[database.CalendarEvent](database.GetCalendarEventCollection(api.DB), userID, params.Query, &eventFilters, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range *events {
+			results = append(results, SearchResult{
+				Type:     SearchResultTypeEvent,
+				ID:       event.Item.ID,
+				Title:    event.Item.Title,
+				Body:     event.Item.Body,
+				SourceID: event.Item.SourceID,
+				Deeplink: event.Item.Deeplink,
+				Datetime: event.Item.DatetimeStart.Time().UTC().Format(time.RFC3339),
+				Score:    event.Score,
+			})
+		}
+	}
+
+	// notes have neither a source nor a completion state
+	if params.SourceID == nil && params.IsCompleted == nil {
+		noteFilters := append(getSearchFilters(params, "created_at"), bson.M{"is_deleted": bson.M{"$ne": true}})
+		notes, err := database.SearchWithCollection[database.Note](database.GetNoteCollection(api.DB), userID, params.Query, &noteFilters, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, note := range *notes {
+			result := SearchResult{
+				Type:     SearchResultTypeNote,
+				ID:       note.Item.ID,
+				Datetime: note.Item.CreatedAt.Time().UTC().Format(time.RFC3339),
+				Score:    note.Score,
+			}
+			if note.Item.Title != nil {
+				result.Title = *note.Item.Title
+			}
+			if note.Item.Body != nil {
+				result.Body = *note.Item.Body
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func getSearchFilters(params SearchParams, datetimeField string) []bson.M {
+	filters := []bson.M{}
+	if params.SourceID != nil {
+		filters = append(filters, bson.M{"source_id": *params.SourceID})
+	}
+	if params.IsCompleted != nil {
+		if *params.IsCompleted {
+			filters = append(filters, bson.M{"is_completed": true})
+		} else {
+			filters = append(filters, bson.M{"is_completed": bson.M{"$ne": true}})
+		}
+	}
+	if params.DatetimeStart != nil {
+		filters = append(filters, bson.M{datetimeField: bson.M{"$gte": primitive.NewDateTimeFromTime(*params.DatetimeStart)}})
+	}
+	if params.DatetimeEnd != nil {
+		filters = append(filters, bson.M{datetimeField: bson.M{"$lt": primitive.NewDateTimeFromTime(*params.DatetimeEnd)}})
+	}
+	return filters
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// the test DB is not migrated, so the indexes from the search migration are created here
+func createSearchIndexes(t *testing.T, db *mongo.Database) {
+	for _, collection := range []*mongo.Collection{
+		database.GetNoteCollection(db),
+		database.GetPullRequestCollection(db),
+		database.GetCalendarEventCollection(db),
+	} {
+		_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+			Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "body", Value: "text"}},
+			Options: options.Index().SetName("search_text").SetWeights(bson.M{"title": 5, "body": 1}),
+		})
+		assert.NoError(t, err)
+	}
+	_, err := database.GetTaskCollection(db).Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "search_body", Value: "text"}},
+		Options: options.Index().SetName("search_text").SetWeights(bson.M{"title": 5, "search_body": 1}),
+	})
+	assert.NoError(t, err)
+}
+
+func TestSearch(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	createSearchIndexes(t, api.DB)
+	authToken := login("test_search@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	completed := true
+	notCompleted := false
+	taskTitle := "Write quarterly report"
+	taskBody := "include the numbers"
+	completedTaskTitle := "Send report to finance"
+	noteTitle := "Report notes"
+	noteBody := "draft of the report outline"
+	otherUserTaskTitle := "Another report"
+	jiraTaskTitle := "Migrate the billing service"
+	jiraTaskBody := `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"see the invoice"}]}]}`
+	jiraTaskSearchBody := "see the invoice"
+	_, err := database.GetTaskCollection(api.DB).InsertMany(context.Background(), []interface{}{
+		database.Task{UserID: userID, SourceID: external.TASK_SOURCE_ID_GT_TASK, Title: &taskTitle, Body: &taskBody, SearchBody: &taskBody, IsCompleted: &notCompleted, CreatedAtExternal: primitive.NewDateTimeFromTime(time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC))},
+		database.Task{UserID: userID, SourceID: external.TASK_SOURCE_ID_LINEAR, Title: &completedTaskTitle, IsCompleted: &completed, CreatedAtExternal: primitive.NewDateTimeFromTime(time.Date(2023, 2, 10, 0, 0, 0, 0, time.UTC))},
+		database.Task{UserID: primitive.NewObjectID(), SourceID: external.TASK_SOURCE_ID_GT_TASK, Title: &otherUserTaskTitle, IsCompleted: &notCompleted},
+		database.Task{UserID: userID, SourceID: external.TASK_SOURCE_ID_JIRA, Title: &jiraTaskTitle, Body: &jiraTaskBody, SearchBody: &jiraTaskSearchBody, IsCompleted: &notCompleted},
+	})
+	assert.NoError(t, err)
+	_, err = database.GetNoteCollection(api.DB).InsertOne(context.Background(), database.Note{UserID: userID, Title: &noteTitle, Body: &noteBody})
+	assert.NoError(t, err)
+	_, err = database.GetPullRequestCollection(api.DB).InsertOne(context.Background(), database.PullRequest{UserID: userID, SourceID: external.TASK_SOURCE_ID_GITHUB_PR, Title: "Fix report export", IsCompleted: &notCompleted})
+	assert.NoError(t, err)
+	_, err = database.GetCalendarEventCollection(api.DB).InsertOne(context.Background(), database.CalendarEvent{UserID: userID, SourceID: external.TASK_SOURCE_ID_GCAL, Title: "Report review", DatetimeStart: primitive.NewDateTimeFromTime(time.Date(2023, 1, 20, 0, 0, 0, 0, time.UTC))})
+	assert.NoError(t, err)
+
+	search := func(query string) (int, SearchResponse) {
+		request, _ := http.NewRequest("GET", "/search/?"+query, nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		var response SearchResponse
+		if recorder.Code == http.StatusOK {
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&response))
+		}
+		return recorder.Code, response
+	}
+	getTypes := func(results []SearchResult) []string {
+		types := []string{}
+		for _, result := range results {
+			types = append(types, result.Type)
+		}
+		return types
+	}
+
+	UnauthorizedTest(t, "GET", "/search/?query=report", nil)
+	t.Run("MissingQuery", func(t *testing.T) {
+		request, _ := http.NewRequest("GET", "/search/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"detail":"invalid or missing 'query' parameter"}`, string(body))
+	})
+	t.Run("InvalidLimit", func(t *testing.T) {
+		code, _ := search("query=report&limit=101")
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = search("query=report&page=0")
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = search("query=report&page=11")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+	t.Run("Success", func(t *testing.T) {
+		code, response := search("query=report")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 5, len(response.Results))
+		assert.False(t, response.HasMore)
+		assert.ElementsMatch(t, []string{SearchResultTypeTask, SearchResultTypeTask, SearchResultTypeNote, SearchResultTypePullRequest, SearchResultTypeEvent}, getTypes(response.Results))
+		for i := 1; i < len(response.Results); i++ {
+			assert.GreaterOrEqual(t, response.Results[i-1].Score, response.Results[i].Score)
+		}
+	})
+	t.Run("TaskBody", func(t *testing.T) {
+		_, response := search("query=numbers")
+		assert.Equal(t, 1, len(response.Results))
+		assert.Equal(t, taskTitle, response.Results[0].Title)
+	})
+	t.Run("JIRADescriptionText", func(t *testing.T) {
+		_, response := search("query=invoice")
+		assert.Equal(t, 1, len(response.Results))
+		assert.Equal(t, jiraTaskTitle, response.Results[0].Title)
+		assert.Equal(t, jiraTaskBody, response.Results[0].Body)
+		// the ADF structure of the description is not searchable
+		_, response = search("query=paragraph")
+		assert.Equal(t, 0, len(response.Results))
+	})
+	t.Run("Pagination", func(t *testing.T) {
+		_, allResults := search("query=report")
+		code, response := search("query=report&limit=2&page=2")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, allResults.Results[2:4], response.Results)
+		assert.True(t, response.HasMore)
+		_, response = search("query=report&limit=2&page=3")
+		assert.Equal(t, 1, len(response.Results))
+		assert.False(t, response.HasMore)
+	})
+	t.Run("SourceFilter", func(t *testing.T) {
+		_, response := search("query=report&source_id=" + external.TASK_SOURCE_ID_LINEAR)
+		assert.Equal(t, 1, len(response.Results))
+		assert.Equal(t, completedTaskTitle, response.Results[0].Title)
+	})
+	t.Run("CompletionFilter", func(t *testing.T) {
+		_, response := search("query=report&is_completed=false")
+		assert.ElementsMatch(t, []string{SearchResultTypeTask, SearchResultTypePullRequest}, getTypes(response.Results))
+		_, response = search("query=report&is_completed=true")
+		assert.Equal(t, 1, len(response.Results))
+		assert.True(t, response.Results[0].IsCompleted)
+	})
+	t.Run("DateFilter", func(t *testing.T) {
+		_, response := search("query=report&datetime_start=2023-01-01T00:00:00Z&datetime_end=2023-02-01T00:00:00Z")
+		assert.ElementsMatch(t, []string{SearchResultTypeTask, SearchResultTypeEvent}, getTypes(response.Results))
+	})
+}
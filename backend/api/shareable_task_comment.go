@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (api *API) ShareableTaskAddComment(c *gin.Context) {
+	taskIDHex := c.Param("task_id")
+	taskID, err := primitive.ObjectIDFromHex(taskIDHex)
+	if err != nil {
+		// This means the task ID is improperly formatted
+		Handle404(c)
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	task, err := database.GetSharedTask(api.DB, taskID, &userID)
+	if err != nil || task == nil {
+		Handle404(c)
+		return
+	}
+	if task.SourceID != external.TASK_SOURCE_ID_GT_TASK {
+		c.JSON(400, gin.H{"detail": "comments can only be added to shared general task tasks"})
+		return
+	}
+	// publicly shared tasks can be viewed by anyone, but only members of the owner's domain can comment
+	isSameDomain, err := database.IsSameDomain(api.DB, userID, task.UserID)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	if !isSameDomain {
+		c.JSON(403, gin.H{"detail": "only users in the same domain as the task owner can comment"})
+		return
+	}
+
+	var commentParams CommentModifyParams
+	err = c.BindJSON(&commentParams)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "parameter missing or malformatted"})
+		return
+	}
+	comment, err := api.getGeneralTaskComment(userID, commentParams.Body)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+
+	// pushed atomically, so that comments added at the same time by other viewers are not lost
+	_, err = database.GetTaskCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": task.ID},
+			{"user_id": task.UserID},
+		}},
+		bson.M{"$push": bson.M{"comments": comment}},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to add comment")
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestShareableTaskAddComment(t *testing.T) {
+	authToken := login("test_shareable_task_comment@generaltask.com", "")
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	taskCollection := database.GetTaskCollection(db)
+	userID := getUserIDFromAuthToken(t, db, authToken)
+
+	publicSharedAccess := database.SharedAccessPublic
+	domainSharedAccess := database.SharedAccessDomain
+	futureTime := primitive.NewDateTimeFromTime(time.Now().Add(1 * time.Hour))
+	insertTask := func(sourceID string, sharedAccess *database.SharedAccess) string {
+		mongoResult, err := taskCollection.InsertOne(context.Background(), &database.Task{
+			UserID:       userID,
+			SourceID:     sourceID,
+			SharedUntil:  futureTime,
+			SharedAccess: sharedAccess,
+		})
+		assert.NoError(t, err)
+		return mongoResult.InsertedID.(primitive.ObjectID).Hex()
+	}
+	domainSharedTaskID := insertTask(external.TASK_SOURCE_ID_GT_TASK, &domainSharedAccess)
+	publicSharedTaskID := insertTask(external.TASK_SOURCE_ID_GT_TASK, &publicSharedAccess)
+	linearSharedTaskID := insertTask(external.TASK_SOURCE_ID_LINEAR, &domainSharedAccess)
+	notSharedTaskID := insertTask(external.TASK_SOURCE_ID_GT_TASK, nil)
+
+	t.Run("InvalidTaskID", func(t *testing.T) {
+		ServeRequest(t, authToken, "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", primitive.NewObjectID().Hex()), bytes.NewBuffer([]byte(`{"body": "hi"}`)), http.StatusNotFound, api)
+	})
+	t.Run("TaskNotShared", func(t *testing.T) {
+		ServeRequest(t, authToken, "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", notSharedTaskID), bytes.NewBuffer([]byte(`{"body": "hi"}`)), http.StatusNotFound, api)
+	})
+	t.Run("Unauthorized", func(t *testing.T) {
+		ServeRequest(t, "", "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", publicSharedTaskID), bytes.NewBuffer([]byte(`{"body": "hi"}`)), http.StatusUnauthorized, api)
+	})
+	t.Run("NotGeneralTask", func(t *testing.T) {
+		ServeRequest(t, authToken, "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", linearSharedTaskID), bytes.NewBuffer([]byte(`{"body": "hi"}`)), http.StatusBadRequest, api)
+	})
+	t.Run("DifferentDomain", func(t *testing.T) {
+		differentDomainUserToken := login("comment_different_domain@applesauce.com", "")
+		ServeRequest(t, differentDomainUserToken, "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", domainSharedTaskID), bytes.NewBuffer([]byte(`{"body": "hi"}`)), http.StatusNotFound, api)
+	})
+	t.Run("DifferentDomainPublicTask", func(t *testing.T) {
+		differentDomainUserToken := login("comment_different_domain@applesauce.com", "")
+		ServeRequest(t, differentDomainUserToken, "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", publicSharedTaskID), bytes.NewBuffer([]byte(`{"body": "hi"}`)), http.StatusForbidden, api)
+	})
+	t.Run("MissingBody", func(t *testing.T) {
+		sameDomainUserToken := login("comment_same_domain@generaltask.com", "")
+		ServeRequest(t, sameDomainUserToken, "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", domainSharedTaskID), bytes.NewBuffer([]byte(`{}`)), http.StatusBadRequest, api)
+	})
+	t.Run("SuccessSameDomain", func(t *testing.T) {
+		sameDomainUserToken := login("comment_same_domain@generaltask.com", "Same Domain")
+		sameDomainUserID := getUserIDFromAuthToken(t, db, sameDomainUserToken)
+		ServeRequest(t, sameDomainUserToken, "POST", fmt.Sprintf("/shareable_tasks/%s/comments/add/", domainSharedTaskID), bytes.NewBuffer([]byte(`{"body": "hi"}`)), http.StatusOK, api)
+
+		taskID, _ := primitive.ObjectIDFromHex(domainSharedTaskID)
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(*task.Comments))
+		assert.Equal(t, "hi", (*task.Comments)[0].Body)
+		assert.Equal(t, sameDomainUserID.Hex(), (*task.Comments)[0].User.ExternalID)
+		assert.Equal(t, "comment_same_domain@generaltask.com", (*task.Comments)[0].User.Email)
+	})
+}
@@ -42,8 +42,8 @@ func (api *API) ShareableTaskDetails(c *gin.Context) {
 		return
 	}
 
-	// Get subtasks for the shared task
-	subtasks, err := database.GetSubtasksFromTask(api.DB, task)
+	// Get subtasks at every level of nesting for the shared task
+	subtasks, err := database.GetTaskDescendants(api.DB, task.UserID, task.ID)
 	if err != nil {
 		Handle404(c)
 		return
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const MaxBulkModifyTasks = 100
+
+type TaskBulkModifyParams struct {
+	TaskIDs       []string `json:"task_ids" binding:"required"`
+	IDTaskSection *string  `json:"id_task_section"`
+	// applies completion, deletion and section moves to all subtasks too
+	CascadeToSubtasks bool `json:"cascade_to_subtasks"`
+	TaskItemChangeableFields
+}
+
+type TaskBulkModifyResult struct {
+	TaskID  string `json:"task_id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+type taskSourceAccount struct {
+	SourceID        string
+	SourceAccountID string
+}
+
+type bulkModifyTask struct {
+	Index int
+	Task  *database.Task
+}
+
+// TaskBulkModify godoc
+// @Summary      Applies the same changes to many tasks
+// @Description  Tasks are updated per source account, and a failure for one task does not stop the others from being updated
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Param        payload  body      TaskBulkModifyParams  true  "task IDs and the changes to apply to them"
+// @Success      200 {array}  TaskBulkModifyResult
+// @Failure      400 {object} string "invalid params"
+// @Router       /tasks/bulk_modify/ [patch]
+func (api *API) TaskBulkModify(c *gin.Context) {
+	var params TaskBulkModifyParams
+	err := c.BindJSON(&params)
+	if err != nil || len(params.TaskIDs) == 0 {
+		c.JSON(400, gin.H{"detail": "invalid or missing 'task_ids' parameter"})
+		return
+	}
+	if len(params.TaskIDs) > MaxBulkModifyTasks {
+		c.JSON(400, gin.H{"detail": "cannot modify more than 100 tasks at once"})
+		return
+	}
+	if params.IDTaskSection != nil {
+		_, err = primitive.ObjectIDFromHex(*params.IDTaskSection)
+		if err != nil {
+			c.JSON(400, gin.H{"detail": "'id_task_section' is not a valid ID"})
+			return
+		}
+	}
+	if params.IDTaskSection == nil && params.TaskItemChangeableFields == (TaskItemChangeableFields{}) {
+		c.JSON(400, gin.H{"detail": "task changes missing"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	results := make([]TaskBulkModifyResult, len(params.TaskIDs))
+	// tasks are grouped so that each external account sees its changes one at a time
+	tasksBySourceAccount := make(map[taskSourceAccount][]bulkModifyTask)
+	for index, taskIDHex := range params.TaskIDs {
+		results[index] = TaskBulkModifyResult{TaskID: taskIDHex}
+		taskID, err := primitive.ObjectIDFromHex(taskIDHex)
+		if err != nil {
+			results[index].Error = "invalid task ID"
+			continue
+		}
+		task, err := database.GetTask(api.DB, taskID, userID)
+		if err != nil {
+			results[index].Error = "task not found"
+			continue
+		}
+		sourceAccount := taskSourceAccount{SourceID: task.SourceID, SourceAccountID: task.SourceAccountID}
+		tasksBySourceAccount[sourceAccount] = append(tasksBySourceAccount[sourceAccount], bulkModifyTask{Index: index, Task: task})
+	}
+
+	resultChannels := []chan map[int]error{}
+	for _, tasks := range tasksBySourceAccount {
+		resultChannel := make(chan map[int]error)
+		go func(tasks []bulkModifyTask) {
+			taskErrors := make(map[int]error)
+			for _, task := range tasks {
+				taskErrors[task.Index] = api.bulkModifyTask(userID, task.Task, params)
+			}
+			resultChannel <- taskErrors
+		}(tasks)
+		resultChannels = append(resultChannels, resultChannel)
+	}
+	for _, resultChannel := range resultChannels {
+		for index, err := range <-resultChannel {
+			if err != nil {
+				results[index].Error = err.Error()
+			} else {
+				results[index].Success = true
+			}
+		}
+	}
+	c.JSON(200, results)
+}
+
+// bulkModifyTask applies the changes to a single task, returning an error which can be shown to the user
+func (api *API) bulkModifyTask(userID primitive.ObjectID, task *database.Task, params TaskBulkModifyParams) error {
+	// validation modifies the fields, so each task needs its own copy
+	updateFields := params.TaskItemChangeableFields
+	if updateFields.TimeAllocation != nil {
+		timeAllocation := *updateFields.TimeAllocation
+		updateFields.TimeAllocation = &timeAllocation
+	}
+
+	if updateFields != (TaskItemChangeableFields{}) {
+		taskSourceResult, err := api.ExternalConfig.GetSourceResult(task.SourceID)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to load external task source")
+			return errors.New("failed to load task source")
+		}
+		err = validateTaskChangeableFields(&updateFields, taskSourceResult, task)
+		if err != nil {
+			return err
+		}
+		updateTask, err := api.getTaskUpdate(userID, task, &updateFields)
+		if err != nil {
+			return err
+		}
+		err = taskSourceResult.Source.ModifyTask(api.DB, userID, task.SourceAccountID, task.IDExternal, updateTask, task)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to update external task source")
+			return errors.New("failed to update external task source")
+		}
+		err = api.UpdateTaskInDBWithError(task, userID, updateTask)
+		if err != nil {
+			return errors.New("failed to update task")
+		}
+		database.RecordTaskHistory(api.DB, userID, task, updateTask, database.TaskHistoryActorUser)
+		if updateTask.IsCompleted != nil && *updateTask.IsCompleted {
+			err = database.UnblockDependentTasks(api.DB, userID, task.ID)
+			if err != nil {
+				api.Logger.Error().Err(err).Msg("failed to unblock dependent tasks")
+				return errors.New("failed to update dependent tasks")
+			}
+		}
+	}
+
+	// subtasks stay with their parent task
+	if params.IDTaskSection != nil && task.ParentTaskID == primitive.NilObjectID {
+		IDTaskSection, _ := primitive.ObjectIDFromHex(*params.IDTaskSection)
+		_, err := database.GetTaskCollection(api.DB).UpdateOne(
+			context.Background(),
+			bson.M{"$and": []bson.M{
+				{"_id": task.ID},
+				{"user_id": userID},
+			}},
+			bson.M{"$set": bson.M{"id_task_section": IDTaskSection, "has_been_reordered": true}},
+		)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to move task to section")
+			return errors.New("failed to move task")
+		}
+		database.RecordTaskHistory(api.DB, userID, task, &database.Task{IDTaskSection: IDTaskSection}, database.TaskHistoryActorUser)
+	}
+
+	if params.CascadeToSubtasks {
+		err := api.cascadeToSubtasks(userID, task, &updateFields, params.IDTaskSection)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to update subtasks")
+			return errors.New("failed to update subtasks")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskBulkModify(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_bulk_modify@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	insertTask := func(sourceID string) primitive.ObjectID {
+		notCompleted := false
+		title := "triage me"
+		insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:      userID,
+			SourceID:    sourceID,
+			Title:       &title,
+			IsCompleted: &notCompleted,
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	bulkModify := func(payload string) (int, []byte) {
+		request, _ := http.NewRequest("PATCH", "/tasks/bulk_modify/", bytes.NewBuffer([]byte(payload)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, body
+	}
+
+	UnauthorizedTest(t, "PATCH", "/tasks/bulk_modify/", nil)
+	t.Run("MissingTaskIDs", func(t *testing.T) {
+		code, body := bulkModify(`{"is_completed": true}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"invalid or missing 'task_ids' parameter"}`, string(body))
+	})
+	t.Run("MissingChanges", func(t *testing.T) {
+		code, body := bulkModify(`{"task_ids": ["` + insertTask(external.TASK_SOURCE_ID_GT_TASK).Hex() + `"]}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"task changes missing"}`, string(body))
+	})
+	t.Run("InvalidSection", func(t *testing.T) {
+		code, body := bulkModify(`{"task_ids": ["` + insertTask(external.TASK_SOURCE_ID_GT_TASK).Hex() + `"], "id_task_section": "uhoh"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"'id_task_section' is not a valid ID"}`, string(body))
+	})
+	t.Run("PartialFailure", func(t *testing.T) {
+		taskID := insertTask(external.TASK_SOURCE_ID_GT_TASK)
+		secondTaskID := insertTask(external.TASK_SOURCE_ID_GT_TASK)
+		// calendar events cannot be marked done
+		calendarTaskID := insertTask(external.TASK_SOURCE_ID_GCAL)
+		missingTaskID := primitive.NewObjectID()
+		sectionID := primitive.NewObjectID()
+
+		code, body := bulkModify(`{"task_ids": ["` + taskID.Hex() + `", "` + calendarTaskID.Hex() + `", "` + missingTaskID.Hex() + `", "` + secondTaskID.Hex() + `"], "is_completed": true, "time_duration": 60, "id_task_section": "` + sectionID.Hex() + `"}`)
+		assert.Equal(t, http.StatusOK, code)
+		var results []TaskBulkModifyResult
+		assert.NoError(t, json.Unmarshal(body, &results))
+		assert.Equal(t, []TaskBulkModifyResult{
+			{TaskID: taskID.Hex(), Success: true},
+			{TaskID: calendarTaskID.Hex(), Error: "cannot be marked done"},
+			{TaskID: missingTaskID.Hex(), Error: "task not found"},
+			{TaskID: secondTaskID.Hex(), Success: true},
+		}, results)
+
+		for _, id := range []primitive.ObjectID{taskID, secondTaskID} {
+			task, err := database.GetTask(api.DB, id, userID)
+			assert.NoError(t, err)
+			assert.True(t, *task.IsCompleted)
+			assert.Equal(t, sectionID, task.IDTaskSection)
+			// each task gets the time allocation converted once
+			assert.Equal(t, int64(60_000_000_000), *task.TimeAllocation)
+		}
+		task, err := database.GetTask(api.DB, calendarTaskID, userID)
+		assert.NoError(t, err)
+		assert.False(t, *task.IsCompleted)
+	})
+}
@@ -1,13 +1,21 @@
 package api
 
 import (
+	"context"
+	"time"
+
 	"github.com/GeneralTask/task-manager/backend/database"
 	"github.com/GeneralTask/task-manager/backend/external"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+type CommentModifyParams struct {
+	Body string `json:"body" binding:"required"`
+}
+
 func (api *API) TaskAddComment(c *gin.Context) {
 	taskIDHex := c.Param("task_id")
 	taskID, err := primitive.ObjectIDFromHex(taskIDHex)
@@ -47,6 +55,13 @@ func (api *API) TaskAddComment(c *gin.Context) {
 	if task.SourceID == external.TASK_SOURCE_ID_LINEAR {
 		commentParams.ExternalID = uuid.New().String()
 	}
+	if task.SourceID == external.TASK_SOURCE_ID_GT_TASK {
+		commentParams, err = api.getGeneralTaskComment(userID, commentParams.Body)
+		if err != nil {
+			Handle500(c)
+			return
+		}
+	}
 
 	err = taskSourceResult.Source.AddComment(api.DB, userID, task.SourceAccountID, commentParams, task)
 	if err != nil {
@@ -68,3 +83,128 @@ func (api *API) TaskAddComment(c *gin.Context) {
 	api.UpdateTaskInDB(c, task, userID, &updateTask)
 	c.JSON(200, gin.H{})
 }
+
+func (api *API) TaskModifyComment(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	task, comment, ok := api.getGeneralTaskCommentWithTask(c, userID)
+	if !ok {
+		return
+	}
+	if comment.User.ExternalID != userID.Hex() {
+		c.JSON(403, gin.H{"detail": "comments can only be modified by their author"})
+		return
+	}
+
+	var modifyParams CommentModifyParams
+	err := c.BindJSON(&modifyParams)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "parameter missing or malformatted"})
+		return
+	}
+
+	result, err := database.GetTaskCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": task.ID},
+			{"user_id": task.UserID},
+			{"comments": bson.M{"$elemMatch": bson.M{"external_id": comment.ExternalID, "user.external_id": userID.Hex()}}},
+		}},
+		bson.M{"$set": bson.M{"comments.$.body": modifyParams.Body}},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to modify comment")
+		Handle500(c)
+		return
+	}
+	if result.MatchedCount != 1 {
+		c.JSON(404, gin.H{"detail": "comment not found.", "commentId": comment.ExternalID})
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+func (api *API) TaskDeleteComment(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	task, comment, ok := api.getGeneralTaskCommentWithTask(c, userID)
+	if !ok {
+		return
+	}
+	// the owner of a task can remove any comment on it, while other users can only remove their own
+	if comment.User.ExternalID != userID.Hex() && task.UserID != userID {
+		c.JSON(403, gin.H{"detail": "comments can only be deleted by their author or the task owner"})
+		return
+	}
+
+	result, err := database.GetTaskCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": task.ID},
+			{"user_id": task.UserID},
+			{"comments.external_id": comment.ExternalID},
+		}},
+		bson.M{"$pull": bson.M{"comments": bson.M{"external_id": comment.ExternalID}}},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to delete comment")
+		Handle500(c)
+		return
+	}
+	if result.MatchedCount != 1 {
+		c.JSON(404, gin.H{"detail": "comment not found.", "commentId": comment.ExternalID})
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+// comments can only be changed on general task tasks where the comments are not synced with an external source.
+// besides the task owner, users who can comment on a shared task can also access its comments
+func (api *API) getGeneralTaskCommentWithTask(c *gin.Context, userID primitive.ObjectID) (*database.Task, *database.Comment, bool) {
+	taskID, err := primitive.ObjectIDFromHex(c.Param("task_id"))
+	if err != nil {
+		// This means the task ID is improperly formatted
+		Handle404(c)
+		return nil, nil, false
+	}
+	task, err := database.GetTask(api.DB, taskID, userID)
+	if err != nil {
+		task, err = database.GetSharedTask(api.DB, taskID, &userID)
+	}
+	if err != nil || task == nil {
+		c.JSON(404, gin.H{"detail": "task not found.", "taskId": taskID})
+		return nil, nil, false
+	}
+	if task.SourceID != external.TASK_SOURCE_ID_GT_TASK {
+		c.JSON(400, gin.H{"detail": "comments can only be modified on general task tasks"})
+		return nil, nil, false
+	}
+
+	commentID := c.Param("comment_id")
+	if task.Comments != nil {
+		for _, comment := range *task.Comments {
+			if comment.ExternalID == commentID {
+				return task, &comment, true
+			}
+		}
+	}
+	c.JSON(404, gin.H{"detail": "comment not found.", "commentId": commentID})
+	return nil, nil, false
+}
+
+func (api *API) getGeneralTaskComment(userID primitive.ObjectID, body string) (database.Comment, error) {
+	user, err := database.GetUser(api.DB, userID)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to find user")
+		return database.Comment{}, err
+	}
+	return database.Comment{
+		ExternalID: uuid.New().String(),
+		Body:       body,
+		User: database.ExternalUser{
+			ExternalID:  userID.Hex(),
+			Name:        user.Name,
+			DisplayName: user.Name,
+			Email:       user.Email,
+		},
+		CreatedAt: primitive.NewDateTimeFromTime(time.Now()),
+	}, nil
+}
@@ -244,4 +244,192 @@ func TestTaskAddComment(t *testing.T) {
 
 		assert.Equal(t, "Hello there!", (*task.Comments)[1].Body)
 	})
+	t.Run("AddCommentGeneralTaskSuccess", func(t *testing.T) {
+		authToken := login("gt_comment@generaltask.com", "GT Commenter")
+		userID := getUserIDFromAuthToken(t, db, authToken)
+
+		expectedTask := sampleTask
+		expectedTask.SourceID = external.TASK_SOURCE_ID_GT_TASK
+		expectedTask.UserID = userID
+		insertResult, err := taskCollection.InsertOne(
+			context.Background(),
+			expectedTask,
+		)
+		assert.NoError(t, err)
+		insertedTaskID := insertResult.InsertedID.(primitive.ObjectID)
+
+		ServeRequest(t, authToken, "POST", "/tasks/"+insertedTaskID.Hex()+"/comments/add/", bytes.NewBuffer([]byte(`{"body": "Hello there!"}`)), http.StatusOK, api)
+
+		var task database.Task
+		err = taskCollection.FindOne(context.Background(), bson.M{"_id": insertedTaskID}).Decode(&task)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, len(*task.Comments))
+		comment := (*task.Comments)[0]
+		assert.Equal(t, "Hello there!", comment.Body)
+		assert.NotEqual(t, "", comment.ExternalID)
+		assert.Equal(t, userID.Hex(), comment.User.ExternalID)
+		assert.Equal(t, "GT Commenter", comment.User.Name)
+		assert.Equal(t, "gt_comment@generaltask.com", comment.User.Email)
+		assert.NotEqual(t, primitive.DateTime(0), comment.CreatedAt)
+	})
+}
+
+func TestTaskModifyComment(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	taskCollection := database.GetTaskCollection(db)
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+
+	authToken := login("modify_comment@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, db, authToken)
+	insertTask := func(sourceID string) primitive.ObjectID {
+		insertResult, err := taskCollection.InsertOne(context.Background(), database.Task{
+			UserID:   userID,
+			SourceID: sourceID,
+			Comments: &[]database.Comment{
+				{ExternalID: "comment-1", Body: "mine", User: database.ExternalUser{ExternalID: userID.Hex()}},
+				{ExternalID: "comment-2", Body: "theirs", User: database.ExternalUser{ExternalID: primitive.NewObjectID().Hex()}},
+			},
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+
+	t.Run("InvalidTaskID", func(t *testing.T) {
+		ServeRequest(t, authToken, "PATCH", "/tasks/"+primitive.NewObjectID().Hex()+"/comments/comment-1/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusNotFound, api)
+	})
+	t.Run("NotGeneralTask", func(t *testing.T) {
+		taskID := insertTask(external.TASK_SOURCE_ID_LINEAR)
+		ServeRequest(t, authToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-1/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusBadRequest, api)
+	})
+	t.Run("CommentNotFound", func(t *testing.T) {
+		taskID := insertTask(external.TASK_SOURCE_ID_GT_TASK)
+		ServeRequest(t, authToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-3/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusNotFound, api)
+	})
+	t.Run("NotAuthor", func(t *testing.T) {
+		taskID := insertTask(external.TASK_SOURCE_ID_GT_TASK)
+		ServeRequest(t, authToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-2/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusForbidden, api)
+	})
+	t.Run("MissingBody", func(t *testing.T) {
+		taskID := insertTask(external.TASK_SOURCE_ID_GT_TASK)
+		ServeRequest(t, authToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-1/", bytes.NewBuffer([]byte(`{}`)), http.StatusBadRequest, api)
+	})
+	t.Run("Success", func(t *testing.T) {
+		taskID := insertTask(external.TASK_SOURCE_ID_GT_TASK)
+		ServeRequest(t, authToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-1/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusOK, api)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(*task.Comments))
+		assert.Equal(t, "edited", (*task.Comments)[0].Body)
+		assert.Equal(t, "theirs", (*task.Comments)[1].Body)
+	})
+	t.Run("SharedTaskAuthor", func(t *testing.T) {
+		commenterAuthToken := login("modify_comment_commenter@generaltask.com", "")
+		commenterID := getUserIDFromAuthToken(t, db, commenterAuthToken)
+		domainSharedAccess := database.SharedAccessDomain
+		insertResult, err := taskCollection.InsertOne(context.Background(), database.Task{
+			UserID:       userID,
+			SourceID:     external.TASK_SOURCE_ID_GT_TASK,
+			SharedUntil:  primitive.NewDateTimeFromTime(time.Now().Add(time.Hour)),
+			SharedAccess: &domainSharedAccess,
+			Comments: &[]database.Comment{
+				{ExternalID: "comment-1", Body: "owner", User: database.ExternalUser{ExternalID: userID.Hex()}},
+				{ExternalID: "comment-2", Body: "commenter", User: database.ExternalUser{ExternalID: commenterID.Hex()}},
+			},
+		})
+		assert.NoError(t, err)
+		taskID := insertResult.InsertedID.(primitive.ObjectID)
+
+		// the task owner can't edit the comments of other users
+		ServeRequest(t, authToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-2/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusForbidden, api)
+		ServeRequest(t, commenterAuthToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-1/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusForbidden, api)
+		ServeRequest(t, commenterAuthToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-2/", bytes.NewBuffer([]byte(`{"body": "edited"}`)), http.StatusOK, api)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "owner", (*task.Comments)[0].Body)
+		assert.Equal(t, "edited", (*task.Comments)[1].Body)
+
+		// comments can't be changed once the task is no longer shared
+		_, err = taskCollection.UpdateOne(context.Background(), bson.M{"_id": taskID}, bson.M{"$unset": bson.M{"shared_access": ""}})
+		assert.NoError(t, err)
+		ServeRequest(t, commenterAuthToken, "PATCH", "/tasks/"+taskID.Hex()+"/comments/comment-2/", bytes.NewBuffer([]byte(`{"body": "edited again"}`)), http.StatusNotFound, api)
+	})
+}
+
+func TestTaskDeleteComment(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	taskCollection := database.GetTaskCollection(db)
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+
+	authToken := login("delete_comment@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, db, authToken)
+	insertResult, err := taskCollection.InsertOne(context.Background(), database.Task{
+		UserID:   userID,
+		SourceID: external.TASK_SOURCE_ID_GT_TASK,
+		Comments: &[]database.Comment{
+			{ExternalID: "comment-1", Body: "first", User: database.ExternalUser{ExternalID: userID.Hex()}},
+			{ExternalID: "comment-2", Body: "theirs", User: database.ExternalUser{ExternalID: primitive.NewObjectID().Hex()}},
+			{ExternalID: "comment-3", Body: "last", User: database.ExternalUser{ExternalID: userID.Hex()}},
+		},
+	})
+	assert.NoError(t, err)
+	taskID := insertResult.InsertedID.(primitive.ObjectID)
+
+	t.Run("NotTaskOwner", func(t *testing.T) {
+		otherAuthToken := login("delete_comment_other@generaltask.com", "")
+		ServeRequest(t, otherAuthToken, "DELETE", "/tasks/"+taskID.Hex()+"/comments/comment-1/", nil, http.StatusNotFound, api)
+	})
+	t.Run("CommentNotFound", func(t *testing.T) {
+		ServeRequest(t, authToken, "DELETE", "/tasks/"+taskID.Hex()+"/comments/comment-4/", nil, http.StatusNotFound, api)
+	})
+	t.Run("Success", func(t *testing.T) {
+		ServeRequest(t, authToken, "DELETE", "/tasks/"+taskID.Hex()+"/comments/comment-1/", nil, http.StatusOK, api)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(*task.Comments))
+		assert.Equal(t, "comment-2", (*task.Comments)[0].ExternalID)
+		assert.Equal(t, "comment-3", (*task.Comments)[1].ExternalID)
+	})
+	t.Run("TaskOwnerDeletesOtherAuthor", func(t *testing.T) {
+		ServeRequest(t, authToken, "DELETE", "/tasks/"+taskID.Hex()+"/comments/comment-2/", nil, http.StatusOK, api)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(*task.Comments))
+		assert.Equal(t, "comment-3", (*task.Comments)[0].ExternalID)
+	})
+	t.Run("SharedTaskAuthor", func(t *testing.T) {
+		commenterAuthToken := login("delete_comment_commenter@generaltask.com", "")
+		commenterID := getUserIDFromAuthToken(t, db, commenterAuthToken)
+		domainSharedAccess := database.SharedAccessDomain
+		insertResult, err := taskCollection.InsertOne(context.Background(), database.Task{
+			UserID:       userID,
+			SourceID:     external.TASK_SOURCE_ID_GT_TASK,
+			SharedUntil:  primitive.NewDateTimeFromTime(time.Now().Add(time.Hour)),
+			SharedAccess: &domainSharedAccess,
+			Comments: &[]database.Comment{
+				{ExternalID: "comment-1", Body: "owner", User: database.ExternalUser{ExternalID: userID.Hex()}},
+				{ExternalID: "comment-2", Body: "commenter", User: database.ExternalUser{ExternalID: commenterID.Hex()}},
+			},
+		})
+		assert.NoError(t, err)
+		sharedTaskID := insertResult.InsertedID.(primitive.ObjectID)
+
+		ServeRequest(t, commenterAuthToken, "DELETE", "/tasks/"+sharedTaskID.Hex()+"/comments/comment-1/", nil, http.StatusForbidden, api)
+		ServeRequest(t, commenterAuthToken, "DELETE", "/tasks/"+sharedTaskID.Hex()+"/comments/comment-2/", nil, http.StatusOK, api)
+
+		task, err := database.GetTask(db, sharedTaskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(*task.Comments))
+		assert.Equal(t, "comment-1", (*task.Comments)[0].ExternalID)
+	})
 }
@@ -15,13 +15,20 @@ import (
 )
 
 type TaskCreateParams struct {
-	AccountID     string     `json:"account_id"`
-	Title         string     `json:"title" binding:"required"`
-	Body          string     `json:"body"`
-	DueDate       *time.Time `json:"due_date"`
-	TimeDuration  *int       `json:"time_duration"`
-	IDTaskSection *string    `json:"id_task_section"`
-	ParentTaskID  *string    `json:"parent_task_id"`
+	AccountID          string     `json:"account_id"`
+	Title              string     `json:"title" binding:"required"`
+	Body               string     `json:"body"`
+	DueDate            *time.Time `json:"due_date"`
+	TimeDuration       *int       `json:"time_duration"`
+	IDTaskSection      *string    `json:"id_task_section"`
+	ParentTaskID       *string    `json:"parent_task_id"`
+	PriorityNormalized *float64   `json:"priority_normalized"`
+	LinearTeamID       *string    `json:"linear_team_id"`
+	LinearStatusID     *string    `json:"linear_status_id"`
+	JIRAProjectKey     *string    `json:"jira_project_key"`
+	JIRAIssueType      *string    `json:"jira_issue_type"`
+	AsanaProjectID     *string    `json:"asana_project_id"`
+	GithubRepositoryID *string    `json:"github_repository_id"`
 }
 
 func (api *API) TaskCreate(c *gin.Context) {
@@ -51,12 +58,17 @@ func (api *API) TaskCreate(c *gin.Context) {
 	}
 
 	if sourceID != external.TASK_SOURCE_ID_GT_TASK {
+		serviceID, err := api.ExternalConfig.GetServiceIDForSource(sourceID)
+		if err != nil {
+			Handle404(c)
+			return
+		}
 		externalAPICollection := database.GetExternalTokenCollection(api.DB)
 		count, err := externalAPICollection.CountDocuments(
 			context.Background(),
 			bson.M{"$and": []bson.M{
 				{"account_id": taskCreateParams.AccountID},
-				{"source_id": sourceID},
+				{"service_id": serviceID},
 				{"user_id": userID},
 			}},
 		)
@@ -93,12 +105,31 @@ func (api *API) TaskCreate(c *gin.Context) {
 	}
 
 	taskCreationObject := external.TaskCreationObject{
-		Title:          taskCreateParams.Title,
-		Body:           taskCreateParams.Body,
-		DueDate:        taskCreateParams.DueDate,
-		TimeAllocation: timeAllocation,
-		IDTaskSection:  IDTaskSection,
-		ParentTaskID:   parentID,
+		Title:              taskCreateParams.Title,
+		Body:               taskCreateParams.Body,
+		DueDate:            taskCreateParams.DueDate,
+		TimeAllocation:     timeAllocation,
+		IDTaskSection:      IDTaskSection,
+		ParentTaskID:       parentID,
+		PriorityNormalized: taskCreateParams.PriorityNormalized,
+	}
+	if taskCreateParams.LinearTeamID != nil {
+		taskCreationObject.LinearTeamID = *taskCreateParams.LinearTeamID
+	}
+	if taskCreateParams.LinearStatusID != nil {
+		taskCreationObject.LinearStatusID = *taskCreateParams.LinearStatusID
+	}
+	if taskCreateParams.JIRAProjectKey != nil {
+		taskCreationObject.JIRAProjectKey = *taskCreateParams.JIRAProjectKey
+	}
+	if taskCreateParams.JIRAIssueType != nil {
+		taskCreationObject.JIRAIssueType = *taskCreateParams.JIRAIssueType
+	}
+	if taskCreateParams.AsanaProjectID != nil {
+		taskCreationObject.AsanaProjectID = *taskCreateParams.AsanaProjectID
+	}
+	if taskCreateParams.GithubRepositoryID != nil {
+		taskCreationObject.GithubRepositoryID = *taskCreateParams.GithubRepositoryID
 	}
 	taskID, err := taskSourceResult.Source.CreateNewTask(api.DB, userID, taskCreateParams.AccountID, taskCreationObject)
 	if err != nil {
@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TaskDependencyParams struct {
+	BlockedByTaskID string `json:"blocked_by_task_id" binding:"required"`
+}
+
+func (api *API) TaskAddDependency(c *gin.Context) {
+	taskID, err := primitive.ObjectIDFromHex(c.Param("task_id"))
+	if err != nil {
+		// This means the task ID is improperly formatted
+		Handle404(c)
+		return
+	}
+	var params TaskDependencyParams
+	err = c.BindJSON(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing 'blocked_by_task_id' parameter"})
+		return
+	}
+	blockedByTaskID, err := primitive.ObjectIDFromHex(params.BlockedByTaskID)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing 'blocked_by_task_id' parameter"})
+		return
+	}
+	if blockedByTaskID == taskID {
+		c.JSON(400, gin.H{"detail": "task cannot block itself"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	_, err = database.GetTask(api.DB, taskID, userID)
+	if err != nil {
+		c.JSON(404, gin.H{"detail": "task not found.", "taskId": taskID})
+		return
+	}
+	blockingTask, err := database.GetTask(api.DB, blockedByTaskID, userID)
+	if err != nil {
+		c.JSON(404, gin.H{"detail": "blocking task not found.", "taskId": blockedByTaskID})
+		return
+	}
+	if blockingTask.IsCompleted != nil && *blockingTask.IsCompleted {
+		c.JSON(400, gin.H{"detail": "blocking task is already complete"})
+		return
+	}
+
+	dependencies, err := api.getTaskDependencies(userID)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	if hasTaskDependencyCycle(dependencies, taskID, blockedByTaskID) {
+		c.JSON(400, gin.H{"detail": "dependency would create a cycle"})
+		return
+	}
+
+	_, err = database.GetTaskCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": taskID},
+			{"user_id": userID},
+		}},
+		bson.M{"$addToSet": bson.M{"blocked_by_task_ids": blockedByTaskID}},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to add task dependency")
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+func (api *API) TaskDeleteDependency(c *gin.Context) {
+	taskID, err := primitive.ObjectIDFromHex(c.Param("task_id"))
+	if err != nil {
+		// This means the task ID is improperly formatted
+		Handle404(c)
+		return
+	}
+	blockedByTaskID, err := primitive.ObjectIDFromHex(c.Param("blocked_by_task_id"))
+	if err != nil {
+		Handle404(c)
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	result, err := database.GetTaskCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": taskID},
+			{"user_id": userID},
+			{"blocked_by_task_ids": blockedByTaskID},
+		}},
+		bson.M{"$pull": bson.M{"blocked_by_task_ids": blockedByTaskID}},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to delete task dependency")
+		Handle500(c)
+		return
+	}
+	if result.MatchedCount != 1 {
+		c.JSON(404, gin.H{"detail": "dependency not found."})
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+// getTaskDependencies maps each of the user's blocked tasks to the tasks blocking it
+func (api *API) getTaskDependencies(userID primitive.ObjectID) (map[primitive.ObjectID][]primitive.ObjectID, error) {
+	tasks, err := database.GetTasks(
+		api.DB,
+		userID,
+		&[]bson.M{{"blocked_by_task_ids.0": bson.M{"$exists": true}}},
+		options.Find().SetProjection(bson.M{"_id": 1, "blocked_by_task_ids": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := make(map[primitive.ObjectID][]primitive.ObjectID)
+	for _, task := range *tasks {
+		dependencies[task.ID] = task.BlockedByTaskIDs
+	}
+	return dependencies, nil
+}
+
+// hasTaskDependencyCycle checks whether taskID is already a blocker of blockedByTaskID, directly or transitively
+func hasTaskDependencyCycle(dependencies map[primitive.ObjectID][]primitive.ObjectID, taskID primitive.ObjectID, blockedByTaskID primitive.ObjectID) bool {
+	visited := make(map[primitive.ObjectID]bool)
+	toVisit := []primitive.ObjectID{blockedByTaskID}
+	for len(toVisit) > 0 {
+		current := toVisit[len(toVisit)-1]
+		toVisit = toVisit[:len(toVisit)-1]
+		if current == taskID {
+			return true
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		toVisit = append(toVisit, dependencies[current]...)
+	}
+	return false
+}
+
+// setTaskDependencyResults fills in the dependency fields of a single task result
+func (api *API) setTaskDependencyResults(taskResult *TaskResultV4, userID primitive.ObjectID) error {
+	dependents, err := database.GetTasks(
+		api.DB,
+		userID,
+		&[]bson.M{{"blocked_by_task_ids": taskResult.ID}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	for _, dependent := range *dependents {
+		taskResult.BlockingTaskIDs = append(taskResult.BlockingTaskIDs, dependent.ID)
+	}
+	if len(taskResult.BlockedByTaskIDs) == 0 {
+		return nil
+	}
+	blockers, err := database.GetTasks(
+		api.DB,
+		userID,
+		&[]bson.M{
+			{"_id": bson.M{"$in": taskResult.BlockedByTaskIDs}},
+			{"is_completed": bson.M{"$ne": true}},
+			{"is_deleted": bson.M{"$ne": true}},
+		},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	taskResult.IsBlocked = len(*blockers) > 0
+	return nil
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskDependencies(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_task_dependencies@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	createTask := func(sourceID string) primitive.ObjectID {
+		notCompleted := false
+		title := "task"
+		result, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:      userID,
+			SourceID:    sourceID,
+			Title:       &title,
+			IsCompleted: &notCompleted,
+		})
+		assert.NoError(t, err)
+		return result.InsertedID.(primitive.ObjectID)
+	}
+	addDependency := func(taskID primitive.ObjectID, blockedByTaskID string) (int, string) {
+		request, _ := http.NewRequest(
+			"POST",
+			"/tasks/"+taskID.Hex()+"/dependencies/add/",
+			bytes.NewBuffer([]byte(`{"blocked_by_task_id": "`+blockedByTaskID+`"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, string(body)
+	}
+	getTaskResult := func(taskID primitive.ObjectID) TaskResultV4 {
+		request, _ := http.NewRequest("GET", "/tasks/detail/"+taskID.Hex()+"/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var result TaskResultV4
+		err := json.NewDecoder(recorder.Body).Decode(&result)
+		assert.NoError(t, err)
+		return result
+	}
+
+	UnauthorizedTest(t, "POST", "/tasks/"+primitive.NewObjectID().Hex()+"/dependencies/add/", nil)
+	t.Run("InvalidBlockedByTaskID", func(t *testing.T) {
+		code, body := addDependency(createTask(external.TASK_SOURCE_ID_GT_TASK), "uhoh")
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"invalid or missing 'blocked_by_task_id' parameter"}`, body)
+	})
+	t.Run("BlockedBySelf", func(t *testing.T) {
+		taskID := createTask(external.TASK_SOURCE_ID_GT_TASK)
+		code, body := addDependency(taskID, taskID.Hex())
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"task cannot block itself"}`, body)
+	})
+	t.Run("BlockingTaskNotFound", func(t *testing.T) {
+		code, _ := addDependency(createTask(external.TASK_SOURCE_ID_GT_TASK), primitive.NewObjectID().Hex())
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("Cycle", func(t *testing.T) {
+		first := createTask(external.TASK_SOURCE_ID_GT_TASK)
+		second := createTask(external.TASK_SOURCE_ID_GT_TASK)
+		third := createTask(external.TASK_SOURCE_ID_LINEAR)
+		code, _ := addDependency(first, second.Hex())
+		assert.Equal(t, http.StatusOK, code)
+		code, _ = addDependency(second, third.Hex())
+		assert.Equal(t, http.StatusOK, code)
+		code, body := addDependency(third, first.Hex())
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"dependency would create a cycle"}`, body)
+	})
+	t.Run("Success", func(t *testing.T) {
+		taskID := createTask(external.TASK_SOURCE_ID_GT_TASK)
+		blockingTaskID := createTask(external.TASK_SOURCE_ID_LINEAR)
+		code, _ := addDependency(taskID, blockingTaskID.Hex())
+		assert.Equal(t, http.StatusOK, code)
+
+		result := getTaskResult(taskID)
+		assert.True(t, result.IsBlocked)
+		assert.Equal(t, []primitive.ObjectID{blockingTaskID}, result.BlockedByTaskIDs)
+		blockingResult := getTaskResult(blockingTaskID)
+		assert.False(t, blockingResult.IsBlocked)
+		assert.Equal(t, []primitive.ObjectID{taskID}, blockingResult.BlockingTaskIDs)
+
+		request, _ := http.NewRequest("DELETE", "/tasks/"+taskID.Hex()+"/dependencies/"+blockingTaskID.Hex()+"/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.False(t, getTaskResult(taskID).IsBlocked)
+
+		// deleting it again finds nothing
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusNotFound, recorder.Code)
+	})
+	t.Run("CompletingBlockerUnblocks", func(t *testing.T) {
+		taskID := createTask(external.TASK_SOURCE_ID_GT_TASK)
+		blockingTaskID := createTask(external.TASK_SOURCE_ID_GT_TASK)
+		code, _ := addDependency(taskID, blockingTaskID.Hex())
+		assert.Equal(t, http.StatusOK, code)
+
+		request, _ := http.NewRequest("PATCH", "/tasks/modify/"+blockingTaskID.Hex()+"/", bytes.NewBuffer([]byte(`{"is_completed": true}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		task, err := database.GetTask(api.DB, taskID, userID)
+		assert.NoError(t, err)
+		assert.Empty(t, task.BlockedByTaskIDs)
+		assert.False(t, getTaskResult(taskID).IsBlocked)
+	})
+}
+
+func TestHasTaskDependencyCycle(t *testing.T) {
+	first := primitive.NewObjectID()
+	second := primitive.NewObjectID()
+	third := primitive.NewObjectID()
+	dependencies := map[primitive.ObjectID][]primitive.ObjectID{
+		first:  {second},
+		second: {third},
+	}
+	assert.True(t, hasTaskDependencyCycle(dependencies, third, first))
+	assert.True(t, hasTaskDependencyCycle(dependencies, second, first))
+	assert.False(t, hasTaskDependencyCycle(dependencies, first, third))
+	assert.False(t, hasTaskDependencyCycle(dependencies, third, primitive.NewObjectID()))
+}
+
+func TestTaskListToTaskResultListV4Blocked(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	completed := true
+	notCompleted := false
+	doneBlockerID := primitive.NewObjectID()
+	activeBlockerID := primitive.NewObjectID()
+	blockedTaskID := primitive.NewObjectID()
+	unblockedTaskID := primitive.NewObjectID()
+	tasks := []database.Task{
+		{ID: doneBlockerID, SourceID: external.TASK_SOURCE_ID_GT_TASK, IsCompleted: &completed},
+		{ID: activeBlockerID, SourceID: external.TASK_SOURCE_ID_GT_TASK, IsCompleted: &notCompleted},
+		{ID: blockedTaskID, SourceID: external.TASK_SOURCE_ID_GT_TASK, BlockedByTaskIDs: []primitive.ObjectID{doneBlockerID, activeBlockerID}},
+		{ID: unblockedTaskID, SourceID: external.TASK_SOURCE_ID_GT_TASK, BlockedByTaskIDs: []primitive.ObjectID{doneBlockerID}},
+	}
+	results := api.taskListToTaskResultListV4(&tasks)
+	assert.Equal(t, 4, len(results))
+	assert.Equal(t, []primitive.ObjectID{blockedTaskID, unblockedTaskID}, results[0].BlockingTaskIDs)
+	assert.Equal(t, []primitive.ObjectID{blockedTaskID}, results[1].BlockingTaskIDs)
+	assert.True(t, results[2].IsBlocked)
+	assert.False(t, results[3].IsBlocked)
+}
@@ -24,5 +24,11 @@ func (api *API) TaskDetail(c *gin.Context) {
 	}
 
 	taskResult := api.taskToTaskResultV4(task)
+	err = api.setTaskDependencyResults(taskResult, userID)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch task dependencies")
+		Handle500(c)
+		return
+	}
 	c.JSON(200, taskResult)
 }
@@ -22,7 +22,7 @@ func (api *API) TasksFetch(c *gin.Context) {
 		return
 	}
 
-	currentTasks, err := database.GetActiveTasks(api.DB, userID.(primitive.ObjectID))
+	currentTasks, err := database.GetActiveAndSnoozedTasks(api.DB, userID.(primitive.ObjectID))
 	if err != nil {
 		Handle500(c)
 		return
@@ -34,6 +34,10 @@ func (api *API) TasksFetch(c *gin.Context) {
 		Handle500(c)
 		return
 	}
+	err = api.importExternalLabels(userID.(primitive.ObjectID), fetchedTasks)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to import external labels")
+	}
 	_, err = userCollection.UpdateOne(
 		context.Background(),
 		bson.M{"_id": userID},
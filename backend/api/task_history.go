@@ -0,0 +1,64 @@
+package api
+
+import (
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TaskHistoryEventResult struct {
+	ID            primitive.ObjectID `json:"id"`
+	Field         string             `json:"field"`
+	PreviousValue string             `json:"previous_value"`
+	NewValue      string             `json:"new_value"`
+	Actor         string             `json:"actor"`
+	SourceID      string             `json:"source_id"`
+	CreatedAt     string             `json:"created_at"`
+}
+
+// TaskHistory godoc
+// @Summary      Lists the changes made to a task's status, completion, section, due date and priority
+// @Description  Events are ordered from oldest to newest, and the actor is one of user, fetch or webhook
+// @Tags         tasks
+// @Produce      json
+// @Param        task_id  path      string  true  "Task ID"
+// @Success      200 {array}  TaskHistoryEventResult
+// @Failure      404 {object} string "task not found"
+// @Failure      500 {object} string "internal server error"
+// @Router       /tasks/{task_id}/history/ [get]
+func (api *API) TaskHistory(c *gin.Context) {
+	taskID, err := primitive.ObjectIDFromHex(c.Param("task_id"))
+	if err != nil {
+		// This means the task ID is improperly formatted
+		Handle404(c)
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	_, err = database.GetTask(api.DB, taskID, userID)
+	if err != nil {
+		Handle404(c)
+		return
+	}
+
+	events, err := database.GetTaskHistory(api.DB, userID, taskID)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	eventResults := []TaskHistoryEventResult{}
+	for _, event := range *events {
+		eventResults = append(eventResults, TaskHistoryEventResult{
+			ID:            event.ID,
+			Field:         event.Field,
+			PreviousValue: event.PreviousValue,
+			NewValue:      event.NewValue,
+			Actor:         event.Actor,
+			SourceID:      event.SourceID,
+			CreatedAt:     event.CreatedAt.Time().UTC().Format(time.RFC3339),
+		})
+	}
+	c.JSON(200, eventResults)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskHistory(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_task_history@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	notCompleted := false
+	title := "history task"
+	insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+		UserID:      userID,
+		SourceID:    external.TASK_SOURCE_ID_GT_TASK,
+		Title:       &title,
+		IsCompleted: &notCompleted,
+	})
+	assert.NoError(t, err)
+	taskID := insertResult.InsertedID.(primitive.ObjectID)
+
+	getHistory := func(taskIDHex string) (int, []TaskHistoryEventResult) {
+		request, _ := http.NewRequest("GET", "/tasks/"+taskIDHex+"/history/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		var events []TaskHistoryEventResult
+		if recorder.Code == http.StatusOK {
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&events))
+		}
+		return recorder.Code, events
+	}
+
+	UnauthorizedTest(t, "GET", "/tasks/"+taskID.Hex()+"/history/", nil)
+	t.Run("TaskNotFound", func(t *testing.T) {
+		code, _ := getHistory(primitive.NewObjectID().Hex())
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("NoHistory", func(t *testing.T) {
+		code, events := getHistory(taskID.Hex())
+		assert.Equal(t, http.StatusOK, code)
+		assert.Empty(t, events)
+	})
+	t.Run("Success", func(t *testing.T) {
+		sectionID := primitive.NewObjectID()
+		request, _ := http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(`{"due_date": "2023-03-01", "id_task_section": "`+sectionID.Hex()+`"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		request, _ = http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(`{"is_completed": true}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+
+		code, events := getHistory(taskID.Hex())
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 3, len(events))
+		assert.Equal(t, "due_date", events[0].Field)
+		assert.Equal(t, "2023-03-01T00:00:00Z", events[0].NewValue)
+		assert.Equal(t, "id_task_section", events[1].Field)
+		assert.Equal(t, sectionID.Hex(), events[1].NewValue)
+		assert.Equal(t, "is_completed", events[2].Field)
+		assert.Equal(t, "false", events[2].PreviousValue)
+		assert.Equal(t, "true", events[2].NewValue)
+		for _, event := range events {
+			assert.Equal(t, database.TaskHistoryActorUser, event.Actor)
+		}
+	})
+}
@@ -0,0 +1,649 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	TaskImportFormatCSV     = "csv"
+	TaskImportFormatJSON    = "json"
+	TaskImportFormatTodoTxt = "todotxt"
+	TaskImportMaxRows       = 500
+)
+
+// fields which a CSV column can be mapped to
+var taskImportFields = []string{"title", "body", "due_date", "priority", "section", "labels", "parent", "is_completed"}
+
+var todoTxtPriorityRegex = regexp.MustCompile(`^\(([A-Z])\)\s+`)
+var todoTxtDateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}\s+`)
+
+type TaskImportParams struct {
+	Format  string `json:"format" binding:"required"`
+	Content string `json:"content" binding:"required"`
+	// maps an import field (e.g. "due_date") to the CSV header of the column holding it
+	ColumnMapping map[string]string `json:"column_mapping"`
+	DryRun        bool              `json:"dry_run"`
+}
+
+type TaskImportRowResult struct {
+	Row                int        `json:"row"`
+	ParentRow          int        `json:"parent_row,omitempty"`
+	Title              string     `json:"title"`
+	DueDate            *time.Time `json:"due_date,omitempty"`
+	PriorityNormalized *float64   `json:"priority_normalized,omitempty"`
+	IsCompleted        bool       `json:"is_completed,omitempty"`
+	Section            string     `json:"section,omitempty"`
+	Labels             []string   `json:"labels,omitempty"`
+	TaskID             string     `json:"task_id,omitempty"`
+	Error              string     `json:"error,omitempty"`
+}
+
+type TaskImportResult struct {
+	DryRun bool `json:"dry_run"`
+	// on a dry run, the number of tasks which would be created
+	CreatedCount int                   `json:"created_count"`
+	ErrorCount   int                   `json:"error_count"`
+	NewSections  []string              `json:"new_sections"`
+	NewLabels    []string              `json:"new_labels"`
+	Rows         []TaskImportRowResult `json:"rows"`
+}
+
+type taskImportJSONItem struct {
+	Title       string               `json:"title"`
+	Body        string               `json:"body"`
+	DueDate     string               `json:"due_date"`
+	Priority    json.RawMessage      `json:"priority"`
+	Section     string               `json:"section"`
+	Labels      []string             `json:"labels"`
+	IsCompleted bool                 `json:"is_completed"`
+	Subtasks    []taskImportJSONItem `json:"subtasks"`
+}
+
+type taskImportRow struct {
+	Row                int
+	ParentRow          int
+	Title              string
+	Body               string
+	DueDate            *time.Time
+	PriorityNormalized *float64
+	IsCompleted        bool
+	Section            string
+	Labels             []string
+	TaskID             primitive.ObjectID
+	Error              string
+}
+
+func (api *API) TaskImport(c *gin.Context) {
+	var params TaskImportParams
+	err := c.BindJSON(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing parameter"})
+		return
+	}
+	rows, err := parseTaskImport(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": err.Error()})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	sectionIDs, newSections, err := api.getTaskImportSectionIDs(userID, rows, params.DryRun)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to load sections for import")
+		Handle500(c)
+		return
+	}
+	labelIDs, newLabels, err := api.getTaskImportLabelIDs(userID, rows, params.DryRun)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to load labels for import")
+		Handle500(c)
+		return
+	}
+	if !params.DryRun {
+		err = api.createImportedTasks(userID, rows, sectionIDs, labelIDs)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to create imported tasks")
+			Handle500(c)
+			return
+		}
+	}
+
+	result := TaskImportResult{
+		DryRun:      params.DryRun,
+		NewSections: newSections,
+		NewLabels:   newLabels,
+		Rows:        []TaskImportRowResult{},
+	}
+	for _, row := range rows {
+		rowResult := TaskImportRowResult{
+			Row:                row.Row,
+			ParentRow:          row.ParentRow,
+			Title:              row.Title,
+			DueDate:            row.DueDate,
+			PriorityNormalized: row.PriorityNormalized,
+			IsCompleted:        row.IsCompleted,
+			Section:            row.Section,
+			Labels:             row.Labels,
+			Error:              row.Error,
+		}
+		if row.TaskID != primitive.NilObjectID {
+			rowResult.TaskID = row.TaskID.Hex()
+		}
+		if row.Error != "" {
+			result.ErrorCount++
+		} else {
+			result.CreatedCount++
+		}
+		result.Rows = append(result.Rows, rowResult)
+	}
+	c.JSON(200, result)
+}
+
+// createImportedTasks creates the rows without errors as General Task tasks, so that parents exist before their subtasks.
+// Imported tasks are moved to the front of their section, keeping the order they had in the import.
+func (api *API) createImportedTasks(userID primitive.ObjectID, rows []taskImportRow, sectionIDs map[string]primitive.ObjectID, labelIDs map[string]primitive.ObjectID) error {
+	taskCollection := database.GetTaskCollection(api.DB)
+	now := primitive.NewDateTimeFromTime(time.Now())
+	timeAllocation := time.Hour.Nanoseconds()
+	notDeleted := false
+	tasks := []interface{}{}
+	taskIDsByRow := map[int]primitive.ObjectID{}
+	// tasks are ordered within their parent for subtasks, or within their section otherwise
+	orderingCounts := map[primitive.ObjectID]int{}
+	sectionTaskIDs := map[primitive.ObjectID][]primitive.ObjectID{}
+	for index := range rows {
+		row := &rows[index]
+		if row.Error != "" {
+			continue
+		}
+		parentID := primitive.NilObjectID
+		if row.ParentRow != 0 {
+			var ok bool
+			parentID, ok = taskIDsByRow[row.ParentRow]
+			if !ok {
+				row.Error = fmt.Sprintf("parent row %d failed to import", row.ParentRow)
+				continue
+			}
+		}
+		IDTaskSection := getTaskImportID(sectionIDs, row.Section, constants.IDTaskSectionDefault)
+		taskID := primitive.NewObjectID()
+		row.TaskID = taskID
+		taskIDsByRow[row.Row] = taskID
+
+		orderingKey := IDTaskSection
+		if parentID != primitive.NilObjectID {
+			orderingKey = parentID
+		} else {
+			sectionTaskIDs[IDTaskSection] = append(sectionTaskIDs[IDTaskSection], taskID)
+		}
+		orderingCounts[orderingKey]++
+
+		isCompleted := row.IsCompleted
+		task := database.Task{
+			ID:                 taskID,
+			UserID:             userID,
+			IDExternal:         primitive.NewObjectID().Hex(),
+			IDOrdering:         orderingCounts[orderingKey],
+			IDTaskSection:      IDTaskSection,
+			ParentTaskID:       parentID,
+			SourceID:           external.TASK_SOURCE_ID_GT_TASK,
+			SourceAccountID:    external.GeneralTaskDefaultAccountID,
+			Title:              &row.Title,
+			Body:               &row.Body,
+			SearchBody:         &row.Body,
+			TimeAllocation:     &timeAllocation,
+			PriorityNormalized: row.PriorityNormalized,
+			HasBeenReordered:   true,
+			IsCompleted:        &isCompleted,
+			IsDeleted:          &notDeleted,
+			CreatedAtExternal:  now,
+			UpdatedAt:          now,
+		}
+		if row.DueDate != nil {
+			dueDate := primitive.NewDateTimeFromTime(*row.DueDate)
+			task.DueDate = &dueDate
+		}
+		if len(row.Labels) > 0 {
+			taskLabelIDs := []primitive.ObjectID{}
+			for _, label := range row.Labels {
+				taskLabelIDs = append(taskLabelIDs, getTaskImportID(labelIDs, label, primitive.NilObjectID))
+			}
+			task.LabelIDs = &taskLabelIDs
+		}
+		if row.IsCompleted {
+			task.CompletedAt = now
+		}
+		tasks = append(tasks, task)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+	_, err := taskCollection.InsertMany(context.Background(), tasks)
+	if err != nil {
+		return err
+	}
+
+	// make room at the front of each section for the imported tasks
+	for IDTaskSection, taskIDs := range sectionTaskIDs {
+		_, err := taskCollection.UpdateMany(
+			context.Background(),
+			bson.M{"$and": []bson.M{
+				{"user_id": userID},
+				{"id_task_section": IDTaskSection},
+				{"_id": bson.M{"$nin": taskIDs}},
+				{"is_deleted": bson.M{"$ne": true}},
+			}},
+			bson.M{"$inc": bson.M{"id_ordering": len(taskIDs)}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTaskImportSectionIDs matches the sections named in the import to the user's sections by name, creating any which are missing.
+// On a dry run, missing sections are only returned by name.
+func (api *API) getTaskImportSectionIDs(userID primitive.ObjectID, rows []taskImportRow, dryRun bool) (map[string]primitive.ObjectID, []string, error) {
+	sections, err := database.GetTaskSections(api.DB, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	sectionIDs := map[string]primitive.ObjectID{
+		strings.ToLower(constants.TaskSectionNameDefault): constants.IDTaskSectionDefault,
+	}
+	for _, section := range *sections {
+		sectionIDs[strings.ToLower(section.Name)] = section.ID
+	}
+	newSections := []string{}
+	for _, row := range rows {
+		if row.Error != "" || row.Section == "" {
+			continue
+		}
+		if _, ok := sectionIDs[strings.ToLower(row.Section)]; ok {
+			continue
+		}
+		newSections = append(newSections, row.Section)
+		sectionIDs[strings.ToLower(row.Section)] = primitive.NilObjectID
+		if dryRun {
+			continue
+		}
+		insertResult, err := database.GetTaskSectionCollection(api.DB).InsertOne(context.Background(), &database.TaskSection{
+			UserID: userID,
+			Name:   row.Section,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		sectionIDs[strings.ToLower(row.Section)] = insertResult.InsertedID.(primitive.ObjectID)
+	}
+	return sectionIDs, newSections, nil
+}
+
+// getTaskImportLabelIDs matches the labels named in the import to the user's labels by name, creating any which are missing.
+// On a dry run, missing labels are only returned by name.
+func (api *API) getTaskImportLabelIDs(userID primitive.ObjectID, rows []taskImportRow, dryRun bool) (map[string]primitive.ObjectID, []string, error) {
+	labels, err := database.GetLabels(api.DB, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	labelIDs := map[string]primitive.ObjectID{}
+	for _, label := range *labels {
+		labelIDs[strings.ToLower(label.Name)] = label.ID
+	}
+	newLabels := []string{}
+	for _, row := range rows {
+		if row.Error != "" {
+			continue
+		}
+		for _, label := range row.Labels {
+			if _, ok := labelIDs[strings.ToLower(label)]; ok {
+				continue
+			}
+			newLabels = append(newLabels, label)
+			labelIDs[strings.ToLower(label)] = primitive.NilObjectID
+			if dryRun {
+				continue
+			}
+			insertResult, err := database.GetLabelCollection(api.DB).InsertOne(context.Background(), &database.Label{
+				UserID: userID,
+				Name:   label,
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+			labelIDs[strings.ToLower(label)] = insertResult.InsertedID.(primitive.ObjectID)
+		}
+	}
+	return labelIDs, newLabels, nil
+}
+
+func getTaskImportID(IDs map[string]primitive.ObjectID, name string, defaultID primitive.ObjectID) primitive.ObjectID {
+	if ID, ok := IDs[strings.ToLower(name)]; ok {
+		return ID
+	}
+	return defaultID
+}
+
+// parseTaskImport parses the import content into rows, with subtasks always following their parent.
+// Problems with a single row are recorded on the row, while an error is returned only if the content can't be imported at all.
+func parseTaskImport(params *TaskImportParams) ([]taskImportRow, error) {
+	var rows []taskImportRow
+	var err error
+	switch params.Format {
+	case TaskImportFormatCSV:
+		rows, err = parseCSVTaskImport(params.Content, params.ColumnMapping)
+	case TaskImportFormatJSON:
+		rows, err = parseJSONTaskImport(params.Content)
+	case TaskImportFormatTodoTxt:
+		rows = parseTodoTxtTaskImport(params.Content)
+	default:
+		return nil, errors.New("'format' must be one of 'csv', 'json' or 'todotxt'")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no tasks found in 'content'")
+	}
+	if len(rows) > TaskImportMaxRows {
+		return nil, fmt.Errorf("cannot import more than %d tasks at once", TaskImportMaxRows)
+	}
+
+	rowIndexes := map[int]int{}
+	for index := range rows {
+		row := &rows[index]
+		rowIndexes[row.Row] = index
+		if row.Error == "" && row.Title == "" {
+			row.Error = "missing title"
+		}
+		row.Labels = dedupeTaskImportNames(row.Labels)
+		if row.ParentRow == 0 {
+			continue
+		}
+		// subtasks live in the same section as their parent
+		parent := rows[rowIndexes[row.ParentRow]]
+		row.Section = parent.Section
+		if row.Error == "" && parent.Error != "" {
+			row.Error = fmt.Sprintf("parent row %d has an error", row.ParentRow)
+		}
+	}
+	return rows, nil
+}
+
+// parseCSVTaskImport reads a CSV with a header row, which counts as row 1.
+// Without a column mapping, columns whose headers match an import field are used.
+// The parent column holds the title of a task in an earlier row.
+func parseCSVTaskImport(content string, columnMapping map[string]string) ([]taskImportRow, error) {
+	reader := csv.NewReader(strings.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.New("'content' is not valid CSV")
+	}
+	if len(records) == 0 {
+		return nil, errors.New("no tasks found in 'content'")
+	}
+
+	headerIndexes := map[string]int{}
+	for index, header := range records[0] {
+		headerIndexes[strings.ToLower(strings.TrimSpace(header))] = index
+	}
+	columns := map[string]int{}
+	if len(columnMapping) == 0 {
+		for _, field := range taskImportFields {
+			if index, ok := headerIndexes[field]; ok {
+				columns[field] = index
+			}
+		}
+	}
+	for field, header := range columnMapping {
+		if !slices.Contains(taskImportFields, field) {
+			return nil, fmt.Errorf("unknown field '%s' in 'column_mapping'", field)
+		}
+		index, ok := headerIndexes[strings.ToLower(strings.TrimSpace(header))]
+		if !ok {
+			return nil, fmt.Errorf("column '%s' not found in CSV header", header)
+		}
+		columns[field] = index
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("a 'title' column is required")
+	}
+
+	rows := []taskImportRow{}
+	rowsByTitle := map[string]int{}
+	for index, record := range records[1:] {
+		getValue := func(field string) string {
+			column, ok := columns[field]
+			if !ok || column >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[column])
+		}
+		row := taskImportRow{
+			Row:     index + 2,
+			Title:   getValue("title"),
+			Body:    getValue("body"),
+			Section: getValue("section"),
+			Labels:  splitTaskImportLabels(getValue("labels")),
+		}
+		setTaskImportDueDate(&row, getValue("due_date"))
+		setTaskImportPriority(&row, getValue("priority"))
+		switch strings.ToLower(getValue("is_completed")) {
+		case "", "false", "no", "0":
+		case "true", "yes", "x", "1":
+			row.IsCompleted = true
+		default:
+			setTaskImportError(&row, fmt.Sprintf("invalid completion value '%s'", getValue("is_completed")))
+		}
+		if parent := getValue("parent"); parent != "" {
+			parentRow, ok := rowsByTitle[strings.ToLower(parent)]
+			if ok {
+				row.ParentRow = parentRow
+			} else {
+				setTaskImportError(&row, fmt.Sprintf("parent task '%s' not found in an earlier row", parent))
+			}
+		}
+		if row.Title != "" {
+			rowsByTitle[strings.ToLower(row.Title)] = row.Row
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseJSONTaskImport reads an array of tasks, each of which can have nested subtasks.
+// Rows are numbered from 1 in the order the tasks appear.
+func parseJSONTaskImport(content string) ([]taskImportRow, error) {
+	var items []taskImportJSONItem
+	err := json.Unmarshal([]byte(content), &items)
+	if err != nil {
+		return nil, errors.New("'content' is not a valid JSON array of tasks")
+	}
+	rows := []taskImportRow{}
+	var addItems func(items []taskImportJSONItem, parentRow int)
+	addItems = func(items []taskImportJSONItem, parentRow int) {
+		for _, item := range items {
+			row := taskImportRow{
+				Row:         len(rows) + 1,
+				ParentRow:   parentRow,
+				Title:       strings.TrimSpace(item.Title),
+				Body:        item.Body,
+				Section:     strings.TrimSpace(item.Section),
+				Labels:      item.Labels,
+				IsCompleted: item.IsCompleted,
+			}
+			setTaskImportDueDate(&row, item.DueDate)
+			priority := strings.Trim(string(item.Priority), `"`)
+			if priority != "null" {
+				setTaskImportPriority(&row, priority)
+			}
+			rows = append(rows, row)
+			addItems(item.Subtasks, row.Row)
+		}
+	}
+	addItems(items, 0)
+	return rows, nil
+}
+
+// parseTodoTxtTaskImport reads one task per line, numbering rows by line.
+// The first +project is used as the section and any others as labels, along with every @context.
+// Indented lines are subtasks of the closest less indented line above them.
+func parseTodoTxtTaskImport(content string) []taskImportRow {
+	type parentLine struct {
+		indent int
+		row    int
+	}
+	rows := []taskImportRow{}
+	parents := []parentLine{}
+	for index, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		for len(parents) > 0 && parents[len(parents)-1].indent >= indent {
+			parents = parents[:len(parents)-1]
+		}
+		row := parseTodoTxtLine(trimmedLine)
+		row.Row = index + 1
+		if len(parents) > 0 {
+			row.ParentRow = parents[len(parents)-1].row
+		}
+		parents = append(parents, parentLine{indent: indent, row: row.Row})
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func parseTodoTxtLine(line string) taskImportRow {
+	row := taskImportRow{}
+	if strings.HasPrefix(line, "x ") {
+		row.IsCompleted = true
+		line = strings.TrimSpace(line[2:])
+	}
+	if match := todoTxtPriorityRegex.FindStringSubmatch(line); match != nil {
+		setTaskImportPriority(&row, match[1])
+		line = line[len(match[0]):]
+	}
+	// completion and creation dates
+	for i := 0; i < 2; i++ {
+		line = strings.TrimPrefix(line, todoTxtDateRegex.FindString(line))
+	}
+
+	words := []string{}
+	projects := []string{}
+	for _, token := range strings.Fields(line) {
+		switch {
+		case len(token) > 1 && token[0] == '+':
+			projects = append(projects, token[1:])
+		case len(token) > 1 && token[0] == '@':
+			row.Labels = append(row.Labels, token[1:])
+		case strings.HasPrefix(token, "due:"):
+			setTaskImportDueDate(&row, strings.TrimPrefix(token, "due:"))
+		case strings.HasPrefix(token, "pri:"):
+			setTaskImportPriority(&row, strings.TrimPrefix(token, "pri:"))
+		default:
+			words = append(words, token)
+		}
+	}
+	row.Title = strings.Join(words, " ")
+	if len(projects) > 0 {
+		row.Section = projects[0]
+		row.Labels = append(row.Labels, projects[1:]...)
+	}
+	return row
+}
+
+func setTaskImportDueDate(row *taskImportRow, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		dueDate, err := time.Parse(layout, value)
+		if err == nil {
+			row.DueDate = &dueDate
+			return
+		}
+	}
+	setTaskImportError(row, fmt.Sprintf("invalid due date '%s'", value))
+}
+
+// setTaskImportPriority accepts priorities 0 to 4 (none, urgent, high, medium and low) by number or name, or todo.txt letters.
+// A, B and C are urgent, high and medium, and every later letter is low.
+func setTaskImportPriority(row *taskImportRow, value string) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return
+	}
+	priorities := map[string]float64{
+		"0": 0, "none": 0,
+		"1": 1, "urgent": 1,
+		"2": 2, "high": 2,
+		"3": 3, "medium": 3,
+		"4": 4, "low": 4,
+	}
+	priority, ok := priorities[value]
+	if !ok && len(value) == 1 && value[0] >= 'a' && value[0] <= 'z' {
+		priority = float64(value[0]-'a') + 1
+		if priority > 4 {
+			priority = 4
+		}
+		ok = true
+	}
+	if !ok {
+		setTaskImportError(row, fmt.Sprintf("invalid priority '%s'", value))
+		return
+	}
+	row.PriorityNormalized = &priority
+}
+
+// setTaskImportError keeps the first error found in a row
+func setTaskImportError(row *taskImportRow, message string) {
+	if row.Error == "" {
+		row.Error = message
+	}
+}
+
+func splitTaskImportLabels(value string) []string {
+	labels := []string{}
+	for _, label := range strings.Split(value, ",") {
+		label = strings.TrimSpace(label)
+		if label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func dedupeTaskImportNames(names []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[strings.ToLower(name)] {
+			continue
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, name)
+	}
+	return result
+}
@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseTaskImport(t *testing.T) {
+	dueDate := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	urgent := 1.0
+	medium := 3.0
+	low := 4.0
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		_, err := parseTaskImport(&TaskImportParams{Format: "xml", Content: "<task/>"})
+		assert.EqualError(t, err, "'format' must be one of 'csv', 'json' or 'todotxt'")
+	})
+	t.Run("TodoTxt", func(t *testing.T) {
+		rows, err := parseTaskImport(&TaskImportParams{
+			Format: TaskImportFormatTodoTxt,
+			Content: "(A) 2023-02-01 write report +Work @office @Office due:2023-03-01\n" +
+				"  draft outline pri:C\n" +
+				"    find sources +Home\n" +
+				"\n" +
+				"x 2023-02-02 2023-02-01 call mom +Home +Family\n" +
+				"pay bills due:tomorrow\n",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []taskImportRow{
+			{Row: 1, Title: "write report", DueDate: &dueDate, PriorityNormalized: &urgent, Section: "Work", Labels: []string{"office"}},
+			{Row: 2, ParentRow: 1, Title: "draft outline", PriorityNormalized: &medium, Section: "Work", Labels: []string{}},
+			// subtasks always use their parent's section
+			{Row: 3, ParentRow: 2, Title: "find sources", Section: "Work", Labels: []string{}},
+			{Row: 5, Title: "call mom", IsCompleted: true, Section: "Home", Labels: []string{"Family"}},
+			{Row: 6, Title: "pay bills", Labels: []string{}, Error: "invalid due date 'tomorrow'"},
+		}, rows)
+	})
+	t.Run("JSON", func(t *testing.T) {
+		rows, err := parseTaskImport(&TaskImportParams{
+			Format: TaskImportFormatJSON,
+			Content: `[
+				{"title": "write report", "priority": "low", "due_date": "2023-03-01", "section": "Work", "labels": ["writing"], "subtasks": [
+					{"title": "draft outline", "priority": 1},
+					{"title": "", "subtasks": [{"title": "find sources"}]}
+				]},
+				{"title": "pay bills", "priority": "someday"}
+			]`,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []taskImportRow{
+			{Row: 1, Title: "write report", DueDate: &dueDate, PriorityNormalized: &low, Section: "Work", Labels: []string{"writing"}},
+			{Row: 2, ParentRow: 1, Title: "draft outline", PriorityNormalized: &urgent, Section: "Work", Labels: []string{}},
+			{Row: 3, ParentRow: 1, Section: "Work", Labels: []string{}, Error: "missing title"},
+			{Row: 4, ParentRow: 3, Title: "find sources", Section: "Work", Labels: []string{}, Error: "parent row 3 has an error"},
+			{Row: 5, Title: "pay bills", Labels: []string{}, Error: "invalid priority 'someday'"},
+		}, rows)
+
+		_, err = parseTaskImport(&TaskImportParams{Format: TaskImportFormatJSON, Content: `{"title": "task"}`})
+		assert.EqualError(t, err, "'content' is not a valid JSON array of tasks")
+	})
+	t.Run("CSV", func(t *testing.T) {
+		rows, err := parseTaskImport(&TaskImportParams{
+			Format: TaskImportFormatCSV,
+			Content: "Task,Notes,Due,Pri,Folder,Tags,Parent Task\n" +
+				"write report,quarterly,2023-03-01,urgent,Work,\"writing, Work\",\n" +
+				"draft outline,,,,,,write report\n" +
+				"find sources,,,,,,missing task\n",
+			ColumnMapping: map[string]string{
+				"title":    "Task",
+				"body":     "Notes",
+				"due_date": "Due",
+				"priority": "Pri",
+				"section":  "Folder",
+				"labels":   "Tags",
+				"parent":   "parent task",
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []taskImportRow{
+			{Row: 2, Title: "write report", Body: "quarterly", DueDate: &dueDate, PriorityNormalized: &urgent, Section: "Work", Labels: []string{"writing", "Work"}},
+			{Row: 3, ParentRow: 2, Title: "draft outline", Section: "Work", Labels: []string{}},
+			{Row: 4, Title: "find sources", Labels: []string{}, Error: "parent task 'missing task' not found in an earlier row"},
+		}, rows)
+	})
+	t.Run("CSVDefaultMapping", func(t *testing.T) {
+		rows, err := parseTaskImport(&TaskImportParams{
+			Format:  TaskImportFormatCSV,
+			Content: "Title,Priority,Is_Completed\nwrite report,b,yes\n",
+		})
+		assert.NoError(t, err)
+		high := 2.0
+		assert.Equal(t, []taskImportRow{
+			{Row: 2, Title: "write report", PriorityNormalized: &high, IsCompleted: true, Labels: []string{}},
+		}, rows)
+	})
+	t.Run("CSVInvalidMapping", func(t *testing.T) {
+		_, err := parseTaskImport(&TaskImportParams{Format: TaskImportFormatCSV, Content: "Name\nwrite report\n"})
+		assert.EqualError(t, err, "a 'title' column is required")
+		_, err = parseTaskImport(&TaskImportParams{Format: TaskImportFormatCSV, Content: "Name\nwrite report\n", ColumnMapping: map[string]string{"title": "Task"}})
+		assert.EqualError(t, err, "column 'Task' not found in CSV header")
+		_, err = parseTaskImport(&TaskImportParams{Format: TaskImportFormatCSV, Content: "Name\nwrite report\n", ColumnMapping: map[string]string{"name": "Name"}})
+		assert.EqualError(t, err, "unknown field 'name' in 'column_mapping'")
+	})
+	t.Run("TooManyRows", func(t *testing.T) {
+		content := ""
+		for i := 0; i <= TaskImportMaxRows; i++ {
+			content += "task\n"
+		}
+		_, err := parseTaskImport(&TaskImportParams{Format: TaskImportFormatTodoTxt, Content: content})
+		assert.EqualError(t, err, "cannot import more than 500 tasks at once")
+	})
+}
+
+func TestTaskImport(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_task_import@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	_, err := database.GetLabelCollection(api.DB).InsertOne(context.Background(), database.Label{UserID: userID, Name: "Office"})
+	assert.NoError(t, err)
+	notCompleted := false
+	existingTaskID := primitive.NewObjectID()
+	_, err = database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+		ID:            existingTaskID,
+		UserID:        userID,
+		IDTaskSection: constants.IDTaskSectionDefault,
+		IDOrdering:    1,
+		IsCompleted:   &notCompleted,
+	})
+	assert.NoError(t, err)
+
+	content := "write report +Work @office\n  draft outline\nbuy milk\n(Q) due:2023-03-01\n"
+	sendImport := func(dryRun bool) (int, TaskImportResult) {
+		payload, _ := json.Marshal(TaskImportParams{Format: TaskImportFormatTodoTxt, Content: content, DryRun: dryRun})
+		request, _ := http.NewRequest("POST", "/tasks/import/", bytes.NewBuffer(payload))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		var result TaskImportResult
+		assert.NoError(t, json.Unmarshal(body, &result))
+		return recorder.Code, result
+	}
+	countTasks := func() int64 {
+		count, err := database.GetTaskCollection(api.DB).CountDocuments(context.Background(), bson.M{"user_id": userID})
+		assert.NoError(t, err)
+		return count
+	}
+
+	UnauthorizedTest(t, "POST", "/tasks/import/", nil)
+	t.Run("InvalidParams", func(t *testing.T) {
+		request, _ := http.NewRequest("POST", "/tasks/import/", bytes.NewBuffer([]byte(`{"format": "todotxt"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+		request, _ = http.NewRequest("POST", "/tasks/import/", bytes.NewBuffer([]byte(`{"format": "xml", "content": "<task/>"}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+	t.Run("DryRun", func(t *testing.T) {
+		code, result := sendImport(true)
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 3, result.CreatedCount)
+		assert.Equal(t, 1, result.ErrorCount)
+		assert.Equal(t, []string{"Work"}, result.NewSections)
+		assert.Equal(t, []string{}, result.NewLabels)
+		assert.Equal(t, 4, len(result.Rows))
+		assert.Equal(t, "", result.Rows[0].TaskID)
+		assert.Equal(t, "missing title", result.Rows[3].Error)
+		assert.Equal(t, int64(1), countTasks())
+	})
+	t.Run("Success", func(t *testing.T) {
+		code, result := sendImport(false)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, 3, result.CreatedCount)
+		assert.Equal(t, 1, result.ErrorCount)
+		assert.Equal(t, int64(4), countTasks())
+
+		sections, err := database.GetTaskSections(api.DB, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(*sections))
+		assert.Equal(t, "Work", (*sections)[0].Name)
+		labels, err := database.GetLabels(api.DB, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(*labels))
+
+		getTask := func(taskIDHex string) *database.Task {
+			taskID, err := primitive.ObjectIDFromHex(taskIDHex)
+			assert.NoError(t, err)
+			task, err := database.GetTask(api.DB, taskID, userID)
+			assert.NoError(t, err)
+			return task
+		}
+		reportTask := getTask(result.Rows[0].TaskID)
+		assert.Equal(t, "write report", *reportTask.Title)
+		assert.Equal(t, (*sections)[0].ID, reportTask.IDTaskSection)
+		assert.Equal(t, []primitive.ObjectID{(*labels)[0].ID}, *reportTask.LabelIDs)
+		outlineTask := getTask(result.Rows[1].TaskID)
+		assert.Equal(t, reportTask.ID, outlineTask.ParentTaskID)
+		assert.Equal(t, (*sections)[0].ID, outlineTask.IDTaskSection)
+
+		// imported tasks are placed in front of existing tasks in their section
+		milkTask := getTask(result.Rows[2].TaskID)
+		assert.Equal(t, constants.IDTaskSectionDefault, milkTask.IDTaskSection)
+		assert.Equal(t, 1, milkTask.IDOrdering)
+		existingTask, err := database.GetTask(api.DB, existingTaskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, existingTask.IDOrdering)
+	})
+}
@@ -67,6 +67,7 @@ type TaskResult struct {
 	SlackMessageParams       *database.SlackMessageParams `json:"slack_message_params,omitempty"`
 	MeetingPreparationParams *MeetingPreparationParams    `json:"meeting_preparation_params,omitempty"`
 	SubTasks                 []*TaskResult                `json:"sub_tasks,omitempty"`
+	LabelIDs                 []primitive.ObjectID         `json:"label_ids,omitempty"`
 	NUXNumber                int                          `json:"nux_number_id,omitempty"`
 	CreatedAt                string                       `json:"created_at,omitempty"`
 	UpdatedAt                string                       `json:"updated_at,omitempty"`
@@ -183,6 +184,12 @@ func (api *API) adjustForCompletedTasks(
 				api.Logger.Error().Err(err).Msg("failed to complete task")
 				return err
 			}
+			completed := true
+			database.RecordTaskHistory(db, currentTask.UserID, &currentTask, &database.Task{IsCompleted: &completed}, database.TaskHistoryActorFetch)
+			err = database.UnblockDependentTasks(db, currentTask.UserID, currentTask.ID)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -423,6 +430,10 @@ func (api *API) taskBaseToTaskResult(t *database.Task, userID primitive.ObjectID
 		taskResult.CompletedAt = t.CompletedAt
 	}
 
+	if t.LabelIDs != nil && len(*t.LabelIDs) > 0 {
+		taskResult.LabelIDs = *t.LabelIDs
+	}
+
 	return taskResult
 }
 
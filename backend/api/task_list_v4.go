@@ -39,21 +39,44 @@ type TaskResultV4 struct {
 	SlackMessageParams       *database.SlackMessageParams `json:"slack_message_params,omitempty"`
 	MeetingPreparationParams *MeetingPreparationParams    `json:"meeting_preparation_params,omitempty"`
 	SubTaskIDs               []primitive.ObjectID         `json:"subtask_ids,omitempty"`
+	SubtaskRollup            *SubtaskRollupV4             `json:"subtask_rollup,omitempty"`
+	LabelIDs                 []primitive.ObjectID         `json:"label_ids,omitempty"`
+	BlockedByTaskIDs         []primitive.ObjectID         `json:"blocked_by_task_ids,omitempty"`
+	BlockingTaskIDs          []primitive.ObjectID         `json:"blocking_task_ids,omitempty"`
+	IsBlocked                bool                         `json:"is_blocked"`
 	NUXNumber                int                          `json:"id_nux_number,omitempty"`
 	LinearCycle              *database.LinearCycle        `json:"linear_cycle,omitempty"`
+	AsanaTaskParams          *database.AsanaTaskParams    `json:"asana_task_params,omitempty"`
+	GithubIssueParams        *database.GithubIssueParams  `json:"github_issue_params,omitempty"`
 	CreatedAt                string                       `json:"created_at,omitempty"`
 	UpdatedAt                string                       `json:"updated_at,omitempty"`
 	CompletedAt              string                       `json:"completed_at,omitempty"`
 	DeletedAt                string                       `json:"deleted_at,omitempty"`
 	SharedAccess             string                       `json:"shared_access,omitempty"`
 	SharedUntil              string                       `json:"shared_until,omitempty"`
+	SnoozedUntil             string                       `json:"snoozed_until,omitempty"`
+	RemindAt                 string                       `json:"remind_at,omitempty"`
+}
+
+// SubtaskRollupV4 summarizes the subtasks at every level of nesting below a task, leaving out deleted subtasks
+type SubtaskRollupV4 struct {
+	SubtaskCount          int `json:"subtask_count"`
+	CompletedSubtaskCount int `json:"completed_subtask_count"`
+	CompletionPercentage  int `json:"completion_percentage"`
+	// time in nanoseconds for the task and all of its subtasks
+	TotalTimeAllocation int64 `json:"total_time_allocated"`
 }
 
 func (api *API) TasksListV4(c *gin.Context) {
+	labelID, err := getLabelFilter(c)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": err.Error()})
+		return
+	}
 	userID := getUserIDFromContext(c)
 	var userObject database.User
 	userCollection := database.GetUserCollection(api.DB)
-	err := userCollection.FindOne(context.Background(), bson.M{"_id": userID}).Decode(&userObject)
+	err = userCollection.FindOne(context.Background(), bson.M{"_id": userID}).Decode(&userObject)
 	if err != nil {
 		api.Logger.Error().Err(err).Msg("failed to find user")
 		Handle500(c)
@@ -82,6 +105,7 @@ func (api *API) TasksListV4(c *gin.Context) {
 		completedTasks,
 		deletedTasks,
 		userID,
+		labelID,
 	)
 	if err != nil {
 		Handle500(c)
@@ -104,12 +128,28 @@ func (api *API) mergeTasksV4(
 	completedTasks *[]database.Task,
 	deletedTasks *[]database.Task,
 	userID primitive.ObjectID,
+	labelID *primitive.ObjectID,
 ) ([]*TaskResultV4, error) {
 	allTasks := []database.Task{}
 	allTasks = append(allTasks, *activeTasks...)
 	allTasks = append(allTasks, *completedTasks...)
 	allTasks = append(allTasks, *deletedTasks...)
-	return api.taskListToTaskResultListV4(&allTasks), nil
+	// the results are built from every task first, so that blockers and subtask rollups outside of the label are still counted
+	taskResults := api.taskListToTaskResultListV4(&allTasks)
+	if labelID == nil {
+		return taskResults, nil
+	}
+	labeledTaskIDs := make(map[primitive.ObjectID]bool)
+	for _, task := range filterTasksByLabel(allTasks, *labelID) {
+		labeledTaskIDs[task.ID] = true
+	}
+	filteredTaskResults := []*TaskResultV4{}
+	for _, taskResult := range taskResults {
+		if labeledTaskIDs[taskResult.ID] {
+			filteredTaskResults = append(filteredTaskResults, taskResult)
+		}
+	}
+	return filteredTaskResults, nil
 }
 
 // shares a lot of duplicate code with taskListToTaskResultList
@@ -117,7 +157,10 @@ func (api *API) mergeTasksV4(
 func (api *API) taskListToTaskResultListV4(tasks *[]database.Task) []*TaskResultV4 {
 	parentToChildIDs := make(map[primitive.ObjectID][]primitive.ObjectID)
 	taskResults := []*TaskResultV4{}
-	taskIDMap := make(map[primitive.ObjectID]bool)
+	taskByID := make(map[primitive.ObjectID]*database.Task)
+	blockerToDependentIDs := make(map[primitive.ObjectID][]primitive.ObjectID)
+	// tasks which are neither done nor deleted, and so still block their dependents
+	unfinishedTaskIDs := make(map[primitive.ObjectID]bool)
 	for _, task := range *tasks {
 		if task.ParentTaskID != primitive.NilObjectID {
 			value, exists := parentToChildIDs[task.ParentTaskID]
@@ -127,10 +170,16 @@ func (api *API) taskListToTaskResultListV4(tasks *[]database.Task) []*TaskResult
 				parentToChildIDs[task.ParentTaskID] = []primitive.ObjectID{task.ID}
 			}
 		}
+		for _, blockedByTaskID := range task.BlockedByTaskIDs {
+			blockerToDependentIDs[blockedByTaskID] = append(blockerToDependentIDs[blockedByTaskID], task.ID)
+		}
+		if (task.IsCompleted == nil || !*task.IsCompleted) && (task.IsDeleted == nil || !*task.IsDeleted) {
+			unfinishedTaskIDs[task.ID] = true
+		}
 		// for implicit memory aliasing
 		tempTask := task
 		taskResults = append(taskResults, api.taskToTaskResultV4(&tempTask))
-		taskIDMap[task.ID] = true
+		taskByID[task.ID] = &tempTask
 	}
 
 	// nodes with no valid parent will not appear in task results
@@ -140,20 +189,69 @@ func (api *API) taskListToTaskResultListV4(tasks *[]database.Task) []*TaskResult
 		value, exists := parentToChildIDs[node.ID]
 		if exists {
 			node.SubTaskIDs = value
+			node.SubtaskRollup = getSubtaskRollup(taskByID[node.ID], taskByID, parentToChildIDs)
 		}
-		// if task is a subtask without a parent task, remove from results
-		if node.IDParent != "" {
-			idParent, _ := primitive.ObjectIDFromHex(node.IDParent)
-			_, exists = taskIDMap[idParent]
-			if !exists {
-				continue
+		node.BlockingTaskIDs = blockerToDependentIDs[node.ID]
+		for _, blockedByTaskID := range node.BlockedByTaskIDs {
+			if unfinishedTaskIDs[blockedByTaskID] {
+				node.IsBlocked = true
 			}
 		}
+		// if task is a subtask without a parent task at any level above it, remove from results
+		if !hasAllAncestors(taskByID[node.ID], taskByID) {
+			continue
+		}
 		taskResultsWithoutOrphans = append(taskResultsWithoutOrphans, node)
 	}
 	return taskResultsWithoutOrphans
 }
 
+func getSubtaskRollup(task *database.Task, taskByID map[primitive.ObjectID]*database.Task, parentToChildIDs map[primitive.ObjectID][]primitive.ObjectID) *SubtaskRollupV4 {
+	rollup := SubtaskRollupV4{}
+	if task.TimeAllocation != nil {
+		rollup.TotalTimeAllocation = *task.TimeAllocation
+	}
+	visitedTaskIDs := map[primitive.ObjectID]bool{task.ID: true}
+	parentIDs := []primitive.ObjectID{task.ID}
+	for len(parentIDs) > 0 {
+		parentID := parentIDs[0]
+		parentIDs = parentIDs[1:]
+		for _, subtaskID := range parentToChildIDs[parentID] {
+			subtask := taskByID[subtaskID]
+			if visitedTaskIDs[subtaskID] || (subtask.IsDeleted != nil && *subtask.IsDeleted) {
+				continue
+			}
+			visitedTaskIDs[subtaskID] = true
+			parentIDs = append(parentIDs, subtaskID)
+			rollup.SubtaskCount += 1
+			if subtask.IsCompleted != nil && *subtask.IsCompleted {
+				rollup.CompletedSubtaskCount += 1
+			}
+			if subtask.TimeAllocation != nil {
+				rollup.TotalTimeAllocation += *subtask.TimeAllocation
+			}
+		}
+	}
+	if rollup.SubtaskCount == 0 {
+		return nil
+	}
+	rollup.CompletionPercentage = rollup.CompletedSubtaskCount * 100 / rollup.SubtaskCount
+	return &rollup
+}
+
+func hasAllAncestors(task *database.Task, taskByID map[primitive.ObjectID]*database.Task) bool {
+	visitedTaskIDs := map[primitive.ObjectID]bool{}
+	for task.ParentTaskID != primitive.NilObjectID {
+		parentTask, exists := taskByID[task.ParentTaskID]
+		if !exists || visitedTaskIDs[parentTask.ID] {
+			return false
+		}
+		visitedTaskIDs[parentTask.ID] = true
+		task = parentTask
+	}
+	return true
+}
+
 // shares a lot of duplicate code with taskBaseToTaskResult
 // TODO: remove taskBaseToTaskResult when frontend switches to new endpoint
 func (api *API) taskToTaskResultV4(t *database.Task) *TaskResultV4 {
@@ -228,6 +326,14 @@ func (api *API) taskToTaskResultV4(t *database.Task) *TaskResultV4 {
 		SharedAccess:       sharedAccess,
 	}
 
+	// cleared times are stored as the unix epoch
+	if t.SnoozedUntil != nil && t.SnoozedUntil.Time().Unix() > 0 {
+		taskResult.SnoozedUntil = t.SnoozedUntil.Time().UTC().Format(time.RFC3339)
+	}
+	if t.RemindAt != nil && t.RemindAt.Time().Unix() > 0 {
+		taskResult.RemindAt = t.RemindAt.Time().UTC().Format(time.RFC3339)
+	}
+
 	if t.ParentTaskID != primitive.NilObjectID {
 		taskResult.IDParent = t.ParentTaskID.Hex()
 		// we want to make folder ID blank if the task is a subtask
@@ -300,9 +406,25 @@ func (api *API) taskToTaskResultV4(t *database.Task) *TaskResultV4 {
 		taskResult.RecurringTaskTemplateID = t.RecurringTaskTemplateID
 	}
 
+	if t.LabelIDs != nil && len(*t.LabelIDs) > 0 {
+		taskResult.LabelIDs = *t.LabelIDs
+	}
+
+	if len(t.BlockedByTaskIDs) > 0 {
+		taskResult.BlockedByTaskIDs = t.BlockedByTaskIDs
+	}
+
 	if t.LinearCycle.ID != "" {
 		taskResult.LinearCycle = &t.LinearCycle
 	}
 
+	if t.AsanaTaskParams != nil && *t.AsanaTaskParams != (database.AsanaTaskParams{}) {
+		taskResult.AsanaTaskParams = t.AsanaTaskParams
+	}
+
+	if t.GithubIssueParams != nil {
+		taskResult.GithubIssueParams = t.GithubIssueParams
+	}
+
 	return taskResult
 }
@@ -41,11 +41,17 @@ type TaskItemChangeableFields struct {
 	DeletedAt      primitive.DateTime `json:"deleted_at,omitempty" bson:"deleted_at"`
 	SharedAccess   *string            `json:"shared_access,omitempty" bson:"shared_access,omitempty"`
 	SharedUntil    primitive.DateTime `json:"shared_until,omitempty" bson:"shared_until,omitempty"`
+	LabelIDs       *[]string          `json:"label_ids,omitempty" bson:"label_ids,omitempty"`
+	// RFC3339 times, where an empty string clears the field
+	SnoozedUntil *string `json:"snoozed_until,omitempty" bson:"snoozed_until,omitempty"`
+	RemindAt     *string `json:"remind_at,omitempty" bson:"remind_at,omitempty"`
 }
 
 type TaskModifyParams struct {
 	IDOrdering    *int    `json:"id_ordering"`
 	IDTaskSection *string `json:"id_task_section"`
+	// applies completion, deletion and section moves to all subtasks too
+	CascadeToSubtasks bool `json:"cascade_to_subtasks"`
 	TaskItemChangeableFields
 }
 
@@ -82,7 +88,7 @@ func (api *API) TaskModify(c *gin.Context) {
 	}
 
 	// check if all fields are empty
-	if modifyParams == (TaskModifyParams{}) {
+	if modifyParams == (TaskModifyParams{CascadeToSubtasks: modifyParams.CascadeToSubtasks}) {
 		c.JSON(400, gin.H{"detail": "task changes missing"})
 		return
 	}
@@ -99,73 +105,14 @@ func (api *API) TaskModify(c *gin.Context) {
 		return
 	}
 
-	var dueDate *primitive.DateTime
-	if modifyParams.TaskItemChangeableFields.DueDate != nil {
-		yearMonthDayDate, yearMonthDayErr := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, *modifyParams.TaskItemChangeableFields.DueDate)
-		rfcDate, rfcErr := time.Parse(time.RFC3339, *modifyParams.TaskItemChangeableFields.DueDate)
-
-		if yearMonthDayErr != nil && rfcErr != nil {
-			c.JSON(400, gin.H{"detail": "due_date is not a valid date"})
-			return
-		}
-		if yearMonthDayErr == nil {
-			result := primitive.NewDateTimeFromTime(yearMonthDayDate)
-			dueDate = &result
-		} else {
-			result := primitive.NewDateTimeFromTime(rfcDate)
-			dueDate = &result
-		}
-	}
 	if modifyParams.TaskItemChangeableFields != (TaskItemChangeableFields{}) {
-		updateTask := database.Task{
-			Title:              modifyParams.TaskItemChangeableFields.Title,
-			Body:               modifyParams.TaskItemChangeableFields.Body,
-			TimeAllocation:     modifyParams.TaskItemChangeableFields.TimeAllocation,
-			IsCompleted:        modifyParams.TaskItemChangeableFields.IsCompleted,
-			CompletedAt:        modifyParams.TaskItemChangeableFields.CompletedAt,
-			IsDeleted:          modifyParams.TaskItemChangeableFields.IsDeleted,
-			DeletedAt:          modifyParams.TaskItemChangeableFields.DeletedAt,
-			SharedUntil:        modifyParams.TaskItemChangeableFields.SharedUntil,
-			UpdatedAt:          primitive.NewDateTimeFromTime(time.Now()),
-			PriorityNormalized: modifyParams.TaskItemChangeableFields.Task.PriorityNormalized,
-			ExternalPriority:   modifyParams.TaskItemChangeableFields.Task.ExternalPriority,
-			TaskNumber:         modifyParams.TaskItemChangeableFields.Task.TaskNumber,
-			Comments:           modifyParams.TaskItemChangeableFields.Task.Comments,
-			Status:             modifyParams.TaskItemChangeableFields.Task.Status,
-			PreviousStatus:     modifyParams.TaskItemChangeableFields.Task.PreviousStatus,
-			CompletedStatus:    modifyParams.TaskItemChangeableFields.Task.CompletedStatus,
-		}
-		if dueDate != nil {
-			updateTask.DueDate = dueDate
-		}
-		if modifyParams.TaskItemChangeableFields.Task.RecurringTaskTemplateID != nil {
-			recurring_task_template_id, err := primitive.ObjectIDFromHex(*modifyParams.TaskItemChangeableFields.Task.RecurringTaskTemplateID)
-			if err != nil {
-				api.Logger.Error().Err(err).Msg("failed to parse recurring_task_template_id")
-				Handle500(c)
-				return
-			}
-			updateTask.RecurringTaskTemplateID = recurring_task_template_id
-		}
-
-		if task.SourceID != external.TASK_SOURCE_ID_GT_TASK && (modifyParams.TaskItemChangeableFields.SharedUntil != 0 || modifyParams.TaskItemChangeableFields.SharedAccess != nil) {
-			c.JSON(400, gin.H{"detail": "only General Task tasks can be shared"})
+		updateTask, err := api.getTaskUpdate(userID, task, &modifyParams.TaskItemChangeableFields)
+		if err != nil {
+			c.JSON(400, gin.H{"detail": err.Error()})
 			return
 		}
-		if modifyParams.TaskItemChangeableFields.SharedAccess != nil {
-			if *modifyParams.TaskItemChangeableFields.SharedAccess == constants.StringSharedAccessPublic {
-				sharedAccessPublic := database.SharedAccessPublic
-				updateTask.SharedAccess = &sharedAccessPublic
-			} else if *modifyParams.TaskItemChangeableFields.SharedAccess == constants.StringSharedAccessDomain {
-				sharedAccessDomain := database.SharedAccessDomain
-				updateTask.SharedAccess = &sharedAccessDomain
-			} else {
-				c.JSON(400, gin.H{"detail": "invalid shared access token"})
-				return
-			}
-		}
 
-		err = taskSourceResult.Source.ModifyTask(api.DB, userID, task.SourceAccountID, task.IDExternal, &updateTask, task)
+		err = taskSourceResult.Source.ModifyTask(api.DB, userID, task.SourceAccountID, task.IDExternal, updateTask, task)
 		if err != nil {
 			api.Logger.Error().Err(err).Msg("failed to update external task source")
 			Handle500(c)
@@ -182,7 +129,19 @@ func (api *API) TaskModify(c *gin.Context) {
 				updateTask.Title = &tempTitle
 			}
 		}
-		api.UpdateTaskInDB(c, task, userID, &updateTask)
+		err = api.UpdateTaskInDBWithError(task, userID, updateTask)
+		if err != nil {
+			Handle500(c)
+			return
+		}
+		database.RecordTaskHistory(api.DB, userID, task, updateTask, database.TaskHistoryActorUser)
+		if updateTask.IsCompleted != nil && *updateTask.IsCompleted {
+			err = database.UnblockDependentTasks(api.DB, userID, task.ID)
+			if err != nil {
+				Handle500(c)
+				return
+			}
+		}
 	}
 
 	// handle reorder task
@@ -193,16 +152,138 @@ func (api *API) TaskModify(c *gin.Context) {
 		}
 	}
 
+	if modifyParams.CascadeToSubtasks {
+		err = api.cascadeToSubtasks(userID, task, &modifyParams.TaskItemChangeableFields, modifyParams.IDTaskSection)
+		if err != nil {
+			api.Logger.Error().Err(err).Msg("failed to update subtasks")
+			Handle500(c)
+			return
+		}
+	}
+
 	c.JSON(200, gin.H{})
 }
 
+// getTaskUpdate converts validated changeable fields into the fields to set on the task
+func (api *API) getTaskUpdate(userID primitive.ObjectID, task *database.Task, updateFields *TaskItemChangeableFields) (*database.Task, error) {
+	updateTask := database.Task{
+		Title:              updateFields.Title,
+		Body:               updateFields.Body,
+		TimeAllocation:     updateFields.TimeAllocation,
+		IsCompleted:        updateFields.IsCompleted,
+		CompletedAt:        updateFields.CompletedAt,
+		IsDeleted:          updateFields.IsDeleted,
+		DeletedAt:          updateFields.DeletedAt,
+		SharedUntil:        updateFields.SharedUntil,
+		UpdatedAt:          primitive.NewDateTimeFromTime(time.Now()),
+		PriorityNormalized: updateFields.Task.PriorityNormalized,
+		ExternalPriority:   updateFields.Task.ExternalPriority,
+		TaskNumber:         updateFields.Task.TaskNumber,
+		Comments:           updateFields.Task.Comments,
+		Status:             updateFields.Task.Status,
+		PreviousStatus:     updateFields.Task.PreviousStatus,
+		CompletedStatus:    updateFields.Task.CompletedStatus,
+	}
+	if updateFields.DueDate != nil {
+		yearMonthDayDate, yearMonthDayErr := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, *updateFields.DueDate)
+		rfcDate, rfcErr := time.Parse(time.RFC3339, *updateFields.DueDate)
+
+		if yearMonthDayErr != nil && rfcErr != nil {
+			return nil, errors.New("due_date is not a valid date")
+		}
+		var dueDate primitive.DateTime
+		if yearMonthDayErr == nil {
+			dueDate = primitive.NewDateTimeFromTime(yearMonthDayDate)
+		} else {
+			dueDate = primitive.NewDateTimeFromTime(rfcDate)
+		}
+		updateTask.DueDate = &dueDate
+	}
+	if updateFields.Body != nil {
+		searchBody := *updateFields.Body
+		if task.SourceID == external.TASK_SOURCE_ID_JIRA {
+			searchBody = external.GetJIRADescriptionText(searchBody)
+		}
+		updateTask.SearchBody = &searchBody
+	}
+	if updateFields.SnoozedUntil != nil {
+		snoozedUntil, err := parseClearableTime(*updateFields.SnoozedUntil)
+		if err != nil {
+			return nil, errors.New("snoozed_until is not a valid time")
+		}
+		updateTask.SnoozedUntil = snoozedUntil
+	}
+	if updateFields.RemindAt != nil {
+		remindAt, err := parseClearableTime(*updateFields.RemindAt)
+		if err != nil {
+			return nil, errors.New("remind_at is not a valid time")
+		}
+		isReminderSent := false
+		updateTask.RemindAt = remindAt
+		updateTask.IsReminderSent = &isReminderSent
+	}
+	if updateFields.LabelIDs != nil {
+		labelIDs, err := api.getLabelIDs(userID, *updateFields.LabelIDs)
+		if err != nil {
+			return nil, errors.New("invalid label IDs")
+		}
+		updateTask.LabelIDs = &labelIDs
+	}
+	if updateFields.Task.RecurringTaskTemplateID != nil {
+		recurringTaskTemplateID, err := primitive.ObjectIDFromHex(*updateFields.Task.RecurringTaskTemplateID)
+		if err != nil {
+			return nil, errors.New("'recurring_task_template_id' is not a valid ID")
+		}
+		updateTask.RecurringTaskTemplateID = recurringTaskTemplateID
+	}
+
+	if task.SourceID != external.TASK_SOURCE_ID_GT_TASK && (updateFields.SharedUntil != 0 || updateFields.SharedAccess != nil) {
+		return nil, errors.New("only General Task tasks can be shared")
+	}
+	if updateFields.SharedAccess != nil {
+		if *updateFields.SharedAccess == constants.StringSharedAccessPublic {
+			sharedAccessPublic := database.SharedAccessPublic
+			updateTask.SharedAccess = &sharedAccessPublic
+		} else if *updateFields.SharedAccess == constants.StringSharedAccessDomain {
+			sharedAccessDomain := database.SharedAccessDomain
+			updateTask.SharedAccess = &sharedAccessDomain
+		} else {
+			return nil, errors.New("invalid shared access token")
+		}
+	}
+	return &updateTask, nil
+}
+
+// parseClearableTime uses the unix epoch for an empty value, so that setting it clears the stored time
+func parseClearableTime(value string) (*primitive.DateTime, error) {
+	result := primitive.NewDateTimeFromTime(time.Unix(0, 0))
+	if value == "" {
+		return &result, nil
+	}
+	parsedTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	result = primitive.NewDateTimeFromTime(parsedTime)
+	return &result, nil
+}
+
 func ValidateFields(c *gin.Context, updateFields *TaskItemChangeableFields, taskSourceResult *external.TaskSourceResult, task *database.Task) bool {
+	err := validateTaskChangeableFields(updateFields, taskSourceResult, task)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": err.Error()})
+		return false
+	}
+	return true
+}
+
+// validateTaskChangeableFields checks the fields against the task and fills in the fields derived from them
+func validateTaskChangeableFields(updateFields *TaskItemChangeableFields, taskSourceResult *external.TaskSourceResult, task *database.Task) error {
 	isTaskDeletedInRequest := updateFields.IsDeleted == nil || *updateFields.IsDeleted
 	isTaskDeletedInDb := task.IsDeleted != nil && *task.IsDeleted
 	isTaskDeleted := isTaskDeletedInRequest && isTaskDeletedInDb
 	if updateFields.IsCompleted != nil && *updateFields.IsCompleted && (!taskSourceResult.Details.IsCompletable || isTaskDeleted) {
-		c.JSON(400, gin.H{"detail": "cannot be marked done"})
-		return false
+		return errors.New("cannot be marked done")
 	}
 	if updateFields.Task.Status != nil {
 		var statusToUpdateTo *database.ExternalTaskStatus
@@ -212,8 +293,7 @@ func ValidateFields(c *gin.Context, updateFields *TaskItemChangeableFields, task
 			}
 		}
 		if statusToUpdateTo == nil {
-			c.JSON(400, gin.H{"detail": "status value not in all status field for task"})
-			return false
+			return errors.New("status value not in all status field for task")
 		}
 		if statusToUpdateTo.IsCompletedStatus {
 			updateFields.Task.CompletedStatus = statusToUpdateTo
@@ -231,13 +311,11 @@ func ValidateFields(c *gin.Context, updateFields *TaskItemChangeableFields, task
 		updateFields.DeletedAt = primitive.NewDateTimeFromTime(time.Now())
 	}
 	if updateFields.Title != nil && *updateFields.Title == "" {
-		c.JSON(400, gin.H{"detail": "title cannot be empty"})
-		return false
+		return errors.New("title cannot be empty")
 	}
 	if updateFields.TimeAllocation != nil {
 		if *updateFields.TimeAllocation < 0 {
-			c.JSON(400, gin.H{"detail": "time duration cannot be negative"})
-			return false
+			return errors.New("time duration cannot be negative")
 		} else {
 			*updateFields.TimeAllocation *= constants.NANOSECONDS_IN_SECOND
 		}
@@ -252,11 +330,10 @@ func ValidateFields(c *gin.Context, updateFields *TaskItemChangeableFields, task
 			}
 		}
 		if !matched {
-			c.JSON(400, gin.H{"detail": "priority value not valid for task"})
-			return false
+			return errors.New("priority value not valid for task")
 		}
 	}
-	return true
+	return nil
 }
 
 // note: check usage of this function before using new fields of the 'task' parameter
@@ -292,6 +369,9 @@ func (api *API) ReOrderTask(c *gin.Context, taskID primitive.ObjectID, userID pr
 		Handle404(c)
 		return errors.New("task not found")
 	}
+	if IDTaskSectionHex != nil {
+		database.RecordTaskHistory(api.DB, userID, task, &database.Task{IDTaskSection: IDTaskSection}, database.TaskHistoryActorUser)
+	}
 
 	if IDOrdering == nil {
 		// if not updating the ordering of the task, then no need to move the other tasks
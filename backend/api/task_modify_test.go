@@ -960,6 +960,7 @@ func TestEditFields(t *testing.T) {
 		newBody := "New Body"
 		expectedTask.Body = &newBody
 		utils.AssertTasksEqual(t, &expectedTask, &task)
+		assert.Equal(t, newBody, *task.SearchBody)
 	})
 	t.Run("Edit Due Date Success", func(t *testing.T) {
 		expectedTask := sampleTask
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskSnooze(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_task_snooze@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	notCompleted := false
+	title := "snooze me"
+	insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+		UserID:      userID,
+		SourceID:    external.TASK_SOURCE_ID_GT_TASK,
+		Title:       &title,
+		IsCompleted: &notCompleted,
+	})
+	assert.NoError(t, err)
+	taskID := insertResult.InsertedID.(primitive.ObjectID)
+
+	modifyTask := func(payload string) (int, []byte) {
+		request, _ := http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(payload)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, body
+	}
+	listTaskIDs := func() []primitive.ObjectID {
+		request, _ := http.NewRequest("GET", "/tasks/v4/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var tasks []TaskResultV4
+		assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&tasks))
+		taskIDs := []primitive.ObjectID{}
+		for _, task := range tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		return taskIDs
+	}
+
+	t.Run("InvalidSnoozedUntil", func(t *testing.T) {
+		code, body := modifyTask(`{"snoozed_until": "tomorrow"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"snoozed_until is not a valid time"}`, string(body))
+	})
+	t.Run("InvalidRemindAt", func(t *testing.T) {
+		code, body := modifyTask(`{"remind_at": "tomorrow"}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"remind_at is not a valid time"}`, string(body))
+	})
+	t.Run("Snooze", func(t *testing.T) {
+		snoozedUntil := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+		code, _ := modifyTask(`{"snoozed_until": "` + snoozedUntil + `", "remind_at": "` + snoozedUntil + `"}`)
+		assert.Equal(t, http.StatusOK, code)
+		task, err := database.GetTask(api.DB, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, snoozedUntil, task.SnoozedUntil.Time().UTC().Format(time.RFC3339))
+		assert.Equal(t, snoozedUntil, task.RemindAt.Time().UTC().Format(time.RFC3339))
+		assert.False(t, *task.IsReminderSent)
+		assert.NotContains(t, listTaskIDs(), taskID)
+	})
+	t.Run("SnoozeExpired", func(t *testing.T) {
+		code, _ := modifyTask(`{"snoozed_until": "` + time.Now().Add(-time.Hour).UTC().Format(time.RFC3339) + `"}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Contains(t, listTaskIDs(), taskID)
+	})
+	t.Run("ClearSnooze", func(t *testing.T) {
+		code, _ := modifyTask(`{"snoozed_until": "` + time.Now().Add(time.Hour).UTC().Format(time.RFC3339) + `"}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.NotContains(t, listTaskIDs(), taskID)
+		code, _ = modifyTask(`{"snoozed_until": ""}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Contains(t, listTaskIDs(), taskID)
+	})
+}
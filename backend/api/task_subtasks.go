@@ -0,0 +1,79 @@
+package api
+
+import (
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// cascadeToSubtasks applies a parent's completion, deletion and section move to its subtasks at every level of nesting.
+// Subtasks which are already done or deleted keep their original completion and deletion times.
+func (api *API) cascadeToSubtasks(userID primitive.ObjectID, task *database.Task, updateFields *TaskItemChangeableFields, IDTaskSectionHex *string) error {
+	isCompleting := updateFields.IsCompleted != nil && *updateFields.IsCompleted
+	isDeleting := updateFields.IsDeleted != nil && *updateFields.IsDeleted
+	var IDTaskSection *primitive.ObjectID
+	if IDTaskSectionHex != nil {
+		IDTaskSectionValue, err := primitive.ObjectIDFromHex(*IDTaskSectionHex)
+		if err != nil {
+			return err
+		}
+		IDTaskSection = &IDTaskSectionValue
+	}
+	if !isCompleting && !isDeleting && IDTaskSection == nil {
+		return nil
+	}
+
+	subtasks, err := database.GetTaskDescendants(api.DB, userID, task.ID)
+	if err != nil {
+		return err
+	}
+	now := primitive.NewDateTimeFromTime(time.Now())
+	// only General Task tasks have subtasks, so there is no external source to update
+	for _, subtask := range *subtasks {
+		// for implicit memory aliasing
+		tempSubtask := subtask
+		updateTask := database.Task{UpdatedAt: now}
+		isChanged := false
+		isSubtaskCompleting := false
+		if isCompleting && (subtask.IsCompleted == nil || !*subtask.IsCompleted) {
+			taskSourceResult, err := api.ExternalConfig.GetSourceResult(subtask.SourceID)
+			if err != nil {
+				return err
+			}
+			if taskSourceResult.Details.IsCompletable {
+				completed := true
+				updateTask.IsCompleted = &completed
+				updateTask.CompletedAt = now
+				isChanged = true
+				isSubtaskCompleting = true
+			}
+		}
+		if isDeleting && (subtask.IsDeleted == nil || !*subtask.IsDeleted) {
+			deleted := true
+			updateTask.IsDeleted = &deleted
+			updateTask.DeletedAt = now
+			isChanged = true
+		}
+		if IDTaskSection != nil && subtask.IDTaskSection != *IDTaskSection {
+			updateTask.IDTaskSection = *IDTaskSection
+			isChanged = true
+		}
+		if !isChanged {
+			continue
+		}
+
+		err = api.UpdateTaskInDBWithError(&tempSubtask, userID, &updateTask)
+		if err != nil {
+			return err
+		}
+		database.RecordTaskHistory(api.DB, userID, &tempSubtask, &updateTask, database.TaskHistoryActorUser)
+		if isSubtaskCompleting {
+			err = database.UnblockDependentTasks(api.DB, userID, subtask.ID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSubtaskRollup(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	completed := true
+	deleted := true
+	timeAllocation := int64(time.Hour)
+	taskID := primitive.NewObjectID()
+	subtaskID := primitive.NewObjectID()
+	nestedSubtaskID := primitive.NewObjectID()
+	orphanedTaskID := primitive.NewObjectID()
+	tasks := []database.Task{
+		{ID: taskID, SourceID: external.TASK_SOURCE_ID_GT_TASK, TimeAllocation: &timeAllocation},
+		{ID: subtaskID, ParentTaskID: taskID, SourceID: external.TASK_SOURCE_ID_GT_TASK, TimeAllocation: &timeAllocation},
+		{ID: nestedSubtaskID, ParentTaskID: subtaskID, SourceID: external.TASK_SOURCE_ID_GT_TASK, IsCompleted: &completed, TimeAllocation: &timeAllocation},
+		// deleted subtasks are left out of the rollup
+		{ID: primitive.NewObjectID(), ParentTaskID: subtaskID, SourceID: external.TASK_SOURCE_ID_GT_TASK, IsDeleted: &deleted, TimeAllocation: &timeAllocation},
+		// the parent of this subtask's parent is missing
+		{ID: primitive.NewObjectID(), ParentTaskID: orphanedTaskID, SourceID: external.TASK_SOURCE_ID_GT_TASK},
+		{ID: orphanedTaskID, ParentTaskID: primitive.NewObjectID(), SourceID: external.TASK_SOURCE_ID_GT_TASK},
+	}
+
+	results := api.taskListToTaskResultListV4(&tasks)
+	assert.Equal(t, 4, len(results))
+	assert.Equal(t, &SubtaskRollupV4{
+		SubtaskCount:          2,
+		CompletedSubtaskCount: 1,
+		CompletionPercentage:  50,
+		TotalTimeAllocation:   int64(3 * time.Hour),
+	}, results[0].SubtaskRollup)
+	assert.Equal(t, &SubtaskRollupV4{
+		SubtaskCount:          1,
+		CompletedSubtaskCount: 1,
+		CompletionPercentage:  100,
+		TotalTimeAllocation:   int64(2 * time.Hour),
+	}, results[1].SubtaskRollup)
+	assert.Nil(t, results[2].SubtaskRollup)
+}
+
+func TestCascadeToSubtasks(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_cascade_subtasks@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	insertTask := func(parentTaskID primitive.ObjectID) primitive.ObjectID {
+		notCompleted := false
+		title := "task"
+		insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:        userID,
+			SourceID:      external.TASK_SOURCE_ID_GT_TASK,
+			Title:         &title,
+			ParentTaskID:  parentTaskID,
+			IDTaskSection: constants.IDTaskSectionDefault,
+			IsCompleted:   &notCompleted,
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	modifyTask := func(taskID primitive.ObjectID, payload string) {
+		request, _ := http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(payload)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+	getTask := func(taskID primitive.ObjectID) *database.Task {
+		task, err := database.GetTask(api.DB, taskID, userID)
+		assert.NoError(t, err)
+		return task
+	}
+
+	t.Run("NoCascade", func(t *testing.T) {
+		taskID := insertTask(primitive.NilObjectID)
+		subtaskID := insertTask(taskID)
+		modifyTask(taskID, `{"is_completed": true}`)
+		assert.True(t, *getTask(taskID).IsCompleted)
+		assert.False(t, *getTask(subtaskID).IsCompleted)
+	})
+	t.Run("CascadeComplete", func(t *testing.T) {
+		taskID := insertTask(primitive.NilObjectID)
+		subtaskID := insertTask(taskID)
+		nestedSubtaskID := insertTask(subtaskID)
+		modifyTask(taskID, `{"is_completed": true, "cascade_to_subtasks": true}`)
+		for _, id := range []primitive.ObjectID{taskID, subtaskID, nestedSubtaskID} {
+			task := getTask(id)
+			assert.True(t, *task.IsCompleted)
+			assert.NotEqual(t, primitive.DateTime(0), task.CompletedAt)
+		}
+		events, err := database.GetTaskHistory(api.DB, userID, nestedSubtaskID)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(*events))
+		assert.Equal(t, "is_completed", (*events)[0].Field)
+	})
+	t.Run("CascadeDeleteAndMove", func(t *testing.T) {
+		taskID := insertTask(primitive.NilObjectID)
+		subtaskID := insertTask(taskID)
+		nestedSubtaskID := insertTask(subtaskID)
+		sectionID := primitive.NewObjectID()
+		modifyTask(taskID, `{"is_deleted": true, "id_task_section": "`+sectionID.Hex()+`", "cascade_to_subtasks": true}`)
+		for _, id := range []primitive.ObjectID{taskID, subtaskID, nestedSubtaskID} {
+			task := getTask(id)
+			assert.True(t, *task.IsDeleted)
+			assert.Equal(t, sectionID, task.IDTaskSection)
+		}
+	})
+	t.Run("CascadeOnly", func(t *testing.T) {
+		taskID := insertTask(primitive.NilObjectID)
+		request, _ := http.NewRequest("PATCH", "/tasks/modify/"+taskID.Hex()+"/", bytes.NewBuffer([]byte(`{"cascade_to_subtasks": true}`)))
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	"sort"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	DefaultTimeReportDays = 7
+	MaxTimeReportDays     = 90
+)
+
+type TimeSessionResult struct {
+	ID           primitive.ObjectID `json:"id"`
+	TaskID       primitive.ObjectID `json:"task_id"`
+	IsActive     bool               `json:"is_active"`
+	StartedAt    string             `json:"started_at"`
+	EndedAt      string             `json:"ended_at,omitempty"`
+	DurationSecs int64              `json:"duration_secs"`
+}
+
+type TimerResult struct {
+	ActiveSession *TimeSessionResult `json:"active_session"`
+}
+
+type TimeReportParams struct {
+	DatetimeStart *time.Time `form:"datetime_start"`
+	DatetimeEnd   *time.Time `form:"datetime_end"`
+}
+
+type TimeReportEntry struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	TrackedSecs   int64  `json:"tracked_secs"`
+	AllocatedSecs int64  `json:"allocated_secs"`
+}
+
+type TimeReportDay struct {
+	Date        string `json:"date"`
+	TrackedSecs int64  `json:"tracked_secs"`
+}
+
+type TimeReportResult struct {
+	DatetimeStart string            `json:"datetime_start"`
+	DatetimeEnd   string            `json:"datetime_end"`
+	Tasks         []TimeReportEntry `json:"tasks"`
+	Sections      []TimeReportEntry `json:"sections"`
+	Sources       []TimeReportEntry `json:"sources"`
+	Daily         []TimeReportDay   `json:"daily"`
+}
+
+// TaskTimerStart godoc
+// @Summary      Starts a timer on a task
+// @Description  A user has at most one running timer, so a timer on another task is stopped first
+// @Tags         time tracking
+// @Produce      json
+// @Param        task_id  path      string  true  "Task ID"
+// @Success      200 {object} TimeSessionResult
+// @Failure      404 {object} string "task not found"
+// @Failure      500 {object} string "internal server error"
+// @Router       /tasks/{task_id}/timer/start/ [post]
+func (api *API) TaskTimerStart(c *gin.Context) {
+	taskID, err := primitive.ObjectIDFromHex(c.Param("task_id"))
+	if err != nil {
+		// This means the task ID is improperly formatted
+		Handle404(c)
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	_, err = database.GetTask(api.DB, taskID, userID)
+	if err != nil {
+		Handle404(c)
+		return
+	}
+
+	now := api.GetCurrentTime()
+	activeSession, err := database.GetActiveTimeSession(api.DB, userID)
+	if err == nil && activeSession.TaskID == taskID {
+		c.JSON(200, getTimeSessionResult(*activeSession, now))
+		return
+	} else if err != nil && err != mongo.ErrNoDocuments {
+		Handle500(c)
+		return
+	}
+	session, err := database.StartTimeSession(api.DB, userID, taskID, now)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	c.JSON(200, getTimeSessionResult(*session, now))
+}
+
+// TimerStop godoc
+// @Summary      Stops the user's running timer
+// @Tags         time tracking
+// @Produce      json
+// @Success      200 {object} TimeSessionResult
+// @Failure      400 {object} string "no timer is running"
+// @Failure      500 {object} string "internal server error"
+// @Router       /timer/stop/ [post]
+func (api *API) TimerStop(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	now := api.GetCurrentTime()
+	session, err := database.StopTimeSession(api.DB, userID, now)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(400, gin.H{"detail": "no timer is running"})
+		return
+	} else if err != nil {
+		Handle500(c)
+		return
+	}
+	c.JSON(200, getTimeSessionResult(*session, now))
+}
+
+// TimerGet godoc
+// @Summary      Returns the user's running timer
+// @Description  The active session is null when no timer is running
+// @Tags         time tracking
+// @Produce      json
+// @Success      200 {object} TimerResult
+// @Failure      500 {object} string "internal server error"
+// @Router       /timer/ [get]
+func (api *API) TimerGet(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	session, err := database.GetActiveTimeSession(api.DB, userID)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(200, TimerResult{})
+		return
+	} else if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch active time session")
+		Handle500(c)
+		return
+	}
+	result := getTimeSessionResult(*session, api.GetCurrentTime())
+	c.JSON(200, TimerResult{ActiveSession: &result})
+}
+
+// TimeTrackingReport godoc
+// @Summary      Compares tracked time with allocated time
+// @Description  Totals are given per task, section and source for the tasks worked on in the time range, along with a total per day. Allocated time comes from each task's time_duration
+// @Tags         time tracking
+// @Produce      json
+// @Param        datetime_start  query     string  false  "start of the report, defaulting to a week before the end"
+// @Param        datetime_end    query     string  false  "end of the report, defaulting to now"
+// @Success      200 {object} TimeReportResult
+// @Failure      400 {object} string "invalid params"
+// @Failure      500 {object} string "internal server error"
+// @Router       /time_tracking/report/ [get]
+func (api *API) TimeTrackingReport(c *gin.Context) {
+	var params TimeReportParams
+	err := c.BindQuery(&params)
+	if err != nil {
+		c.JSON(400, gin.H{"detail": "invalid or missing parameter"})
+		return
+	}
+	now := api.GetCurrentTime()
+	end := now
+	if params.DatetimeEnd != nil {
+		end = *params.DatetimeEnd
+	}
+	start := end.Add(-DefaultTimeReportDays * 24 * time.Hour)
+	if params.DatetimeStart != nil {
+		start = *params.DatetimeStart
+	}
+	if !start.Before(end) {
+		c.JSON(400, gin.H{"detail": "'datetime_start' must be before 'datetime_end'"})
+		return
+	}
+	if end.Sub(start) > MaxTimeReportDays*24*time.Hour {
+		c.JSON(400, gin.H{"detail": "time range cannot be longer than 90 days"})
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	sessions, err := database.GetTimeSessions(api.DB, userID, start, end)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	taskIDToTracked := make(map[primitive.ObjectID]time.Duration)
+	taskIDs := []primitive.ObjectID{}
+	for _, session := range *sessions {
+		if _, exists := taskIDToTracked[session.TaskID]; !exists {
+			taskIDs = append(taskIDs, session.TaskID)
+		}
+		taskIDToTracked[session.TaskID] += database.GetTimeSessionDuration(session, start, end, now)
+	}
+	// deleted and completed tasks are included, as the time was still spent on them
+	var tasks []database.Task
+	err = database.FindWithCollection(database.GetTaskCollection(api.DB), userID, &[]bson.M{{"_id": bson.M{"$in": taskIDs}}}, &tasks, nil)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch tracked tasks")
+		Handle500(c)
+		return
+	}
+
+	result := TimeReportResult{
+		DatetimeStart: start.UTC().Format(time.RFC3339),
+		DatetimeEnd:   end.UTC().Format(time.RFC3339),
+		Tasks:         []TimeReportEntry{},
+		Sections:      []TimeReportEntry{},
+		Sources:       []TimeReportEntry{},
+		Daily:         []TimeReportDay{},
+	}
+	sectionIndexes := make(map[primitive.ObjectID]int)
+	sourceIndexes := make(map[string]int)
+	for _, task := range tasks {
+		entry := TimeReportEntry{
+			ID:          task.ID.Hex(),
+			TrackedSecs: int64(taskIDToTracked[task.ID].Seconds()),
+		}
+		if task.Title != nil {
+			entry.Name = *task.Title
+		}
+		if task.TimeAllocation != nil {
+			entry.AllocatedSecs = int64(time.Duration(*task.TimeAllocation).Seconds())
+		}
+		result.Tasks = append(result.Tasks, entry)
+
+		sectionIndex, exists := sectionIndexes[task.IDTaskSection]
+		if !exists {
+			sectionName, err := database.GetTaskSectionName(api.DB, task.IDTaskSection, userID)
+			if err != nil {
+				sectionName = ""
+			}
+			sectionIndex = len(result.Sections)
+			sectionIndexes[task.IDTaskSection] = sectionIndex
+			result.Sections = append(result.Sections, TimeReportEntry{ID: task.IDTaskSection.Hex(), Name: sectionName})
+		}
+		result.Sections[sectionIndex].TrackedSecs += entry.TrackedSecs
+		result.Sections[sectionIndex].AllocatedSecs += entry.AllocatedSecs
+
+		sourceIndex, exists := sourceIndexes[task.SourceID]
+		if !exists {
+			sourceName := task.SourceID
+			taskSourceResult, err := api.ExternalConfig.GetSourceResult(task.SourceID)
+			if err == nil {
+				sourceName = taskSourceResult.Details.Name
+			}
+			sourceIndex = len(result.Sources)
+			sourceIndexes[task.SourceID] = sourceIndex
+			result.Sources = append(result.Sources, TimeReportEntry{ID: task.SourceID, Name: sourceName})
+		}
+		result.Sources[sourceIndex].TrackedSecs += entry.TrackedSecs
+		result.Sources[sourceIndex].AllocatedSecs += entry.AllocatedSecs
+	}
+	for _, entries := range [][]TimeReportEntry{result.Tasks, result.Sections, result.Sources} {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].TrackedSecs > entries[j].TrackedSecs
+		})
+	}
+
+	for day, duration := range database.GetTimeSessionDailyTotals(*sessions, start, end, now) {
+		result.Daily = append(result.Daily, TimeReportDay{Date: day.Format("2006-01-02"), TrackedSecs: int64(duration.Seconds())})
+	}
+	sort.Slice(result.Daily, func(i, j int) bool {
+		return result.Daily[i].Date < result.Daily[j].Date
+	})
+	c.JSON(200, result)
+}
+
+func getTimeSessionResult(session database.TaskTimeSession, now time.Time) TimeSessionResult {
+	result := TimeSessionResult{
+		ID:        session.ID,
+		TaskID:    session.TaskID,
+		IsActive:  session.IsActive,
+		StartedAt: session.StartedAt.Time().UTC().Format(time.RFC3339),
+	}
+	sessionEnd := now
+	if !session.IsActive {
+		sessionEnd = session.EndedAt.Time()
+		result.EndedAt = sessionEnd.UTC().Format(time.RFC3339)
+	}
+	result.DurationSecs = int64(sessionEnd.Sub(session.StartedAt.Time()).Seconds())
+	return result
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTimeTracking(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_time_tracking@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	insertTask := func(title string, sourceID string, timeAllocation int64) primitive.ObjectID {
+		notCompleted := false
+		insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:         userID,
+			SourceID:       sourceID,
+			Title:          &title,
+			IDTaskSection:  constants.IDTaskSectionDefault,
+			IsCompleted:    &notCompleted,
+			TimeAllocation: &timeAllocation,
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	taskID := insertTask("write report", external.TASK_SOURCE_ID_GT_TASK, int64(time.Hour))
+	otherTaskID := insertTask("review PR", external.TASK_SOURCE_ID_LINEAR, int64(30*time.Minute))
+
+	sendRequest := func(method string, url string, at time.Time) (int, []byte) {
+		api.OverrideTime = &at
+		request, _ := http.NewRequest(method, url, nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		body, err := io.ReadAll(recorder.Body)
+		assert.NoError(t, err)
+		return recorder.Code, body
+	}
+	startTime := time.Date(2023, 3, 1, 16, 0, 0, 0, time.UTC)
+
+	UnauthorizedTest(t, "POST", "/tasks/"+taskID.Hex()+"/timer/start/", nil)
+	UnauthorizedTest(t, "GET", "/timer/", nil)
+	UnauthorizedTest(t, "POST", "/timer/stop/", nil)
+	UnauthorizedTest(t, "GET", "/time_tracking/report/", nil)
+	t.Run("NoTimerRunning", func(t *testing.T) {
+		code, body := sendRequest("GET", "/timer/", startTime)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, `{"active_session":null}`, string(body))
+		code, body = sendRequest("POST", "/timer/stop/", startTime)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"no timer is running"}`, string(body))
+	})
+	t.Run("StartTaskNotFound", func(t *testing.T) {
+		code, _ := sendRequest("POST", "/tasks/"+primitive.NewObjectID().Hex()+"/timer/start/", startTime)
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+	t.Run("StartAndStop", func(t *testing.T) {
+		code, body := sendRequest("POST", "/tasks/"+taskID.Hex()+"/timer/start/", startTime)
+		assert.Equal(t, http.StatusOK, code)
+		var session TimeSessionResult
+		assert.NoError(t, json.Unmarshal(body, &session))
+		assert.Equal(t, taskID, session.TaskID)
+		assert.True(t, session.IsActive)
+
+		// starting the same timer again keeps it running
+		code, body = sendRequest("POST", "/tasks/"+taskID.Hex()+"/timer/start/", startTime.Add(10*time.Minute))
+		assert.Equal(t, http.StatusOK, code)
+		var sameSession TimeSessionResult
+		assert.NoError(t, json.Unmarshal(body, &sameSession))
+		assert.Equal(t, session.ID, sameSession.ID)
+		assert.Equal(t, int64(600), sameSession.DurationSecs)
+
+		// starting a timer on another task stops the first one
+		code, _ = sendRequest("POST", "/tasks/"+otherTaskID.Hex()+"/timer/start/", startTime.Add(45*time.Minute))
+		assert.Equal(t, http.StatusOK, code)
+		code, body = sendRequest("GET", "/timer/", startTime.Add(50*time.Minute))
+		assert.Equal(t, http.StatusOK, code)
+		var timer TimerResult
+		assert.NoError(t, json.Unmarshal(body, &timer))
+		assert.Equal(t, otherTaskID, timer.ActiveSession.TaskID)
+
+		code, body = sendRequest("POST", "/timer/stop/", startTime.Add(time.Hour))
+		assert.Equal(t, http.StatusOK, code)
+		var stoppedSession TimeSessionResult
+		assert.NoError(t, json.Unmarshal(body, &stoppedSession))
+		assert.False(t, stoppedSession.IsActive)
+		assert.Equal(t, int64(900), stoppedSession.DurationSecs)
+	})
+	t.Run("InvalidReportRange", func(t *testing.T) {
+		code, body := sendRequest("GET", "/time_tracking/report/?datetime_start=2023-03-02T00:00:00Z&datetime_end=2023-03-01T00:00:00Z", startTime)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, `{"detail":"'datetime_start' must be before 'datetime_end'"}`, string(body))
+		code, _ = sendRequest("GET", "/time_tracking/report/?datetime_start=2022-01-01T00:00:00Z&datetime_end=2023-03-01T00:00:00Z", startTime)
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+	t.Run("Report", func(t *testing.T) {
+		code, body := sendRequest("GET", "/time_tracking/report/", startTime.Add(2*time.Hour))
+		assert.Equal(t, http.StatusOK, code)
+		var report TimeReportResult
+		assert.NoError(t, json.Unmarshal(body, &report))
+		assert.Equal(t, []TimeReportEntry{
+			{ID: taskID.Hex(), Name: "write report", TrackedSecs: 2700, AllocatedSecs: 3600},
+			{ID: otherTaskID.Hex(), Name: "review PR", TrackedSecs: 900, AllocatedSecs: 1800},
+		}, report.Tasks)
+		assert.Equal(t, []TimeReportEntry{
+			{ID: constants.IDTaskSectionDefault.Hex(), Name: constants.TaskSectionNameDefault, TrackedSecs: 3600, AllocatedSecs: 5400},
+		}, report.Sections)
+		assert.Equal(t, 2, len(report.Sources))
+		assert.Equal(t, external.TASK_SOURCE_ID_GT_TASK, report.Sources[0].ID)
+		assert.Equal(t, []TimeReportDay{{Date: "2023-03-01", TrackedSecs: 3600}}, report.Daily)
+	})
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type TrashResult struct {
+	Tasks []*TaskResultV4 `json:"tasks"`
+	Notes []*NoteResult   `json:"notes"`
+}
+
+// TrashList godoc
+// @Summary      Lists the user's deleted tasks and notes, most recently deleted first
+// @Tags         trash
+// @Produce      json
+// @Success      200 {object} TrashResult
+// @Failure      500 {object} string "internal server error"
+// @Router       /trash/ [get]
+func (api *API) TrashList(c *gin.Context) {
+	userID := getUserIDFromContext(c)
+	tasks, err := database.GetDeletedTasks(api.DB, userID)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	notes, err := database.GetDeletedNotes(api.DB, userID)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	c.JSON(200, TrashResult{
+		Tasks: api.trashTaskListToTaskResultList(tasks),
+		Notes: api.noteListToNoteResultList(notes),
+	})
+}
+
+// unlike the task list, subtasks are kept when their parent task is not deleted, so that they can be restored on their own
+func (api *API) trashTaskListToTaskResultList(tasks *[]database.Task) []*TaskResultV4 {
+	parentToChildIDs := make(map[primitive.ObjectID][]primitive.ObjectID)
+	for _, task := range *tasks {
+		if task.ParentTaskID != primitive.NilObjectID {
+			parentToChildIDs[task.ParentTaskID] = append(parentToChildIDs[task.ParentTaskID], task.ID)
+		}
+	}
+	taskResults := []*TaskResultV4{}
+	for _, task := range *tasks {
+		// for implicit memory aliasing
+		tempTask := task
+		taskResult := api.taskToTaskResultV4(&tempTask)
+		taskResult.SubTaskIDs = parentToChildIDs[task.ID]
+		taskResults = append(taskResults, taskResult)
+	}
+	return taskResults
+}
+
+// TaskRestore godoc
+// @Summary      Restores a deleted task to its original section and ordering
+// @Description  Tasks whose section has since been deleted are restored to the default section. Subtasks which were deleted along with the task are restored with it, and a subtask cannot be restored while its parent task is deleted
+// @Tags         trash
+// @Produce      json
+// @Param        task_id  path      string  true  "Task ID"
+// @Success      200 {object} string "success"
+// @Failure      400 {object} string "task is not deleted, or its parent task is deleted"
+// @Failure      404 {object} string "task not found"
+// @Failure      500 {object} string "internal server error"
+// @Router       /tasks/restore/{task_id}/ [post]
+func (api *API) TaskRestore(c *gin.Context) {
+	taskID, err := primitive.ObjectIDFromHex(c.Param("task_id"))
+	if err != nil {
+		// This means the task ID is improperly formatted
+		Handle404(c)
+		return
+	}
+	userID := getUserIDFromContext(c)
+	task, err := database.GetTask(api.DB, taskID, userID)
+	if err != nil {
+		Handle404(c)
+		return
+	}
+	if task.IsDeleted == nil || !*task.IsDeleted {
+		c.JSON(400, gin.H{"detail": "task is not deleted"})
+		return
+	}
+	if task.ParentTaskID != primitive.NilObjectID {
+		parentTask, err := database.GetTask(api.DB, task.ParentTaskID, userID)
+		// the parent may have been purged from the trash already, in which case the subtask is restored on its own
+		if err == nil && parentTask.IsDeleted != nil && *parentTask.IsDeleted {
+			c.JSON(400, gin.H{"detail": "parent task is deleted"})
+			return
+		}
+	}
+
+	IDTaskSection := task.IDTaskSection
+	if IDTaskSection != primitive.NilObjectID {
+		_, err = database.GetTaskSectionName(api.DB, IDTaskSection, userID)
+		if err != nil {
+			IDTaskSection = constants.IDTaskSectionDefault
+		}
+	}
+	descendants, err := database.GetTaskDescendants(api.DB, userID, taskID)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to fetch subtasks to restore")
+		Handle500(c)
+		return
+	}
+	restoredTaskIDs := []primitive.ObjectID{taskID}
+	for _, subtask := range *descendants {
+		// subtasks which were deleted before the task stay in the trash
+		if subtask.IsDeleted != nil && *subtask.IsDeleted && subtask.DeletedAt >= task.DeletedAt {
+			restoredTaskIDs = append(restoredTaskIDs, subtask.ID)
+		}
+	}
+	taskCollection := database.GetTaskCollection(api.DB)
+	_, err = taskCollection.UpdateMany(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": bson.M{"$in": restoredTaskIDs}},
+			{"user_id": userID},
+		}},
+		bson.M{
+			"$set":   bson.M{"is_deleted": false, "id_task_section": IDTaskSection},
+			"$unset": bson.M{"deleted_at": ""},
+		},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to restore task")
+		Handle500(c)
+		return
+	}
+	if IDTaskSection != task.IDTaskSection {
+		database.RecordTaskHistory(api.DB, userID, task, &database.Task{IDTaskSection: IDTaskSection}, database.TaskHistoryActorUser)
+	}
+	err = database.AdjustOrderingIDsForCollection(taskCollection, userID, taskID, task.IDOrdering)
+	if err != nil {
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
+
+// NoteRestore godoc
+// @Summary      Restores a deleted note
+// @Tags         trash
+// @Produce      json
+// @Param        note_id  path      string  true  "Note ID"
+// @Success      200 {object} string "success"
+// @Failure      400 {object} string "note is not deleted"
+// @Failure      404 {object} string "note not found"
+// @Failure      500 {object} string "internal server error"
+// @Router       /notes/restore/{note_id}/ [post]
+func (api *API) NoteRestore(c *gin.Context) {
+	noteID, err := primitive.ObjectIDFromHex(c.Param("note_id"))
+	if err != nil {
+		// This means the note ID is improperly formatted
+		Handle404(c)
+		return
+	}
+	userID := getUserIDFromContext(c)
+	note, err := database.GetNote(api.DB, noteID, userID)
+	if err != nil {
+		Handle404(c)
+		return
+	}
+	if note.IsDeleted == nil || !*note.IsDeleted {
+		c.JSON(400, gin.H{"detail": "note is not deleted"})
+		return
+	}
+
+	_, err = database.GetNoteCollection(api.DB).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": noteID},
+			{"user_id": userID},
+		}},
+		bson.M{
+			"$set":   bson.M{"is_deleted": false},
+			"$unset": bson.M{"deleted_at": ""},
+		},
+	)
+	if err != nil {
+		api.Logger.Error().Err(err).Msg("failed to restore note")
+		Handle500(c)
+		return
+	}
+	c.JSON(200, gin.H{})
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTrash(t *testing.T) {
+	api, dbCleanup := GetAPIWithDBCleanup()
+	defer dbCleanup()
+	router := GetRouter(api)
+	authToken := login("test_trash@generaltask.com", "")
+	userID := getUserIDFromAuthToken(t, api.DB, authToken)
+
+	deleted := true
+	notDeleted := false
+	notCompleted := false
+	deletedAt := primitive.NewDateTimeFromTime(time.Now())
+	insertTask := func(isDeleted *bool, IDTaskSection primitive.ObjectID, IDOrdering int) primitive.ObjectID {
+		insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:        userID,
+			SourceID:      external.TASK_SOURCE_ID_GT_TASK,
+			IsCompleted:   &notCompleted,
+			IsDeleted:     isDeleted,
+			DeletedAt:     deletedAt,
+			IDTaskSection: IDTaskSection,
+			IDOrdering:    IDOrdering,
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	insertSubtask := func(parentTaskID primitive.ObjectID, subtaskDeletedAt primitive.DateTime) primitive.ObjectID {
+		insertResult, err := database.GetTaskCollection(api.DB).InsertOne(context.Background(), database.Task{
+			UserID:        userID,
+			SourceID:      external.TASK_SOURCE_ID_GT_TASK,
+			ParentTaskID:  parentTaskID,
+			IsCompleted:   &notCompleted,
+			IsDeleted:     &deleted,
+			DeletedAt:     subtaskDeletedAt,
+			IDTaskSection: constants.IDTaskSectionDefault,
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	restore := func(path string) int {
+		request, _ := http.NewRequest("POST", path, nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	// orderings are after those of the starter tasks
+	firstTaskID := insertTask(&notDeleted, constants.IDTaskSectionDefault, 100)
+	deletedTaskID := insertTask(&deleted, constants.IDTaskSectionDefault, 101)
+	lastTaskID := insertTask(&notDeleted, constants.IDTaskSectionDefault, 101)
+	// the section of this task no longer exists
+	orphanedTaskID := insertTask(&deleted, primitive.NewObjectID(), 103)
+	// subtasks deleted along with their parent, and one deleted on its own beforehand
+	subtaskID := insertSubtask(deletedTaskID, deletedAt)
+	nestedSubtaskID := insertSubtask(subtaskID, deletedAt)
+	previouslyDeletedSubtaskID := insertSubtask(deletedTaskID, primitive.NewDateTimeFromTime(deletedAt.Time().Add(-time.Hour)))
+	// a subtask deleted on its own, while its parent task is not deleted
+	subtaskOfActiveTaskID := insertSubtask(firstTaskID, deletedAt)
+	title := "deleted note"
+	noteResult, err := database.GetNoteCollection(api.DB).InsertOne(context.Background(), database.Note{
+		UserID:    userID,
+		Title:     &title,
+		IsDeleted: &deleted,
+		DeletedAt: deletedAt,
+	})
+	assert.NoError(t, err)
+	noteID := noteResult.InsertedID.(primitive.ObjectID)
+
+	UnauthorizedTest(t, "GET", "/trash/", nil)
+	UnauthorizedTest(t, "POST", "/tasks/restore/"+deletedTaskID.Hex()+"/", nil)
+	UnauthorizedTest(t, "POST", "/notes/restore/"+noteID.Hex()+"/", nil)
+	t.Run("List", func(t *testing.T) {
+		request, _ := http.NewRequest("GET", "/trash/", nil)
+		request.Header.Add("Authorization", "Bearer "+authToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		var result TrashResult
+		assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		taskIDs := []primitive.ObjectID{}
+		for _, task := range result.Tasks {
+			taskIDs = append(taskIDs, task.ID)
+		}
+		assert.ElementsMatch(t, []primitive.ObjectID{deletedTaskID, orphanedTaskID, subtaskID, nestedSubtaskID, previouslyDeletedSubtaskID, subtaskOfActiveTaskID}, taskIDs)
+		assert.Equal(t, 1, len(result.Notes))
+		assert.Equal(t, noteID, result.Notes[0].ID)
+	})
+	t.Run("RestoreNotDeleted", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, restore("/tasks/restore/"+firstTaskID.Hex()+"/"))
+	})
+	t.Run("RestoreNotFound", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, restore("/tasks/restore/"+primitive.NewObjectID().Hex()+"/"))
+		assert.Equal(t, http.StatusNotFound, restore("/notes/restore/"+primitive.NewObjectID().Hex()+"/"))
+	})
+	t.Run("RestoreSubtaskOfDeletedTask", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, restore("/tasks/restore/"+subtaskID.Hex()+"/"))
+		subtask, err := database.GetTask(api.DB, subtaskID, userID)
+		assert.NoError(t, err)
+		assert.True(t, *subtask.IsDeleted)
+	})
+	t.Run("RestoreTask", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, restore("/tasks/restore/"+deletedTaskID.Hex()+"/"))
+		task, err := database.GetTask(api.DB, deletedTaskID, userID)
+		assert.NoError(t, err)
+		assert.False(t, *task.IsDeleted)
+		assert.Equal(t, constants.IDTaskSectionDefault, task.IDTaskSection)
+		// the task is back between its neighbours, and the task after it moves back to make room
+		firstTask, err := database.GetTask(api.DB, firstTaskID, userID)
+		assert.NoError(t, err)
+		lastTask, err := database.GetTask(api.DB, lastTaskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, firstTask.IDOrdering+1, task.IDOrdering)
+		assert.Equal(t, task.IDOrdering+1, lastTask.IDOrdering)
+		for _, restoredSubtaskID := range []primitive.ObjectID{subtaskID, nestedSubtaskID} {
+			subtask, err := database.GetTask(api.DB, restoredSubtaskID, userID)
+			assert.NoError(t, err)
+			assert.False(t, *subtask.IsDeleted)
+		}
+		subtask, err := database.GetTask(api.DB, previouslyDeletedSubtaskID, userID)
+		assert.NoError(t, err)
+		assert.True(t, *subtask.IsDeleted)
+	})
+	t.Run("RestoreTaskToDefaultSection", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, restore("/tasks/restore/"+orphanedTaskID.Hex()+"/"))
+		task, err := database.GetTask(api.DB, orphanedTaskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, constants.IDTaskSectionDefault, task.IDTaskSection)
+	})
+	t.Run("RestoreSubtaskOfActiveTask", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, restore("/tasks/restore/"+subtaskOfActiveTaskID.Hex()+"/"))
+		subtask, err := database.GetTask(api.DB, subtaskOfActiveTaskID, userID)
+		assert.NoError(t, err)
+		assert.False(t, *subtask.IsDeleted)
+	})
+	t.Run("RestoreNote", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, restore("/notes/restore/"+noteID.Hex()+"/"))
+		note, err := database.GetNote(api.DB, noteID, userID)
+		assert.NoError(t, err)
+		assert.False(t, *note.IsDeleted)
+		assert.Equal(t, http.StatusBadRequest, restore("/notes/restore/"+noteID.Hex()+"/"))
+	})
+}
@@ -10,6 +10,9 @@ import (
 
 type Environment int
 
+// PlaceholderSecret is the value secrets are given in the checked in .env file
+const PlaceholderSecret = "dummy_value"
+
 const (
 	Undefined Environment = iota
 	Dev
@@ -56,3 +59,9 @@ func GetConfigValue(key string) string {
 func GetAuthorizationURL(serviceID string) string {
 	return GetConfigValue("SERVER_URL") + "link/" + serviceID + "/"
 }
+
+// IsSecretConfigured is false for secrets left empty or set to the .env placeholder,
+// which anyone could use to forge a signature
+func IsSecretConfigured(secret string) bool {
+	return secret != "" && secret != PlaceholderSecret
+}
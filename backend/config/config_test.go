@@ -42,3 +42,9 @@ func TestGetAuthorizationURL(t *testing.T) {
 		assert.True(t, strings.HasSuffix(authURL, "link/test_service/"))
 	})
 }
+
+func TestIsSecretConfigured(t *testing.T) {
+	assert.False(t, IsSecretConfigured(""))
+	assert.False(t, IsSecretConfigured(PlaceholderSecret))
+	assert.True(t, IsSecretConfigured("a_real_secret"))
+}
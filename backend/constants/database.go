@@ -3,5 +3,11 @@ package constants
 const MAX_COMPLETED_TASKS = 100
 const MAX_DELETED_TASKS = 100
 
+// used when TRASH_RETENTION_DAYS is not set to a positive number of days
+const DEFAULT_TRASH_RETENTION_DAYS = 30
+
 const COMMENT_TYPE_TOPLEVEL = "toplevel"
 const COMMENT_TYPE_INLINE = "inline"
+
+// how long the ZIP file of a completed data export is kept for download
+const DATA_EXPORT_RETENTION_DAYS = 7
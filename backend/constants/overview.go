@@ -3,9 +3,10 @@ package constants
 type ViewType string
 
 const (
-	ViewJiraSourceName   = "Jira"
-	ViewLinearSourceName = "Linear"
-	ViewSlackSourceName  = "Slack"
+	ViewJiraSourceName        = "Jira"
+	ViewLinearSourceName      = "Linear"
+	ViewSlackSourceName       = "Slack"
+	ViewGithubIssueSourceName = "GitHub"
 )
 
 const (
@@ -13,6 +14,7 @@ const (
 	ViewLinearName             = "Linear Issues"
 	ViewSlackName              = "Slack Messages"
 	ViewGithubName             = "Github"
+	ViewGithubIssueName        = "GitHub Issues"
 	ViewMeetingPreparationName = "Meeting Preparation"
 	ViewDueTodayName           = "Due Today"
 )
@@ -23,6 +25,7 @@ const (
 	ViewLinear             ViewType = "linear"
 	ViewSlack              ViewType = "slack"
 	ViewGithub             ViewType = "github"
+	ViewGithubIssue        ViewType = "github_issue"
 	ViewMeetingPreparation ViewType = "meeting_preparation"
 	ViewDueToday           ViewType = "due_today"
 )
@@ -0,0 +1,10 @@
+package constants
+
+// Valid values for recurrence_rate on recurring task templates
+const (
+	RecurrenceRateDaily     int = 0
+	RecurrenceRateWeekDaily int = 1
+	RecurrenceRateWeekly    int = 2
+	RecurrenceRateMonthly   int = 3
+	RecurrenceRateAnnually  int = 4
+)
@@ -46,6 +46,12 @@ const (
 	LabSmartPrioritizeEnabled = "lab_smart_prioritize_enabled"
 	// Misc settings
 	HasDismissedMulticalPrompt = "has_dismissed_multical_prompt"
+	// Reminder settings
+	SettingFieldReminderNotifier = "reminder_notifier_preference"
+	ChoiceKeyEmail               = "email"
+	ChoiceKeySlack               = "slack"
+	// Dashboard settings
+	SettingFieldShareTrackedTime = "share_tracked_time_with_teams"
 )
 
 const (
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -24,19 +26,106 @@ func UpdateOrCreateTask(
 	fieldsToInsertIfMissing interface{},
 	fieldsToUpdate interface{},
 	additionalFilters *[]bson.M,
+) (*Task, error) {
+	return UpdateOrCreateTaskWithActor(db, userID, IDExternal, sourceID, fieldsToInsertIfMissing, fieldsToUpdate, additionalFilters, TaskHistoryActorFetch)
+}
+
+// UpdateOrCreateTaskWithActor records changes to the existing task in the task history as made by the actor
+func UpdateOrCreateTaskWithActor(
+	db *mongo.Database,
+	userID primitive.ObjectID,
+	IDExternal string,
+	sourceID string,
+	fieldsToInsertIfMissing interface{},
+	fieldsToUpdate interface{},
+	additionalFilters *[]bson.M,
+	actor string,
 ) (*Task, error) {
 	taskCollection := GetTaskCollection(db)
 	logger := logging.GetSentryLogger()
+	dbQuery := getDBQuery(userID, IDExternal, sourceID, additionalFilters)
+
+	isNewTask := false
+	if fieldsToInsertIfMissing != nil {
+		insertResult, err := taskCollection.UpdateOne(
+			context.Background(),
+			dbQuery,
+			bson.M{"$setOnInsert": fieldsToInsertIfMissing},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to update or create task")
+			return nil, err
+		}
+		isNewTask = insertResult.UpsertedCount > 0
+	}
+
+	// the task is returned as it was before the update for the history, and the new values are applied from fieldsToUpdate
+	previousDocument, err := taskCollection.FindOneAndUpdate(
+		context.Background(),
+		dbQuery,
+		bson.M{"$set": fieldsToUpdate},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).DecodeBytes()
+	if err == mongo.ErrNoDocuments {
+		// the task was created by the update, so there is no history to record
+		var task Task
+		err = taskCollection.FindOne(context.Background(), dbQuery).Decode(&task)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to load created task")
+			return nil, err
+		}
+		return &task, nil
+	} else if err != nil {
+		logger.Error().Err(err).Msg("failed to update or create task")
+		return nil, err
+	}
 
-	mongoResult, err := FindOneAndUpdateWithCollection(taskCollection, userID, IDExternal, sourceID, fieldsToInsertIfMissing, fieldsToUpdate, additionalFilters)
+	var previousTask Task
+	err = bson.Unmarshal(previousDocument, &previousTask)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to decode task before update")
+		return nil, err
+	}
+	task, err := applyTaskUpdate(previousDocument, fieldsToUpdate)
 	if err != nil {
+		logger.Error().Err(err).Msg("failed to apply task update")
 		return nil, err
 	}
+	if !isNewTask {
+		// the history is best effort, so a failure to record it should not fail the sync
+		RecordTaskHistory(db, userID, &previousTask, task, actor)
+	}
+	return task, nil
+}
 
+// applyTaskUpdate replaces the top level fields of the document like $set does
+func applyTaskUpdate(previousDocument bson.Raw, fieldsToUpdate interface{}) (*Task, error) {
+	document := bson.M{}
+	err := bson.Unmarshal(previousDocument, &document)
+	if err != nil {
+		return nil, err
+	}
+	updateBytes, err := bson.Marshal(fieldsToUpdate)
+	if err != nil {
+		return nil, err
+	}
+	updateDocument := bson.M{}
+	err = bson.Unmarshal(updateBytes, &updateDocument)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range updateDocument {
+		document[key] = value
+	}
+
+	documentBytes, err := bson.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
 	var task Task
-	err = mongoResult.Decode(&task)
+	err = bson.Unmarshal(documentBytes, &task)
 	if err != nil {
-		logger.Error().Err(err).Msg("failed to update or create task")
 		return nil, err
 	}
 	return &task, nil
@@ -254,29 +343,11 @@ func GetSharedTask(db *mongo.Database, taskID primitive.ObjectID, userID *primit
 		if userID == nil {
 			return nil, errors.New("user is not allowed to access this task")
 		}
-		user, err := GetUser(db, *userID)
+		isSameDomain, err := IsSameDomain(db, *userID, task.UserID)
 		if err != nil {
-			logger.Error().Err(err).Msgf("failed to get user: %+v", userID)
 			return nil, err
 		}
-		taskOwner, err := GetUser(db, task.UserID)
-		if err != nil {
-			logger.Error().Err(err).Msgf("failed to get user: %+v", task.UserID)
-			return nil, err
-		}
-		userDomain, err := GetEmailDomain(user.Email)
-		if err != nil {
-			logger.Error().Err(err).Msgf("failed to get user domain: %+v", user.Email)
-			return nil, err
-		}
-		taskOwnerDomain, err := GetEmailDomain(taskOwner.Email)
-		if err != nil {
-			logger.Error().Err(err).Msgf("failed to get task owner domain: %+v", taskOwner.Email)
-			return nil, err
-		}
-
-		// Check if the user and the task owner are in the same domain
-		if userDomain != taskOwnerDomain {
+		if !isSameDomain {
 			return nil, errors.New("user domain does not match task owner domain")
 		}
 	}
@@ -284,6 +355,31 @@ func GetSharedTask(db *mongo.Database, taskID primitive.ObjectID, userID *primit
 	return &task, nil
 }
 
+func IsSameDomain(db *mongo.Database, userID primitive.ObjectID, otherUserID primitive.ObjectID) (bool, error) {
+	logger := logging.GetSentryLogger()
+	user, err := GetUser(db, userID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to get user: %+v", userID)
+		return false, err
+	}
+	otherUser, err := GetUser(db, otherUserID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to get user: %+v", otherUserID)
+		return false, err
+	}
+	userDomain, err := GetEmailDomain(user.Email)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to get user domain: %+v", user.Email)
+		return false, err
+	}
+	otherUserDomain, err := GetEmailDomain(otherUser.Email)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to get user domain: %+v", otherUser.Email)
+		return false, err
+	}
+	return userDomain == otherUserDomain, nil
+}
+
 func GetSharedNote(db *mongo.Database, itemID primitive.ObjectID) (*Note, error) {
 	logger := logging.GetSentryLogger()
 	mongoResult := GetNoteCollection(db).FindOne(
@@ -407,6 +503,65 @@ func GetTaskByExternalIDWithoutUser(db *mongo.Database, externalID string, logEr
 	return &task, nil
 }
 
+func GetTaskByExternalID(db *mongo.Database, externalID string, userID primitive.ObjectID) (*Task, error) {
+	logger := logging.GetSentryLogger()
+	var task Task
+
+	err := FindOneExternalWithCollection(
+		GetTaskCollection(db),
+		userID,
+		externalID,
+	).Decode(&task)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			logger.Error().Err(err).Msgf("failed to get task: %+v", externalID)
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+// external IDs are only unique within a source, e.g. Jira and Linear issues can share an ID
+func GetTaskBySourceExternalID(db *mongo.Database, externalID string, sourceID string, userID primitive.ObjectID) (*Task, error) {
+	var task Task
+	err := GetTaskCollection(db).FindOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"id_external": externalID},
+			{"source_id": sourceID},
+			{"user_id": userID},
+		}},
+	).Decode(&task)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			logging.GetSentryLogger().Error().Err(err).Msgf("failed to get task: %+v", externalID)
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+func GetTasksBySourceExternalIDWithoutUser(db *mongo.Database, externalID string, sourceID string) (*[]Task, error) {
+	cursor, err := GetTaskCollection(db).Find(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"id_external": externalID},
+			{"source_id": sourceID},
+		}},
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get tasks: %+v", externalID)
+		return nil, err
+	}
+	var tasks []Task
+	err = cursor.All(context.Background(), &tasks)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get tasks: %+v", externalID)
+		return nil, err
+	}
+	return &tasks, nil
+}
+
 func GetCalendarEventWithoutUserID(db *mongo.Database, itemID primitive.ObjectID) (*CalendarEvent, error) {
 	logger := logging.GetSentryLogger()
 	mongoResult := GetCalendarEventCollection(db).FindOne(
@@ -473,6 +628,41 @@ func GetPullRequestByExternalID(db *mongo.Database, externalID string, userID pr
 	return &pullRequest, nil
 }
 
+func GetPullRequestsByRepositoryWithoutUser(db *mongo.Database, repositoryID string, number int) (*[]PullRequest, error) {
+	cursor, err := GetPullRequestCollection(db).Find(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"repository_id": repositoryID},
+			{"number": number},
+		}})
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get pull requests: %s #%d", repositoryID, number)
+		return nil, err
+	}
+	var pullRequests []PullRequest
+	err = cursor.All(context.Background(), &pullRequests)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get pull requests: %s #%d", repositoryID, number)
+		return nil, err
+	}
+	return &pullRequests, nil
+}
+
+func GetRepositoriesByRepositoryIDWithoutUser(db *mongo.Database, repositoryID string) (*[]Repository, error) {
+	cursor, err := GetRepositoryCollection(db).Find(context.Background(), bson.M{"repository_id": repositoryID})
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get repositories: %s", repositoryID)
+		return nil, err
+	}
+	var repositories []Repository
+	err = cursor.All(context.Background(), &repositories)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get repositories: %s", repositoryID)
+		return nil, err
+	}
+	return &repositories, nil
+}
+
 func FindOneExternalWithCollection(
 	collection *mongo.Collection,
 	userID primitive.ObjectID,
@@ -612,7 +802,17 @@ func getDBQuery(userID primitive.ObjectID, IDExternal string, sourceID string, a
 	return dbQuery
 }
 
+// GetActiveTasks excludes tasks which are snoozed
 func GetActiveTasks(db *mongo.Database, userID primitive.ObjectID) (*[]Task, error) {
+	return GetTasks(db, userID, &[]bson.M{
+		{"is_completed": false},
+		{"is_deleted": bson.M{"$ne": true}},
+		GetNotSnoozedFilter(time.Now()),
+	}, nil)
+}
+
+// GetActiveAndSnoozedTasks is used when syncing with external sources, which should also update snoozed tasks
+func GetActiveAndSnoozedTasks(db *mongo.Database, userID primitive.ObjectID) (*[]Task, error) {
 	taskCollection := GetTaskCollection(db)
 	cursor, err := GetActiveItemsWithCollection(taskCollection, userID)
 	if err != nil {
@@ -630,6 +830,34 @@ func GetActiveTasks(db *mongo.Database, userID primitive.ObjectID) (*[]Task, err
 	return &tasks, nil
 }
 
+func GetNotSnoozedFilter(now time.Time) bson.M {
+	return bson.M{"$or": []bson.M{
+		{"snoozed_until": bson.M{"$exists": false}},
+		{"snoozed_until": bson.M{"$lte": primitive.NewDateTimeFromTime(now)}},
+	}}
+}
+
+// ClaimDueTaskReminder marks a due reminder as sent before returning its task, so that each reminder is only sent once
+func ClaimDueTaskReminder(db *mongo.Database, now time.Time) (*Task, error) {
+	var task Task
+	err := GetTaskCollection(db).FindOneAndUpdate(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"remind_at": bson.M{"$gt": primitive.NewDateTimeFromTime(time.Unix(0, 0))}},
+			{"remind_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)}},
+			{"is_reminder_sent": bson.M{"$ne": true}},
+			{"is_completed": bson.M{"$ne": true}},
+			{"is_deleted": bson.M{"$ne": true}},
+		}},
+		bson.M{"$set": bson.M{"is_reminder_sent": true}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
 func GetNotes(db *mongo.Database, userID primitive.ObjectID) (*[]Note, error) {
 	noteCollection := GetNoteCollection(db)
 	cursor, err := noteCollection.Find(
@@ -738,6 +966,26 @@ func FindWithCollection(collection *mongo.Collection, userID primitive.ObjectID,
 	return cursor.All(context.Background(), result)
 }
 
+// SearchWithCollection returns the user's documents matching the text query, most relevant first
+func SearchWithCollection[T any](collection *mongo.Collection, userID primitive.ObjectID, query string, additionalFilters *[]bson.M, limit int64) (*[]SearchResult[T], error) {
+	filters := []bson.M{{"$text": bson.M{"$search": query}}}
+	if additionalFilters != nil {
+		filters = append(filters, *additionalFilters...)
+	}
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetLimit(limit)
+
+	var results []SearchResult[T]
+	err := FindWithCollection(collection, userID, &filters, &results, findOptions)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to search %s", collection.Name())
+		return nil, err
+	}
+	return &results, nil
+}
+
 func GetCompletedTasks(db *mongo.Database, userID primitive.ObjectID) (*[]Task, error) {
 	findOptions := options.Find()
 	findOptions.SetSort(bson.D{{Key: "completed_at", Value: -1}, {Key: "_id", Value: -1}})
@@ -796,6 +1044,30 @@ func GetSubtasksFromTask(db *mongo.Database, task *Task) (*[]Task, error) {
 	return GetTasks(db, task.UserID, &[]bson.M{{"parent_task_id": task.ID}}, nil)
 }
 
+// GetTaskDescendants returns the subtasks at every level of nesting below the task, with parents before their subtasks
+func GetTaskDescendants(db *mongo.Database, userID primitive.ObjectID, taskID primitive.ObjectID) (*[]Task, error) {
+	descendants := []Task{}
+	// tracks the visited tasks, so that a malformed cycle of parents cannot loop forever
+	visitedTaskIDs := map[primitive.ObjectID]bool{taskID: true}
+	parentIDs := []primitive.ObjectID{taskID}
+	for len(parentIDs) > 0 {
+		subtasks, err := GetTasks(db, userID, &[]bson.M{{"parent_task_id": bson.M{"$in": parentIDs}}}, nil)
+		if err != nil {
+			return nil, err
+		}
+		parentIDs = []primitive.ObjectID{}
+		for _, subtask := range *subtasks {
+			if visitedTaskIDs[subtask.ID] {
+				continue
+			}
+			visitedTaskIDs[subtask.ID] = true
+			descendants = append(descendants, subtask)
+			parentIDs = append(parentIDs, subtask.ID)
+		}
+	}
+	return &descendants, nil
+}
+
 func GetDeletedTasks(db *mongo.Database, userID primitive.ObjectID) (*[]Task, error) {
 	findOptions := options.Find()
 	findOptions.SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}})
@@ -810,6 +1082,75 @@ func GetDeletedTasks(db *mongo.Database, userID primitive.ObjectID) (*[]Task, er
 	return tasks, nil
 }
 
+func GetDeletedNotes(db *mongo.Database, userID primitive.ObjectID) (*[]Note, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{{Key: "deleted_at", Value: -1}, {Key: "_id", Value: -1}})
+	findOptions.SetLimit(int64(constants.MAX_DELETED_TASKS))
+
+	var notes []Note
+	err := FindWithCollection(GetNoteCollection(db), userID, &[]bson.M{{"is_deleted": true}}, &notes, findOptions)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to fetch deleted notes for user")
+		return nil, err
+	}
+	return &notes, nil
+}
+
+// PurgeDeletedTasks permanently removes tasks from the source which were deleted before the cutoff, along with their history
+func PurgeDeletedTasks(db *mongo.Database, sourceID string, deletedBefore time.Time) (int64, error) {
+	var tasks []Task
+	cursor, err := GetTaskCollection(db).Find(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			getPurgeableItemsFilter(deletedBefore),
+			{"source_id": sourceID},
+		}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	err = cursor.All(context.Background(), &tasks)
+	if err != nil {
+		return 0, err
+	}
+	if len(tasks) == 0 {
+		return 0, nil
+	}
+	taskIDs := []primitive.ObjectID{}
+	for _, task := range tasks {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	res, err := GetTaskCollection(db).DeleteMany(context.Background(), bson.M{"_id": bson.M{"$in": taskIDs}})
+	if err != nil {
+		return 0, err
+	}
+	_, err = GetTaskHistoryCollection(db).DeleteMany(context.Background(), bson.M{"task_id": bson.M{"$in": taskIDs}})
+	if err != nil {
+		return res.DeletedCount, err
+	}
+	return res.DeletedCount, nil
+}
+
+// PurgeDeletedNotes permanently removes notes which were deleted before the cutoff
+func PurgeDeletedNotes(db *mongo.Database, deletedBefore time.Time) (int64, error) {
+	res, err := GetNoteCollection(db).DeleteMany(context.Background(), getPurgeableItemsFilter(deletedBefore))
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
+
+// items deleted before deleted_at was recorded are kept, as there is no way to know how long they have been in the trash
+func getPurgeableItemsFilter(deletedBefore time.Time) bson.M {
+	return bson.M{"$and": []bson.M{
+		{"is_deleted": true},
+		{"deleted_at": bson.M{"$gt": primitive.NewDateTimeFromTime(time.Unix(0, 0))}},
+		{"deleted_at": bson.M{"$lt": primitive.NewDateTimeFromTime(deletedBefore)}},
+	}}
+}
+
 func GetAllMeetingPreparationTasksUntilEndOfDay(db *mongo.Database, userID primitive.ObjectID, currentTime time.Time) (*[]Task, error) {
 	timeEndOfDay := time.Date(currentTime.Year(), currentTime.Month(), currentTime.Day(), 23, 59, 59, 0, currentTime.Location())
 	return GetTasks(db, userID,
@@ -971,6 +1312,393 @@ func GetTaskSections(db *mongo.Database, userID primitive.ObjectID) (*[]TaskSect
 	return &sections, nil
 }
 
+func GetLabels(db *mongo.Database, userID primitive.ObjectID) (*[]Label, error) {
+	var labels []Label
+	err := FindWithCollection(GetLabelCollection(db), userID, nil, &labels, nil)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to fetch labels for user")
+		return nil, err
+	}
+	return &labels, nil
+}
+
+// RecordTaskHistory compares the tracked fields of the task with the fields being set on it, and appends an event for each one which changed
+func RecordTaskHistory(db *mongo.Database, userID primitive.ObjectID, previousTask *Task, updateFields *Task, actor string) {
+	events := getTaskHistoryEvents(previousTask, updateFields, actor, primitive.NewDateTimeFromTime(time.Now()))
+	if len(events) == 0 {
+		return
+	}
+	documents := []interface{}{}
+	for _, event := range events {
+		event.UserID = userID
+		documents = append(documents, event)
+	}
+	_, err := GetTaskHistoryCollection(db).InsertMany(context.Background(), documents)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to record history for task %s", previousTask.ID.Hex())
+	}
+}
+
+// getTaskHistoryEvents only compares the fields which are set in updateFields, so it works for both partial updates and full documents
+func getTaskHistoryEvents(previousTask *Task, updateFields *Task, actor string, createdAt primitive.DateTime) []TaskHistoryEvent {
+	events := []TaskHistoryEvent{}
+	addEvent := func(field string, previousValue string, newValue string) {
+		if previousValue == newValue {
+			return
+		}
+		events = append(events, TaskHistoryEvent{
+			TaskID:        previousTask.ID,
+			Field:         field,
+			PreviousValue: previousValue,
+			NewValue:      newValue,
+			Actor:         actor,
+			SourceID:      previousTask.SourceID,
+			CreatedAt:     createdAt,
+		})
+	}
+	formatStatus := func(status *ExternalTaskStatus) string {
+		if status == nil {
+			return ""
+		}
+		return status.State
+	}
+	formatBool := func(value *bool) string {
+		return strconv.FormatBool(value != nil && *value)
+	}
+	formatDate := func(date *primitive.DateTime) string {
+		// the unix epoch is used by the external sources to clear the due date
+		if date == nil || date.Time().Unix() == 0 {
+			return ""
+		}
+		return date.Time().UTC().Format(time.RFC3339)
+	}
+	formatSection := func(sectionID primitive.ObjectID) string {
+		if sectionID == primitive.NilObjectID {
+			return ""
+		}
+		return sectionID.Hex()
+	}
+	formatPriority := func(priority *float64) string {
+		if priority == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*priority, 'f', -1, 64)
+	}
+
+	if updateFields.Status != nil {
+		addEvent("status", formatStatus(previousTask.Status), formatStatus(updateFields.Status))
+	}
+	if updateFields.IsCompleted != nil {
+		addEvent("is_completed", formatBool(previousTask.IsCompleted), formatBool(updateFields.IsCompleted))
+	}
+	if updateFields.IDTaskSection != primitive.NilObjectID {
+		addEvent("id_task_section", formatSection(previousTask.IDTaskSection), formatSection(updateFields.IDTaskSection))
+	}
+	if updateFields.DueDate != nil {
+		addEvent("due_date", formatDate(previousTask.DueDate), formatDate(updateFields.DueDate))
+	}
+	if updateFields.PriorityNormalized != nil {
+		addEvent("priority_normalized", formatPriority(previousTask.PriorityNormalized), formatPriority(updateFields.PriorityNormalized))
+	}
+	return events
+}
+
+func GetTaskHistory(db *mongo.Database, userID primitive.ObjectID, taskID primitive.ObjectID) (*[]TaskHistoryEvent, error) {
+	var events []TaskHistoryEvent
+	err := FindWithCollection(
+		GetTaskHistoryCollection(db),
+		userID,
+		&[]bson.M{{"task_id": taskID}},
+		&events,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to fetch task history")
+		return nil, err
+	}
+	return &events, nil
+}
+
+// StartTimeSession stops the user's running timer, if any, and starts a new one on the task
+func StartTimeSession(db *mongo.Database, userID primitive.ObjectID, taskID primitive.ObjectID, now time.Time) (*TaskTimeSession, error) {
+	_, err := StopTimeSession(db, userID, now)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	session := TaskTimeSession{
+		UserID:    userID,
+		TaskID:    taskID,
+		IsActive:  true,
+		StartedAt: primitive.NewDateTimeFromTime(now),
+	}
+	// the unique index on active sessions keeps a concurrent start from creating a second timer
+	insertResult, err := GetTaskTimeSessionCollection(db).InsertOne(context.Background(), session)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to start time session")
+		return nil, err
+	}
+	session.ID = insertResult.InsertedID.(primitive.ObjectID)
+	return &session, nil
+}
+
+// StopTimeSession returns mongo.ErrNoDocuments when the user has no running timer
+func StopTimeSession(db *mongo.Database, userID primitive.ObjectID, now time.Time) (*TaskTimeSession, error) {
+	var session TaskTimeSession
+	err := GetTaskTimeSessionCollection(db).FindOneAndUpdate(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"is_active": true},
+		}},
+		bson.M{"$set": bson.M{"is_active": false, "ended_at": primitive.NewDateTimeFromTime(now)}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&session)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			logging.GetSentryLogger().Error().Err(err).Msg("failed to stop time session")
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveTimeSession returns mongo.ErrNoDocuments when the user has no running timer
+func GetActiveTimeSession(db *mongo.Database, userID primitive.ObjectID) (*TaskTimeSession, error) {
+	var session TaskTimeSession
+	err := GetTaskTimeSessionCollection(db).FindOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"is_active": true},
+		}},
+	).Decode(&session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetTimeSessions returns the sessions which overlap the time range, including a running timer
+func GetTimeSessions(db *mongo.Database, userID primitive.ObjectID, start time.Time, end time.Time) (*[]TaskTimeSession, error) {
+	var sessions []TaskTimeSession
+	err := FindWithCollection(
+		GetTaskTimeSessionCollection(db),
+		userID,
+		&[]bson.M{
+			{"started_at": bson.M{"$lt": primitive.NewDateTimeFromTime(end)}},
+			{"$or": []bson.M{
+				{"is_active": true},
+				{"ended_at": bson.M{"$gt": primitive.NewDateTimeFromTime(start)}},
+			}},
+		},
+		&sessions,
+		options.Find().SetSort(bson.D{{Key: "started_at", Value: 1}}),
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to fetch time sessions")
+		return nil, err
+	}
+	return &sessions, nil
+}
+
+// GetTimeSessionDuration is the part of the session inside the time range, where a running timer counts up to now
+func GetTimeSessionDuration(session TaskTimeSession, start time.Time, end time.Time, now time.Time) time.Duration {
+	sessionStart := session.StartedAt.Time()
+	sessionEnd := session.EndedAt.Time()
+	if session.IsActive {
+		sessionEnd = now
+	}
+	if sessionStart.Before(start) {
+		sessionStart = start
+	}
+	if sessionEnd.After(end) {
+		sessionEnd = end
+	}
+	if !sessionEnd.After(sessionStart) {
+		return 0
+	}
+	return sessionEnd.Sub(sessionStart)
+}
+
+// GetTimeSessionDailyTotals splits the tracked time in the range into days, which start at the same hour as the dashboard's days
+func GetTimeSessionDailyTotals(sessions []TaskTimeSession, start time.Time, end time.Time, now time.Time) map[time.Time]time.Duration {
+	dailyTotals := make(map[time.Time]time.Duration)
+	for _, session := range sessions {
+		for day := getDashboardDayStart(session.StartedAt.Time()); day.Before(end) && day.Before(now); day = day.Add(24 * time.Hour) {
+			dayStart, dayEnd := day, day.Add(24*time.Hour)
+			if dayStart.Before(start) {
+				dayStart = start
+			}
+			if dayEnd.After(end) {
+				dayEnd = end
+			}
+			duration := GetTimeSessionDuration(session, dayStart, dayEnd, now)
+			if duration > 0 {
+				dailyTotals[day] += duration
+			}
+		}
+	}
+	return dailyTotals
+}
+
+func getDashboardDayStart(datetime time.Time) time.Time {
+	datetime = datetime.UTC()
+	dayStart := time.Date(datetime.Year(), datetime.Month(), datetime.Day(), constants.UTC_OFFSET, 0, 0, 0, time.UTC)
+	if dayStart.After(datetime) {
+		dayStart = dayStart.Add(-24 * time.Hour)
+	}
+	return dayStart
+}
+
+// GetOrCreateDataExport returns the user's unfinished export if there is one, so that repeated requests don't queue up duplicate exports
+func GetOrCreateDataExport(db *mongo.Database, userID primitive.ObjectID, now time.Time) (*DataExport, bool, error) {
+	dataExport, err := getUnfinishedDataExport(db, userID)
+	if err == nil {
+		return dataExport, false, nil
+	} else if err != mongo.ErrNoDocuments {
+		return nil, false, err
+	}
+
+	dataExport = &DataExport{
+		UserID:    userID,
+		Status:    DataExportStatusPending,
+		IsActive:  true,
+		CreatedAt: primitive.NewDateTimeFromTime(now),
+	}
+	// the unique index on active exports keeps a concurrent request from creating a second export
+	insertResult, err := GetDataExportCollection(db).InsertOne(context.Background(), dataExport)
+	if mongo.IsDuplicateKeyError(err) {
+		dataExport, err = getUnfinishedDataExport(db, userID)
+		if err != nil {
+			return nil, false, err
+		}
+		return dataExport, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	dataExport.ID = insertResult.InsertedID.(primitive.ObjectID)
+	return dataExport, true, nil
+}
+
+func getUnfinishedDataExport(db *mongo.Database, userID primitive.ObjectID) (*DataExport, error) {
+	var dataExport DataExport
+	err := GetDataExportCollection(db).FindOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"status": bson.M{"$in": []string{DataExportStatusPending, DataExportStatusProcessing}}},
+		}},
+	).Decode(&dataExport)
+	if err != nil {
+		return nil, err
+	}
+	return &dataExport, nil
+}
+
+func GetDataExport(db *mongo.Database, exportID primitive.ObjectID, userID primitive.ObjectID) (*DataExport, error) {
+	var dataExport DataExport
+	err := GetDataExportCollection(db).FindOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": exportID},
+			{"user_id": userID},
+		}},
+	).Decode(&dataExport)
+	if err != nil {
+		return nil, err
+	}
+	return &dataExport, nil
+}
+
+// ClaimPendingDataExport marks the oldest pending export as processing and returns it, or returns mongo.ErrNoDocuments if there is none.
+// Exports which started processing before staleBefore are claimed again, in case the server processing them went away.
+func ClaimPendingDataExport(db *mongo.Database, now time.Time, staleBefore time.Time) (*DataExport, error) {
+	var dataExport DataExport
+	err := GetDataExportCollection(db).FindOneAndUpdate(
+		context.Background(),
+		bson.M{"$or": []bson.M{
+			{"status": DataExportStatusPending},
+			{"$and": []bson.M{
+				{"status": DataExportStatusProcessing},
+				{"started_at": bson.M{"$lt": primitive.NewDateTimeFromTime(staleBefore)}},
+			}},
+		}},
+		bson.M{"$set": bson.M{"status": DataExportStatusProcessing, "started_at": primitive.NewDateTimeFromTime(now)}},
+		options.FindOneAndUpdate().SetSort(bson.M{"created_at": 1}).SetReturnDocument(options.After),
+	).Decode(&dataExport)
+	if err != nil {
+		return nil, err
+	}
+	return &dataExport, nil
+}
+
+// ClaimExpiredDataExport marks a completed export past its expiry as expired and returns it, so its file can be deleted
+func ClaimExpiredDataExport(db *mongo.Database, now time.Time) (*DataExport, error) {
+	var dataExport DataExport
+	err := GetDataExportCollection(db).FindOneAndUpdate(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"status": DataExportStatusComplete},
+			{"expires_at": bson.M{"$lte": primitive.NewDateTimeFromTime(now)}},
+		}},
+		bson.M{"$set": bson.M{"status": DataExportStatusExpired}},
+	).Decode(&dataExport)
+	if err != nil {
+		return nil, err
+	}
+	return &dataExport, nil
+}
+
+// UpdateOrCreateExternalLabel keeps the user's copy of a label from an external source up to date
+func UpdateOrCreateExternalLabel(db *mongo.Database, userID primitive.ObjectID, sourceID string, externalLabel ExternalLabel) (*Label, error) {
+	mongoResult, err := FindOneAndUpdateWithCollection(
+		GetLabelCollection(db),
+		userID,
+		externalLabel.ExternalID,
+		sourceID,
+		nil,
+		bson.M{"name": externalLabel.Name, "color": externalLabel.Color},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var label Label
+	err = mongoResult.Decode(&label)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to update or create label")
+		return nil, err
+	}
+	return &label, nil
+}
+
+// GetExternalLabelIDs returns the IDs of the user's labels which were imported from the source
+func GetExternalLabelIDs(db *mongo.Database, userID primitive.ObjectID, sourceID string) ([]primitive.ObjectID, error) {
+	cursor, err := GetLabelCollection(db).Find(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"source_id": sourceID},
+		}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to fetch external labels")
+		return nil, err
+	}
+	var labels []Label
+	err = cursor.All(context.Background(), &labels)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to fetch external labels")
+		return nil, err
+	}
+	labelIDs := []primitive.ObjectID{}
+	for _, label := range labels {
+		labelIDs = append(labelIDs, label.ID)
+	}
+	return labelIDs, nil
+}
+
 func MarkCompleteWithCollection(collection *mongo.Collection, itemID primitive.ObjectID) error {
 	res, err := collection.UpdateOne(
 		context.Background(),
@@ -989,6 +1717,134 @@ func MarkCompleteWithCollection(collection *mongo.Collection, itemID primitive.O
 	return nil
 }
 
+// UnblockDependentTasks removes a completed task from the blockers of the tasks which depend on it
+func UnblockDependentTasks(db *mongo.Database, userID primitive.ObjectID, blockingTaskID primitive.ObjectID) error {
+	_, err := GetTaskCollection(db).UpdateMany(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"blocked_by_task_ids": blockingTaskID},
+		}},
+		bson.M{"$pull": bson.M{"blocked_by_task_ids": blockingTaskID}},
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to unblock tasks dependent on %s", blockingTaskID.Hex())
+		return err
+	}
+	return nil
+}
+
+func GetGithubEnterpriseConfiguration(db *mongo.Database, userID primitive.ObjectID) (*GithubEnterpriseConfiguration, error) {
+	var configuration GithubEnterpriseConfiguration
+	err := GetGithubEnterpriseConfigurationCollection(db).FindOne(context.Background(), bson.M{"user_id": userID}).Decode(&configuration)
+	if err != nil {
+		return nil, err
+	}
+	return &configuration, nil
+}
+
+func GetAtlassianSiteConfigurationsByCloudID(db *mongo.Database, cloudID string) (*[]AtlassianSiteConfiguration, error) {
+	cursor, err := GetJiraSitesCollection(db).Find(context.Background(), bson.M{"cloud_id": cloudID})
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get site configurations: %s", cloudID)
+		return nil, err
+	}
+	var siteConfigurations []AtlassianSiteConfiguration
+	err = cursor.All(context.Background(), &siteConfigurations)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get site configurations: %s", cloudID)
+		return nil, err
+	}
+	return &siteConfigurations, nil
+}
+
+func GetAtlassianSiteConfigurationsWithExpiringWebhooks(db *mongo.Database, expiresBefore time.Time) (*[]AtlassianSiteConfiguration, error) {
+	cursor, err := GetJiraSitesCollection(db).Find(
+		context.Background(),
+		bson.M{"$or": []bson.M{
+			{"webhook_expires_at": bson.M{"$exists": false}},
+			{"webhook_expires_at": bson.M{"$lt": primitive.NewDateTimeFromTime(expiresBefore)}},
+		}},
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to get site configurations with expiring webhooks")
+		return nil, err
+	}
+	var siteConfigurations []AtlassianSiteConfiguration
+	err = cursor.All(context.Background(), &siteConfigurations)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to get site configurations with expiring webhooks")
+		return nil, err
+	}
+	return &siteConfigurations, nil
+}
+
+func GetLinearWebhookSecret(db *mongo.Database, userID primitive.ObjectID, organizationID string) (*LinearWebhookSecret, error) {
+	var webhookSecret LinearWebhookSecret
+	err := GetLinearWebhookSecretCollection(db).FindOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"organization_id": organizationID},
+		}},
+	).Decode(&webhookSecret)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			logging.GetSentryLogger().Error().Err(err).Msgf("failed to get linear webhook secret: %s", organizationID)
+		}
+		return nil, err
+	}
+	return &webhookSecret, nil
+}
+
+func GetLinearWebhookSecrets(db *mongo.Database, organizationID string) (*[]LinearWebhookSecret, error) {
+	cursor, err := GetLinearWebhookSecretCollection(db).Find(context.Background(), bson.M{"organization_id": organizationID})
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get linear webhook secrets: %s", organizationID)
+		return nil, err
+	}
+	var webhookSecrets []LinearWebhookSecret
+	err = cursor.All(context.Background(), &webhookSecrets)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to get linear webhook secrets: %s", organizationID)
+		return nil, err
+	}
+	return &webhookSecrets, nil
+}
+
+// each user registers their own webhook, so rotating a secret never replaces the secret of another user in the organization.
+// the replaced secret is kept as the previous secret, so deliveries signed before the rotation can still be verified
+func RotateLinearWebhookSecret(db *mongo.Database, userID primitive.ObjectID, organizationID string, secret string, now time.Time) error {
+	previousSecret := ""
+	webhookSecret, err := GetLinearWebhookSecret(db, userID, organizationID)
+	if err == nil {
+		previousSecret = webhookSecret.Secret
+	} else if err != mongo.ErrNoDocuments {
+		return err
+	}
+
+	_, err = GetLinearWebhookSecretCollection(db).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"organization_id": organizationID},
+		}},
+		bson.M{"$set": LinearWebhookSecret{
+			UserID:         userID,
+			OrganizationID: organizationID,
+			Secret:         secret,
+			PreviousSecret: previousSecret,
+			RotatedAt:      primitive.NewDateTimeFromTime(now),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("failed to rotate linear webhook secret: %s", organizationID)
+		return err
+	}
+	return nil
+}
+
 func GetUser(db *mongo.Database, userID primitive.ObjectID) (*User, error) {
 	var userObject User
 	err := GetUserCollection(db).FindOne(
@@ -1398,6 +2254,14 @@ func GetJiraSitesCollection(db *mongo.Database) *mongo.Collection {
 	return db.Collection("jira_sites")
 }
 
+func GetGithubEnterpriseConfigurationCollection(db *mongo.Database) *mongo.Collection {
+	return db.Collection("github_enterprise_configurations")
+}
+
+func GetLinearWebhookSecretCollection(db *mongo.Database) *mongo.Collection {
+	return db.Collection("linear_webhook_secrets")
+}
+
 func GetJiraPrioritiesCollection(db *mongo.Database) *mongo.Collection {
 	return db.Collection("jira_priorities")
 }
@@ -1418,6 +2282,18 @@ func GetTaskSectionCollection(db *mongo.Database) *mongo.Collection {
 	return db.Collection("task_sections")
 }
 
+func GetLabelCollection(db *mongo.Database) *mongo.Collection {
+	return db.Collection("labels")
+}
+
+func GetTaskHistoryCollection(db *mongo.Database) *mongo.Collection {
+	return db.Collection("task_history_events")
+}
+
+func GetTaskTimeSessionCollection(db *mongo.Database) *mongo.Collection {
+	return db.Collection("task_time_sessions")
+}
+
 func GetRecurringTaskTemplateCollection(db *mongo.Database) *mongo.Collection {
 	return db.Collection("recurring_task_templates")
 }
@@ -1438,6 +2314,15 @@ func GetDashboardTeamMemberCollection(db *mongo.Database) *mongo.Collection {
 	return db.Collection("dashboard_team_members")
 }
 
+func GetDataExportCollection(db *mongo.Database) *mongo.Collection {
+	return db.Collection("data_exports")
+}
+
+// GetDataExportFileBucket stores the ZIP files of data exports, which can be larger than a single document allows
+func GetDataExportFileBucket(db *mongo.Database) (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(db, options.GridFSBucket().SetName("data_export_files"))
+}
+
 func HasUserGrantedMultiCalendarScope(scopes []string) bool {
 	return slices.Contains(scopes, "https://www.googleapis.com/auth/calendar")
 }
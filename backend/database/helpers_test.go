@@ -1203,6 +1203,177 @@ func TestGetPullRequestByExternalID(t *testing.T) {
 	})
 }
 
+func TestGetPullRequestsByRepositoryWithoutUser(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	repositoryID := primitive.NewObjectID().Hex()
+	for _, pullRequest := range []*PullRequest{
+		{IDExternal: "123abc", SourceID: "foobar_source", UserID: primitive.NewObjectID(), RepositoryID: repositoryID, Number: 1},
+		{IDExternal: "123abc", SourceID: "foobar_source", UserID: primitive.NewObjectID(), RepositoryID: repositoryID, Number: 1},
+		{IDExternal: "456def", SourceID: "foobar_source", UserID: primitive.NewObjectID(), RepositoryID: repositoryID, Number: 2},
+	} {
+		_, err := GetOrCreatePullRequest(db, pullRequest.UserID, pullRequest.IDExternal, pullRequest.SourceID, pullRequest)
+		assert.NoError(t, err)
+	}
+
+	t.Run("WrongRepository", func(t *testing.T) {
+		pullRequests, err := GetPullRequestsByRepositoryWithoutUser(db, "wrong ID", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(*pullRequests))
+	})
+	t.Run("Success", func(t *testing.T) {
+		pullRequests, err := GetPullRequestsByRepositoryWithoutUser(db, repositoryID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(*pullRequests))
+		for _, pullRequest := range *pullRequests {
+			assert.Equal(t, "123abc", pullRequest.IDExternal)
+		}
+	})
+}
+
+func TestGetTaskBySourceExternalID(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	externalID := primitive.NewObjectID().Hex()
+	task, err := GetOrCreateTask(db, userID, externalID, "foobar_source", &Task{IDExternal: externalID, SourceID: "foobar_source", UserID: userID})
+	assert.NoError(t, err)
+	_, err = GetOrCreateTask(db, userID, externalID, "other_source", &Task{IDExternal: externalID, SourceID: "other_source", UserID: userID})
+	assert.NoError(t, err)
+
+	t.Run("WrongSource", func(t *testing.T) {
+		_, err := GetTaskBySourceExternalID(db, externalID, "missing_source", userID)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+	t.Run("WrongUser", func(t *testing.T) {
+		_, err := GetTaskBySourceExternalID(db, externalID, "foobar_source", primitive.NewObjectID())
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+	t.Run("Success", func(t *testing.T) {
+		dbTask, err := GetTaskBySourceExternalID(db, externalID, "foobar_source", userID)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ID, dbTask.ID)
+	})
+}
+
+func TestGetRepositoriesByRepositoryIDWithoutUser(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	repositoryID := primitive.NewObjectID().Hex()
+	_, err = GetRepositoryCollection(db).InsertMany(context.Background(), []interface{}{
+		Repository{UserID: primitive.NewObjectID(), AccountID: "account1", RepositoryID: repositoryID},
+		Repository{UserID: primitive.NewObjectID(), AccountID: "account2", RepositoryID: repositoryID},
+		Repository{UserID: primitive.NewObjectID(), AccountID: "account3", RepositoryID: "other ID"},
+	})
+	assert.NoError(t, err)
+
+	repositories, err := GetRepositoriesByRepositoryIDWithoutUser(db, repositoryID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(*repositories))
+	for _, repository := range *repositories {
+		assert.Equal(t, repositoryID, repository.RepositoryID)
+	}
+}
+
+func TestGetTaskByExternalID(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	externalID := primitive.NewObjectID().Hex()
+	task, err := GetOrCreateTask(db, userID, externalID, "foobar_source", &Task{IDExternal: externalID, SourceID: "foobar_source", UserID: userID})
+	assert.NoError(t, err)
+
+	t.Run("WrongUser", func(t *testing.T) {
+		_, err := GetTaskByExternalID(db, externalID, primitive.NewObjectID())
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+	t.Run("Success", func(t *testing.T) {
+		dbTask, err := GetTaskByExternalID(db, externalID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, task.ID, dbTask.ID)
+	})
+}
+
+func TestGetAtlassianSiteConfigurationsByCloudID(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	cloudID := primitive.NewObjectID().Hex()
+	_, err = GetJiraSitesCollection(db).InsertMany(context.Background(), []interface{}{
+		AtlassianSiteConfiguration{UserID: primitive.NewObjectID(), CloudID: cloudID},
+		AtlassianSiteConfiguration{UserID: primitive.NewObjectID(), CloudID: cloudID},
+		AtlassianSiteConfiguration{UserID: primitive.NewObjectID(), CloudID: "other_cloud_id"},
+	})
+	assert.NoError(t, err)
+
+	t.Run("WrongCloudID", func(t *testing.T) {
+		siteConfigurations, err := GetAtlassianSiteConfigurationsByCloudID(db, "wrong ID")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(*siteConfigurations))
+	})
+	t.Run("Success", func(t *testing.T) {
+		siteConfigurations, err := GetAtlassianSiteConfigurationsByCloudID(db, cloudID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(*siteConfigurations))
+	})
+}
+
+func TestRotateLinearWebhookSecret(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	organizationID := primitive.NewObjectID().Hex()
+	userID := primitive.NewObjectID()
+	otherUserID := primitive.NewObjectID()
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := GetLinearWebhookSecret(db, userID, organizationID)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+	t.Run("Register", func(t *testing.T) {
+		err := RotateLinearWebhookSecret(db, userID, organizationID, "old_secret", time.Now())
+		assert.NoError(t, err)
+
+		webhookSecret, err := GetLinearWebhookSecret(db, userID, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, "old_secret", webhookSecret.Secret)
+		assert.Equal(t, "", webhookSecret.PreviousSecret)
+	})
+	t.Run("Rotate", func(t *testing.T) {
+		rotatedAt := time.Now()
+		err := RotateLinearWebhookSecret(db, userID, organizationID, "new_secret", rotatedAt)
+		assert.NoError(t, err)
+
+		webhookSecret, err := GetLinearWebhookSecret(db, userID, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, webhookSecret.UserID)
+		assert.Equal(t, "new_secret", webhookSecret.Secret)
+		assert.Equal(t, "old_secret", webhookSecret.PreviousSecret)
+		assert.Equal(t, primitive.NewDateTimeFromTime(rotatedAt), webhookSecret.RotatedAt)
+	})
+	t.Run("OtherUser", func(t *testing.T) {
+		err := RotateLinearWebhookSecret(db, otherUserID, organizationID, "other_secret", time.Now())
+		assert.NoError(t, err)
+
+		// the secret of another user in the organization is left alone
+		webhookSecret, err := GetLinearWebhookSecret(db, userID, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, "new_secret", webhookSecret.Secret)
+		webhookSecret, err = GetLinearWebhookSecret(db, otherUserID, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, "other_secret", webhookSecret.Secret)
+		assert.Equal(t, "", webhookSecret.PreviousSecret)
+
+		webhookSecrets, err := GetLinearWebhookSecrets(db, organizationID)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(*webhookSecrets))
+	})
+}
+
 func TestMarkItemComplete(t *testing.T) {
 	db, dbCleanup, err := GetDBConnection()
 	assert.NoError(t, err)
@@ -1788,3 +1959,194 @@ func TestGetCalendarEventByExternalId(t *testing.T) {
 		assert.Equal(t, event, respEvent.ID)
 	})
 }
+
+func TestGetTaskHistoryEvents(t *testing.T) {
+	createdAt := primitive.NewDateTimeFromTime(time.Now())
+	notCompleted := false
+	completed := true
+	priority := 2.0
+	newPriority := 1.0
+	previousTask := &Task{
+		ID:                 primitive.NewObjectID(),
+		SourceID:           "linear",
+		IsCompleted:        &notCompleted,
+		Status:             &ExternalTaskStatus{ExternalID: "todo", State: "Todo"},
+		PriorityNormalized: &priority,
+	}
+	t.Run("NoChanges", func(t *testing.T) {
+		events := getTaskHistoryEvents(previousTask, &Task{IsCompleted: &notCompleted, PriorityNormalized: &priority}, TaskHistoryActorUser, createdAt)
+		assert.Empty(t, events)
+	})
+	t.Run("Changes", func(t *testing.T) {
+		sectionID := primitive.NewObjectID()
+		dueDate := primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))
+		events := getTaskHistoryEvents(previousTask, &Task{
+			Status:             &ExternalTaskStatus{ExternalID: "done", State: "Done"},
+			IsCompleted:        &completed,
+			IDTaskSection:      sectionID,
+			DueDate:            &dueDate,
+			PriorityNormalized: &newPriority,
+		}, TaskHistoryActorWebhook, createdAt)
+		assert.Equal(t, []TaskHistoryEvent{
+			{TaskID: previousTask.ID, Field: "status", PreviousValue: "Todo", NewValue: "Done", Actor: TaskHistoryActorWebhook, SourceID: "linear", CreatedAt: createdAt},
+			{TaskID: previousTask.ID, Field: "is_completed", PreviousValue: "false", NewValue: "true", Actor: TaskHistoryActorWebhook, SourceID: "linear", CreatedAt: createdAt},
+			{TaskID: previousTask.ID, Field: "id_task_section", PreviousValue: "", NewValue: sectionID.Hex(), Actor: TaskHistoryActorWebhook, SourceID: "linear", CreatedAt: createdAt},
+			{TaskID: previousTask.ID, Field: "due_date", PreviousValue: "", NewValue: "2023-03-01T00:00:00Z", Actor: TaskHistoryActorWebhook, SourceID: "linear", CreatedAt: createdAt},
+			{TaskID: previousTask.ID, Field: "priority_normalized", PreviousValue: "2", NewValue: "1", Actor: TaskHistoryActorWebhook, SourceID: "linear", CreatedAt: createdAt},
+		}, events)
+	})
+	t.Run("ClearedDueDate", func(t *testing.T) {
+		dueDate := primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))
+		clearedDueDate := primitive.NewDateTimeFromTime(time.Unix(0, 0))
+		events := getTaskHistoryEvents(&Task{ID: previousTask.ID, DueDate: &dueDate}, &Task{DueDate: &clearedDueDate}, TaskHistoryActorFetch, createdAt)
+		assert.Equal(t, 1, len(events))
+		assert.Equal(t, "", events[0].NewValue)
+	})
+}
+
+func TestUpdateOrCreateTaskRecordsHistory(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	todo := &ExternalTaskStatus{ExternalID: "todo", State: "Todo"}
+	done := &ExternalTaskStatus{ExternalID: "done", State: "Done"}
+
+	// creating a task has no previous values to record
+	task, err := UpdateOrCreateTask(db, userID, "history_task", "linear", nil, &Task{Status: todo}, nil)
+	assert.NoError(t, err)
+	events, err := GetTaskHistory(db, userID, task.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, *events)
+
+	updatedTask, err := UpdateOrCreateTaskWithActor(db, userID, "history_task", "linear", nil, &Task{Status: done}, nil, TaskHistoryActorWebhook)
+	assert.NoError(t, err)
+	assert.Equal(t, task.ID, updatedTask.ID)
+	assert.Equal(t, "Done", updatedTask.Status.State)
+	events, err = GetTaskHistory(db, userID, task.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*events))
+	assert.Equal(t, "status", (*events)[0].Field)
+	assert.Equal(t, "Todo", (*events)[0].PreviousValue)
+	assert.Equal(t, "Done", (*events)[0].NewValue)
+	assert.Equal(t, TaskHistoryActorWebhook, (*events)[0].Actor)
+	assert.Equal(t, userID, (*events)[0].UserID)
+
+	// other users cannot see the history
+	events, err = GetTaskHistory(db, primitive.NewObjectID(), task.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, *events)
+}
+
+func TestApplyTaskUpdate(t *testing.T) {
+	title := "old title"
+	body := "unchanged body"
+	previousDocument, err := bson.Marshal(Task{
+		ID:     primitive.NewObjectID(),
+		Title:  &title,
+		Body:   &body,
+		Status: &ExternalTaskStatus{ExternalID: "todo", State: "Todo"},
+	})
+	assert.NoError(t, err)
+
+	newTitle := "new title"
+	task, err := applyTaskUpdate(previousDocument, &Task{Title: &newTitle, Status: &ExternalTaskStatus{ExternalID: "done", State: "Done"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "new title", *task.Title)
+	assert.Equal(t, "unchanged body", *task.Body)
+	assert.Equal(t, ExternalTaskStatus{ExternalID: "done", State: "Done"}, *task.Status)
+}
+
+func TestTimeSessions(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	taskID := primitive.NewObjectID()
+	otherTaskID := primitive.NewObjectID()
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	_, err = StopTimeSession(db, userID, now)
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	session, err := StartTimeSession(db, userID, taskID, now)
+	assert.NoError(t, err)
+	assert.True(t, session.IsActive)
+	// starting another timer stops the first one
+	otherSession, err := StartTimeSession(db, userID, otherTaskID, now.Add(time.Hour))
+	assert.NoError(t, err)
+	activeSession, err := GetActiveTimeSession(db, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, otherSession.ID, activeSession.ID)
+
+	stoppedSession, err := StopTimeSession(db, userID, now.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, stoppedSession.IsActive)
+	_, err = GetActiveTimeSession(db, userID)
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	sessions, err := GetTimeSessions(db, userID, now, now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(*sessions))
+	assert.Equal(t, session.ID, (*sessions)[0].ID)
+	assert.Equal(t, time.Hour, GetTimeSessionDuration((*sessions)[0], now, now.Add(2*time.Hour), now))
+	assert.Equal(t, 30*time.Minute, GetTimeSessionDuration((*sessions)[1], now, now.Add(2*time.Hour), now))
+
+	sessions, err = GetTimeSessions(db, userID, now.Add(2*time.Hour), now.Add(3*time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, *sessions)
+}
+
+func TestGetTimeSessionDailyTotals(t *testing.T) {
+	start := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2023, 3, 5, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2023, 3, 3, 10, 0, 0, 0, time.UTC)
+	sessions := []TaskTimeSession{
+		// crosses the start of the day at 08:00 UTC
+		{StartedAt: primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 7, 0, 0, 0, time.UTC)), EndedAt: primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 9, 30, 0, 0, time.UTC))},
+		{StartedAt: primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 20, 0, 0, 0, time.UTC)), EndedAt: primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 21, 0, 0, 0, time.UTC))},
+		// a running timer counts up to now
+		{IsActive: true, StartedAt: primitive.NewDateTimeFromTime(time.Date(2023, 3, 3, 9, 0, 0, 0, time.UTC))},
+		// the part before the start of the range is left out
+		{StartedAt: primitive.NewDateTimeFromTime(time.Date(2023, 2, 28, 23, 0, 0, 0, time.UTC)), EndedAt: primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 0, 30, 0, 0, time.UTC))},
+	}
+	assert.Equal(t, map[time.Time]time.Duration{
+		time.Date(2023, 2, 28, 8, 0, 0, 0, time.UTC): 90 * time.Minute,
+		time.Date(2023, 3, 1, 8, 0, 0, 0, time.UTC):  150 * time.Minute,
+		time.Date(2023, 3, 3, 8, 0, 0, 0, time.UTC):  time.Hour,
+	}, GetTimeSessionDailyTotals(sessions, start, end, now))
+}
+
+func TestGetTaskDescendants(t *testing.T) {
+	db, dbCleanup, err := GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+
+	insertTask := func(userID primitive.ObjectID, parentTaskID primitive.ObjectID) primitive.ObjectID {
+		insertResult, err := GetTaskCollection(db).InsertOne(context.Background(), Task{UserID: userID, ParentTaskID: parentTaskID})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	taskID := insertTask(userID, primitive.NilObjectID)
+	subtaskID := insertTask(userID, taskID)
+	secondSubtaskID := insertTask(userID, taskID)
+	nestedSubtaskID := insertTask(userID, subtaskID)
+	insertTask(userID, primitive.NilObjectID)
+	// subtasks of other users are left out
+	insertTask(primitive.NewObjectID(), taskID)
+
+	descendants, err := GetTaskDescendants(db, userID, taskID)
+	assert.NoError(t, err)
+	descendantIDs := []primitive.ObjectID{}
+	for _, descendant := range *descendants {
+		descendantIDs = append(descendantIDs, descendant.ID)
+	}
+	assert.ElementsMatch(t, []primitive.ObjectID{subtaskID, secondSubtaskID, nestedSubtaskID}, descendantIDs)
+	// parents come before their subtasks
+	assert.Equal(t, nestedSubtaskID, descendantIDs[2])
+
+	descendants, err = GetTaskDescendants(db, userID, nestedSubtaskID)
+	assert.NoError(t, err)
+	assert.Empty(t, *descendants)
+}
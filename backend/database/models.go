@@ -21,6 +21,7 @@ type User struct {
 	LinearDisplayName     string             `bson:"linear_display_name"`
 	GPTSuggestionsLeft    int                `bson:"gpt_suggestions_left"`
 	GPTLastSuggestionTime primitive.DateTime `bson:"gpt_last_suggestion_time"`
+	Timezone              string             `bson:"timezone,omitempty"`
 }
 
 type UserChangeable struct {
@@ -40,19 +41,20 @@ type InternalAPIToken struct {
 
 // ExternalAPIToken model
 type ExternalAPIToken struct {
-	ID                  primitive.ObjectID `bson:"_id,omitempty"`
-	ServiceID           string             `bson:"service_id"`
-	Token               string             `bson:"token"`
-	UserID              primitive.ObjectID `bson:"user_id"`
-	AccountID           string             `bson:"account_id"`
-	DisplayID           string             `bson:"display_id"`
-	IsUnlinkable        bool               `bson:"is_unlinkable"`
-	IsPrimaryLogin      bool               `bson:"is_primary_login"`
-	IsBadToken          bool               `bson:"is_bad_token"`
-	ExternalID          string             `bson:"external_id"`
-	LastFullRefreshTime primitive.DateTime `bson:"last_full_refresh_time"`
-	Scopes              []string           `bson:"scopes"`
-	Timezone            string             `bson:"timezone"`
+	ID                      primitive.ObjectID `bson:"_id,omitempty"`
+	ServiceID               string             `bson:"service_id"`
+	Token                   string             `bson:"token"`
+	UserID                  primitive.ObjectID `bson:"user_id"`
+	AccountID               string             `bson:"account_id"`
+	DisplayID               string             `bson:"display_id"`
+	IsUnlinkable            bool               `bson:"is_unlinkable"`
+	IsPrimaryLogin          bool               `bson:"is_primary_login"`
+	IsBadToken              bool               `bson:"is_bad_token"`
+	ExternalID              string             `bson:"external_id"`
+	LastFullRefreshTime     primitive.DateTime `bson:"last_full_refresh_time"`
+	Scopes                  []string           `bson:"scopes"`
+	Timezone                string             `bson:"timezone"`
+	GithubEnterpriseBaseURL string             `bson:"github_enterprise_base_url,omitempty"`
 }
 
 type AtlassianSiteConfiguration struct {
@@ -60,6 +62,27 @@ type AtlassianSiteConfiguration struct {
 	UserID  primitive.ObjectID `bson:"user_id"`
 	CloudID string             `bson:"cloud_id"`
 	SiteURL string             `bson:"site_url"`
+	// dynamic webhooks registered by the app expire after 30 days unless they are refreshed
+	WebhookIDs       []int64            `bson:"webhook_ids,omitempty"`
+	WebhookExpiresAt primitive.DateTime `bson:"webhook_expires_at,omitempty"`
+}
+
+// GitHub Enterprise instances each have their own OAuth app, which the user registers before linking
+type GithubEnterpriseConfiguration struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	UserID            primitive.ObjectID `bson:"user_id"`
+	BaseURL           string             `bson:"base_url"`
+	OauthClientID     string             `bson:"oauth_client_id"`
+	OauthClientSecret string             `bson:"oauth_client_secret"`
+}
+
+type LinearWebhookSecret struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty"`
+	UserID         primitive.ObjectID `bson:"user_id"`
+	OrganizationID string             `bson:"organization_id"`
+	Secret         string             `bson:"secret"`
+	PreviousSecret string             `bson:"previous_secret"`
+	RotatedAt      primitive.DateTime `bson:"rotated_at"`
 }
 
 type JIRAPriority struct {
@@ -97,17 +120,19 @@ type Task struct {
 	// required for recurring tasks
 	RecurringTaskTemplateID primitive.ObjectID `bson:"recurring_task_template_id,omitempty"`
 	// generic task values (for all sources)
-	IDExternal         string              `bson:"id_external,omitempty"`
-	IDOrdering         int                 `bson:"id_ordering,omitempty"`
-	IDTaskSection      primitive.ObjectID  `bson:"id_task_section,omitempty"`
-	IsCompleted        *bool               `bson:"is_completed,omitempty"`
-	IsDeleted          *bool               `bson:"is_deleted,omitempty"`
-	Sender             string              `bson:"sender,omitempty"`
-	SourceID           string              `bson:"source_id,omitempty"`
-	SourceAccountID    string              `bson:"source_account_id,omitempty"`
-	Deeplink           string              `bson:"deeplink,omitempty"`
-	Title              *string             `bson:"title,omitempty"`
-	Body               *string             `bson:"body,omitempty"`
+	IDExternal      string             `bson:"id_external,omitempty"`
+	IDOrdering      int                `bson:"id_ordering,omitempty"`
+	IDTaskSection   primitive.ObjectID `bson:"id_task_section,omitempty"`
+	IsCompleted     *bool              `bson:"is_completed,omitempty"`
+	IsDeleted       *bool              `bson:"is_deleted,omitempty"`
+	Sender          string             `bson:"sender,omitempty"`
+	SourceID        string             `bson:"source_id,omitempty"`
+	SourceAccountID string             `bson:"source_account_id,omitempty"`
+	Deeplink        string             `bson:"deeplink,omitempty"`
+	Title           *string            `bson:"title,omitempty"`
+	Body            *string            `bson:"body,omitempty"`
+	// the plain text of the body which is indexed for search, and only differs from the body for sources whose body is not plain text
+	SearchBody         *string             `bson:"search_body,omitempty"`
 	HasBeenReordered   bool                `bson:"has_been_reordered,omitempty"`
 	DueDate            *primitive.DateTime `bson:"due_date,omitempty"`
 	TimeAllocation     *int64              `bson:"time_allocated,omitempty"` // time in nanoseconds
@@ -120,6 +145,17 @@ type Task struct {
 	PriorityNormalized *float64            `bson:"priority_normalized,omitempty"`
 	TaskNumber         *int                `bson:"task_number,omitempty"`
 	Comments           *[]Comment          `bson:"comments,omitempty"`
+	// user-defined labels, including those imported from external sources
+	LabelIDs *[]primitive.ObjectID `bson:"label_ids,omitempty"`
+	// labels as they appear in the external source
+	ExternalLabels *[]ExternalLabel `bson:"external_labels,omitempty"`
+	// tasks which must be completed before this one, from any source
+	BlockedByTaskIDs []primitive.ObjectID `bson:"blocked_by_task_ids,omitempty"`
+	// hidden from the active task lists until this time
+	SnoozedUntil *primitive.DateTime `bson:"snoozed_until,omitempty"`
+	RemindAt     *primitive.DateTime `bson:"remind_at,omitempty"`
+	// set once the reminder at remind_at has been sent, and reset whenever remind_at changes
+	IsReminderSent *bool `bson:"is_reminder_sent,omitempty"`
 	// used for external priority handling
 	ExternalPriority      *ExternalTaskPriority   `bson:"priority,omitempty"`
 	AllExternalPriorities []*ExternalTaskPriority `bson:"all_priorities,omitempty"`
@@ -134,6 +170,10 @@ type Task struct {
 	SlackMessageParams *SlackMessageParams `bson:"slack_message_params,omitempty"`
 	// info required for JIRA integration
 	JIRATaskParams *JIRATaskParams `bson:"jira_task_params,omitempty"`
+	// project and section the task lives in for Asana
+	AsanaTaskParams *AsanaTaskParams `bson:"asana_task_params,omitempty"`
+	// repository and number the issue lives under for GitHub
+	GithubIssueParams *GithubIssueParams `bson:"github_issue_params,omitempty"`
 	// meeting prep fields
 	MeetingPreparationParams *MeetingPreparationParams `bson:"meeting_preparation_params,omitempty"`
 	IsMeetingPreparationTask bool                      `bson:"is_meeting_preparation_task,omitempty"`
@@ -155,7 +195,13 @@ type RecurringTaskTemplate struct {
 	TimeOfDaySecondsToCreateTask *int               `bson:"time_of_day_seconds_to_create_task,omitempty" json:"time_of_day_seconds_to_create_task,omitempty"`
 	DayToCreateTask              *int               `bson:"day_to_create_task,omitempty" json:"day_to_create_task,omitempty"`
 	MonthToCreateTask            *int               `bson:"month_to_create_task,omitempty" json:"month_to_create_task,omitempty"`
+	RecurrenceRule               *string            `bson:"recurrence_rule,omitempty" json:"recurrence_rule,omitempty"` // RFC 5545 RRULE with optional EXDATE lines, used instead of the recurrence rate
+	SkippedDates                 []string           `bson:"skipped_dates,omitempty" json:"skipped_dates,omitempty"`     // dates in the user's timezone on which no task is created, i.e. 2006-01-02
 	LastBackfillDatetime         primitive.DateTime `bson:"last_backfill_datetime,omitempty" json:"last_backfill_datetime,omitempty"`
+	// fields copied to the created tasks
+	DueDateOffsetSeconds *int                           `bson:"due_date_offset_seconds,omitempty" json:"due_date_offset_seconds,omitempty"` // relative to when the task is due to be created
+	TimeAllocation       *int64                         `bson:"time_allocated,omitempty" json:"time_allocated,omitempty"`                   // time in nanoseconds
+	Subtasks             []RecurringTaskTemplateSubtask `bson:"subtasks,omitempty" json:"subtasks,omitempty"`
 	// existing template tasks replaced by new task
 	ReplaceExisting *bool `bson:"replace_existing,omitempty" json:"replace_existing,omitempty"`
 	// created at
@@ -163,6 +209,11 @@ type RecurringTaskTemplate struct {
 	UpdatedAt primitive.DateTime `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
 }
 
+type RecurringTaskTemplateSubtask struct {
+	Title string `bson:"title" json:"title"`
+	Body  string `bson:"body,omitempty" json:"body,omitempty"`
+}
+
 type PullRequest struct {
 	ID                primitive.ObjectID   `bson:"_id,omitempty"`
 	UserID            primitive.ObjectID   `bson:"user_id,omitempty"`
@@ -275,6 +326,19 @@ type JIRATaskParams struct {
 	HasDueDateField  *bool `bson:"has_due_date_field,omitempty"`
 }
 
+type AsanaTaskParams struct {
+	ProjectID   string `bson:"project_id,omitempty" json:"project_id,omitempty"`
+	ProjectName string `bson:"project_name,omitempty" json:"project_name,omitempty"`
+	SectionID   string `bson:"section_id,omitempty" json:"section_id,omitempty"`
+	SectionName string `bson:"section_name,omitempty" json:"section_name,omitempty"`
+}
+
+type GithubIssueParams struct {
+	RepositoryID   string `bson:"repository_id,omitempty" json:"repository_id,omitempty"`
+	RepositoryName string `bson:"repository_name,omitempty" json:"repository_name,omitempty"`
+	Number         int    `bson:"number,omitempty" json:"number,omitempty"`
+}
+
 // Note that this model is used in the request for Slack, and thus should match
 // the payload from the Slack request.
 type SlackMessageParams struct {
@@ -387,6 +451,78 @@ type TaskSection struct {
 	Name       string             `bson:"name"`
 }
 
+type Label struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	UserID     primitive.ObjectID `bson:"user_id"`
+	Name       string             `bson:"name"`
+	Color      string             `bson:"color,omitempty"`
+	SourceID   string             `bson:"source_id,omitempty"` // only set for labels imported from an external source
+	IDExternal string             `bson:"id_external,omitempty"`
+}
+
+type ExternalLabel struct {
+	ExternalID string `bson:"external_id"`
+	Name       string `bson:"name"`
+	Color      string `bson:"color,omitempty"`
+}
+
+const (
+	TaskHistoryActorUser    = "user"
+	TaskHistoryActorFetch   = "fetch"
+	TaskHistoryActorWebhook = "webhook"
+)
+
+// TaskHistoryEvent records a single change to a tracked task field, and is never modified once written
+type TaskHistoryEvent struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	UserID        primitive.ObjectID `bson:"user_id"`
+	TaskID        primitive.ObjectID `bson:"task_id"`
+	Field         string             `bson:"field"`
+	PreviousValue string             `bson:"previous_value"`
+	NewValue      string             `bson:"new_value"`
+	Actor         string             `bson:"actor"`
+	SourceID      string             `bson:"source_id"`
+	CreatedAt     primitive.DateTime `bson:"created_at"`
+}
+
+// TaskTimeSession is a span of time worked on a task, which is still running while IsActive is set
+type TaskTimeSession struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	TaskID    primitive.ObjectID `bson:"task_id"`
+	IsActive  bool               `bson:"is_active"`
+	StartedAt primitive.DateTime `bson:"started_at"`
+	EndedAt   primitive.DateTime `bson:"ended_at,omitempty"`
+}
+
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusComplete   = "complete"
+	DataExportStatusFailed     = "failed"
+	DataExportStatusExpired    = "expired"
+)
+
+// DataExport is a request for a copy of a user's data, whose ZIP file is kept in GridFS until ExpiresAt
+type DataExport struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	UserID      primitive.ObjectID `bson:"user_id"`
+	Status      string             `bson:"status"`
+	IsActive    bool               `bson:"is_active"` // set while pending or processing, for the unique index on unfinished exports
+	FileID      primitive.ObjectID `bson:"file_id,omitempty"`
+	SizeBytes   int64              `bson:"size_bytes,omitempty"`
+	CreatedAt   primitive.DateTime `bson:"created_at"`
+	StartedAt   primitive.DateTime `bson:"started_at,omitempty"`
+	CompletedAt primitive.DateTime `bson:"completed_at,omitempty"`
+	ExpiresAt   primitive.DateTime `bson:"expires_at,omitempty"`
+}
+
+// SearchResult pairs a document matched by a text search with its relevance
+type SearchResult[T any] struct {
+	Item  T       `bson:",inline"`
+	Score float64 `bson:"score"`
+}
+
 type Pagination struct {
 	Limit *int `form:"limit" json:"limit"`
 	Page  *int `form:"page" json:"page"`
@@ -441,6 +577,7 @@ type Note struct {
 	SharedUntil   primitive.DateTime `bson:"shared_until,omitempty"`
 	SharedAccess  *SharedAccess      `bson:"shared_access,omitempty"`
 	IsDeleted     *bool              `bson:"is_deleted,omitempty"`
+	DeletedAt     primitive.DateTime `bson:"deleted_at,omitempty"`
 }
 
 type DashboardDataPoint struct {
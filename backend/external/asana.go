@@ -23,9 +23,12 @@ type AsanaService struct {
 }
 
 type AsanaConfigValues struct {
-	UserInfoURL   *string
-	TaskFetchURL  *string
-	TaskUpdateURL *string
+	UserInfoURL    *string
+	TaskFetchURL   *string
+	TaskUpdateURL  *string
+	TaskCreateURL  *string
+	StoryFetchURL  *string
+	StoryCreateURL *string
 }
 
 func getAsanaConfig() *OauthConfig {
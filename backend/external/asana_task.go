@@ -19,8 +19,9 @@ type AsanaTaskSource struct {
 }
 
 const (
-	AsanaUserInfoURL = "https://app.asana.com/api/1.0/users/me"
-	AsanaTasksURL    = "https://app.asana.com/api/1.0/tasks/"
+	AsanaUserInfoURL  = "https://app.asana.com/api/1.0/users/me"
+	AsanaTasksURL     = "https://app.asana.com/api/1.0/tasks/"
+	AsanaStoryComment = "comment"
 )
 
 type AsanaUserInfoResponse struct {
@@ -32,16 +33,71 @@ type AsanaUserInfoResponse struct {
 }
 
 type AsanaTasksResponse struct {
+	Data []AsanaTask `json:"data"`
+}
+
+type AsanaTask struct {
+	GID          string             `json:"gid"`
+	DueOn        string             `json:"due_on"`
+	HTMLNotes    string             `json:"html_notes"`
+	Name         string             `json:"name"`
+	PermalinkURL string             `json:"permalink_url"`
+	CreatedAt    primitive.DateTime `json:"created_at"`
+	Memberships  []AsanaMembership  `json:"memberships"`
+}
+
+type AsanaMembership struct {
+	Project AsanaResource `json:"project"`
+	Section AsanaResource `json:"section"`
+}
+
+type AsanaResource struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+type AsanaTaskCreateResponse struct {
+	Data AsanaTask `json:"data"`
+}
+
+type AsanaTasksCreateFields struct {
+	Name      string   `json:"name"`
+	HTMLNotes string   `json:"html_notes,omitempty"`
+	DueOn     *string  `json:"due_on,omitempty"`
+	Assignee  string   `json:"assignee"`
+	Workspace string   `json:"workspace,omitempty"`
+	Projects  []string `json:"projects,omitempty"`
+}
+
+type AsanaTasksCreateBody struct {
+	Data AsanaTasksCreateFields `json:"data"`
+}
+
+type AsanaStoriesResponse struct {
 	Data []struct {
-		GID          string             `json:"gid"`
-		DueOn        string             `json:"due_on"`
-		HTMLNotes    string             `json:"html_notes"`
-		Name         string             `json:"name"`
-		PermalinkURL string             `json:"permalink_url"`
-		CreatedAt    primitive.DateTime `json:"created_at"`
+		GID       string             `json:"gid"`
+		Type      string             `json:"type"`
+		Text      string             `json:"text"`
+		CreatedAt primitive.DateTime `json:"created_at"`
+		CreatedBy struct {
+			GID   string `json:"gid"`
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"created_by"`
 	} `json:"data"`
 }
 
+type AsanaStoryCreateBody struct {
+	Data struct {
+		Text string `json:"text"`
+	} `json:"data"`
+}
+
+type AsanaCommentsResult struct {
+	Comments *[]database.Comment
+	Error    error
+}
+
 type AsanaTasksUpdateFields struct {
 	Name      *string `json:"name,omitempty"`
 	HTMLNotes *string `json:"html_notes,omitempty"`
@@ -58,33 +114,19 @@ func (asanaTask AsanaTaskSource) GetEvents(db *mongo.Database, userID primitive.
 }
 
 func (asanaTask AsanaTaskSource) GetTasks(db *mongo.Database, userID primitive.ObjectID, accountID string, result chan<- TaskResult) {
-	client := getAsanaHttpClient(db, userID, accountID)
-
-	userInfoURL := AsanaUserInfoURL
-	if asanaTask.Asana.ConfigValues.UserInfoURL != nil {
-		userInfoURL = *asanaTask.Asana.ConfigValues.UserInfoURL
-		client = http.DefaultClient
-	}
-
-	var userInfo AsanaUserInfoResponse
-	err := getJSON(client, userInfoURL, &userInfo)
 	logger := logging.GetSentryLogger()
-	if err != nil || len(userInfo.Data.Workspaces) == 0 {
+	workspaceID, err := asanaTask.getWorkspaceID(db, userID, accountID)
+	if err != nil {
 		logger.Error().Err(err).Msg("failed to get asana workspace ID")
-		if err == nil {
-			err = errors.New("user has not workspaces")
-		}
 		result <- emptyTaskResultWithSource(err, TASK_SOURCE_ID_ASANA)
 		return
 	}
-	workspaceID := userInfo.Data.Workspaces[0].ID
 
-	taskFetchURL := fmt.Sprintf(AsanaTasksURL+"?assignee=me&workspace=%s&completed_since=3022-01-01&opt_fields=this.html_notes,this.name,this.due_at,this.due_on,this.permalink_url", workspaceID)
+	client := getAsanaHttpClient(db, userID, accountID)
+	taskFetchURL := fmt.Sprintf(AsanaTasksURL+"?assignee=me&workspace=%s&completed_since=3022-01-01&opt_fields=this.html_notes,this.name,this.due_at,this.due_on,this.permalink_url,this.memberships.project.name,this.memberships.section.name", workspaceID)
 	if asanaTask.Asana.ConfigValues.TaskFetchURL != nil {
 		taskFetchURL = *asanaTask.Asana.ConfigValues.TaskFetchURL
 		client = http.DefaultClient
-	} else if client == nil {
-		client = getAsanaHttpClient(db, userID, accountID)
 	}
 
 	var asanaTasks AsanaTasksResponse
@@ -95,25 +137,22 @@ func (asanaTask AsanaTaskSource) GetTasks(db *mongo.Database, userID primitive.O
 		return
 	}
 
-	var tasks []*database.Task
+	// fetch the comments in tandem to avoid too much latency for tasks fetch endpoint
+	commentChannelList := []chan AsanaCommentsResult{}
 	for _, asanaTaskData := range asanaTasks.Data {
-		title := asanaTaskData.Name
-		body := asanaTaskData.HTMLNotes
-		task := &database.Task{
-			UserID:            userID,
-			IDExternal:        asanaTaskData.GID,
-			IDTaskSection:     constants.IDTaskSectionDefault,
-			Deeplink:          asanaTaskData.PermalinkURL,
-			SourceID:          TASK_SOURCE_ID_ASANA,
-			Title:             &title,
-			Body:              &body,
-			SourceAccountID:   accountID,
-			CreatedAtExternal: asanaTaskData.CreatedAt,
-		}
-		dueDate, err := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, asanaTaskData.DueOn)
-		if err == nil {
-			dueDatePrim := primitive.NewDateTimeFromTime(dueDate)
-			task.DueDate = &dueDatePrim
+		commentsChan := make(chan AsanaCommentsResult)
+		go asanaTask.getComments(db, userID, accountID, asanaTaskData.GID, commentsChan)
+		commentChannelList = append(commentChannelList, commentsChan)
+	}
+
+	var tasks []*database.Task
+	for idx, asanaTaskData := range asanaTasks.Data {
+		task := asanaTask.getTaskFromAsanaTask(userID, accountID, asanaTaskData)
+		commentsOutput := <-commentChannelList[idx]
+		if commentsOutput.Error != nil {
+			logger.Error().Err(commentsOutput.Error).Msg("failed to fetch asana comments")
+		} else {
+			task.Comments = commentsOutput.Comments
 		}
 		isCompleted := false
 		dbTask, err := database.UpdateOrCreateTask(
@@ -123,10 +162,13 @@ func (asanaTask AsanaTaskSource) GetTasks(db *mongo.Database, userID primitive.O
 			task.SourceID,
 			task,
 			database.Task{
-				Title:       task.Title,
-				Body:        task.Body,
-				DueDate:     task.DueDate,
-				IsCompleted: &isCompleted,
+				Title:           task.Title,
+				Body:            task.Body,
+				SearchBody:      task.Body,
+				DueDate:         task.DueDate,
+				IsCompleted:     &isCompleted,
+				Comments:        task.Comments,
+				AsanaTaskParams: task.AsanaTaskParams,
 			},
 			nil,
 		)
@@ -147,6 +189,98 @@ func (asanaTask AsanaTaskSource) GetTasks(db *mongo.Database, userID primitive.O
 	}
 }
 
+func (asanaTask AsanaTaskSource) getWorkspaceID(db *mongo.Database, userID primitive.ObjectID, accountID string) (string, error) {
+	client := getAsanaHttpClient(db, userID, accountID)
+	userInfoURL := AsanaUserInfoURL
+	if asanaTask.Asana.ConfigValues.UserInfoURL != nil {
+		userInfoURL = *asanaTask.Asana.ConfigValues.UserInfoURL
+		client = http.DefaultClient
+	}
+
+	var userInfo AsanaUserInfoResponse
+	err := getJSON(client, userInfoURL, &userInfo)
+	if err != nil {
+		return "", err
+	}
+	if len(userInfo.Data.Workspaces) == 0 {
+		return "", errors.New("user has not workspaces")
+	}
+	return userInfo.Data.Workspaces[0].ID, nil
+}
+
+func (asanaTask AsanaTaskSource) getTaskFromAsanaTask(userID primitive.ObjectID, accountID string, asanaTaskData AsanaTask) *database.Task {
+	title := asanaTaskData.Name
+	body := asanaTaskData.HTMLNotes
+	task := &database.Task{
+		UserID:            userID,
+		IDExternal:        asanaTaskData.GID,
+		IDTaskSection:     constants.IDTaskSectionDefault,
+		Deeplink:          asanaTaskData.PermalinkURL,
+		SourceID:          TASK_SOURCE_ID_ASANA,
+		Title:             &title,
+		Body:              &body,
+		SearchBody:        &body,
+		SourceAccountID:   accountID,
+		CreatedAtExternal: asanaTaskData.CreatedAt,
+	}
+	dueDate, err := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, asanaTaskData.DueOn)
+	if err == nil {
+		dueDatePrim := primitive.NewDateTimeFromTime(dueDate)
+		task.DueDate = &dueDatePrim
+	}
+	// a task can be multi-homed, but we only surface the first project it belongs to
+	if len(asanaTaskData.Memberships) > 0 {
+		membership := asanaTaskData.Memberships[0]
+		task.AsanaTaskParams = &database.AsanaTaskParams{
+			ProjectID:   membership.Project.GID,
+			ProjectName: membership.Project.Name,
+			SectionID:   membership.Section.GID,
+			SectionName: membership.Section.Name,
+		}
+	}
+	return task
+}
+
+func (asanaTask AsanaTaskSource) getComments(db *mongo.Database, userID primitive.ObjectID, accountID string, taskGID string, result chan<- AsanaCommentsResult) {
+	client := getAsanaHttpClient(db, userID, accountID)
+	storyFetchURL := fmt.Sprintf(AsanaTasksURL+"%s/stories?opt_fields=type,text,created_at,created_by.name,created_by.email", taskGID)
+	if asanaTask.Asana.ConfigValues.StoryFetchURL != nil {
+		storyFetchURL = *asanaTask.Asana.ConfigValues.StoryFetchURL
+		client = http.DefaultClient
+	}
+	if client == nil {
+		result <- AsanaCommentsResult{Error: errors.New("failed to create asana client")}
+		return
+	}
+
+	var stories AsanaStoriesResponse
+	err := getJSON(client, storyFetchURL, &stories)
+	if err != nil {
+		result <- AsanaCommentsResult{Error: err}
+		return
+	}
+
+	// stories also include system activity (e.g. assignment changes), so only keep user comments
+	comments := []database.Comment{}
+	for _, story := range stories.Data {
+		if story.Type != AsanaStoryComment {
+			continue
+		}
+		comments = append(comments, database.Comment{
+			ExternalID: story.GID,
+			Body:       story.Text,
+			User: database.ExternalUser{
+				ExternalID:  story.CreatedBy.GID,
+				Name:        story.CreatedBy.Name,
+				DisplayName: story.CreatedBy.Name,
+				Email:       story.CreatedBy.Email,
+			},
+			CreatedAt: story.CreatedAt,
+		})
+	}
+	result <- AsanaCommentsResult{Comments: &comments}
+}
+
 func (asanaTask AsanaTaskSource) GetPullRequests(db *mongo.Database, userID primitive.ObjectID, accountID string, result chan<- PullRequestResult) {
 	result <- emptyPullRequestResult(nil, false)
 }
@@ -191,7 +325,64 @@ func (asanaTask AsanaTaskSource) GetTaskUpdateBody(updateFields *database.Task)
 }
 
 func (asanaTask AsanaTaskSource) CreateNewTask(db *mongo.Database, userID primitive.ObjectID, accountID string, task TaskCreationObject) (primitive.ObjectID, error) {
-	return primitive.NilObjectID, errors.New("has not been implemented yet")
+	logger := logging.GetSentryLogger()
+	createFields := AsanaTasksCreateFields{
+		Name:      task.Title,
+		HTMLNotes: task.Body,
+		Assignee:  "me",
+	}
+	// asana requires either a workspace or a project, and the workspace is implied by the project
+	if task.AsanaProjectID != "" {
+		createFields.Projects = []string{task.AsanaProjectID}
+	} else {
+		workspaceID, err := asanaTask.getWorkspaceID(db, userID, accountID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to get asana workspace ID")
+			return primitive.NilObjectID, err
+		}
+		createFields.Workspace = workspaceID
+	}
+	if task.DueDate != nil {
+		dueDate := task.DueDate.Format(constants.YEAR_MONTH_DAY_FORMAT)
+		createFields.DueOn = &dueDate
+	}
+	bodyJson, err := json.Marshal(AsanaTasksCreateBody{Data: createFields})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	client := getAsanaHttpClient(db, userID, accountID)
+	taskCreateURL := AsanaTasksURL + "?opt_fields=this.html_notes,this.name,this.due_on,this.permalink_url,this.created_at,this.memberships.project.name,this.memberships.section.name"
+	if asanaTask.Asana.ConfigValues.TaskCreateURL != nil {
+		taskCreateURL = *asanaTask.Asana.ConfigValues.TaskCreateURL
+		client = http.DefaultClient
+	}
+	var createResponse AsanaTaskCreateResponse
+	err = requestJSON(client, "POST", taskCreateURL, string(bodyJson), &createResponse)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create asana task")
+		return primitive.NilObjectID, err
+	}
+
+	newTask := asanaTask.getTaskFromAsanaTask(userID, accountID, createResponse.Data)
+	if task.IDTaskSection != primitive.NilObjectID {
+		newTask.IDTaskSection = task.IDTaskSection
+	}
+	isCompleted := false
+	isDeleted := false
+	newTask.IsCompleted = &isCompleted
+	newTask.IsDeleted = &isDeleted
+	newTask.TimeAllocation = task.TimeAllocation
+	newTask.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	comments := []database.Comment{}
+	newTask.Comments = &comments
+
+	dbTask, err := database.GetOrCreateTask(db, userID, newTask.IDExternal, TASK_SOURCE_ID_ASANA, newTask)
+	if err != nil {
+		logger.Error().Err(err).Msg("could not create task")
+		return primitive.NilObjectID, err
+	}
+	return dbTask.ID, nil
 }
 
 func (asanaTask AsanaTaskSource) CreateNewEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, event EventCreateObject) error {
@@ -207,5 +398,23 @@ func (asanaTask AsanaTaskSource) DeleteEvent(db *mongo.Database, userID primitiv
 }
 
 func (asanaTask AsanaTaskSource) AddComment(db *mongo.Database, userID primitive.ObjectID, accountID string, comment database.Comment, task *database.Task) error {
-	return errors.New("has not been implemented yet")
+	client := getAsanaHttpClient(db, userID, accountID)
+	storyCreateURL := fmt.Sprintf(AsanaTasksURL+"%s/stories", task.IDExternal)
+	if asanaTask.Asana.ConfigValues.StoryCreateURL != nil {
+		storyCreateURL = *asanaTask.Asana.ConfigValues.StoryCreateURL
+		client = http.DefaultClient
+	}
+	var body AsanaStoryCreateBody
+	body.Data.Text = comment.Body
+	bodyJson, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	err = requestJSON(client, "POST", storyCreateURL, string(bodyJson), EmptyResponsePlaceholder)
+	if err != nil {
+		logger := logging.GetSentryLogger()
+		logger.Error().Err(err).Msg("failed to create asana comment")
+		return err
+	}
+	return nil
 }
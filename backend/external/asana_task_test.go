@@ -2,6 +2,9 @@ package external
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -25,7 +28,14 @@ func TestLoadAsanaTasks(t *testing.T) {
 	defer dbCleanup()
 	taskCollection := database.GetTaskCollection(db)
 
-	taskServerSuccess := testutils.GetMockAPIServer(t, 200, `{"data": [{"gid": "6942069420", "due_on": "2021-04-20", "html_notes": "hmm", "name": "Task!", "permalink_url": "https://example.com/"}]}`)
+	taskServerSuccess := testutils.GetMockAPIServer(t, 200, `{"data": [{"gid": "6942069420", "due_on": "2021-04-20", "html_notes": "hmm", "name": "Task!", "permalink_url": "https://example.com/", "memberships": [{"project": {"gid": "1234", "name": "Moon Launch"}, "section": {"gid": "5678", "name": "Backlog"}}]}]}`)
+	storyServerSuccess := testutils.GetMockAPIServer(t, 200, `{"data": [{"gid": "111", "type": "system", "text": "assigned to you", "created_at": "2022-04-20T07:05:06.416Z", "created_by": {"gid": "222", "name": "Asana"}}, {"gid": "333", "type": "comment", "text": "to the moon", "created_at": "2022-04-20T07:05:06.416Z", "created_by": {"gid": "444", "name": "Elon", "email": "elon@example.com"}}]}`)
+	expectedAsanaTaskParams := &database.AsanaTaskParams{
+		ProjectID:   "1234",
+		ProjectName: "Moon Launch",
+		SectionID:   "5678",
+		SectionName: "Backlog",
+	}
 	userInfoServerSuccess := testutils.GetMockAPIServer(t, 200, `{"data": {"workspaces": [{"gid": "6942069420"}]}}`)
 
 	t.Run("BadUserInfoStatusCode", func(t *testing.T) {
@@ -101,16 +111,31 @@ func TestLoadAsanaTasks(t *testing.T) {
 	})
 	t.Run("Success", func(t *testing.T) {
 		asanaTask := AsanaTaskSource{Asana: AsanaService{ConfigValues: AsanaConfigValues{
-			TaskFetchURL: &taskServerSuccess.URL,
-			UserInfoURL:  &userInfoServerSuccess.URL,
+			TaskFetchURL:  &taskServerSuccess.URL,
+			UserInfoURL:   &userInfoServerSuccess.URL,
+			StoryFetchURL: &storyServerSuccess.URL,
 		}}}
 		userID := primitive.NewObjectID()
 
 		dueDate, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, "2021-04-20")
 		dueDatePrim := primitive.NewDateTimeFromTime(dueDate)
 		createdAt, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, "2019-04-20")
+		commentCreatedAt, _ := time.Parse(time.RFC3339, "2022-04-20T07:05:06.416Z")
 		title := "Task!"
 		body := "hmm"
+		comments := []database.Comment{
+			{
+				ExternalID: "333",
+				Body:       "to the moon",
+				User: database.ExternalUser{
+					ExternalID:  "444",
+					Name:        "Elon",
+					DisplayName: "Elon",
+					Email:       "elon@example.com",
+				},
+				CreatedAt: primitive.NewDateTimeFromTime(commentCreatedAt),
+			},
+		}
 		expectedTask := database.Task{
 			IDOrdering:        0,
 			IDExternal:        "6942069420",
@@ -123,6 +148,8 @@ func TestLoadAsanaTasks(t *testing.T) {
 			UserID:            userID,
 			CreatedAtExternal: primitive.NewDateTimeFromTime(createdAt),
 			DueDate:           &dueDatePrim,
+			Comments:          &comments,
+			AsanaTaskParams:   expectedAsanaTaskParams,
 		}
 
 		var taskResult = make(chan TaskResult)
@@ -188,6 +215,7 @@ func TestLoadAsanaTasks(t *testing.T) {
 		correctBody := "hmm"
 		expectedTask.Title = &correctTitle
 		expectedTask.Body = &correctBody
+		expectedTask.AsanaTaskParams = expectedAsanaTaskParams
 
 		var taskResult = make(chan TaskResult)
 		go asanaTask.GetTasks(db, userID, "sample_account@email.com", taskResult)
@@ -423,3 +451,118 @@ func TestModifyAsanaTask(t *testing.T) {
 		assert.Equal(t, expected, *body)
 	})
 }
+
+func TestCreateAsanaTask(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	t.Run("BadUserInfoStatusCode", func(t *testing.T) {
+		userInfoServer := testutils.GetMockAPIServer(t, 400, "")
+		defer userInfoServer.Close()
+		asanaTask := AsanaTaskSource{Asana: AsanaService{ConfigValues: AsanaConfigValues{UserInfoURL: &userInfoServer.URL}}}
+		userID := primitive.NewObjectID()
+
+		_, err := asanaTask.CreateNewTask(db, userID, "sample_account@email.com", TaskCreationObject{Title: "new task"})
+		assert.Error(t, err)
+		assert.Equal(t, "bad status code: 400", err.Error())
+	})
+	t.Run("BadCreateStatusCode", func(t *testing.T) {
+		taskCreateServer := testutils.GetMockAPIServer(t, 400, "")
+		defer taskCreateServer.Close()
+		asanaTask := AsanaTaskSource{Asana: AsanaService{ConfigValues: AsanaConfigValues{TaskCreateURL: &taskCreateServer.URL}}}
+		userID := primitive.NewObjectID()
+
+		_, err := asanaTask.CreateNewTask(db, userID, "sample_account@email.com", TaskCreationObject{Title: "new task", AsanaProjectID: "1234"})
+		assert.Error(t, err)
+		assert.Equal(t, "bad status code: 400", err.Error())
+	})
+	t.Run("Success", func(t *testing.T) {
+		userInfoServer := testutils.GetMockAPIServer(t, 200, DefaultUserInfoResponse)
+		defer userInfoServer.Close()
+		taskCreateServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"data":{"name":"new task","html_notes":"new body","due_on":"2021-04-20","assignee":"me","workspace":"6942069420"}}`, string(body))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data": {"gid": "123456", "due_on": "2021-04-20", "html_notes": "new body", "name": "new task", "permalink_url": "https://example.com/", "memberships": []}}`))
+		}))
+		defer taskCreateServer.Close()
+		asanaTask := AsanaTaskSource{Asana: AsanaService{ConfigValues: AsanaConfigValues{
+			UserInfoURL:   &userInfoServer.URL,
+			TaskCreateURL: &taskCreateServer.URL,
+		}}}
+		userID := primitive.NewObjectID()
+
+		dueDate, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, "2021-04-20")
+		taskID, err := asanaTask.CreateNewTask(db, userID, "sample_account@email.com", TaskCreationObject{
+			Title:   "new task",
+			Body:    "new body",
+			DueDate: &dueDate,
+		})
+		assert.NoError(t, err)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "123456", task.IDExternal)
+		assert.Equal(t, TASK_SOURCE_ID_ASANA, task.SourceID)
+		assert.Equal(t, "new task", *task.Title)
+		assert.Equal(t, "new body", *task.Body)
+		assert.Equal(t, "https://example.com/", task.Deeplink)
+		assert.Equal(t, primitive.NewDateTimeFromTime(dueDate), *task.DueDate)
+		assert.False(t, *task.IsCompleted)
+	})
+	t.Run("SuccessWithProject", func(t *testing.T) {
+		taskCreateServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"data":{"name":"new task","assignee":"me","projects":["1234"]}}`, string(body))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data": {"gid": "123457", "name": "new task", "permalink_url": "https://example.com/", "memberships": [{"project": {"gid": "1234", "name": "Moon Launch"}, "section": {"gid": "5678", "name": "Backlog"}}]}}`))
+		}))
+		defer taskCreateServer.Close()
+		asanaTask := AsanaTaskSource{Asana: AsanaService{ConfigValues: AsanaConfigValues{TaskCreateURL: &taskCreateServer.URL}}}
+		userID := primitive.NewObjectID()
+
+		taskID, err := asanaTask.CreateNewTask(db, userID, "sample_account@email.com", TaskCreationObject{Title: "new task", AsanaProjectID: "1234"})
+		assert.NoError(t, err)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "123457", task.IDExternal)
+		assert.Equal(t, "Moon Launch", task.AsanaTaskParams.ProjectName)
+		assert.Equal(t, "Backlog", task.AsanaTaskParams.SectionName)
+	})
+}
+
+func TestAddAsanaComment(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	t.Run("BadResponse", func(t *testing.T) {
+		storyCreateServer := testutils.GetMockAPIServer(t, 400, "")
+		defer storyCreateServer.Close()
+		asanaTask := AsanaTaskSource{Asana: AsanaService{ConfigValues: AsanaConfigValues{StoryCreateURL: &storyCreateServer.URL}}}
+
+		err := asanaTask.AddComment(db, primitive.NewObjectID(), "sample_account@email.com", database.Comment{Body: "to the moon"}, &database.Task{IDExternal: "6942069420"})
+		assert.Error(t, err)
+		assert.Equal(t, "bad status code: 400", err.Error())
+	})
+	t.Run("Success", func(t *testing.T) {
+		storyCreateServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"data":{"text":"to the moon"}}`, string(body))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"data": {"gid": "333"}}`))
+		}))
+		defer storyCreateServer.Close()
+		asanaTask := AsanaTaskSource{Asana: AsanaService{ConfigValues: AsanaConfigValues{StoryCreateURL: &storyCreateServer.URL}}}
+
+		err := asanaTask.AddComment(db, primitive.NewObjectID(), "sample_account@email.com", database.Comment{Body: "to the moon"}, &database.Task{IDExternal: "6942069420"})
+		assert.NoError(t, err)
+	})
+}
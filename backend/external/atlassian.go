@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/GeneralTask/task-manager/backend/config"
 	"github.com/GeneralTask/task-manager/backend/constants"
@@ -39,12 +40,18 @@ type AtlassianConfigValues struct {
 	FieldsListURL   *string
 	IssueUpdateURL  *string
 	IssueDeleteURL  *string
+	IssueCreateURL  *string
+	CreateMetaURL   *string
+	UserInfoURL     *string
+	IssueURL        *string
+	WebhookURL      *string
 }
 
 // AtlassianConfig ...
 type AtlassianConfig struct {
-	OauthConfig  OauthConfigWrapper
-	ConfigValues AtlassianConfigValues
+	OauthConfig   OauthConfigWrapper
+	WebhookSecret string
+	ConfigValues  AtlassianConfigValues
 }
 
 // AtlassianSite ...
@@ -153,6 +160,15 @@ func (atlassian AtlassianService) HandleLinkCallback(db *mongo.Database, params
 		logger.Error().Err(err).Msg("failed to create external site collection record")
 		return errors.New("internal server error")
 	}
+
+	storedSiteConfiguration, err := atlassian.getSiteConfiguration(userID)
+	if err == nil {
+		err = JIRASource{Atlassian: atlassian}.RegisterWebhooks(db, storedSiteConfiguration, time.Now())
+	}
+	if err != nil {
+		// the issues are still fetched by polling, so the link itself is kept
+		logger.Error().Err(err).Msg("failed to register jira webhooks")
+	}
 	return nil
 }
 
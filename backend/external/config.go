@@ -2,29 +2,37 @@ package external
 
 import (
 	"fmt"
+
+	"github.com/GeneralTask/task-manager/backend/config"
 )
 
 const (
-	TASK_SERVICE_ID_ASANA     = "asana"
-	TASK_SERVICE_ID_ATLASSIAN = "atlassian"
-	TASK_SERVICE_ID_GT        = "gt"
-	TASK_SERVICE_ID_GITHUB    = "github"
-	TASK_SERVICE_ID_GOOGLE    = "google"
-	TASK_SERVICE_ID_LINEAR    = "linear"
-	TASK_SERVICE_ID_SLACK     = "slack"
-	TASK_SERVICE_ID_SLACK_APP = "slack_app"
+	TASK_SERVICE_ID_ASANA             = "asana"
+	TASK_SERVICE_ID_ATLASSIAN         = "atlassian"
+	TASK_SERVICE_ID_GT                = "gt"
+	TASK_SERVICE_ID_GITHUB            = "github"
+	TASK_SERVICE_ID_GITHUB_ENTERPRISE = "github_enterprise"
+	TASK_SERVICE_ID_GITLAB            = "gitlab"
+	TASK_SERVICE_ID_GOOGLE            = "google"
+	TASK_SERVICE_ID_LINEAR            = "linear"
+	TASK_SERVICE_ID_SLACK             = "slack"
+	TASK_SERVICE_ID_SLACK_APP         = "slack_app"
 
-	TASK_SOURCE_ID_ASANA       = "asana_task"
-	TASK_SOURCE_ID_GCAL        = "gcal"
-	TASK_SOURCE_ID_GITHUB_PR   = "github_pr"
-	TASK_SOURCE_ID_GT_TASK     = "gt_task"
-	TASK_SOURCE_ID_JIRA        = "jira"
-	TASK_SOURCE_ID_LINEAR      = "linear_task"
-	TASK_SOURCE_ID_SLACK_SAVED = "slack"
+	TASK_SOURCE_ID_ASANA        = "asana_task"
+	TASK_SOURCE_ID_GCAL         = "gcal"
+	TASK_SOURCE_ID_GITHUB_ISSUE = "github_issue"
+	TASK_SOURCE_ID_GITHUB_PR    = "github_pr"
+	TASK_SOURCE_ID_GITLAB_MR    = "gitlab_mr"
+	TASK_SOURCE_ID_GT_TASK      = "gt_task"
+	TASK_SOURCE_ID_JIRA         = "jira"
+	TASK_SOURCE_ID_LINEAR       = "linear_task"
+	TASK_SOURCE_ID_SLACK_SAVED  = "slack"
 )
 
 type Config struct {
 	Github                GithubConfig
+	GithubEnterprise      GithubConfig
+	Gitlab                GitlabConfig
 	GoogleLoginConfig     OauthConfigWrapper
 	GoogleAuthorizeConfig OauthConfigWrapper
 	Slack                 SlackConfig
@@ -42,12 +50,26 @@ func GetConfig() Config {
 	return Config{
 		GoogleLoginConfig:     getGoogleLoginConfig(),
 		GoogleAuthorizeConfig: getGoogleLinkConfig(),
-		Github:                GithubConfig{OauthConfig: getGithubConfig(), ConfigValues: GithubConfigValues{FetchExternalAPIToken: &fetchToken}},
-		Slack:                 getSlackConfig(),
-		SlackApp:              GetSlackAppConfig(),
-		Linear:                LinearConfig{OauthConfig: getLinearOauthConfig()},
-		Asana:                 getAsanaConfig(),
-		Atlassian:             AtlassianConfig{OauthConfig: getAtlassianOauthConfig()},
+		Github: GithubConfig{
+			OauthConfig:   getGithubConfig(),
+			ServiceID:     TASK_SERVICE_ID_GITHUB,
+			WebhookSecret: config.GetConfigValue("GITHUB_WEBHOOK_SECRET"),
+			ConfigValues:  GithubConfigValues{FetchExternalAPIToken: &fetchToken},
+		},
+		GithubEnterprise: GithubConfig{
+			ServiceID:     TASK_SERVICE_ID_GITHUB_ENTERPRISE,
+			WebhookSecret: config.GetConfigValue("GITHUB_ENTERPRISE_WEBHOOK_SECRET"),
+			ConfigValues:  GithubConfigValues{FetchExternalAPIToken: &fetchToken},
+		},
+		Gitlab:   GitlabConfig{OauthConfig: getGitlabOauthConfig(), BaseURL: getGitlabBaseURL()},
+		Slack:    getSlackConfig(),
+		SlackApp: GetSlackAppConfig(),
+		Linear:   LinearConfig{OauthConfig: getLinearOauthConfig()},
+		Asana:    getAsanaConfig(),
+		Atlassian: AtlassianConfig{
+			OauthConfig:   getAtlassianOauthConfig(),
+			WebhookSecret: config.GetConfigValue("JIRA_OAUTH_CLIENT_SECRET"),
+		},
 	}
 }
 
@@ -80,6 +102,17 @@ func (config Config) GetSourceResult(sourceID string) (*TaskSourceResult, error)
 	return &result, nil
 }
 
+func (config Config) GetServiceIDForSource(sourceID string) (string, error) {
+	for serviceID, taskServiceResult := range config.GetNameToService() {
+		for _, taskSourceResult := range taskServiceResult.Sources {
+			if taskSourceResult.Details.ID == sourceID {
+				return serviceID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("task service for source %s not found", sourceID)
+}
+
 func (config Config) getNameToSource() map[string]TaskSourceResult {
 	asanaService := AsanaService{Config: config.Asana}
 	atlassianService := AtlassianService{Config: config.Atlassian}
@@ -90,6 +123,7 @@ func (config Config) getNameToSource() map[string]TaskSourceResult {
 	}
 	linearService := LinearService{Config: config.Linear}
 	githubService := GithubService{Config: config.Github}
+	gitlabService := GitlabService{Config: config.Gitlab}
 	slackService := SlackService{Config: config.Slack}
 
 	return map[string]TaskSourceResult{
@@ -117,6 +151,14 @@ func (config Config) getNameToSource() map[string]TaskSourceResult {
 			Details: TaskSourceGithubPR,
 			Source:  GithubPRSource{Github: githubService},
 		},
+		TASK_SOURCE_ID_GITHUB_ISSUE: {
+			Details: TaskSourceGithubIssue,
+			Source:  GithubIssueSource{Github: githubService},
+		},
+		TASK_SOURCE_ID_GITLAB_MR: {
+			Details: TaskSourceGitlabMR,
+			Source:  GitlabMRSource{Gitlab: gitlabService},
+		},
 		TASK_SOURCE_ID_SLACK_SAVED: {
 			Details: TaskSourceSlackSaved,
 			Source:  SlackSavedTaskSource{Slack: slackService},
@@ -134,6 +176,8 @@ func (config Config) GetNameToService() map[string]TaskServiceResult {
 		OverrideURLs: config.GoogleOverrideURLs,
 	}
 	githubService := GithubService{Config: config.Github}
+	githubEnterpriseService := GithubService{Config: config.GithubEnterprise}
+	gitlabService := GitlabService{Config: config.Gitlab}
 	slackService := SlackService{Config: config.Slack}
 
 	return map[string]TaskServiceResult{
@@ -172,7 +216,20 @@ func (config Config) GetNameToService() map[string]TaskServiceResult {
 		TASK_SERVICE_ID_GITHUB: {
 			Service: githubService,
 			Details: TaskServiceGithub,
-			Sources: []TaskSourceResult{{Source: GithubPRSource{Github: githubService}, Details: TaskSourceGithubPR}},
+			Sources: []TaskSourceResult{
+				{Source: GithubPRSource{Github: githubService}, Details: TaskSourceGithubPR},
+				{Source: GithubIssueSource{Github: githubService}, Details: TaskSourceGithubIssue},
+			},
+		},
+		TASK_SERVICE_ID_GITHUB_ENTERPRISE: {
+			Service: githubEnterpriseService,
+			Details: TaskServiceGithubEnterprise,
+			Sources: []TaskSourceResult{{Source: GithubPRSource{Github: githubEnterpriseService}, Details: TaskSourceGithubPR}},
+		},
+		TASK_SERVICE_ID_GITLAB: {
+			Service: gitlabService,
+			Details: TaskServiceGitlab,
+			Sources: []TaskSourceResult{{Source: GitlabMRSource{Gitlab: gitlabService}, Details: TaskSourceGitlabMR}},
 		},
 		TASK_SERVICE_ID_LINEAR: {
 			Service: linearService,
@@ -233,6 +290,24 @@ var TaskServiceGithub = TaskServiceDetails{
 	IsLinkable:   true,
 	IsSignupable: false,
 }
+var TaskServiceGithubEnterprise = TaskServiceDetails{
+	ID:           TASK_SERVICE_ID_GITHUB_ENTERPRISE,
+	Name:         "GitHub Enterprise",
+	Logo:         "/images/github.svg",
+	LogoV2:       "github",
+	AuthType:     AuthTypeOauth2,
+	IsLinkable:   true,
+	IsSignupable: false,
+}
+var TaskServiceGitlab = TaskServiceDetails{
+	ID:           TASK_SERVICE_ID_GITLAB,
+	Name:         "GitLab",
+	Logo:         "/images/gitlab.svg",
+	LogoV2:       "gitlab",
+	AuthType:     AuthTypeOauth2,
+	IsLinkable:   true,
+	IsSignupable: false,
+}
 var TaskServiceGoogle = TaskServiceDetails{
 	ID:           TASK_SERVICE_ID_GOOGLE,
 	Name:         "Google Calendar",
@@ -278,7 +353,7 @@ var TaskSourceAsana = TaskSourceDetails{
 	Logo:                   "/images/asana.svg",
 	LogoV2:                 "asana",
 	IsCompletable:          true,
-	CanCreateTask:          false,
+	CanCreateTask:          true,
 	IsReplyable:            false,
 	CanCreateCalendarEvent: false,
 }
@@ -312,13 +387,33 @@ var TaskSourceGithubPR = TaskSourceDetails{
 	IsReplyable:            false,
 	CanCreateCalendarEvent: false,
 }
+var TaskSourceGithubIssue = TaskSourceDetails{
+	ID:                     TASK_SOURCE_ID_GITHUB_ISSUE,
+	Name:                   "GitHub Issue",
+	Logo:                   "/images/github.svg",
+	LogoV2:                 "github",
+	IsCompletable:          true,
+	CanCreateTask:          true,
+	IsReplyable:            false,
+	CanCreateCalendarEvent: false,
+}
+var TaskSourceGitlabMR = TaskSourceDetails{
+	ID:                     TASK_SOURCE_ID_GITLAB_MR,
+	Name:                   "GitLab MR",
+	Logo:                   "/images/gitlab.svg",
+	LogoV2:                 "gitlab",
+	IsCompletable:          true,
+	CanCreateTask:          false,
+	IsReplyable:            false,
+	CanCreateCalendarEvent: false,
+}
 var TaskSourceJIRA = TaskSourceDetails{
 	ID:                     TASK_SOURCE_ID_JIRA,
 	Name:                   "Jira",
 	Logo:                   "/images/jira.svg",
 	LogoV2:                 "jira",
 	IsCompletable:          true,
-	CanCreateTask:          false,
+	CanCreateTask:          true,
 	IsReplyable:            false,
 	CanCreateCalendarEvent: false,
 }
@@ -328,7 +423,7 @@ var TaskSourceLinear = TaskSourceDetails{
 	Logo:                   "/images/linear.png",
 	LogoV2:                 "linear",
 	IsCompletable:          true,
-	CanCreateTask:          false,
+	CanCreateTask:          true,
 	IsReplyable:            false,
 	CanCreateCalendarEvent: false,
 }
@@ -469,5 +469,17 @@ func updateUserTimezone(calendarService *calendar.Service, db *mongo.Database, u
 		return err
 	}
 	_, err = database.GetExternalTokenCollection(db).UpdateByID(context.Background(), token.ID, bson.M{"$set": bson.M{"timezone": setting.Value}})
+	if err != nil {
+		return err
+	}
+	// default the user's timezone to their calendar's so recurring tasks can be created server-side
+	_, err = database.GetUserCollection(db).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": userID},
+			{"timezone": bson.M{"$exists": false}},
+		}},
+		bson.M{"$set": bson.M{"timezone": setting.Value}},
+	)
 	return err
 }
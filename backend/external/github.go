@@ -21,10 +21,16 @@ import (
 	"golang.org/x/oauth2"
 )
 
+const (
+	// prefixed so that GitHub Enterprise repository IDs never collide with github.com repository IDs
+	GithubEnterpriseRepositoryIDPrefix = "github_enterprise_"
+)
+
 type GithubConfigValues struct {
 	FetchExternalAPIToken       *bool
 	CompareURL                  *string
 	GetUserURL                  *string
+	GetPullRequestURL           *string
 	ListPullRequestsURL         *string
 	ListPullRequestReviewURL    *string
 	ListPullRequestReviewersURL *string
@@ -34,11 +40,18 @@ type GithubConfigValues struct {
 	ListRepositoriesURL         *string
 	ListUserTeamsURL            *string
 	PullRequestModifiedURL      *string
+	ListIssuesURL               *string
+	IssueCreateURL              *string
+	IssueModifyURL              *string
+	IssueAddCommentURL          *string
 }
 
 type GithubConfig struct {
-	OauthConfig  OauthConfigWrapper
-	ConfigValues GithubConfigValues
+	// GitHub Enterprise OAuth configs are stored per user, see getOauthConfig
+	OauthConfig   OauthConfigWrapper
+	ServiceID     string
+	WebhookSecret string
+	ConfigValues  GithubConfigValues
 }
 
 type GithubService struct {
@@ -58,29 +71,92 @@ func getGithubConfig() *OauthConfig {
 	}}
 }
 
+func getGithubEnterpriseOauthConfig(configuration *database.GithubEnterpriseConfiguration) *OauthConfig {
+	return &OauthConfig{Config: &oauth2.Config{
+		ClientID:     configuration.OauthClientID,
+		ClientSecret: configuration.OauthClientSecret,
+		RedirectURL:  config.GetConfigValue("SERVER_URL") + "link/github_enterprise/callback/",
+		Scopes:       []string{"repo"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  configuration.BaseURL + "/login/oauth/authorize",
+			TokenURL: configuration.BaseURL + "/login/oauth/access_token",
+		},
+	}}
+}
+
+// getOauthConfig also returns the base URL of the instance being linked, which is empty for github.com
+func (githubService GithubService) getOauthConfig(db *mongo.Database, userID primitive.ObjectID) (OauthConfigWrapper, string, error) {
+	if githubService.getServiceID() != TASK_SERVICE_ID_GITHUB_ENTERPRISE {
+		return githubService.Config.OauthConfig, "", nil
+	}
+	configuration, err := database.GetGithubEnterpriseConfiguration(db, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	return getGithubEnterpriseOauthConfig(configuration), configuration.BaseURL, nil
+}
+
+func (githubService GithubService) getServiceID() string {
+	if githubService.Config.ServiceID == "" {
+		return TASK_SERVICE_ID_GITHUB
+	}
+	return githubService.Config.ServiceID
+}
+
+// GitHub Enterprise repository IDs also include the host, as IDs are only unique within an instance
+func (githubService GithubService) GetRepositoryID(repository *github.Repository) string {
+	if githubService.getServiceID() == TASK_SERVICE_ID_GITHUB_ENTERPRISE {
+		host := ""
+		repositoryURL, err := url.Parse(repository.GetHTMLURL())
+		if err == nil {
+			host = repositoryURL.Host
+		}
+		return GithubEnterpriseRepositoryIDPrefix + host + "_" + fmt.Sprint(repository.GetID())
+	}
+	return fmt.Sprint(repository.GetID())
+}
+
 func GetGithubToken(externalAPITokenCollection *mongo.Collection, userID primitive.ObjectID, accountID string) (*oauth2.Token, error) {
+	token, _, err := getGithubTokenAndBaseURL(externalAPITokenCollection, userID, accountID, TASK_SERVICE_ID_GITHUB)
+	return token, err
+}
+
+// getGithubTokenAndBaseURL also returns the GitHub Enterprise base URL stored with the token, which is empty for github.com
+func getGithubTokenAndBaseURL(externalAPITokenCollection *mongo.Collection, userID primitive.ObjectID, accountID string, serviceID string) (*oauth2.Token, string, error) {
 	var githubToken database.ExternalAPIToken
 
 	if err := externalAPITokenCollection.FindOne(
 		context.Background(),
 		bson.M{"$and": []bson.M{
 			{"user_id": userID},
-			{"service_id": TASK_SERVICE_ID_GITHUB},
+			{"service_id": serviceID},
 			{"account_id": accountID},
 		}}).Decode(&githubToken); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var token oauth2.Token
 	err := json.Unmarshal([]byte(githubToken.Token), &token)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return &token, nil
+	return &token, githubToken.GithubEnterpriseBaseURL, nil
 }
 
 func (githubService GithubService) GetLinkURL(stateTokenID primitive.ObjectID, userID primitive.ObjectID) (*string, error) {
-	authURL := githubService.Config.OauthConfig.AuthCodeURL(stateTokenID.Hex(), oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+	oauthConfig := githubService.Config.OauthConfig
+	if githubService.getServiceID() == TASK_SERVICE_ID_GITHUB_ENTERPRISE {
+		db, dbCleanup, err := database.GetDBConnection()
+		if err != nil {
+			return nil, err
+		}
+		defer dbCleanup()
+		oauthConfig, _, err = githubService.getOauthConfig(db, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	authURL := oauthConfig.AuthCodeURL(stateTokenID.Hex(), oauth2.AccessTypeOffline, oauth2.ApprovalForce)
 	return &authURL, nil
 }
 
@@ -93,8 +169,13 @@ func (githubService GithubService) HandleLinkCallback(db *mongo.Database, params
 
 	extCtx, cancel := context.WithTimeout(parentCtx, constants.ExternalTimeout)
 	defer cancel()
-	token, err := githubService.Config.OauthConfig.Exchange(extCtx, *params.Oauth2Code)
 	logger := logging.GetSentryLogger()
+	oauthConfig, baseURL, err := githubService.getOauthConfig(db, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to load Github Enterprise configuration")
+		return errors.New("internal server error")
+	}
+	token, err := oauthConfig.Exchange(extCtx, *params.Oauth2Code)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to fetch token from Github")
 		return errors.New("internal server error")
@@ -107,7 +188,7 @@ func (githubService GithubService) HandleLinkCallback(db *mongo.Database, params
 		return errors.New("internal server error")
 	}
 
-	githubAccountID, githubLogin, err := getGithubUserInfoFromToken(extCtx, token, CurrentlyAuthedUserFilter, githubService.Config.ConfigValues.GetUserURL)
+	githubAccountID, githubLogin, err := getGithubUserInfoFromToken(extCtx, token, baseURL, CurrentlyAuthedUserFilter, githubService.Config.ConfigValues.GetUserURL)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to fetch Github user")
 		return errors.New("internal server error")
@@ -116,15 +197,16 @@ func (githubService GithubService) HandleLinkCallback(db *mongo.Database, params
 	externalAPITokenCollection := database.GetExternalTokenCollection(db)
 	_, err = externalAPITokenCollection.UpdateOne(
 		context.Background(),
-		bson.M{"$and": []bson.M{{"user_id": userID}, {"service_id": TASK_SERVICE_ID_GITHUB}}},
+		bson.M{"$and": []bson.M{{"user_id": userID}, {"service_id": githubService.getServiceID()}}},
 		bson.M{"$set": &database.ExternalAPIToken{
-			UserID:         userID,
-			ServiceID:      TASK_SERVICE_ID_GITHUB,
-			Token:          string(tokenString),
-			AccountID:      fmt.Sprint(githubAccountID),
-			DisplayID:      githubLogin,
-			IsUnlinkable:   true,
-			IsPrimaryLogin: false,
+			UserID:                  userID,
+			ServiceID:               githubService.getServiceID(),
+			Token:                   string(tokenString),
+			AccountID:               fmt.Sprint(githubAccountID),
+			DisplayID:               githubLogin,
+			IsUnlinkable:            true,
+			IsPrimaryLogin:          false,
+			GithubEnterpriseBaseURL: baseURL,
 		}},
 		options.Update().SetUpsert(true),
 	)
@@ -139,16 +221,23 @@ func (github GithubService) HandleSignupCallback(db *mongo.Database, params Call
 	return primitive.NilObjectID, nil, nil, errors.New("github does not support signup")
 }
 
-func getGithubClientFromToken(ctx context.Context, token *oauth2.Token) *github.Client {
+// getGithubClientFromToken returns a github.com client, or a GitHub Enterprise client if baseURL is set
+func getGithubClientFromToken(ctx context.Context, token *oauth2.Token, baseURL string) (*github.Client, error) {
 	tokenSource := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token.AccessToken},
 	)
 	tokenClient := oauth2.NewClient(ctx, tokenSource)
-	return github.NewClient(tokenClient)
+	if baseURL == "" {
+		return github.NewClient(tokenClient), nil
+	}
+	return github.NewEnterpriseClient(baseURL, baseURL, tokenClient)
 }
 
-func getGithubUserInfoFromToken(ctx context.Context, token *oauth2.Token, currentlyAuthedUserFilter string, overrideURL *string) (int64, string, error) {
-	githubClient := getGithubClientFromToken(ctx, token)
+func getGithubUserInfoFromToken(ctx context.Context, token *oauth2.Token, baseURL string, currentlyAuthedUserFilter string, overrideURL *string) (int64, string, error) {
+	githubClient, err := getGithubClientFromToken(ctx, token, baseURL)
+	if err != nil {
+		return 0, "", err
+	}
 	return getGithubUserInfo(ctx, currentlyAuthedUserFilter, githubClient, overrideURL)
 }
 
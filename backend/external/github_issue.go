@@ -0,0 +1,383 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"github.com/google/go-github/v45/github"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	GithubIssueStateOpen   string = "open"
+	GithubIssueStateClosed string = "closed"
+	GithubIssueFilter      string = "assigned"
+	GithubResultsPerPage   int    = 100
+)
+
+type GithubIssueSource struct {
+	Github GithubService
+}
+
+func (gitIssue GithubIssueSource) getGithubClient(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, accountID string) (*github.Client, error) {
+	if gitIssue.Github.Config.ConfigValues.FetchExternalAPIToken == nil || !*gitIssue.Github.Config.ConfigValues.FetchExternalAPIToken {
+		return github.NewClient(nil), nil
+	}
+	token, baseURL, err := getGithubTokenAndBaseURL(database.GetExternalTokenCollection(db), userID, accountID, gitIssue.Github.getServiceID())
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, errors.New("failed to fetch Github API token")
+	}
+	return getGithubClientFromToken(ctx, token, baseURL)
+}
+
+func (gitIssue GithubIssueSource) GetEvents(db *mongo.Database, userID primitive.ObjectID, accountID string, startTime time.Time, endTime time.Time, scopes []string, result chan<- CalendarResult) {
+	result <- emptyCalendarResult(errors.New("github issue cannot fetch events"))
+}
+
+func (gitIssue GithubIssueSource) GetTasks(db *mongo.Database, userID primitive.ObjectID, accountID string, result chan<- TaskResult) {
+	logger := logging.GetSentryLogger()
+	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
+	defer cancel()
+
+	githubClient, err := gitIssue.getGithubClient(extCtx, db, userID, accountID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create Github client")
+		result <- emptyTaskResultWithSource(err, TASK_SOURCE_ID_GITHUB_ISSUE)
+		return
+	}
+	err = setOverrideURL(githubClient, gitIssue.Github.Config.ConfigValues.ListIssuesURL)
+	if err != nil {
+		result <- emptyTaskResultWithSource(err, TASK_SOURCE_ID_GITHUB_ISSUE)
+		return
+	}
+	// every open issue must be fetched, otherwise the ones past the first page would be marked as completed
+	issueListOptions := github.IssueListOptions{
+		Filter:      GithubIssueFilter,
+		State:       GithubIssueStateOpen,
+		ListOptions: github.ListOptions{PerPage: GithubResultsPerPage},
+	}
+	issues := []*github.Issue{}
+	for {
+		issuesPage, resp, err := githubClient.Issues.List(extCtx, true, &issueListOptions)
+		if err != nil {
+			handleErrorLogging(err, db, userID, "failed to fetch Github issues")
+			result <- emptyTaskResultWithSource(err, TASK_SOURCE_ID_GITHUB_ISSUE)
+			return
+		}
+		issues = append(issues, issuesPage...)
+		if resp.NextPage == 0 {
+			break
+		}
+		issueListOptions.Page = resp.NextPage
+	}
+
+	tasks := []*database.Task{}
+	for _, issue := range issues {
+		// the issues API also returns pull requests, which are handled by the PR source
+		if issue.IsPullRequest() || issue.Repository == nil {
+			continue
+		}
+		err = updateOrCreateRepository(db, issue.Repository, accountID, userID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to update or create repository")
+			result <- emptyTaskResultWithSource(err, TASK_SOURCE_ID_GITHUB_ISSUE)
+			return
+		}
+
+		task := getTaskFromGithubIssue(userID, accountID, issue, issue.Repository.GetFullName())
+		if issue.GetComments() > 0 {
+			comments, err := gitIssue.getIssueComments(db, userID, accountID, issue)
+			if err != nil {
+				handleErrorLogging(err, db, userID, "failed to fetch Github issue comments")
+			} else {
+				task.Comments = &comments
+			}
+		}
+
+		dbTask, err := database.UpdateOrCreateTask(
+			db,
+			userID,
+			task.IDExternal,
+			task.SourceID,
+			task,
+			database.Task{
+				Title:             task.Title,
+				Body:              task.Body,
+				SearchBody:        task.Body,
+				Comments:          task.Comments,
+				IsCompleted:       task.IsCompleted,
+				UpdatedAt:         task.UpdatedAt,
+				GithubIssueParams: task.GithubIssueParams,
+				ExternalLabels:    task.ExternalLabels,
+			},
+			nil,
+		)
+		if err != nil {
+			logger.Error().Err(err).Msg("could not create task")
+			result <- emptyTaskResultWithSource(err, TASK_SOURCE_ID_GITHUB_ISSUE)
+			return
+		}
+		task.HasBeenReordered = dbTask.HasBeenReordered
+		task.ID = dbTask.ID
+		task.IDOrdering = dbTask.IDOrdering
+		task.IDTaskSection = dbTask.IDTaskSection
+		tasks = append(tasks, task)
+	}
+
+	result <- TaskResult{Tasks: tasks, SourceID: TASK_SOURCE_ID_GITHUB_ISSUE}
+}
+
+func (gitIssue GithubIssueSource) getIssueComments(db *mongo.Database, userID primitive.ObjectID, accountID string, issue *github.Issue) ([]database.Comment, error) {
+	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
+	defer cancel()
+	githubClient, err := gitIssue.getGithubClient(extCtx, db, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	err = setOverrideURL(githubClient, gitIssue.Github.Config.ConfigValues.ListIssueCommentsURL)
+	if err != nil {
+		return nil, err
+	}
+	owner, repositoryName, err := splitRepositoryFullName(issue.Repository.GetFullName())
+	if err != nil {
+		return nil, err
+	}
+	commentListOptions := github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{PerPage: GithubResultsPerPage},
+	}
+	issueComments := []*github.IssueComment{}
+	for {
+		commentsPage, resp, err := githubClient.Issues.ListComments(extCtx, owner, repositoryName, issue.GetNumber(), &commentListOptions)
+		if err != nil {
+			return nil, err
+		}
+		issueComments = append(issueComments, commentsPage...)
+		if resp.NextPage == 0 {
+			break
+		}
+		commentListOptions.Page = resp.NextPage
+	}
+	comments := []database.Comment{}
+	for _, issueComment := range issueComments {
+		comments = append(comments, database.Comment{
+			ExternalID: fmt.Sprint(issueComment.GetID()),
+			Body:       issueComment.GetBody(),
+			User: database.ExternalUser{
+				ExternalID:  fmt.Sprint(issueComment.User.GetID()),
+				Name:        issueComment.User.GetLogin(),
+				DisplayName: issueComment.User.GetLogin(),
+			},
+			CreatedAt: primitive.NewDateTimeFromTime(issueComment.GetCreatedAt()),
+		})
+	}
+	return comments, nil
+}
+
+func getTaskFromGithubIssue(userID primitive.ObjectID, accountID string, issue *github.Issue, repositoryName string) *database.Task {
+	title := issue.GetTitle()
+	body := issue.GetBody()
+	isCompleted := false
+	isDeleted := false
+	externalLabels := GetGithubIssueExternalLabels(issue)
+	return &database.Task{
+		UserID:            userID,
+		IDExternal:        fmt.Sprint(issue.GetID()),
+		IDTaskSection:     constants.IDTaskSectionDefault,
+		Deeplink:          issue.GetHTMLURL(),
+		SourceID:          TASK_SOURCE_ID_GITHUB_ISSUE,
+		Title:             &title,
+		Body:              &body,
+		SearchBody:        &body,
+		SourceAccountID:   accountID,
+		CreatedAtExternal: primitive.NewDateTimeFromTime(issue.GetCreatedAt()),
+		UpdatedAt:         primitive.NewDateTimeFromTime(issue.GetUpdatedAt()),
+		IsCompleted:       &isCompleted,
+		IsDeleted:         &isDeleted,
+		ExternalLabels:    &externalLabels,
+		GithubIssueParams: &database.GithubIssueParams{
+			RepositoryID:   fmt.Sprint(issue.Repository.GetID()),
+			RepositoryName: repositoryName,
+			Number:         issue.GetNumber(),
+		},
+	}
+}
+
+func GetGithubIssueExternalLabels(issue *github.Issue) []database.ExternalLabel {
+	externalLabels := []database.ExternalLabel{}
+	for _, label := range issue.Labels {
+		externalLabel := database.ExternalLabel{ExternalID: fmt.Sprint(label.GetID()), Name: label.GetName()}
+		// GitHub colors are hex without the leading #
+		if label.GetColor() != "" {
+			externalLabel.Color = "#" + label.GetColor()
+		}
+		externalLabels = append(externalLabels, externalLabel)
+	}
+	return externalLabels
+}
+
+func splitRepositoryFullName(fullName string) (string, string, error) {
+	parts := strings.SplitN(fullName, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("invalid repository name")
+	}
+	return parts[0], parts[1], nil
+}
+
+func (gitIssue GithubIssueSource) GetPullRequests(db *mongo.Database, userID primitive.ObjectID, accountID string, result chan<- PullRequestResult) {
+	result <- emptyPullRequestResult(nil, false)
+}
+
+func (gitIssue GithubIssueSource) CreateNewTask(db *mongo.Database, userID primitive.ObjectID, accountID string, task TaskCreationObject) (primitive.ObjectID, error) {
+	logger := logging.GetSentryLogger()
+	if task.GithubRepositoryID == "" {
+		return primitive.NilObjectID, errors.New("github repository is required to create an issue")
+	}
+	var repository database.Repository
+	err := database.GetRepositoryCollection(db).FindOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"repository_id": task.GithubRepositoryID},
+			{"user_id": userID},
+			{"account_id": accountID},
+		}},
+	).Decode(&repository)
+	if err != nil {
+		return primitive.NilObjectID, errors.New("invalid github repository")
+	}
+	owner, repositoryName, err := splitRepositoryFullName(repository.FullName)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	// assign the issue to the linked account so that it shows up in the user's assigned issues
+	token, err := database.GetExternalToken(db, accountID, TASK_SERVICE_ID_GITHUB)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
+	defer cancel()
+	githubClient, err := gitIssue.getGithubClient(extCtx, db, userID, accountID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create Github client")
+		return primitive.NilObjectID, err
+	}
+	err = setOverrideURL(githubClient, gitIssue.Github.Config.ConfigValues.IssueCreateURL)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	issueRequest := github.IssueRequest{
+		Title:     &task.Title,
+		Body:      &task.Body,
+		Assignees: &[]string{token.DisplayID},
+	}
+	issue, _, err := githubClient.Issues.Create(extCtx, owner, repositoryName, &issueRequest)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create Github issue")
+		return primitive.NilObjectID, err
+	}
+
+	newTask := getTaskFromGithubIssue(userID, accountID, issue, repository.FullName)
+	newTask.GithubIssueParams.RepositoryID = repository.RepositoryID
+	newTask.TimeAllocation = task.TimeAllocation
+	if task.IDTaskSection != primitive.NilObjectID {
+		newTask.IDTaskSection = task.IDTaskSection
+	}
+	dbTask, err := database.GetOrCreateTask(db, userID, newTask.IDExternal, TASK_SOURCE_ID_GITHUB_ISSUE, newTask)
+	if err != nil {
+		logger.Error().Err(err).Msg("could not create task")
+		return primitive.NilObjectID, err
+	}
+	return dbTask.ID, nil
+}
+
+func (gitIssue GithubIssueSource) CreateNewEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, event EventCreateObject) error {
+	return errors.New("has not been implemented yet")
+}
+
+func (gitIssue GithubIssueSource) DeleteEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, externalID string, calendarID string) error {
+	return errors.New("has not been implemented yet")
+}
+
+func (gitIssue GithubIssueSource) ModifyTask(db *mongo.Database, userID primitive.ObjectID, accountID string, issueID string, updateFields *database.Task, task *database.Task) error {
+	if task == nil || task.GithubIssueParams == nil {
+		return errors.New("github issue params missing from task")
+	}
+	issueRequest := github.IssueRequest{
+		Title: updateFields.Title,
+		Body:  updateFields.Body,
+	}
+	if updateFields.IsCompleted != nil {
+		state := GithubIssueStateOpen
+		if *updateFields.IsCompleted {
+			state = GithubIssueStateClosed
+		}
+		issueRequest.State = &state
+	}
+	if issueRequest == (github.IssueRequest{}) {
+		return nil
+	}
+	owner, repositoryName, err := splitRepositoryFullName(task.GithubIssueParams.RepositoryName)
+	if err != nil {
+		return err
+	}
+
+	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
+	defer cancel()
+	githubClient, err := gitIssue.getGithubClient(extCtx, db, userID, accountID)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to create Github client")
+		return err
+	}
+	err = setOverrideURL(githubClient, gitIssue.Github.Config.ConfigValues.IssueModifyURL)
+	if err != nil {
+		return err
+	}
+	_, _, err = githubClient.Issues.Edit(extCtx, owner, repositoryName, task.GithubIssueParams.Number, &issueRequest)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to update Github issue")
+	}
+	return err
+}
+
+func (gitIssue GithubIssueSource) ModifyEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, eventID string, updateFields *EventModifyObject) error {
+	return errors.New("has not been implemented yet")
+}
+
+func (gitIssue GithubIssueSource) AddComment(db *mongo.Database, userID primitive.ObjectID, accountID string, comment database.Comment, task *database.Task) error {
+	if task == nil || task.GithubIssueParams == nil {
+		return errors.New("github issue params missing from task")
+	}
+	owner, repositoryName, err := splitRepositoryFullName(task.GithubIssueParams.RepositoryName)
+	if err != nil {
+		return err
+	}
+
+	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
+	defer cancel()
+	githubClient, err := gitIssue.getGithubClient(extCtx, db, userID, accountID)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to create Github client")
+		return err
+	}
+	err = setOverrideURL(githubClient, gitIssue.Github.Config.ConfigValues.IssueAddCommentURL)
+	if err != nil {
+		return err
+	}
+	_, _, err = githubClient.Issues.CreateComment(extCtx, owner, repositoryName, task.GithubIssueParams.Number, &github.IssueComment{Body: &comment.Body})
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to add comment to Github issue")
+	}
+	return err
+}
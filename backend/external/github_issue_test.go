@@ -0,0 +1,257 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/testutils"
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const githubIssuesPayload = `[
+	{
+		"id": 1001,
+		"number": 7,
+		"title": "fix the flux capacitor",
+		"body": "it is broken",
+		"html_url": "https://github.com/dankmemes/ExampleRepository/issues/7",
+		"comments": 0,
+		"repository": {"id": 1234, "full_name": "dankmemes/ExampleRepository", "html_url": "https://github.com/dankmemes/ExampleRepository"}
+	},
+	{
+		"id": 1002,
+		"number": 8,
+		"title": "this is a pull request",
+		"html_url": "https://github.com/dankmemes/ExampleRepository/pull/8",
+		"pull_request": {"url": "https://api.github.com/repos/dankmemes/ExampleRepository/pulls/8"},
+		"repository": {"id": 1234, "full_name": "dankmemes/ExampleRepository"}
+	}
+]`
+
+func getGithubIssueSourceForTest(configValues GithubConfigValues) GithubIssueSource {
+	fetchExternalAPIToken := false
+	configValues.FetchExternalAPIToken = &fetchExternalAPIToken
+	return GithubIssueSource{Github: GithubService{Config: GithubConfig{ConfigValues: configValues}}}
+}
+
+func TestGetGithubIssues(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	t.Run("BadResponse", func(t *testing.T) {
+		issuesServer := testutils.GetMockAPIServer(t, 400, "")
+		defer issuesServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{ListIssuesURL: &issuesServer.URL})
+
+		var taskResult = make(chan TaskResult)
+		go githubIssue.GetTasks(db, primitive.NewObjectID(), "exampleAccountID", taskResult)
+		result := <-taskResult
+		assert.Error(t, result.Error)
+		assert.Equal(t, TASK_SOURCE_ID_GITHUB_ISSUE, result.SourceID)
+		assert.Equal(t, 0, len(result.Tasks))
+	})
+	t.Run("Success", func(t *testing.T) {
+		issuesServer := testutils.GetMockAPIServer(t, 200, githubIssuesPayload)
+		defer issuesServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{ListIssuesURL: &issuesServer.URL})
+		userID := primitive.NewObjectID()
+
+		var taskResult = make(chan TaskResult)
+		go githubIssue.GetTasks(db, userID, "exampleAccountID", taskResult)
+		result := <-taskResult
+		assert.NoError(t, result.Error)
+		// the pull request should be skipped
+		assert.Equal(t, 1, len(result.Tasks))
+		task := result.Tasks[0]
+		assert.Equal(t, "1001", task.IDExternal)
+		assert.Equal(t, TASK_SOURCE_ID_GITHUB_ISSUE, task.SourceID)
+		assert.Equal(t, "fix the flux capacitor", *task.Title)
+		assert.Equal(t, "it is broken", *task.Body)
+		assert.Equal(t, "https://github.com/dankmemes/ExampleRepository/issues/7", task.Deeplink)
+		assert.Equal(t, database.GithubIssueParams{RepositoryID: "1234", RepositoryName: "dankmemes/ExampleRepository", Number: 7}, *task.GithubIssueParams)
+
+		dbTask, err := database.GetTask(db, task.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "1001", dbTask.IDExternal)
+		assert.False(t, *dbTask.IsCompleted)
+
+		var repository database.Repository
+		err = database.GetRepositoryCollection(db).FindOne(
+			context.Background(),
+			bson.M{"$and": []bson.M{{"repository_id": "1234"}, {"user_id": userID}}},
+		).Decode(&repository)
+		assert.NoError(t, err)
+		assert.Equal(t, "dankmemes/ExampleRepository", repository.FullName)
+		assert.Equal(t, "exampleAccountID", repository.AccountID)
+	})
+	t.Run("MultiplePages", func(t *testing.T) {
+		var issuesServer *httptest.Server
+		issuesServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+			if r.URL.Query().Get("page") == "2" {
+				w.WriteHeader(200)
+				w.Write([]byte(`[{"id": 1003, "number": 9, "title": "second page issue", "repository": {"id": 1234, "full_name": "dankmemes/ExampleRepository"}}]`))
+				return
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/issues?page=2&per_page=100>; rel="next"`, issuesServer.URL))
+			w.WriteHeader(200)
+			w.Write([]byte(githubIssuesPayload))
+		}))
+		defer issuesServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{ListIssuesURL: &issuesServer.URL})
+
+		var taskResult = make(chan TaskResult)
+		go githubIssue.GetTasks(db, primitive.NewObjectID(), "exampleAccountID", taskResult)
+		result := <-taskResult
+		assert.NoError(t, result.Error)
+		assert.Equal(t, 2, len(result.Tasks))
+		assert.Equal(t, "1001", result.Tasks[0].IDExternal)
+		assert.Equal(t, "1003", result.Tasks[1].IDExternal)
+	})
+}
+
+func TestCreateGithubIssue(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	userID := primitive.NewObjectID()
+	accountID := "exampleAccountID"
+	_, err = database.GetExternalTokenCollection(db).InsertOne(context.Background(), database.ExternalAPIToken{
+		UserID:    userID,
+		AccountID: accountID,
+		DisplayID: "dankmemer",
+		ServiceID: TASK_SERVICE_ID_GITHUB,
+	})
+	assert.NoError(t, err)
+	_, err = database.GetRepositoryCollection(db).InsertOne(context.Background(), database.Repository{
+		UserID:       userID,
+		AccountID:    accountID,
+		FullName:     "dankmemes/ExampleRepository",
+		RepositoryID: "1234",
+	})
+	assert.NoError(t, err)
+
+	t.Run("MissingRepository", func(t *testing.T) {
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{})
+		_, err := githubIssue.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue"})
+		assert.EqualError(t, err, "github repository is required to create an issue")
+	})
+	t.Run("InvalidRepository", func(t *testing.T) {
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{})
+		_, err := githubIssue.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue", GithubRepositoryID: "4321"})
+		assert.EqualError(t, err, "invalid github repository")
+	})
+	t.Run("BadResponse", func(t *testing.T) {
+		createServer := testutils.GetMockAPIServer(t, 400, "")
+		defer createServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{IssueCreateURL: &createServer.URL})
+		_, err := githubIssue.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue", GithubRepositoryID: "1234"})
+		assert.Error(t, err)
+	})
+	t.Run("Success", func(t *testing.T) {
+		createServer := testutils.GetMockAPIServer(t, 201, `{"id": 2001, "number": 9, "title": "new issue", "body": "details", "html_url": "https://github.com/dankmemes/ExampleRepository/issues/9"}`)
+		defer createServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{IssueCreateURL: &createServer.URL})
+		taskID, err := githubIssue.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue", Body: "details", GithubRepositoryID: "1234"})
+		assert.NoError(t, err)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "2001", task.IDExternal)
+		assert.Equal(t, TASK_SOURCE_ID_GITHUB_ISSUE, task.SourceID)
+		assert.Equal(t, "new issue", *task.Title)
+		assert.Equal(t, database.GithubIssueParams{RepositoryID: "1234", RepositoryName: "dankmemes/ExampleRepository", Number: 9}, *task.GithubIssueParams)
+	})
+}
+
+func TestModifyGithubIssue(t *testing.T) {
+	task := &database.Task{GithubIssueParams: &database.GithubIssueParams{RepositoryName: "dankmemes/ExampleRepository", Number: 7}}
+	t.Run("MissingParams", func(t *testing.T) {
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{})
+		isCompleted := true
+		err := githubIssue.ModifyTask(nil, primitive.NewObjectID(), "exampleAccountID", "1001", &database.Task{IsCompleted: &isCompleted}, &database.Task{})
+		assert.EqualError(t, err, "github issue params missing from task")
+	})
+	t.Run("NoUpdates", func(t *testing.T) {
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{})
+		err := githubIssue.ModifyTask(nil, primitive.NewObjectID(), "exampleAccountID", "1001", &database.Task{}, task)
+		assert.NoError(t, err)
+	})
+	t.Run("BadResponse", func(t *testing.T) {
+		modifyServer := testutils.GetMockAPIServer(t, 400, "")
+		defer modifyServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{IssueModifyURL: &modifyServer.URL})
+		isCompleted := true
+		err := githubIssue.ModifyTask(nil, primitive.NewObjectID(), "exampleAccountID", "1001", &database.Task{IsCompleted: &isCompleted}, task)
+		assert.Error(t, err)
+	})
+	t.Run("MarkAsDone", func(t *testing.T) {
+		modifyServer := testutils.GetMockAPIServer(t, 200, `{"id": 1001, "state": "closed"}`)
+		defer modifyServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{IssueModifyURL: &modifyServer.URL})
+		isCompleted := true
+		err := githubIssue.ModifyTask(nil, primitive.NewObjectID(), "exampleAccountID", "1001", &database.Task{IsCompleted: &isCompleted}, task)
+		assert.NoError(t, err)
+	})
+	t.Run("Reopen", func(t *testing.T) {
+		modifyServer := testutils.GetMockAPIServer(t, 200, `{"id": 1001, "state": "open"}`)
+		defer modifyServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{IssueModifyURL: &modifyServer.URL})
+		isCompleted := false
+		err := githubIssue.ModifyTask(nil, primitive.NewObjectID(), "exampleAccountID", "1001", &database.Task{IsCompleted: &isCompleted}, task)
+		assert.NoError(t, err)
+	})
+}
+
+func TestAddGithubIssueComment(t *testing.T) {
+	task := &database.Task{GithubIssueParams: &database.GithubIssueParams{RepositoryName: "dankmemes/ExampleRepository", Number: 7}}
+	t.Run("BadResponse", func(t *testing.T) {
+		commentServer := testutils.GetMockAPIServer(t, 400, "")
+		defer commentServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{IssueAddCommentURL: &commentServer.URL})
+		err := githubIssue.AddComment(nil, primitive.NewObjectID(), "exampleAccountID", database.Comment{Body: "hello"}, task)
+		assert.Error(t, err)
+	})
+	t.Run("Success", func(t *testing.T) {
+		commentServer := testutils.GetMockAPIServer(t, 201, `{"id": 3001, "body": "hello"}`)
+		defer commentServer.Close()
+		githubIssue := getGithubIssueSourceForTest(GithubConfigValues{IssueAddCommentURL: &commentServer.URL})
+		err := githubIssue.AddComment(nil, primitive.NewObjectID(), "exampleAccountID", database.Comment{Body: "hello"}, task)
+		assert.NoError(t, err)
+	})
+}
+
+func TestSplitRepositoryFullName(t *testing.T) {
+	owner, name, err := splitRepositoryFullName("dankmemes/ExampleRepository")
+	assert.NoError(t, err)
+	assert.Equal(t, "dankmemes", owner)
+	assert.Equal(t, "ExampleRepository", name)
+
+	_, _, err = splitRepositoryFullName("ExampleRepository")
+	assert.EqualError(t, err, "invalid repository name")
+}
+
+func TestGetGithubIssueExternalLabels(t *testing.T) {
+	labelID := int64(208045946)
+	name := "bug"
+	color := "f29513"
+	emptyColor := ""
+	issue := &github.Issue{Labels: []*github.Label{
+		{ID: &labelID, Name: &name, Color: &color},
+		{ID: &labelID, Name: &name, Color: &emptyColor},
+	}}
+	assert.Equal(t, []database.ExternalLabel{
+		{ExternalID: "208045946", Name: "bug", Color: "#f29513"},
+		{ExternalID: "208045946", Name: "bug"},
+	}, GetGithubIssueExternalLabels(issue))
+	assert.Equal(t, []database.ExternalLabel{}, GetGithubIssueExternalLabels(&github.Issue{}))
+}
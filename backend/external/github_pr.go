@@ -68,6 +68,10 @@ const (
 	GithubAPIBaseURL string = "https://api.github.com/"
 )
 
+const (
+	GithubPullRequestStateClosed string = "closed"
+)
+
 type GithubPRSource struct {
 	Github GithubService
 }
@@ -92,6 +96,8 @@ type GithubPRRequestData struct {
 	PullRequest *github.PullRequest
 	Token       *oauth2.Token
 	UserTeams   []*github.Team
+	// set when we already know the pull request has changed, e.g. from a webhook event
+	SkipModifiedCheck bool
 }
 
 type GithubUserResult struct {
@@ -145,7 +151,8 @@ func (gitPR GithubPRSource) GetPullRequests(db *mongo.Database, userID primitive
 		externalAPITokenCollection := database.GetExternalTokenCollection(db)
 		// need to do this to ensure `token` is the same `token` initialized above vs creating a new one with the := operator
 		var err error
-		token, err = GetGithubToken(externalAPITokenCollection, userID, accountID)
+		var baseURL string
+		token, baseURL, err = getGithubTokenAndBaseURL(externalAPITokenCollection, userID, accountID, gitPR.Github.getServiceID())
 		if token == nil {
 			logger.Error().Msg("failed to fetch Github API token")
 			result <- emptyPullRequestResult(errors.New("failed to fetch Github API token"), false)
@@ -156,10 +163,16 @@ func (gitPR GithubPRSource) GetPullRequests(db *mongo.Database, userID primitive
 			return
 		}
 
-		githubClient = getGithubClientFromToken(extCtx, token)
-		githubClientUser = getGithubClientFromToken(extCtx, token)
-		githubClientTeams = getGithubClientFromToken(extCtx, token)
-		githubClientRepos = getGithubClientFromToken(extCtx, token)
+		githubClient, err = getGithubClientFromToken(extCtx, token, baseURL)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to create Github client")
+			result <- emptyPullRequestResult(err, false)
+			return
+		}
+		// the base URL has been validated above, so these cannot fail
+		githubClientUser, _ = getGithubClientFromToken(extCtx, token, baseURL)
+		githubClientTeams, _ = getGithubClientFromToken(extCtx, token, baseURL)
+		githubClientRepos, _ = getGithubClientFromToken(extCtx, token, baseURL)
 	} else {
 		githubClient = github.NewClient(nil)
 		githubClientUser = github.NewClient(nil)
@@ -260,7 +273,7 @@ func (gitPR GithubPRSource) GetPullRequests(db *mongo.Database, userID primitive
 }
 
 func (gitPR GithubPRSource) processRepository(db *mongo.Database, userID primitive.ObjectID, accountID string, repository *github.Repository, githubClient *github.Client, token *oauth2.Token, githubUser *github.User, userTeams []*github.Team, result chan<- ProcessRepositoryResult) {
-	err := updateOrCreateRepository(db, repository, accountID, userID)
+	err := upsertRepository(db, gitPR.Github.GetRepositoryID(repository), repository.GetFullName(), repository.GetHTMLURL(), accountID, userID)
 	if err != nil {
 		logging.GetSentryLogger().Error().Err(err).Msg("failed to update or create repository")
 		result <- ProcessRepositoryResult{Error: err}
@@ -310,10 +323,12 @@ func (gitPR GithubPRSource) getPullRequestInfo(db *mongo.Database, userID primit
 	// do the check
 	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
 	defer cancel()
-	hasBeenModified, cachedPR := pullRequestHasBeenModified(db, extCtx, userID, requestData, gitPR.Github.Config.ConfigValues.PullRequestModifiedURL)
-	if !hasBeenModified {
-		result <- cachedPR
-		return
+	if !requestData.SkipModifiedCheck {
+		hasBeenModified, cachedPR := pullRequestHasBeenModified(db, extCtx, userID, requestData, gitPR.Github.Config.ConfigValues.PullRequestModifiedURL)
+		if !hasBeenModified {
+			result <- cachedPR
+			return
+		}
 	}
 
 	err = setOverrideURL(githubClient, gitPR.Github.Config.ConfigValues.ListPullRequestReviewURL)
@@ -405,7 +420,7 @@ func (gitPR GithubPRSource) getPullRequestInfo(db *mongo.Database, userID primit
 		Body:              pullRequest.GetBody(),
 		SourceAccountID:   accountID,
 		CreatedAtExternal: primitive.NewDateTimeFromTime(pullRequest.GetCreatedAt()),
-		RepositoryID:      fmt.Sprint(*repository.ID),
+		RepositoryID:      gitPR.Github.GetRepositoryID(repository),
 		RepositoryName:    repository.GetFullName(),
 		Number:            pullRequest.GetNumber(),
 		Author:            pullRequest.User.GetLogin(),
@@ -421,6 +436,95 @@ func (gitPR GithubPRSource) getPullRequestInfo(db *mongo.Database, userID primit
 	}
 }
 
+// RefreshPullRequest refetches a single pull request, e.g. in response to a webhook event, and updates it in the DB
+func (gitPR GithubPRSource) RefreshPullRequest(db *mongo.Database, userID primitive.ObjectID, accountID string, repository *github.Repository, number int) (*database.PullRequest, error) {
+	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
+	defer cancel()
+	githubClient, token, err := gitPR.getGithubClient(extCtx, db, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	userResultChan := make(chan GithubUserResult)
+	go getGithubUser(extCtx, githubClient, CurrentlyAuthedUserFilter, gitPR.Github.Config.ConfigValues.GetUserURL, userResultChan)
+	userResult := <-userResultChan
+	if userResult.Error != nil || userResult.User == nil {
+		if userResult.Error != nil {
+			handleErrorLogging(userResult.Error, db, userID, "failed to fetch Github user")
+		}
+		return nil, errors.New("failed to fetch Github user")
+	}
+
+	userTeamsResultChan := make(chan GithubUserTeamsResult)
+	go getUserTeams(extCtx, githubClient, gitPR.Github.Config.ConfigValues.ListUserTeamsURL, userTeamsResultChan)
+	userTeamsResult := <-userTeamsResultChan
+	if userTeamsResult.Error != nil {
+		handleErrorLogging(userTeamsResult.Error, db, userID, "failed to fetch Github user teams")
+		return nil, errors.New("failed to fetch Github user teams")
+	}
+
+	err = setOverrideURL(githubClient, gitPR.Github.Config.ConfigValues.GetPullRequestURL)
+	if err != nil {
+		return nil, err
+	}
+	pullRequest, _, err := githubClient.PullRequests.Get(extCtx, repository.GetOwner().GetLogin(), repository.GetName(), number)
+	if err != nil {
+		handleErrorLogging(err, db, userID, "failed to fetch Github PR")
+		return nil, err
+	}
+
+	pullRequestChan := make(chan *database.PullRequest)
+	go gitPR.getPullRequestInfo(db, userID, accountID, GithubPRRequestData{
+		Client:            githubClient,
+		User:              userResult.User,
+		Repository:        repository,
+		PullRequest:       pullRequest,
+		Token:             token,
+		UserTeams:         userTeamsResult.UserTeams,
+		SkipModifiedCheck: true,
+	}, pullRequestChan)
+	updatedPullRequest := <-pullRequestChan
+	if updatedPullRequest == nil {
+		return nil, errors.New("failed to fetch Github PR info")
+	}
+
+	isCompleted := pullRequest.GetState() == GithubPullRequestStateClosed
+	updatedPullRequest.IsCompleted = &isCompleted
+	if isCompleted {
+		updatedPullRequest.CompletedAt = primitive.NewDateTimeFromTime(time.Now())
+	}
+	updatedPullRequest.LastFetched = primitive.NewDateTimeFromTime(time.Now())
+	dbPR, err := database.UpdateOrCreatePullRequest(
+		db,
+		userID,
+		updatedPullRequest.IDExternal,
+		updatedPullRequest.SourceID,
+		updatedPullRequest,
+		nil)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to update or create pull request")
+		return nil, err
+	}
+	updatedPullRequest.ID = dbPR.ID
+	updatedPullRequest.IDOrdering = dbPR.IDOrdering
+	return updatedPullRequest, nil
+}
+
+func (gitPR GithubPRSource) getGithubClient(ctx context.Context, db *mongo.Database, userID primitive.ObjectID, accountID string) (*github.Client, *oauth2.Token, error) {
+	if gitPR.Github.Config.ConfigValues.FetchExternalAPIToken == nil || !*gitPR.Github.Config.ConfigValues.FetchExternalAPIToken {
+		return github.NewClient(nil), nil, nil
+	}
+	token, baseURL, err := getGithubTokenAndBaseURL(database.GetExternalTokenCollection(db), userID, accountID, gitPR.Github.getServiceID())
+	if err != nil {
+		return nil, nil, err
+	}
+	if token == nil {
+		return nil, nil, errors.New("failed to fetch Github API token")
+	}
+	githubClient, err := getGithubClientFromToken(ctx, token, baseURL)
+	return githubClient, token, err
+}
+
 func handleErrorLogging(err error, db *mongo.Database, userID primitive.ObjectID, msg string) bool {
 	shouldLog := shouldLogError(err)
 	if shouldLog {
@@ -471,7 +575,12 @@ func pullRequestHasBeenModified(db *mongo.Database, ctx context.Context, userID
 		return true, nil
 	}
 
-	requestURL := GithubAPIBaseURL + "repos/" + *repository.Owner.Login + "/" + *repository.Name + "/pulls/" + fmt.Sprint(*pullRequest.Number)
+	// use the client's base URL so that GitHub Enterprise requests go to the right host
+	baseURL := GithubAPIBaseURL
+	if requestData.Client != nil {
+		baseURL = requestData.Client.BaseURL.String()
+	}
+	requestURL := baseURL + "repos/" + *repository.Owner.Login + "/" + *repository.Name + "/pulls/" + fmt.Sprint(*pullRequest.Number)
 	if overrideURL != nil {
 		requestURL = *overrideURL
 	}
@@ -528,18 +637,22 @@ func getGithubRepositories(ctx context.Context, githubClient *github.Client, cur
 }
 
 func updateOrCreateRepository(db *mongo.Database, repository *github.Repository, accountID string, userID primitive.ObjectID) error {
+	return upsertRepository(db, fmt.Sprint(repository.GetID()), repository.GetFullName(), repository.GetHTMLURL(), accountID, userID)
+}
+
+func upsertRepository(db *mongo.Database, repositoryID string, fullName string, deeplink string, accountID string, userID primitive.ObjectID) error {
 	repositoryCollection := database.GetRepositoryCollection(db)
 	_, err := repositoryCollection.UpdateOne(
 		context.Background(),
 		bson.M{"$and": []bson.M{
 			// TODO: add account_id to query once backfill is completed
-			{"repository_id": fmt.Sprint(repository.GetID())},
+			{"repository_id": repositoryID},
 			{"user_id": userID},
 		}},
 		bson.M{"$set": bson.M{
 			"account_id": accountID,
-			"full_name":  repository.GetFullName(),
-			"deeplink":   repository.GetHTMLURL(),
+			"full_name":  fullName,
+			"deeplink":   deeplink,
 		}},
 		options.Update().SetUpsert(true),
 	)
@@ -289,6 +289,114 @@ func TestGetPullRequests(t *testing.T) {
 	})
 }
 
+func TestRefreshPullRequest(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	githubCompareServer := testutils.GetMockAPIServer(t, 200, testutils.CompareResponsePayload)
+	defer githubCompareServer.Close()
+	githubUserServer := testutils.GetMockAPIServer(t, 200, testutils.UserResponsePayload)
+	defer githubUserServer.Close()
+	githubPullRequestServer := testutils.GetMockAPIServer(t, 200, testutils.PullRequestPayload)
+	defer githubPullRequestServer.Close()
+	githubPullRequestReviewersServer := testutils.GetMockAPIServer(t, 200, testutils.EmptyPullRequestReviewersPayload)
+	defer githubPullRequestReviewersServer.Close()
+	githubPullRequestReviewServer := testutils.GetMockAPIServer(t, 200, `[]`)
+	defer githubPullRequestReviewServer.Close()
+	githubListCheckRunsForRefServer := testutils.GetMockAPIServer(t, 200, testutils.EmptyCheckRunsForRefPayload)
+	defer githubListCheckRunsForRefServer.Close()
+	githubListPullRequestCommentsServer := testutils.GetMockAPIServer(t, 200, testutils.PullRequestCommentsPayload)
+	defer githubListPullRequestCommentsServer.Close()
+	githubListIssueCommentsServer := testutils.GetMockAPIServer(t, 200, `[]`)
+	defer githubListIssueCommentsServer.Close()
+	githubListUserTeamsServer := testutils.GetMockAPIServer(t, 200, `[]`)
+	defer githubListUserTeamsServer.Close()
+	// the modified check should be skipped, so this would return the cached PR if it were used
+	githubPullRequestNotModifiedServer := testutils.GetMockAPIServer(t, 304, ``)
+	defer githubPullRequestNotModifiedServer.Close()
+
+	fetchExternalAPITokenValue := false
+	githubPR := GithubPRSource{
+		Github: GithubService{
+			Config: GithubConfig{
+				ConfigValues: GithubConfigValues{
+					FetchExternalAPIToken:       &fetchExternalAPITokenValue,
+					CompareURL:                  &githubCompareServer.URL,
+					GetUserURL:                  &githubUserServer.URL,
+					GetPullRequestURL:           &githubPullRequestServer.URL,
+					ListPullRequestCommentsURL:  &githubListPullRequestCommentsServer.URL,
+					ListIssueCommentsURL:        &githubListIssueCommentsServer.URL,
+					ListPullRequestReviewURL:    &githubPullRequestReviewServer.URL,
+					ListPullRequestReviewersURL: &githubPullRequestReviewersServer.URL,
+					ListCheckRunsForRefURL:      &githubListCheckRunsForRefServer.URL,
+					ListUserTeamsURL:            &githubListUserTeamsServer.URL,
+					PullRequestModifiedURL:      &githubPullRequestNotModifiedServer.URL,
+				},
+			},
+		},
+	}
+	repositoryID := int64(1234)
+	repository := &github.Repository{
+		ID:       &repositoryID,
+		Name:     github.String("ExampleRepository"),
+		FullName: github.String("dankmemes/ExampleRepository"),
+		Owner:    &github.User{Login: github.String("gigaChad123")},
+	}
+
+	t.Run("ExternalError", func(t *testing.T) {
+		badGithubPR := githubPR
+		githubUserErrorServer := testutils.GetMockAPIServer(t, 401, ``)
+		defer githubUserErrorServer.Close()
+		badGithubPR.Github.Config.ConfigValues.GetUserURL = &githubUserErrorServer.URL
+
+		_, err := badGithubPR.RefreshPullRequest(db, primitive.NewObjectID(), "exampleAccountID", repository, 420)
+		assert.EqualError(t, err, "failed to fetch Github user")
+	})
+	t.Run("Success", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		isCompleted := false
+		_, err := database.GetPullRequestCollection(db).InsertOne(context.Background(), database.PullRequest{
+			UserID:         userID,
+			IDExternal:     "1",
+			SourceID:       TASK_SOURCE_ID_GITHUB_PR,
+			IsCompleted:    &isCompleted,
+			Title:          "something cached in the db",
+			RequiredAction: ActionNoneNeeded,
+		})
+		assert.NoError(t, err)
+
+		pullRequest, err := githubPR.RefreshPullRequest(db, userID, "exampleAccountID", repository, 420)
+		assert.NoError(t, err)
+		assert.Equal(t, "Fix big oopsie", pullRequest.Title)
+		assert.Equal(t, ActionAddReviewers, pullRequest.RequiredAction)
+		assert.Equal(t, "1234", pullRequest.RepositoryID)
+		assert.False(t, *pullRequest.IsCompleted)
+
+		dbPR, err := database.GetPullRequestByExternalID(db, "1", userID)
+		assert.NoError(t, err)
+		assert.Equal(t, pullRequest.ID, dbPR.ID)
+		assert.Equal(t, "Fix big oopsie", dbPR.Title)
+		assert.Equal(t, ActionAddReviewers, dbPR.RequiredAction)
+	})
+	t.Run("Closed", func(t *testing.T) {
+		githubClosedPullRequestServer := testutils.GetMockAPIServer(t, 200, testutils.ClosedPullRequestPayload)
+		defer githubClosedPullRequestServer.Close()
+		closedGithubPR := githubPR
+		closedGithubPR.Github.Config.ConfigValues.GetPullRequestURL = &githubClosedPullRequestServer.URL
+		userID := primitive.NewObjectID()
+
+		pullRequest, err := closedGithubPR.RefreshPullRequest(db, userID, "exampleAccountID", repository, 420)
+		assert.NoError(t, err)
+		assert.True(t, *pullRequest.IsCompleted)
+
+		dbPR, err := database.GetPullRequestByExternalID(db, "1", userID)
+		assert.NoError(t, err)
+		assert.True(t, *dbPR.IsCompleted)
+		assert.False(t, dbPR.CompletedAt.Time().IsZero())
+	})
+}
+
 func TestUserIsOwner(t *testing.T) {
 	githubUserId1 := int64(1)
 	githubUserId2 := int64(2)
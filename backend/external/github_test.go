@@ -3,13 +3,16 @@ package external
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"github.com/GeneralTask/task-manager/backend/database"
 	"github.com/GeneralTask/task-manager/backend/testutils"
+	"github.com/google/go-github/v45/github"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/oauth2"
 )
 
 func TestGetGithubToken(t *testing.T) {
@@ -36,6 +39,60 @@ func TestGetGithubToken(t *testing.T) {
 	})
 }
 
+func TestGetGithubTokenAndBaseURL(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+
+	database.GetExternalTokenCollection(db).InsertOne(context.Background(), database.ExternalAPIToken{
+		UserID:                  userID,
+		ServiceID:               TASK_SERVICE_ID_GITHUB_ENTERPRISE,
+		AccountID:               "accountID",
+		Token:                   `{"access_token":"enterprise"}`,
+		GithubEnterpriseBaseURL: "https://github.example.com",
+	})
+
+	t.Run("WrongService", func(t *testing.T) {
+		_, _, err := getGithubTokenAndBaseURL(database.GetExternalTokenCollection(db), userID, "accountID", TASK_SERVICE_ID_GITHUB)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+	t.Run("Success", func(t *testing.T) {
+		token, baseURL, err := getGithubTokenAndBaseURL(database.GetExternalTokenCollection(db), userID, "accountID", TASK_SERVICE_ID_GITHUB_ENTERPRISE)
+		assert.NoError(t, err)
+		assert.Equal(t, "enterprise", token.AccessToken)
+		assert.Equal(t, "https://github.example.com", baseURL)
+	})
+}
+
+func TestGetGithubClientFromToken(t *testing.T) {
+	token := &oauth2.Token{AccessToken: "example"}
+	t.Run("Github", func(t *testing.T) {
+		githubClient, err := getGithubClientFromToken(context.Background(), token, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://api.github.com/", githubClient.BaseURL.String())
+	})
+	t.Run("GithubEnterprise", func(t *testing.T) {
+		githubClient, err := getGithubClientFromToken(context.Background(), token, "https://github.example.com")
+		assert.NoError(t, err)
+		assert.Equal(t, "https://github.example.com/api/v3/", githubClient.BaseURL.String())
+		assert.Equal(t, "https://github.example.com/api/uploads/", githubClient.UploadURL.String())
+	})
+	t.Run("InvalidBaseURL", func(t *testing.T) {
+		_, err := getGithubClientFromToken(context.Background(), token, "://github.example.com")
+		assert.Error(t, err)
+	})
+}
+
+func TestGithubGetRepositoryID(t *testing.T) {
+	repositoryID := int64(1234)
+	repositoryURL := "https://github.example.com/GeneralTask/task-manager"
+	repository := &github.Repository{ID: &repositoryID, HTMLURL: &repositoryURL}
+	assert.Equal(t, "1234", GithubService{}.GetRepositoryID(repository))
+	assert.Equal(t, "1234", GithubService{Config: GithubConfig{ServiceID: TASK_SERVICE_ID_GITHUB}}.GetRepositoryID(repository))
+	assert.Equal(t, "github_enterprise_github.example.com_1234", GithubService{Config: GithubConfig{ServiceID: TASK_SERVICE_ID_GITHUB_ENTERPRISE}}.GetRepositoryID(repository))
+}
+
 func TestGithubHandleLinkCallback(t *testing.T) {
 	db, dbCleanup, err := database.GetDBConnection()
 	assert.NoError(t, err)
@@ -56,4 +113,37 @@ func TestGithubHandleLinkCallback(t *testing.T) {
 		err = githubService.HandleLinkCallback(db, CallbackParams{Oauth2Code: &oauth2Code}, userID)
 		assert.Error(t, err)
 	})
+	t.Run("GithubEnterpriseNotConfigured", func(t *testing.T) {
+		githubService := GithubService{Config: GithubConfig{ServiceID: TASK_SERVICE_ID_GITHUB_ENTERPRISE}}
+		oauth2Code := "example"
+		err = githubService.HandleLinkCallback(db, CallbackParams{Oauth2Code: &oauth2Code}, userID)
+		assert.EqualError(t, err, "internal server error")
+	})
+}
+
+func TestGithubEnterpriseGetOauthConfig(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	githubService := GithubService{Config: GithubConfig{ServiceID: TASK_SERVICE_ID_GITHUB_ENTERPRISE}}
+
+	_, _, err = githubService.getOauthConfig(db, userID)
+	assert.Equal(t, mongo.ErrNoDocuments, err)
+
+	_, err = database.GetGithubEnterpriseConfigurationCollection(db).InsertOne(context.Background(), database.GithubEnterpriseConfiguration{
+		UserID:            userID,
+		BaseURL:           "https://github.example.com",
+		OauthClientID:     "client_id",
+		OauthClientSecret: "client_secret",
+	})
+	assert.NoError(t, err)
+	oauthConfig, baseURL, err := githubService.getOauthConfig(db, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://github.example.com", baseURL)
+	authURL, err := url.Parse(oauthConfig.AuthCodeURL("state"))
+	assert.NoError(t, err)
+	assert.Equal(t, "github.example.com", authURL.Host)
+	assert.Equal(t, "/login/oauth/authorize", authURL.Path)
+	assert.Equal(t, "client_id", authURL.Query().Get("client_id"))
 }
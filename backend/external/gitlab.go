@@ -0,0 +1,164 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/oauth2"
+)
+
+const (
+	GitlabDefaultBaseURL = "https://gitlab.com"
+	// prefixed so that GitLab project IDs never collide with GitHub repository IDs
+	GitlabRepositoryIDPrefix = "gitlab_"
+)
+
+type GitlabConfigValues struct {
+	UserInfoURL          *string
+	ListMergeRequestsURL *string
+	GetMergeRequestURL   *string
+	ListApprovalsURL     *string
+	ListNotesURL         *string
+	GetProjectURL        *string
+}
+
+type GitlabConfig struct {
+	OauthConfig  OauthConfigWrapper
+	BaseURL      string
+	ConfigValues GitlabConfigValues
+}
+
+type GitlabService struct {
+	Config GitlabConfig
+}
+
+type gitlabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+func getGitlabBaseURL() string {
+	baseURL := strings.TrimSuffix(config.GetConfigValue("GITLAB_BASE_URL"), "/")
+	if baseURL == "" {
+		return GitlabDefaultBaseURL
+	}
+	return baseURL
+}
+
+func getGitlabOauthConfig() *OauthConfig {
+	baseURL := getGitlabBaseURL()
+	return &OauthConfig{Config: &oauth2.Config{
+		ClientID:     config.GetConfigValue("GITLAB_OAUTH_CLIENT_ID"),
+		ClientSecret: config.GetConfigValue("GITLAB_OAUTH_CLIENT_SECRET"),
+		RedirectURL:  config.GetConfigValue("SERVER_URL") + "link/gitlab/callback/",
+		Scopes:       []string{"read_api", "read_user"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  baseURL + "/oauth/authorize",
+			TokenURL: baseURL + "/oauth/token",
+		},
+	}}
+}
+
+func (gitlab GitlabService) GetLinkURL(stateTokenID primitive.ObjectID, userID primitive.ObjectID) (*string, error) {
+	authURL := gitlab.Config.OauthConfig.AuthCodeURL(stateTokenID.Hex(), oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+	return &authURL, nil
+}
+
+func (gitlab GitlabService) GetSignupURL(stateTokenID primitive.ObjectID, forcePrompt bool) (*string, error) {
+	return nil, errors.New("gitlab does not support signup")
+}
+
+func (gitlab GitlabService) HandleLinkCallback(db *mongo.Database, params CallbackParams, userID primitive.ObjectID) error {
+	extCtx, cancel := context.WithTimeout(context.Background(), constants.ExternalTimeout)
+	defer cancel()
+	token, err := gitlab.Config.OauthConfig.Exchange(extCtx, *params.Oauth2Code)
+	logger := logging.GetSentryLogger()
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch token from GitLab")
+		return errors.New("internal server error")
+	}
+
+	tokenString, err := json.Marshal(&token)
+	if err != nil {
+		logger.Error().Err(err).Msg("error parsing token")
+		return errors.New("internal server error")
+	}
+
+	httpClient := oauth2.NewClient(extCtx, oauth2.StaticTokenSource(token))
+	var user gitlabUser
+	err = gitlab.getJSON(httpClient, gitlab.Config.ConfigValues.UserInfoURL, "/api/v4/user", &user)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch GitLab user")
+		return errors.New("internal server error")
+	}
+
+	accountID := fmt.Sprint(user.ID)
+	externalAPITokenCollection := database.GetExternalTokenCollection(db)
+	_, err = externalAPITokenCollection.UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{{"user_id": userID}, {"service_id": TASK_SERVICE_ID_GITLAB}, {"account_id": accountID}}},
+		bson.M{"$set": &database.ExternalAPIToken{
+			UserID:         userID,
+			ServiceID:      TASK_SERVICE_ID_GITLAB,
+			Token:          string(tokenString),
+			AccountID:      accountID,
+			DisplayID:      user.Username,
+			ExternalID:     accountID,
+			IsUnlinkable:   true,
+			IsPrimaryLogin: false,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		logger.Error().Err(err).Msg("error saving token")
+		return errors.New("internal server error")
+	}
+	return nil
+}
+
+func (gitlab GitlabService) HandleSignupCallback(db *mongo.Database, params CallbackParams) (primitive.ObjectID, *bool, *string, error) {
+	return primitive.NilObjectID, nil, nil, errors.New("gitlab does not support signup")
+}
+
+func getGitlabHttpClient(db *mongo.Database, userID primitive.ObjectID, accountID string) *http.Client {
+	return getExternalOauth2Client(db, userID, accountID, TASK_SERVICE_ID_GITLAB, getGitlabOauthConfig())
+}
+
+// getJSON requests the given API path from the configured GitLab instance, or from the override URL if set
+func (gitlab GitlabService) getJSON(client *http.Client, overrideURL *string, path string, result interface{}) error {
+	requestURL := gitlab.Config.BaseURL + path
+	if overrideURL != nil {
+		requestURL = *overrideURL
+	}
+	request, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	responseBytes, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("gitlab request failed: %s %d", responseBytes, response.StatusCode)
+	}
+	return json.Unmarshal(responseBytes, result)
+}
@@ -0,0 +1,353 @@
+package external
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	GitlabPipelineStatusFailed          string = "failed"
+	GitlabDetailedMergeStatusReqChanges string = "requested_changes"
+	GitlabMergeRequestScopeCreatedByMe  string = "created_by_me"
+	GitlabMergeRequestScopeAll          string = "all"
+	GitlabMergeRequestStateOpened       string = "opened"
+	GitlabResultsPerPage                int    = 100
+)
+
+// pipeline statuses which mean CI has not yet finished running
+var gitlabPipelineUnfinishedStatuses = map[string]bool{
+	"created":              true,
+	"waiting_for_resource": true,
+	"preparing":            true,
+	"pending":              true,
+	"running":              true,
+	"scheduled":            true,
+}
+
+type GitlabMRSource struct {
+	Gitlab GitlabService
+}
+
+type gitlabMergeRequest struct {
+	ID                          int64           `json:"id"`
+	IID                         int             `json:"iid"`
+	ProjectID                   int64           `json:"project_id"`
+	Title                       string          `json:"title"`
+	Description                 string          `json:"description"`
+	WebURL                      string          `json:"web_url"`
+	SourceBranch                string          `json:"source_branch"`
+	TargetBranch                string          `json:"target_branch"`
+	Author                      gitlabUser      `json:"author"`
+	Reviewers                   []gitlabUser    `json:"reviewers"`
+	HasConflicts                bool            `json:"has_conflicts"`
+	BlockingDiscussionsResolved bool            `json:"blocking_discussions_resolved"`
+	DetailedMergeStatus         string          `json:"detailed_merge_status"`
+	HeadPipeline                *gitlabPipeline `json:"head_pipeline"`
+	CreatedAt                   time.Time       `json:"created_at"`
+	UpdatedAt                   time.Time       `json:"updated_at"`
+}
+
+type gitlabPipeline struct {
+	Status string `json:"status"`
+}
+
+type gitlabApprovals struct {
+	Approved   bool `json:"approved"`
+	ApprovedBy []struct {
+		User gitlabUser `json:"user"`
+	} `json:"approved_by"`
+}
+
+type gitlabNote struct {
+	Body      string     `json:"body"`
+	Author    gitlabUser `json:"author"`
+	System    bool       `json:"system"`
+	CreatedAt time.Time  `json:"created_at"`
+	Position  *struct {
+		NewPath string `json:"new_path"`
+		NewLine int    `json:"new_line"`
+	} `json:"position"`
+}
+
+type gitlabProject struct {
+	ID                int64  `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+}
+
+func getGitlabRepositoryID(projectID int64) string {
+	return GitlabRepositoryIDPrefix + fmt.Sprint(projectID)
+}
+
+func (gitlabMR GitlabMRSource) getClient(overrideURL *string, db *mongo.Database, userID primitive.ObjectID, accountID string) (*http.Client, error) {
+	if overrideURL != nil {
+		return &http.Client{}, nil
+	}
+	client := getGitlabHttpClient(db, userID, accountID)
+	if client == nil {
+		return nil, errors.New("could not create gitlab client")
+	}
+	return client, nil
+}
+
+func (gitlabMR GitlabMRSource) getJSON(overrideURL *string, path string, db *mongo.Database, userID primitive.ObjectID, accountID string, result interface{}) error {
+	client, err := gitlabMR.getClient(overrideURL, db, userID, accountID)
+	if err != nil {
+		return err
+	}
+	return gitlabMR.Gitlab.getJSON(client, overrideURL, path, result)
+}
+
+func (gitlabMR GitlabMRSource) GetEvents(db *mongo.Database, userID primitive.ObjectID, accountID string, startTime time.Time, endTime time.Time, scopes []string, result chan<- CalendarResult) {
+	result <- emptyCalendarResult(errors.New("gitlab MR cannot fetch events"))
+}
+
+func (gitlabMR GitlabMRSource) GetTasks(db *mongo.Database, userID primitive.ObjectID, accountID string, result chan<- TaskResult) {
+	result <- emptyTaskResult(nil)
+}
+
+func (gitlabMR GitlabMRSource) GetPullRequests(db *mongo.Database, userID primitive.ObjectID, accountID string, result chan<- PullRequestResult) {
+	logger := logging.GetSentryLogger()
+	configValues := gitlabMR.Gitlab.Config.ConfigValues
+
+	var user gitlabUser
+	err := gitlabMR.getJSON(configValues.UserInfoURL, "/api/v4/user", db, userID, accountID, &user)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch GitLab user")
+		result <- emptyPullRequestResultWithSource(err, TASK_SOURCE_ID_GITLAB_MR, false)
+		return
+	}
+
+	mergeRequests, err := gitlabMR.listMergeRequests(db, userID, accountID, user)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch GitLab merge requests")
+		result <- emptyPullRequestResultWithSource(err, TASK_SOURCE_ID_GITLAB_MR, false)
+		return
+	}
+
+	projectIDToProject := make(map[int64]gitlabProject)
+	pullRequests := []*database.PullRequest{}
+	for _, mergeRequest := range mergeRequests {
+		project, ok := projectIDToProject[mergeRequest.ProjectID]
+		if !ok {
+			err = gitlabMR.getJSON(configValues.GetProjectURL, fmt.Sprintf("/api/v4/projects/%d", mergeRequest.ProjectID), db, userID, accountID, &project)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to fetch GitLab project")
+				result <- emptyPullRequestResultWithSource(err, TASK_SOURCE_ID_GITLAB_MR, false)
+				return
+			}
+			err = upsertRepository(db, getGitlabRepositoryID(project.ID), project.PathWithNamespace, project.WebURL, accountID, userID)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to update or create repository")
+				result <- emptyPullRequestResultWithSource(err, TASK_SOURCE_ID_GITLAB_MR, false)
+				return
+			}
+			projectIDToProject[mergeRequest.ProjectID] = project
+		}
+
+		pullRequest, err := gitlabMR.getPullRequestInfo(db, userID, accountID, user, project, mergeRequest)
+		if err != nil {
+			// skip this merge request and keep processing the rest
+			logger.Error().Err(err).Msg("failed to fetch GitLab merge request info")
+			continue
+		}
+
+		dbPR, err := database.UpdateOrCreatePullRequest(
+			db,
+			userID,
+			pullRequest.IDExternal,
+			pullRequest.SourceID,
+			pullRequest,
+			nil)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to update or create pull request")
+			result <- emptyPullRequestResultWithSource(err, TASK_SOURCE_ID_GITLAB_MR, false)
+			return
+		}
+		pullRequest.ID = dbPR.ID
+		pullRequest.IDOrdering = dbPR.IDOrdering
+		pullRequests = append(pullRequests, pullRequest)
+	}
+
+	result <- PullRequestResult{
+		PullRequests: pullRequests,
+		SourceID:     TASK_SOURCE_ID_GITLAB_MR,
+	}
+}
+
+// listMergeRequests returns the open merge requests the user has either authored or been asked to review
+func (gitlabMR GitlabMRSource) listMergeRequests(db *mongo.Database, userID primitive.ObjectID, accountID string, user gitlabUser) ([]gitlabMergeRequest, error) {
+	queries := []url.Values{
+		{"state": {GitlabMergeRequestStateOpened}, "scope": {GitlabMergeRequestScopeCreatedByMe}},
+		{"state": {GitlabMergeRequestStateOpened}, "scope": {GitlabMergeRequestScopeAll}, "reviewer_id": {fmt.Sprint(user.ID)}},
+	}
+	seenMergeRequests := make(map[int64]bool)
+	mergeRequests := []gitlabMergeRequest{}
+	for _, query := range queries {
+		query.Set("per_page", fmt.Sprint(GitlabResultsPerPage))
+		var fetchedMergeRequests []gitlabMergeRequest
+		err := gitlabMR.getJSON(gitlabMR.Gitlab.Config.ConfigValues.ListMergeRequestsURL, "/api/v4/merge_requests?"+query.Encode(), db, userID, accountID, &fetchedMergeRequests)
+		if err != nil {
+			return nil, err
+		}
+		for _, mergeRequest := range fetchedMergeRequests {
+			if seenMergeRequests[mergeRequest.ID] {
+				continue
+			}
+			seenMergeRequests[mergeRequest.ID] = true
+			mergeRequests = append(mergeRequests, mergeRequest)
+		}
+	}
+	return mergeRequests, nil
+}
+
+func (gitlabMR GitlabMRSource) getPullRequestInfo(db *mongo.Database, userID primitive.ObjectID, accountID string, user gitlabUser, project gitlabProject, mergeRequest gitlabMergeRequest) (*database.PullRequest, error) {
+	configValues := gitlabMR.Gitlab.Config.ConfigValues
+	mergeRequestPath := fmt.Sprintf("/api/v4/projects/%d/merge_requests/%d", mergeRequest.ProjectID, mergeRequest.IID)
+
+	// the list endpoint does not include pipeline or merge status details
+	var mergeRequestDetails gitlabMergeRequest
+	err := gitlabMR.getJSON(configValues.GetMergeRequestURL, mergeRequestPath, db, userID, accountID, &mergeRequestDetails)
+	if err != nil {
+		return nil, err
+	}
+	var approvals gitlabApprovals
+	err = gitlabMR.getJSON(configValues.ListApprovalsURL, mergeRequestPath+"/approvals", db, userID, accountID, &approvals)
+	if err != nil {
+		return nil, err
+	}
+	var notes []gitlabNote
+	err = gitlabMR.getJSON(configValues.ListNotesURL, fmt.Sprintf("%s/notes?per_page=%d", mergeRequestPath, GitlabResultsPerPage), db, userID, accountID, &notes)
+	if err != nil {
+		return nil, err
+	}
+	comments := getGitlabMergeRequestComments(notes)
+
+	isOwner := mergeRequest.Author.ID == user.ID
+	requiredAction := ActionNoneNeeded
+	if isOwner || gitlabUserIsReviewer(user, mergeRequest) {
+		requiredAction = getPullRequestRequiredAction(GithubPRData{
+			RequestedReviewers:   len(mergeRequest.Reviewers),
+			IsMergeable:          !mergeRequestDetails.HasConflicts,
+			IsApproved:           approvals.Approved && len(approvals.ApprovedBy) > 0,
+			HaveRequestedChanges: !mergeRequestDetails.BlockingDiscussionsResolved || mergeRequestDetails.DetailedMergeStatus == GitlabDetailedMergeStatusReqChanges,
+			ChecksDidFail:        gitlabPipelineDidFail(mergeRequestDetails.HeadPipeline),
+			ChecksDidFinish:      gitlabPipelineDidFinish(mergeRequestDetails.HeadPipeline),
+			IsOwnedByUser:        isOwner,
+			UserLogin:            user.Username,
+			UserIsReviewer:       gitlabUserNeedsToSubmitReview(user, mergeRequest, approvals),
+		})
+	}
+
+	isCompleted := false
+	return &database.PullRequest{
+		UserID:            userID,
+		IDExternal:        fmt.Sprint(mergeRequest.ID),
+		IsCompleted:       &isCompleted,
+		Deeplink:          mergeRequest.WebURL,
+		SourceID:          TASK_SOURCE_ID_GITLAB_MR,
+		Title:             mergeRequest.Title,
+		Body:              mergeRequest.Description,
+		SourceAccountID:   accountID,
+		CreatedAtExternal: primitive.NewDateTimeFromTime(mergeRequest.CreatedAt),
+		RepositoryID:      getGitlabRepositoryID(project.ID),
+		RepositoryName:    project.PathWithNamespace,
+		Number:            mergeRequest.IID,
+		Author:            mergeRequest.Author.Username,
+		Branch:            mergeRequest.SourceBranch,
+		BaseBranch:        mergeRequest.TargetBranch,
+		RequiredAction:    requiredAction,
+		Comments:          comments,
+		CommentCount:      len(comments),
+		LastFetched:       primitive.NewDateTimeFromTime(time.Now()),
+		LastUpdatedAt:     primitive.NewDateTimeFromTime(mergeRequest.UpdatedAt),
+	}, nil
+}
+
+func getGitlabMergeRequestComments(notes []gitlabNote) []database.PullRequestComment {
+	comments := []database.PullRequestComment{}
+	for _, note := range notes {
+		// system notes are generated by GitLab itself (e.g. "added 1 commit")
+		if note.System {
+			continue
+		}
+		comment := database.PullRequestComment{
+			Type:      constants.COMMENT_TYPE_TOPLEVEL,
+			Body:      note.Body,
+			Author:    note.Author.Username,
+			CreatedAt: primitive.NewDateTimeFromTime(note.CreatedAt),
+		}
+		if note.Position != nil {
+			comment.Type = constants.COMMENT_TYPE_INLINE
+			comment.Filepath = note.Position.NewPath
+			comment.LineNumberStart = note.Position.NewLine
+			comment.LineNumberEnd = note.Position.NewLine
+		}
+		comments = append(comments, comment)
+	}
+	return comments
+}
+
+func gitlabUserIsReviewer(user gitlabUser, mergeRequest gitlabMergeRequest) bool {
+	for _, reviewer := range mergeRequest.Reviewers {
+		if reviewer.ID == user.ID {
+			return true
+		}
+	}
+	return false
+}
+
+func gitlabUserNeedsToSubmitReview(user gitlabUser, mergeRequest gitlabMergeRequest, approvals gitlabApprovals) bool {
+	if !gitlabUserIsReviewer(user, mergeRequest) {
+		return false
+	}
+	for _, approval := range approvals.ApprovedBy {
+		if approval.User.ID == user.ID {
+			return false
+		}
+	}
+	return true
+}
+
+func gitlabPipelineDidFail(pipeline *gitlabPipeline) bool {
+	return pipeline != nil && pipeline.Status == GitlabPipelineStatusFailed
+}
+
+func gitlabPipelineDidFinish(pipeline *gitlabPipeline) bool {
+	// merge requests without a pipeline have no checks to wait on
+	return pipeline == nil || !gitlabPipelineUnfinishedStatuses[pipeline.Status]
+}
+
+func (gitlabMR GitlabMRSource) CreateNewTask(db *mongo.Database, userID primitive.ObjectID, accountID string, task TaskCreationObject) (primitive.ObjectID, error) {
+	return primitive.NilObjectID, errors.New("has not been implemented yet")
+}
+
+func (gitlabMR GitlabMRSource) CreateNewEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, event EventCreateObject) error {
+	return errors.New("has not been implemented yet")
+}
+
+func (gitlabMR GitlabMRSource) DeleteEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, externalID string, calendarID string) error {
+	return errors.New("has not been implemented yet")
+}
+
+func (gitlabMR GitlabMRSource) ModifyTask(db *mongo.Database, userID primitive.ObjectID, accountID string, issueID string, updateFields *database.Task, task *database.Task) error {
+	// allow users to mark MR as done in GT even if it's not done in GitLab
+	return nil
+}
+
+func (gitlabMR GitlabMRSource) ModifyEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, eventID string, updateFields *EventModifyObject) error {
+	return errors.New("has not been implemented yet")
+}
+
+func (gitlabMR GitlabMRSource) AddComment(db *mongo.Database, userID primitive.ObjectID, accountID string, comment database.Comment, task *database.Task) error {
+	return errors.New("has not been implemented yet")
+}
@@ -0,0 +1,167 @@
+package external
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const gitlabMergeRequestPayload = `{
+	"id": 501,
+	"iid": 12,
+	"project_id": 42,
+	"title": "add flux capacitor",
+	"description": "1.21 gigawatts",
+	"web_url": "https://gitlab.example.com/dankmemes/example/-/merge_requests/12",
+	"source_branch": "flux",
+	"target_branch": "main",
+	"author": {"id": 7, "username": "docbrown"},
+	"reviewers": [{"id": 8, "username": "marty"}],
+	"has_conflicts": false,
+	"blocking_discussions_resolved": true,
+	"head_pipeline": {"status": "success"},
+	"created_at": "2022-10-01T12:00:00Z",
+	"updated_at": "2022-10-02T12:00:00Z"
+}`
+
+func TestGetGitlabPullRequests(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	userServer := testutils.GetMockAPIServer(t, 200, `{"id": 7, "username": "docbrown"}`)
+	defer userServer.Close()
+	mergeRequestsServer := testutils.GetMockAPIServer(t, 200, "["+gitlabMergeRequestPayload+"]")
+	defer mergeRequestsServer.Close()
+	mergeRequestServer := testutils.GetMockAPIServer(t, 200, gitlabMergeRequestPayload)
+	defer mergeRequestServer.Close()
+	approvalsServer := testutils.GetMockAPIServer(t, 200, `{"approved": false, "approved_by": []}`)
+	defer approvalsServer.Close()
+	notesServer := testutils.GetMockAPIServer(t, 200, `[{"body": "looks great", "author": {"id": 8, "username": "marty"}, "system": false, "created_at": "2022-10-02T12:00:00Z"}, {"body": "added 1 commit", "author": {"id": 7, "username": "docbrown"}, "system": true}]`)
+	defer notesServer.Close()
+	projectServer := testutils.GetMockAPIServer(t, 200, `{"id": 42, "path_with_namespace": "dankmemes/example", "web_url": "https://gitlab.example.com/dankmemes/example"}`)
+	defer projectServer.Close()
+
+	gitlabMR := GitlabMRSource{Gitlab: GitlabService{Config: GitlabConfig{ConfigValues: GitlabConfigValues{
+		UserInfoURL:          &userServer.URL,
+		ListMergeRequestsURL: &mergeRequestsServer.URL,
+		GetMergeRequestURL:   &mergeRequestServer.URL,
+		ListApprovalsURL:     &approvalsServer.URL,
+		ListNotesURL:         &notesServer.URL,
+		GetProjectURL:        &projectServer.URL,
+	}}}}
+
+	t.Run("BadUserResponse", func(t *testing.T) {
+		badUserServer := testutils.GetMockAPIServer(t, 401, "")
+		defer badUserServer.Close()
+		badGitlabMR := GitlabMRSource{Gitlab: GitlabService{Config: GitlabConfig{ConfigValues: GitlabConfigValues{UserInfoURL: &badUserServer.URL}}}}
+
+		var pullRequests = make(chan PullRequestResult)
+		go badGitlabMR.GetPullRequests(db, primitive.NewObjectID(), "7", pullRequests)
+		result := <-pullRequests
+		assert.Error(t, result.Error)
+		assert.Equal(t, TASK_SOURCE_ID_GITLAB_MR, result.SourceID)
+		assert.Equal(t, 0, len(result.PullRequests))
+	})
+	t.Run("Success", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		var pullRequests = make(chan PullRequestResult)
+		go gitlabMR.GetPullRequests(db, userID, "7", pullRequests)
+		result := <-pullRequests
+		assert.NoError(t, result.Error)
+		// both merge request queries return the same merge request, so it should only appear once
+		assert.Equal(t, 1, len(result.PullRequests))
+		pullRequest := result.PullRequests[0]
+		assert.Equal(t, "501", pullRequest.IDExternal)
+		assert.Equal(t, TASK_SOURCE_ID_GITLAB_MR, pullRequest.SourceID)
+		assert.Equal(t, "gitlab_42", pullRequest.RepositoryID)
+		assert.Equal(t, "dankmemes/example", pullRequest.RepositoryName)
+		assert.Equal(t, 12, pullRequest.Number)
+		assert.Equal(t, "docbrown", pullRequest.Author)
+		assert.Equal(t, "flux", pullRequest.Branch)
+		assert.Equal(t, "main", pullRequest.BaseBranch)
+		assert.Equal(t, ActionWaitingOnReview, pullRequest.RequiredAction)
+		assert.Equal(t, 1, pullRequest.CommentCount)
+
+		dbPR, err := database.GetPullRequestByExternalID(db, "501", userID)
+		assert.NoError(t, err)
+		assert.Equal(t, pullRequest.ID, dbPR.ID)
+
+		count, err := database.GetRepositoryCollection(db).CountDocuments(context.Background(), bson.M{"user_id": userID, "repository_id": "gitlab_42"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
+
+func TestGetGitlabMergeRequestComments(t *testing.T) {
+	createdAt := time.Date(2022, 10, 2, 12, 0, 0, 0, time.UTC)
+	notes := []gitlabNote{
+		{Body: "top level", Author: gitlabUser{Username: "marty"}, CreatedAt: createdAt},
+		{Body: "added 1 commit", Author: gitlabUser{Username: "docbrown"}, System: true},
+		{Body: "inline", Author: gitlabUser{Username: "biff"}, CreatedAt: createdAt, Position: &struct {
+			NewPath string `json:"new_path"`
+			NewLine int    `json:"new_line"`
+		}{NewPath: "flux.go", NewLine: 88}},
+	}
+	assert.Equal(t, []database.PullRequestComment{
+		{
+			Type:      constants.COMMENT_TYPE_TOPLEVEL,
+			Body:      "top level",
+			Author:    "marty",
+			CreatedAt: primitive.NewDateTimeFromTime(createdAt),
+		},
+		{
+			Type:            constants.COMMENT_TYPE_INLINE,
+			Body:            "inline",
+			Author:          "biff",
+			Filepath:        "flux.go",
+			LineNumberStart: 88,
+			LineNumberEnd:   88,
+			CreatedAt:       primitive.NewDateTimeFromTime(createdAt),
+		},
+	}, getGitlabMergeRequestComments(notes))
+}
+
+func TestGitlabUserNeedsToSubmitReview(t *testing.T) {
+	user := gitlabUser{ID: 8}
+	mergeRequest := gitlabMergeRequest{Reviewers: []gitlabUser{{ID: 8}}}
+	t.Run("NotReviewer", func(t *testing.T) {
+		assert.False(t, gitlabUserNeedsToSubmitReview(gitlabUser{ID: 9}, mergeRequest, gitlabApprovals{}))
+	})
+	t.Run("NotYetApproved", func(t *testing.T) {
+		assert.True(t, gitlabUserNeedsToSubmitReview(user, mergeRequest, gitlabApprovals{}))
+	})
+	t.Run("AlreadyApproved", func(t *testing.T) {
+		approvals := gitlabApprovals{Approved: true}
+		approvals.ApprovedBy = append(approvals.ApprovedBy, struct {
+			User gitlabUser `json:"user"`
+		}{User: user})
+		assert.False(t, gitlabUserNeedsToSubmitReview(user, mergeRequest, approvals))
+	})
+}
+
+func TestGitlabPipelineStatus(t *testing.T) {
+	t.Run("NoPipeline", func(t *testing.T) {
+		assert.False(t, gitlabPipelineDidFail(nil))
+		assert.True(t, gitlabPipelineDidFinish(nil))
+	})
+	t.Run("Running", func(t *testing.T) {
+		assert.False(t, gitlabPipelineDidFail(&gitlabPipeline{Status: "running"}))
+		assert.False(t, gitlabPipelineDidFinish(&gitlabPipeline{Status: "running"}))
+	})
+	t.Run("Failed", func(t *testing.T) {
+		assert.True(t, gitlabPipelineDidFail(&gitlabPipeline{Status: "failed"}))
+		assert.True(t, gitlabPipelineDidFinish(&gitlabPipeline{Status: "failed"}))
+	})
+	t.Run("Success", func(t *testing.T) {
+		assert.False(t, gitlabPipelineDidFail(&gitlabPipeline{Status: "success"}))
+		assert.True(t, gitlabPipelineDidFinish(&gitlabPipeline{Status: "success"}))
+	})
+}
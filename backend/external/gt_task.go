@@ -61,6 +61,7 @@ func (generalTask GeneralTaskTaskSource) CreateNewTask(db *mongo.Database, userI
 		SourceID:          TASK_SOURCE_ID_GT_TASK,
 		Title:             &task.Title,
 		Body:              &task.Body,
+		SearchBody:        &task.Body,
 		TimeAllocation:    &timeAllocation,
 		SourceAccountID:   accountID,
 		IsCompleted:       &completed,
@@ -78,6 +79,9 @@ func (generalTask GeneralTaskTaskSource) CreateNewTask(db *mongo.Database, userI
 	if task.ParentTaskID != primitive.NilObjectID {
 		newTask.ParentTaskID = task.ParentTaskID
 	}
+	if task.PriorityNormalized != nil {
+		newTask.PriorityNormalized = task.PriorityNormalized
+	}
 
 	taskCollection := database.GetTaskCollection(db)
 	insertResult, err := taskCollection.InsertOne(context.Background(), newTask)
@@ -101,5 +105,6 @@ func (generalTask GeneralTaskTaskSource) ModifyEvent(db *mongo.Database, userID
 }
 
 func (generalTask GeneralTaskTaskSource) AddComment(db *mongo.Database, userID primitive.ObjectID, accountID string, comment database.Comment, task *database.Task) error {
-	return errors.New("has not been implemented yet")
+	// comments on general task tasks only live in Task.Comments, which the caller is responsible for updating
+	return nil
 }
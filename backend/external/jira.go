@@ -2,27 +2,39 @@ package external
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
+	"math"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
+	"github.com/GeneralTask/task-manager/backend/config"
 	"github.com/GeneralTask/task-manager/backend/constants"
 	"github.com/GeneralTask/task-manager/backend/database"
 	"github.com/GeneralTask/task-manager/backend/logging"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const (
 	JIRADone        = "done"
+	JIRANew         = "new"
 	JIRAPriorityKey = "priority"
 	JIRADueDateKey  = "duedate"
 	NoProject       = "noProject"
 )
 
+// Jira expires dynamic webhooks 30 days after they are registered or last refreshed
+const JIRAWebhookExpiration = 30 * 24 * time.Hour
+
+var JIRAWebhookEvents = []string{"jira:issue_created", "jira:issue_updated", "jira:issue_deleted", "comment_created", "comment_updated", "comment_deleted"}
+
 type JIRASource struct {
 	Atlassian AtlassianService
 }
@@ -72,6 +84,8 @@ type JIRATaskFields struct {
 	Project     JIRAProject     `json:"project"`
 	Status      JIRAStatus      `json:"status"`
 	Priority    JIRAPriority    `json:"priority"`
+	Assignee    *JIRAUser       `json:"assignee"`
+	Labels      []string        `json:"labels"`
 }
 
 // JIRATask represents the API detail result for issues - only fields we need
@@ -81,6 +95,31 @@ type JIRATask struct {
 	Key    string         `json:"key"`
 }
 
+type JIRAWebhookRegisterRequest struct {
+	URL      string               `json:"url"`
+	Webhooks []JIRAWebhookDetails `json:"webhooks"`
+}
+
+type JIRAWebhookDetails struct {
+	Events    []string `json:"events"`
+	JQLFilter string   `json:"jqlFilter"`
+}
+
+type JIRAWebhookRegisterResponse struct {
+	WebhookRegistrationResult []struct {
+		CreatedWebhookID int64    `json:"createdWebhookId"`
+		Errors           []string `json:"errors"`
+	} `json:"webhookRegistrationResult"`
+}
+
+type JIRAWebhookRefreshRequest struct {
+	WebhookIDs []int64 `json:"webhookIds"`
+}
+
+type JIRAWebhookRefreshResponse struct {
+	ExpirationDate string `json:"expirationDate"`
+}
+
 // JIRATaskList represents the API list result for issues - only fields we need
 type JIRATaskList struct {
 	Issues []JIRATask `json:"issues"`
@@ -155,6 +194,11 @@ func (jira JIRASource) GetTasks(db *mongo.Database, userID primitive.ObjectID, a
 	if err != nil {
 		// still want to continue if cannot fetch priorities, as it is not a required field
 		logger.Error().Err(err).Msg("failed to fetch priorities")
+	} else {
+		err = updateStoredJIRAPriorities(db, userID, priorityList)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to store priorities")
+		}
 	}
 
 	// fetch the comments in tandem to avoid too much latency for tasks fetch endpoint
@@ -177,31 +221,7 @@ func (jira JIRASource) GetTasks(db *mongo.Database, userID primitive.ObjectID, a
 
 	var tasks []*database.Task
 	for idx, jiraTask := range jiraTasks.Issues {
-		titleString := jiraTask.Fields.Summary
-		bodyString := string(jiraTask.Fields.Description)
-
-		task := &database.Task{
-			UserID:          userID,
-			IDExternal:      jiraTask.ID,
-			IDTaskSection:   constants.IDTaskSectionDefault,
-			Deeplink:        siteConfiguration.SiteURL + "/browse/" + jiraTask.Key,
-			SourceID:        TASK_SOURCE_ID_JIRA,
-			Title:           &titleString,
-			SourceAccountID: accountID,
-			Status: &database.ExternalTaskStatus{
-				ExternalID:        jiraTask.Fields.Status.ID,
-				State:             jiraTask.Fields.Status.Name,
-				Type:              jiraTask.Fields.Status.Category.Key,
-				IsCompletedStatus: jiraTask.Fields.Status.Category.Key == JIRADone,
-			},
-		}
-
-		if bodyString != "null" {
-			task.Body = &bodyString
-		} else {
-			bodyString = ""
-			task.Body = &bodyString
-		}
+		task := populateJIRATask(userID, accountID, siteConfiguration, jiraTask)
 		commentsResult := commentChannelList[idx]
 		commentsOutput := <-commentsResult
 		if commentsOutput.Error != nil {
@@ -220,91 +240,17 @@ func (jira JIRASource) GetTasks(db *mongo.Database, userID primitive.ObjectID, a
 
 		transitionListResult := transitionChannelList[idx]
 		transitionList := <-transitionListResult
-		allStatuses, exists := statusMap[jiraTask.Fields.Project.ID]
-		if exists {
-			task.AllStatuses = allStatuses
-		} else {
-			task.AllStatuses = statusMap[NoProject]
-		}
-		for _, status := range task.AllStatuses {
-			for _, transition := range transitionList.Transitions {
-				if transition.ToStatus.ID == status.ExternalID {
-					status.IsValidTransition = true
-				}
-			}
-			// if no results returned, allow frontend to show all statuses
-			if len(transitionList.Transitions) == 0 {
-				status.IsValidTransition = true
-			}
-		}
+		setJIRATaskStatuses(task, statusMap, jiraTask.Fields.Project.ID, transitionList)
 
-		dueDate, err := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, jiraTask.Fields.DueDate)
-		if err == nil {
-			primDueDate := primitive.NewDateTimeFromTime(dueDate)
-			task.DueDate = &primDueDate
-		}
-		createdAt, err := time.Parse("2006-01-02T15:04:05.999-0700", jiraTask.Fields.CreatedAt)
-		if err == nil {
-			primCreatedAt := primitive.NewDateTimeFromTime(createdAt)
-			task.CreatedAtExternal = primCreatedAt
-		}
-		updatedAt, err := time.Parse("2006-01-02T15:04:05.999-0700", jiraTask.Fields.UpdatedAt)
-		if err == nil {
-			primUpdatedAt := primitive.NewDateTimeFromTime(updatedAt)
-			task.UpdatedAt = primUpdatedAt
-		}
 		if jiraTask.Fields.Priority.ID != "" && len(priorityList) > 0 {
-			priorityLength := len(priorityList)
-
-			var allPriorities []*database.ExternalTaskPriority
-			for idx, priority := range priorityList {
-				priorityNormalized := getNormalizedPriority(idx, priorityLength)
-				priorityObject := database.ExternalTaskPriority{
-					ExternalID:         priority.ID,
-					Name:               priority.Name,
-					Color:              priority.Color,
-					PriorityNormalized: priorityNormalized,
-					IconURL:            priority.IconURL,
-				}
-				if priority.ID == jiraTask.Fields.Priority.ID {
-					task.ExternalPriority = &priorityObject
-					task.PriorityNormalized = &priorityNormalized
-				}
-				allPriorities = append(allPriorities, &priorityObject)
-			}
-
-			task.AllExternalPriorities = allPriorities
+			setJIRATaskPriorities(task, priorityList, jiraTask.Fields.Priority.ID)
 		}
 
 		tasks = append(tasks, task)
 	}
 
-	isCompleted := false
 	for _, task := range tasks {
-		updateTask := database.Task{
-			Title:                 task.Title,
-			Body:                  task.Body,
-			DueDate:               task.DueDate,
-			Status:                task.Status,
-			UpdatedAt:             task.UpdatedAt,
-			PriorityNormalized:    task.PriorityNormalized,
-			ExternalPriority:      task.ExternalPriority,
-			AllExternalPriorities: task.AllExternalPriorities,
-			AllStatuses:           task.AllStatuses,
-			Comments:              task.Comments,
-			IsCompleted:           &isCompleted,
-			JIRATaskParams:        task.JIRATaskParams,
-		}
-
-		dbTask, err := database.UpdateOrCreateTask(
-			db,
-			userID,
-			task.IDExternal,
-			task.SourceID,
-			task,
-			updateTask,
-			nil,
-		)
+		dbTask, err := updateOrCreateJIRATask(db, userID, task, database.TaskHistoryActorFetch)
 		if err != nil {
 			result <- emptyTaskResultWithSource(err, TASK_SOURCE_ID_JIRA)
 			return
@@ -320,6 +266,236 @@ func (jira JIRASource) GetTasks(db *mongo.Database, userID primitive.ObjectID, a
 	}
 }
 
+// RefreshTask re-fetches a single issue, e.g. after a webhook notification, and stores it for the user.
+// Issues which are done or no longer assigned to the user are marked complete, matching the JQL used by GetTasks.
+func (jira JIRASource) RefreshTask(db *mongo.Database, userID primitive.ObjectID, accountID string, issueID string) (*database.Task, error) {
+	authToken, _ := jira.Atlassian.getAndRefreshToken(userID, accountID)
+	siteConfiguration, _ := jira.Atlassian.getSiteConfiguration(userID)
+	if authToken == nil || siteConfiguration == nil {
+		return nil, errors.New("missing authToken or siteConfiguration")
+	}
+	logger := logging.GetSentryLogger()
+
+	jiraTask, err := jira.getIssue(siteConfiguration, authToken.AccessToken, issueID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch JIRA issue")
+		return nil, err
+	}
+	userAccountID, err := jira.getCurrentUserAccountID(siteConfiguration, authToken.AccessToken)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch JIRA user")
+		return nil, err
+	}
+
+	task := populateJIRATask(userID, accountID, siteConfiguration, *jiraTask)
+	if task.DueDate == nil {
+		// the due date may have just been cleared, so it must be overwritten
+		dueDate := primitive.NewDateTimeFromTime(time.Unix(0, 0))
+		task.DueDate = &dueDate
+	}
+	if jiraTask.Fields.Assignee == nil || jiraTask.Fields.Assignee.AccountID != userAccountID || task.Status.IsCompletedStatus {
+		return completeJIRATask(db, userID, task)
+	}
+
+	statusMap, err := jira.GetListOfStatuses(siteConfiguration, userID, authToken.AccessToken)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch statuses")
+		return nil, err
+	}
+	transitionsChan := make(chan JIRATransitionList)
+	go jira.getTransitionList(siteConfiguration, jiraTask.ID, authToken.AccessToken, transitionsChan)
+	setJIRATaskStatuses(task, statusMap, jiraTask.Fields.Project.ID, <-transitionsChan)
+
+	priorityList, err := jira.GetListOfPriorities(siteConfiguration, userID, authToken.AccessToken)
+	if err != nil {
+		// still want to continue if cannot fetch priorities, as it is not a required field
+		logger.Error().Err(err).Msg("failed to fetch priorities")
+	} else if jiraTask.Fields.Priority.ID != "" && len(priorityList) > 0 {
+		setJIRATaskPriorities(task, priorityList, jiraTask.Fields.Priority.ID)
+	}
+
+	commentsChan := make(chan JIRACommentResult)
+	go jira.GetListOfComments(siteConfiguration, userID, jiraTask.ID, authToken.AccessToken, commentsChan)
+	commentsOutput := <-commentsChan
+	if commentsOutput.Error != nil {
+		logger.Error().Err(commentsOutput.Error).Msg("failed to fetch comments")
+	} else {
+		task.Comments = commentsOutput.CommentList
+	}
+
+	fieldsChan := make(chan JIRAFieldsResult)
+	go jira.GetListOfFields(siteConfiguration, userID, jiraTask.ID, authToken.AccessToken, fieldsChan)
+	fieldsOutput := <-fieldsChan
+	if fieldsOutput.Error != nil {
+		logger.Error().Err(fieldsOutput.Error).Msg("failed to fetch editable fields")
+	} else {
+		task.JIRATaskParams = &fieldsOutput.JIRATaskParams
+	}
+
+	return updateOrCreateJIRATask(db, userID, task, database.TaskHistoryActorWebhook)
+}
+
+func (jira JIRASource) getIssue(siteConfiguration *database.AtlassianSiteConfiguration, authToken string, issueID string) (*JIRATask, error) {
+	baseURL := jira.getJIRABaseURL(siteConfiguration, jira.Atlassian.Config.ConfigValues.IssueURL)
+	req, _ := http.NewRequest("GET", baseURL+"/rest/api/3/issue/"+issueID, nil)
+	req = addJIRARequestHeaders(req, authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unable to successfully fetch JIRA issue")
+	}
+
+	var jiraTask JIRATask
+	err = json.Unmarshal(responseBytes, &jiraTask)
+	if err != nil {
+		return nil, err
+	}
+	return &jiraTask, nil
+}
+
+// only updates tasks which are already stored, as issues which are not assigned to the user should not be added
+func completeJIRATask(db *mongo.Database, userID primitive.ObjectID, task *database.Task) (*database.Task, error) {
+	_, err := database.GetTaskBySourceExternalID(db, task.IDExternal, TASK_SOURCE_ID_JIRA, userID)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	isCompleted := true
+	return database.UpdateOrCreateTaskWithActor(
+		db,
+		userID,
+		task.IDExternal,
+		task.SourceID,
+		nil,
+		database.Task{
+			Title:       task.Title,
+			Body:        task.Body,
+			SearchBody:  task.SearchBody,
+			DueDate:     task.DueDate,
+			Status:      task.Status,
+			UpdatedAt:   task.UpdatedAt,
+			IsCompleted: &isCompleted,
+			CompletedAt: primitive.NewDateTimeFromTime(time.Now()),
+		},
+		nil,
+		database.TaskHistoryActorWebhook,
+	)
+}
+
+func populateJIRATask(userID primitive.ObjectID, accountID string, siteConfiguration *database.AtlassianSiteConfiguration, jiraTask JIRATask) *database.Task {
+	titleString := jiraTask.Fields.Summary
+	bodyString := string(jiraTask.Fields.Description)
+
+	task := &database.Task{
+		UserID:          userID,
+		IDExternal:      jiraTask.ID,
+		IDTaskSection:   constants.IDTaskSectionDefault,
+		Deeplink:        siteConfiguration.SiteURL + "/browse/" + jiraTask.Key,
+		SourceID:        TASK_SOURCE_ID_JIRA,
+		Title:           &titleString,
+		SourceAccountID: accountID,
+		Status: &database.ExternalTaskStatus{
+			ExternalID:        jiraTask.Fields.Status.ID,
+			State:             jiraTask.Fields.Status.Name,
+			Type:              jiraTask.Fields.Status.Category.Key,
+			IsCompletedStatus: jiraTask.Fields.Status.Category.Key == JIRADone,
+		},
+	}
+
+	// JIRA labels are plain strings, so the name is also the ID
+	externalLabels := []database.ExternalLabel{}
+	for _, label := range jiraTask.Fields.Labels {
+		externalLabels = append(externalLabels, database.ExternalLabel{ExternalID: label, Name: label})
+	}
+	task.ExternalLabels = &externalLabels
+
+	if bodyString != "null" {
+		task.Body = &bodyString
+	} else {
+		bodyString = ""
+		task.Body = &bodyString
+	}
+	searchBody := GetJIRADescriptionText(bodyString)
+	task.SearchBody = &searchBody
+
+	dueDate, err := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, jiraTask.Fields.DueDate)
+	if err == nil {
+		primDueDate := primitive.NewDateTimeFromTime(dueDate)
+		task.DueDate = &primDueDate
+	}
+	createdAt, err := time.Parse("2006-01-02T15:04:05.999-0700", jiraTask.Fields.CreatedAt)
+	if err == nil {
+		primCreatedAt := primitive.NewDateTimeFromTime(createdAt)
+		task.CreatedAtExternal = primCreatedAt
+	}
+	updatedAt, err := time.Parse("2006-01-02T15:04:05.999-0700", jiraTask.Fields.UpdatedAt)
+	if err == nil {
+		primUpdatedAt := primitive.NewDateTimeFromTime(updatedAt)
+		task.UpdatedAt = primUpdatedAt
+	}
+	return task
+}
+
+func setJIRATaskStatuses(task *database.Task, statusMap map[string][]*database.ExternalTaskStatus, projectID string, transitionList JIRATransitionList) {
+	allStatuses, exists := statusMap[projectID]
+	if exists {
+		task.AllStatuses = allStatuses
+	} else {
+		task.AllStatuses = statusMap[NoProject]
+	}
+	for _, status := range task.AllStatuses {
+		for _, transition := range transitionList.Transitions {
+			if transition.ToStatus.ID == status.ExternalID {
+				status.IsValidTransition = true
+			}
+		}
+		// if no results returned, allow frontend to show all statuses
+		if len(transitionList.Transitions) == 0 {
+			status.IsValidTransition = true
+		}
+	}
+}
+
+func updateOrCreateJIRATask(db *mongo.Database, userID primitive.ObjectID, task *database.Task, actor string) (*database.Task, error) {
+	isCompleted := false
+	updateTask := database.Task{
+		Title:                 task.Title,
+		Body:                  task.Body,
+		SearchBody:            task.SearchBody,
+		DueDate:               task.DueDate,
+		Status:                task.Status,
+		UpdatedAt:             task.UpdatedAt,
+		PriorityNormalized:    task.PriorityNormalized,
+		ExternalPriority:      task.ExternalPriority,
+		AllExternalPriorities: task.AllExternalPriorities,
+		AllStatuses:           task.AllStatuses,
+		Comments:              task.Comments,
+		IsCompleted:           &isCompleted,
+		JIRATaskParams:        task.JIRATaskParams,
+		ExternalLabels:        task.ExternalLabels,
+	}
+
+	return database.UpdateOrCreateTaskWithActor(
+		db,
+		userID,
+		task.IDExternal,
+		task.SourceID,
+		task,
+		updateTask,
+		nil,
+		actor,
+	)
+}
+
 func (JIRA JIRASource) GetPullRequests(db *mongo.Database, userID primitive.ObjectID, accountID string, result chan<- PullRequestResult) {
 	result <- emptyPullRequestResult(nil, false)
 }
@@ -527,27 +703,444 @@ func (jira JIRASource) GetListOfFields(siteConfiguration *database.AtlassianSite
 		return
 	}
 
+	result <- JIRAFieldsResult{
+		JIRATaskParams: getJIRATaskParamsFromFields(fields.Fields),
+		Error:          err,
+	}
+}
+
+func getJIRATaskParamsFromFields(fields map[string]JIRAFieldResponse) database.JIRATaskParams {
 	hasPriority := false
 	hasDueDate := false
-	for _, field := range fields.Fields {
+	for _, field := range fields {
 		if field.Key == JIRAPriorityKey {
 			hasPriority = true
 		} else if field.Key == JIRADueDateKey {
 			hasDueDate = true
 		}
 	}
+	return database.JIRATaskParams{
+		HasPriorityField: &hasPriority,
+		HasDueDateField:  &hasDueDate,
+	}
+}
+
+func setJIRATaskPriorities(task *database.Task, priorityList []JIRAPriority, priorityID string) {
+	priorityLength := len(priorityList)
+
+	var allPriorities []*database.ExternalTaskPriority
+	for idx, priority := range priorityList {
+		priorityNormalized := getNormalizedPriority(idx, priorityLength)
+		priorityObject := database.ExternalTaskPriority{
+			ExternalID:         priority.ID,
+			Name:               priority.Name,
+			Color:              priority.Color,
+			PriorityNormalized: priorityNormalized,
+			IconURL:            priority.IconURL,
+		}
+		if priority.ID == priorityID {
+			task.ExternalPriority = &priorityObject
+			task.PriorityNormalized = &priorityNormalized
+		}
+		allPriorities = append(allPriorities, &priorityObject)
+	}
 
-	result <- JIRAFieldsResult{
-		JIRATaskParams: database.JIRATaskParams{
-			HasPriorityField: &hasPriority,
-			HasDueDateField:  &hasDueDate,
-		},
-		Error: err,
+	task.AllExternalPriorities = allPriorities
+}
+
+// the priority list is returned by JIRA in order, so we store each priority's position for later lookups
+func updateStoredJIRAPriorities(db *mongo.Database, userID primitive.ObjectID, priorityList []JIRAPriority) error {
+	priorityCollection := database.GetJiraPrioritiesCollection(db)
+	priorityIDs := []string{}
+	for idx, priority := range priorityList {
+		_, err := priorityCollection.UpdateOne(
+			context.Background(),
+			bson.M{"$and": []bson.M{
+				{"user_id": userID},
+				{"jira_id": priority.ID},
+			}},
+			bson.M{"$set": database.JIRAPriority{
+				UserID:          userID,
+				JIRAID:          priority.ID,
+				IntegerPriority: idx,
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return err
+		}
+		priorityIDs = append(priorityIDs, priority.ID)
+	}
+	_, err := priorityCollection.DeleteMany(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": userID},
+			{"jira_id": bson.M{"$nin": priorityIDs}},
+		}},
+	)
+	return err
+}
+
+// returns the ID of the stored JIRA priority whose normalized value is closest to the provided one
+func getJIRAPriorityIDFromNormalized(db *mongo.Database, userID primitive.ObjectID, priorityNormalized float64) (string, error) {
+	var priorities []database.JIRAPriority
+	err := database.FindWithCollection(database.GetJiraPrioritiesCollection(db), userID, nil, &priorities, nil)
+	if err != nil {
+		return "", err
 	}
+	if len(priorities) == 0 {
+		return "", errors.New("no JIRA priorities found")
+	}
+
+	priorityID := ""
+	closestDistance := math.MaxFloat64
+	for _, priority := range priorities {
+		distance := math.Abs(getNormalizedPriority(priority.IntegerPriority, len(priorities)) - priorityNormalized)
+		if distance < closestDistance {
+			closestDistance = distance
+			priorityID = priority.JIRAID
+		}
+	}
+	return priorityID, nil
+}
+
+type JIRACreateMetaResponse struct {
+	Projects []JIRACreateMetaProject `json:"projects"`
+}
+
+type JIRACreateMetaProject struct {
+	ID         string                    `json:"id"`
+	Key        string                    `json:"key"`
+	IssueTypes []JIRACreateMetaIssueType `json:"issuetypes"`
+}
+
+type JIRACreateMetaIssueType struct {
+	ID      string                       `json:"id"`
+	Name    string                       `json:"name"`
+	Subtask bool                         `json:"subtask"`
+	Fields  map[string]JIRAFieldResponse `json:"fields"`
+}
+
+type JIRAIssueCreateRequest struct {
+	Fields JIRAIssueCreateFields `json:"fields"`
+}
+
+type JIRAIssueCreateFields struct {
+	Project     JIRAProjectKey   `json:"project"`
+	IssueType   JIRAIssueTypeID  `json:"issuetype"`
+	Summary     string           `json:"summary"`
+	Description *json.RawMessage `json:"description,omitempty"`
+	Assignee    *JIRAAccountID   `json:"assignee,omitempty"`
+	Priority    *JIRAPriority    `json:"priority,omitempty"`
+	DueDate     *string          `json:"duedate,omitempty"`
+}
+
+type JIRAProjectKey struct {
+	Key string `json:"key"`
+}
+
+type JIRAIssueTypeID struct {
+	ID string `json:"id"`
+}
+
+type JIRAAccountID struct {
+	AccountID string `json:"accountId"`
+}
+
+type JIRAIssueCreateResponse struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
 }
 
 func (jira JIRASource) CreateNewTask(db *mongo.Database, userID primitive.ObjectID, accountID string, task TaskCreationObject) (primitive.ObjectID, error) {
-	return primitive.NilObjectID, errors.New("has not been implemented yet")
+	token, _ := jira.Atlassian.getAndRefreshToken(userID, accountID)
+	siteConfiguration, _ := jira.Atlassian.getSiteConfiguration(userID)
+	if token == nil || siteConfiguration == nil {
+		return primitive.NilObjectID, errors.New("missing token or siteConfiguration")
+	}
+	logger := logging.GetSentryLogger()
+
+	project, issueType, err := jira.getCreateMetadata(siteConfiguration, token.AccessToken, task.JIRAProjectKey, task.JIRAIssueType)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch JIRA create metadata")
+		return primitive.NilObjectID, err
+	}
+	jiraTaskParams := getJIRATaskParamsFromFields(issueType.Fields)
+	if task.DueDate != nil && !*jiraTaskParams.HasDueDateField {
+		return primitive.NilObjectID, errors.New("JIRA issue type does not support due dates")
+	}
+	if task.PriorityNormalized != nil && !*jiraTaskParams.HasPriorityField {
+		return primitive.NilObjectID, errors.New("JIRA issue type does not support priorities")
+	}
+
+	createRequest := JIRAIssueCreateRequest{
+		Fields: JIRAIssueCreateFields{
+			Project:     JIRAProjectKey{Key: project.Key},
+			IssueType:   JIRAIssueTypeID{ID: issueType.ID},
+			Summary:     task.Title,
+			Description: getJIRADescription(task.Body),
+		},
+	}
+	if task.DueDate != nil {
+		dueDateString := task.DueDate.Format(constants.YEAR_MONTH_DAY_FORMAT)
+		createRequest.Fields.DueDate = &dueDateString
+	}
+
+	var priorityList []JIRAPriority
+	if task.PriorityNormalized != nil {
+		priorityList, err = jira.GetListOfPriorities(siteConfiguration, userID, token.AccessToken)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to fetch priorities")
+			return primitive.NilObjectID, err
+		}
+		err = updateStoredJIRAPriorities(db, userID, priorityList)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to store priorities")
+			return primitive.NilObjectID, err
+		}
+		priorityID, err := getJIRAPriorityIDFromNormalized(db, userID, *task.PriorityNormalized)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to map priority to JIRA priority")
+			return primitive.NilObjectID, err
+		}
+		createRequest.Fields.Priority = &JIRAPriority{ID: priorityID}
+	}
+
+	// assign the issue to the linked account so that it shows up in the user's assigned issues
+	jiraAccountID, err := jira.getCurrentUserAccountID(siteConfiguration, token.AccessToken)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to fetch JIRA user")
+		return primitive.NilObjectID, err
+	}
+	createRequest.Fields.Assignee = &JIRAAccountID{AccountID: jiraAccountID}
+
+	createdIssue, err := jira.executeIssueCreate(siteConfiguration, token.AccessToken, createRequest)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to create JIRA issue")
+		return primitive.NilObjectID, err
+	}
+
+	taskSection := constants.IDTaskSectionDefault
+	if task.IDTaskSection != primitive.NilObjectID {
+		taskSection = task.IDTaskSection
+	}
+	isCompleted := false
+	isDeleted := false
+	body := string(*createRequest.Fields.Description)
+	searchBody := GetJIRADescriptionText(body)
+	newTask := &database.Task{
+		UserID:            userID,
+		IDExternal:        createdIssue.ID,
+		IDTaskSection:     taskSection,
+		Deeplink:          siteConfiguration.SiteURL + "/browse/" + createdIssue.Key,
+		SourceID:          TASK_SOURCE_ID_JIRA,
+		Title:             &task.Title,
+		Body:              &body,
+		SearchBody:        &searchBody,
+		SourceAccountID:   accountID,
+		IsCompleted:       &isCompleted,
+		IsDeleted:         &isDeleted,
+		TimeAllocation:    task.TimeAllocation,
+		CreatedAtExternal: primitive.NewDateTimeFromTime(time.Now()),
+		UpdatedAt:         primitive.NewDateTimeFromTime(time.Now()),
+		JIRATaskParams:    &jiraTaskParams,
+	}
+	if task.DueDate != nil {
+		dueDate, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, *createRequest.Fields.DueDate)
+		primDueDate := primitive.NewDateTimeFromTime(dueDate)
+		newTask.DueDate = &primDueDate
+	}
+	if createRequest.Fields.Priority != nil {
+		setJIRATaskPriorities(newTask, priorityList, createRequest.Fields.Priority.ID)
+	}
+
+	statusMap, err := jira.GetListOfStatuses(siteConfiguration, userID, token.AccessToken)
+	if err != nil {
+		// the issue has already been created, so the statuses will be filled in on the next fetch
+		logger.Error().Err(err).Msg("failed to fetch statuses")
+	} else {
+		allStatuses, exists := statusMap[project.ID]
+		if !exists {
+			allStatuses = statusMap[NoProject]
+		}
+		newTask.AllStatuses = allStatuses
+		for _, status := range allStatuses {
+			if status.Type == JIRANew {
+				newTask.Status = status
+				break
+			}
+		}
+	}
+
+	dbTask, err := database.GetOrCreateTask(db, userID, newTask.IDExternal, TASK_SOURCE_ID_JIRA, newTask)
+	if err != nil {
+		logger.Error().Err(err).Msg("could not create task")
+		return primitive.NilObjectID, err
+	}
+	return dbTask.ID, nil
+}
+
+func (jira JIRASource) getCreateMetadata(siteConfiguration *database.AtlassianSiteConfiguration, authToken string, projectKey string, issueTypeName string) (*JIRACreateMetaProject, *JIRACreateMetaIssueType, error) {
+	baseURL := jira.getJIRABaseURL(siteConfiguration, jira.Atlassian.Config.ConfigValues.CreateMetaURL)
+	createMetaURL := baseURL + "/rest/api/3/issue/createmeta?expand=projects.issuetypes.fields"
+	if projectKey != "" {
+		createMetaURL += "&projectKeys=" + url.QueryEscape(projectKey)
+	}
+	req, _ := http.NewRequest("GET", createMetaURL, nil)
+	req = addJIRARequestHeaders(req, authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.New("unable to successfully fetch JIRA create metadata")
+	}
+
+	var createMeta JIRACreateMetaResponse
+	err = json.Unmarshal(responseBytes, &createMeta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hasProject := false
+	for _, project := range createMeta.Projects {
+		if projectKey != "" && project.Key != projectKey {
+			continue
+		}
+		hasProject = true
+		for _, issueType := range project.IssueTypes {
+			if issueTypeName == "" && !issueType.Subtask || issueTypeName != "" && issueType.Name == issueTypeName {
+				return &project, &issueType, nil
+			}
+		}
+		// without a project key, any project with a matching issue type will do
+		if projectKey != "" {
+			return nil, nil, errors.New("invalid JIRA issue type for project")
+		}
+	}
+	if hasProject {
+		return nil, nil, errors.New("invalid JIRA issue type for project")
+	}
+	return nil, nil, errors.New("invalid JIRA project")
+}
+
+func (jira JIRASource) getCurrentUserAccountID(siteConfiguration *database.AtlassianSiteConfiguration, authToken string) (string, error) {
+	baseURL := jira.getJIRABaseURL(siteConfiguration, jira.Atlassian.Config.ConfigValues.UserInfoURL)
+	req, _ := http.NewRequest("GET", baseURL+"/rest/api/3/myself", nil)
+	req = addJIRARequestHeaders(req, authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("unable to successfully fetch JIRA user")
+	}
+
+	var user JIRAUser
+	err = json.Unmarshal(responseBytes, &user)
+	if err != nil {
+		return "", err
+	}
+	return user.AccountID, nil
+}
+
+func (jira JIRASource) executeIssueCreate(siteConfiguration *database.AtlassianSiteConfiguration, authToken string, createRequest JIRAIssueCreateRequest) (*JIRAIssueCreateResponse, error) {
+	baseURL := jira.getJIRABaseURL(siteConfiguration, jira.Atlassian.Config.ConfigValues.IssueCreateURL)
+	requestBytes, err := json.Marshal(&createRequest)
+	if err != nil {
+		return nil, errors.New("unable to marshal create fields for JIRA request")
+	}
+	req, _ := http.NewRequest("POST", baseURL+"/rest/api/3/issue", bytes.NewBuffer(requestBytes))
+	req = addJIRARequestHeaders(req, authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, errors.New("unable to successfully make issue create request")
+	}
+
+	var createdIssue JIRAIssueCreateResponse
+	err = json.Unmarshal(responseBytes, &createdIssue)
+	if err != nil {
+		return nil, err
+	}
+	return &createdIssue, nil
+}
+
+// JIRA expects descriptions in Atlassian Document Format, so plain text bodies are wrapped in a paragraph
+func getJIRADescription(body string) *json.RawMessage {
+	// only bodies which are already an ADF document are sent as is, as any other JSON (e.g. "42") is plain text
+	var document map[string]interface{}
+	if json.Unmarshal([]byte(body), &document) == nil && document["type"] == "doc" {
+		description := json.RawMessage(body)
+		return &description
+	}
+	content := []interface{}{}
+	if body != "" {
+		content = append(content, map[string]interface{}{
+			"type": "paragraph",
+			"content": []interface{}{
+				map[string]interface{}{"type": "text", "text": body},
+			},
+		})
+	}
+	descriptionBytes, _ := json.Marshal(map[string]interface{}{
+		"type":    "doc",
+		"version": 1,
+		"content": content,
+	})
+	description := json.RawMessage(descriptionBytes)
+	return &description
+}
+
+type JIRADocumentNode struct {
+	Type    string             `json:"type"`
+	Text    string             `json:"text"`
+	Content []JIRADocumentNode `json:"content"`
+}
+
+// GetJIRADescriptionText extracts the text of an ADF description, with one line per block, so that it can be searched
+func GetJIRADescriptionText(description string) string {
+	var document JIRADocumentNode
+	if json.Unmarshal([]byte(description), &document) != nil || document.Type != "doc" {
+		return description
+	}
+	return strings.TrimSpace(getJIRADocumentNodeText(document))
+}
+
+func getJIRADocumentNodeText(node JIRADocumentNode) string {
+	switch node.Type {
+	case "text":
+		return node.Text
+	case "hardBreak":
+		return "\n"
+	}
+	text := ""
+	for _, child := range node.Content {
+		childText := getJIRADocumentNodeText(child)
+		// blocks such as paragraphs and list items contain other nodes, while inline nodes do not
+		if len(child.Content) > 0 && text != "" && childText != "" {
+			text += "\n"
+		}
+		text += childText
+	}
+	return text
 }
 
 func (jira JIRASource) CreateNewEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, event EventCreateObject) error {
@@ -781,6 +1374,112 @@ func (jira JIRASource) AddComment(db *mongo.Database, userID primitive.ObjectID,
 	return errors.New("has not been implemented yet")
 }
 
+// RegisterWebhooks refreshes the site's webhooks, or registers them again if they are missing or have already expired
+func (jira JIRASource) RegisterWebhooks(db *mongo.Database, siteConfiguration *database.AtlassianSiteConfiguration, now time.Time) error {
+	authToken, _ := jira.Atlassian.getAndRefreshToken(siteConfiguration.UserID, siteConfiguration.CloudID)
+	if authToken == nil {
+		return errors.New("missing authToken")
+	}
+	baseURL := jira.getJIRABaseURL(siteConfiguration, jira.Atlassian.Config.ConfigValues.WebhookURL) + "/rest/api/3/webhook"
+
+	webhookIDs := siteConfiguration.WebhookIDs
+	expiresAt, err := jira.refreshWebhooks(baseURL, authToken.AccessToken, webhookIDs, now)
+	if len(webhookIDs) == 0 || err != nil {
+		webhookIDs, err = jira.registerWebhooks(baseURL, authToken.AccessToken, siteConfiguration.CloudID)
+		if err != nil {
+			return err
+		}
+		expiresAt = now.Add(JIRAWebhookExpiration)
+	}
+
+	_, err = database.GetJiraSitesCollection(db).UpdateOne(
+		context.Background(),
+		bson.M{"_id": siteConfiguration.ID},
+		bson.M{"$set": bson.M{
+			"webhook_ids":        webhookIDs,
+			"webhook_expires_at": primitive.NewDateTimeFromTime(expiresAt),
+		}},
+	)
+	return err
+}
+
+func (jira JIRASource) refreshWebhooks(baseURL string, authToken string, webhookIDs []int64, now time.Time) (time.Time, error) {
+	if len(webhookIDs) == 0 {
+		return now, nil
+	}
+	requestBytes, err := json.Marshal(&JIRAWebhookRefreshRequest{WebhookIDs: webhookIDs})
+	if err != nil {
+		return now, err
+	}
+	req, _ := http.NewRequest("PUT", baseURL+"/refresh", bytes.NewBuffer(requestBytes))
+	req = addJIRARequestHeaders(req, authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return now, err
+	}
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return now, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return now, errors.New("unable to successfully refresh JIRA webhooks")
+	}
+
+	var refreshResponse JIRAWebhookRefreshResponse
+	err = json.Unmarshal(responseBytes, &refreshResponse)
+	if err != nil {
+		return now, err
+	}
+	expiresAt, err := time.Parse("2006-01-02T15:04:05.000-0700", refreshResponse.ExpirationDate)
+	if err != nil {
+		return now.Add(JIRAWebhookExpiration), nil
+	}
+	return expiresAt, nil
+}
+
+func (jira JIRASource) registerWebhooks(baseURL string, authToken string, cloudID string) ([]int64, error) {
+	requestBytes, err := json.Marshal(&JIRAWebhookRegisterRequest{
+		URL: config.GetConfigValue("SERVER_URL") + "jira/webhook/" + cloudID + "/",
+		// dynamic webhooks require a JQL filter, so this matches every issue the user can see
+		Webhooks: []JIRAWebhookDetails{{Events: JIRAWebhookEvents, JQLFilter: "project != EMPTY"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, _ := http.NewRequest("POST", baseURL, bytes.NewBuffer(requestBytes))
+	req = addJIRARequestHeaders(req, authToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("unable to successfully register JIRA webhooks")
+	}
+
+	var registerResponse JIRAWebhookRegisterResponse
+	err = json.Unmarshal(responseBytes, &registerResponse)
+	if err != nil {
+		return nil, err
+	}
+	webhookIDs := []int64{}
+	for _, result := range registerResponse.WebhookRegistrationResult {
+		if len(result.Errors) > 0 || result.CreatedWebhookID == 0 {
+			return nil, errors.New("JIRA rejected the webhook registration")
+		}
+		webhookIDs = append(webhookIDs, result.CreatedWebhookID)
+	}
+	if len(webhookIDs) == 0 {
+		return nil, errors.New("no JIRA webhooks were registered")
+	}
+	return webhookIDs, nil
+}
+
 func addJIRARequestHeaders(req *http.Request, authToken string) *http.Request {
 	req.Header.Add("Authorization", "Bearer "+authToken)
 	req.Header.Add("Content-Type", "application/json")
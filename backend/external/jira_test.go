@@ -524,6 +524,169 @@ func TestGetJIRAComments(t *testing.T) {
 	})
 }
 
+func TestRefreshJIRATask(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	externalAPITokenCollection := database.GetExternalTokenCollection(db)
+	AtlassianSiteCollection := database.GetJiraSitesCollection(db)
+
+	issueResponse := `{"id": "42069", "key": "MOON-1969", "fields": {"summary": "Sample Taskeroni", "description": null, "duedate": "", "status": {"id": "10000", "name": "Todo", "statusCategory": {"key": "new"}}, "project": {"id": "10000"}, "priority": {"id": "2"}, "assignee": {"accountId": "example-user"}}}`
+	tokenServer := getTokenServerForJIRA(t, http.StatusOK)
+	defer tokenServer.Close()
+	userInfoServer := testutils.GetMockAPIServer(t, http.StatusOK, `{"accountId": "example-user"}`)
+	defer userInfoServer.Close()
+	statusServer := getStatusServerForJIRA(t, http.StatusOK, false)
+	defer statusServer.Close()
+	transitionServer := getTransitionServerForJIRA(t, http.StatusOK, false, true)
+	defer transitionServer.Close()
+	priorityServer := getJIRAPriorityServer(t, http.StatusOK, []byte(`[{"id":"1","name":"Highest"},{"id":"2","name":"Medium"},{"id":"3","name":"Lowest"}]`))
+	defer priorityServer.Close()
+	commentsServer := getJIRACommentsServer(t, http.StatusOK, []byte(`{"comments": [{"id": "10000","author":{"accountId": "example-id-1", "displayName": "test"}}]}`))
+	defer commentsServer.Close()
+	fieldsServer := getJIRAFieldsServer(t, http.StatusOK, []byte(`{"fields":{"priority":{"key":"priority"}}}`))
+	defer fieldsServer.Close()
+	getJIRA := func(issueURL string) JIRASource {
+		return JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{
+			TokenURL:        &tokenServer.URL,
+			IssueURL:        &issueURL,
+			UserInfoURL:     &userInfoServer.URL,
+			StatusListURL:   &statusServer.URL,
+			TransitionURL:   &transitionServer.URL,
+			PriorityListURL: &priorityServer.URL,
+			CommentsListURL: &commentsServer.URL,
+			FieldsListURL:   &fieldsServer.URL,
+		}}}}
+	}
+
+	t.Run("IssueFetchFailed", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		issueServer := testutils.GetMockAPIServer(t, http.StatusNotFound, "")
+		defer issueServer.Close()
+
+		_, err := getJIRA(issueServer.URL).RefreshTask(db, *userID, accountID, "42069")
+		assert.Error(t, err)
+		assert.Equal(t, "unable to successfully fetch JIRA issue", err.Error())
+	})
+	t.Run("UnassignedIssueNotStored", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		issueServer := testutils.GetMockAPIServer(t, http.StatusOK, `{"id": "42069", "key": "MOON-1969", "fields": {"summary": "Sample Taskeroni", "status": {"id": "10000"}}}`)
+		defer issueServer.Close()
+
+		task, err := getJIRA(issueServer.URL).RefreshTask(db, *userID, accountID, "42069")
+		assert.NoError(t, err)
+		assert.Nil(t, task)
+		_, err = database.GetTaskByExternalID(db, "42069", *userID)
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+	})
+	t.Run("DoneIssueCompleted", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		isCompleted := false
+		_, err := database.GetOrCreateTask(db, *userID, "42069", TASK_SOURCE_ID_JIRA, &database.Task{
+			UserID:      *userID,
+			IDExternal:  "42069",
+			SourceID:    TASK_SOURCE_ID_JIRA,
+			IsCompleted: &isCompleted,
+		})
+		assert.NoError(t, err)
+		issueServer := testutils.GetMockAPIServer(t, http.StatusOK, `{"id": "42069", "key": "MOON-1969", "fields": {"summary": "Sample Taskeroni", "status": {"id": "10003", "name": "Done", "statusCategory": {"key": "done"}}, "assignee": {"accountId": "example-user"}}}`)
+		defer issueServer.Close()
+
+		task, err := getJIRA(issueServer.URL).RefreshTask(db, *userID, accountID, "42069")
+		assert.NoError(t, err)
+		assert.True(t, *task.IsCompleted)
+		assert.NotEqual(t, primitive.DateTime(0), task.CompletedAt)
+		assert.Equal(t, "Done", task.Status.State)
+	})
+	t.Run("Success", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		dueDate := primitive.NewDateTimeFromTime(time.Now())
+		_, err := database.GetOrCreateTask(db, *userID, "42069", TASK_SOURCE_ID_JIRA, &database.Task{
+			UserID:     *userID,
+			IDExternal: "42069",
+			SourceID:   TASK_SOURCE_ID_JIRA,
+			DueDate:    &dueDate,
+		})
+		assert.NoError(t, err)
+		issueServer := testutils.GetMockAPIServer(t, http.StatusOK, issueResponse)
+		defer issueServer.Close()
+
+		task, err := getJIRA(issueServer.URL).RefreshTask(db, *userID, accountID, "42069")
+		assert.NoError(t, err)
+		assert.Equal(t, "Sample Taskeroni", *task.Title)
+		assert.False(t, *task.IsCompleted)
+		assert.Equal(t, "Todo", task.Status.State)
+		assert.Equal(t, 2, len(task.AllStatuses))
+		assert.Equal(t, "2", task.ExternalPriority.ExternalID)
+		assert.Equal(t, 1, len(*task.Comments))
+		assert.True(t, *task.JIRATaskParams.HasPriorityField)
+		// the due date was cleared in JIRA, so it should be cleared here as well
+		assert.Equal(t, primitive.NewDateTimeFromTime(time.Unix(0, 0)), *task.DueDate)
+	})
+}
+
+func TestRegisterJIRAWebhooks(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	now := time.Date(2023, 3, 1, 16, 0, 0, 0, time.UTC)
+
+	refreshStatusCode := http.StatusOK
+	var requests []string
+	webhookServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.Method == "PUT" {
+			w.WriteHeader(refreshStatusCode)
+			_, err = w.Write([]byte(`{"expirationDate": "2023-04-01T12:00:00.000+0000"}`))
+		} else {
+			w.WriteHeader(http.StatusOK)
+			_, err = w.Write([]byte(`{"webhookRegistrationResult": [{"createdWebhookId": 1000}]}`))
+		}
+		assert.NoError(t, err)
+	}))
+	defer webhookServer.Close()
+	jira := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{WebhookURL: &webhookServer.URL}}}}
+
+	userID, accountID := createJIRAToken(t, database.GetExternalTokenCollection(db))
+	insertResult, err := database.GetJiraSitesCollection(db).InsertOne(context.Background(), database.AtlassianSiteConfiguration{UserID: *userID, CloudID: accountID})
+	assert.NoError(t, err)
+	getSiteConfiguration := func() *database.AtlassianSiteConfiguration {
+		var siteConfiguration database.AtlassianSiteConfiguration
+		assert.NoError(t, database.GetJiraSitesCollection(db).FindOne(context.Background(), bson.M{"_id": insertResult.InsertedID}).Decode(&siteConfiguration))
+		return &siteConfiguration
+	}
+
+	t.Run("Register", func(t *testing.T) {
+		requests = nil
+		assert.NoError(t, jira.RegisterWebhooks(db, getSiteConfiguration(), now))
+		assert.Equal(t, 1, len(requests))
+		assert.Contains(t, requests[0], "POST /rest/api/3/webhook ")
+		assert.Contains(t, requests[0], "jira/webhook/"+accountID+"/")
+		siteConfiguration := getSiteConfiguration()
+		assert.Equal(t, []int64{1000}, siteConfiguration.WebhookIDs)
+		assert.Equal(t, primitive.NewDateTimeFromTime(now.Add(JIRAWebhookExpiration)), siteConfiguration.WebhookExpiresAt)
+	})
+	t.Run("Refresh", func(t *testing.T) {
+		requests = nil
+		assert.NoError(t, jira.RegisterWebhooks(db, getSiteConfiguration(), now))
+		assert.Equal(t, []string{`PUT /rest/api/3/webhook/refresh {"webhookIds":[1000]}`}, requests)
+		assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)), getSiteConfiguration().WebhookExpiresAt)
+	})
+	t.Run("RefreshExpired", func(t *testing.T) {
+		requests = nil
+		refreshStatusCode = http.StatusBadRequest
+		assert.NoError(t, jira.RegisterWebhooks(db, getSiteConfiguration(), now))
+		assert.Equal(t, 2, len(requests))
+		assert.Contains(t, requests[1], "POST /rest/api/3/webhook ")
+		assert.Equal(t, primitive.NewDateTimeFromTime(now.Add(JIRAWebhookExpiration)), getSiteConfiguration().WebhookExpiresAt)
+	})
+	t.Run("MissingToken", func(t *testing.T) {
+		assert.Error(t, jira.RegisterWebhooks(db, &database.AtlassianSiteConfiguration{UserID: primitive.NewObjectID(), CloudID: accountID}, now))
+	})
+}
+
 func setupJIRA(t *testing.T, externalAPITokenCollection *mongo.Collection, AtlassianSiteCollection *mongo.Collection) (*primitive.ObjectID, string) {
 	userID, accountID := createJIRAToken(t, externalAPITokenCollection)
 	createAtlassianSiteConfiguration(t, userID, AtlassianSiteCollection)
@@ -954,3 +1117,205 @@ func TestModifyJIRATask(t *testing.T) {
 		assert.Equal(t, `cannot undelete JIRA tasks`, err.Error())
 	})
 }
+
+func TestCreateJIRATask(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	externalAPITokenCollection := database.GetExternalTokenCollection(db)
+	AtlassianSiteCollection := database.GetJiraSitesCollection(db)
+	createMetaResponse := `{"projects":[{"id":"10000","key":"MOON","issuetypes":[{"id":"10002","name":"Subtask","subtask":true,"fields":{}},{"id":"10001","name":"Task","subtask":false,"fields":{"summary":{"key":"summary"},"priority":{"key":"priority"},"duedate":{"key":"duedate"}}},{"id":"10004","name":"Bug","subtask":false,"fields":{"summary":{"key":"summary"}}}]}]}`
+	priorityResponse := []byte(`[{"id":"1","statusColor":"#ff0000","name":"Highest"},{"id":"2","statusColor":"#00ff00","name":"Medium"},{"id":"3","statusColor":"#0000ff","name":"Lowest"}]`)
+
+	t.Run("InvalidProject", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		tokenServer := getTokenServerForJIRA(t, http.StatusOK)
+		createMetaServer := testutils.GetMockAPIServer(t, http.StatusOK, `{"projects":[]}`)
+		defer createMetaServer.Close()
+		JIRA := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{TokenURL: &tokenServer.URL, CreateMetaURL: &createMetaServer.URL}}}}
+
+		_, err := JIRA.CreateNewTask(db, *userID, accountID, TaskCreationObject{Title: "new task", JIRAProjectKey: "SUN"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid JIRA project", err.Error())
+	})
+	t.Run("InvalidIssueType", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		tokenServer := getTokenServerForJIRA(t, http.StatusOK)
+		createMetaServer := testutils.GetMockAPIServer(t, http.StatusOK, createMetaResponse)
+		defer createMetaServer.Close()
+		JIRA := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{TokenURL: &tokenServer.URL, CreateMetaURL: &createMetaServer.URL}}}}
+
+		_, err := JIRA.CreateNewTask(db, *userID, accountID, TaskCreationObject{Title: "new task", JIRAProjectKey: "MOON", JIRAIssueType: "Epic"})
+		assert.Error(t, err)
+		assert.Equal(t, "invalid JIRA issue type for project", err.Error())
+	})
+	t.Run("UnsupportedDueDate", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		tokenServer := getTokenServerForJIRA(t, http.StatusOK)
+		createMetaServer := testutils.GetMockAPIServer(t, http.StatusOK, createMetaResponse)
+		defer createMetaServer.Close()
+		JIRA := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{TokenURL: &tokenServer.URL, CreateMetaURL: &createMetaServer.URL}}}}
+
+		dueDate := time.Now()
+		_, err := JIRA.CreateNewTask(db, *userID, accountID, TaskCreationObject{Title: "new task", JIRAIssueType: "Bug", DueDate: &dueDate})
+		assert.Error(t, err)
+		assert.Equal(t, "JIRA issue type does not support due dates", err.Error())
+	})
+	t.Run("UnsupportedPriority", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		tokenServer := getTokenServerForJIRA(t, http.StatusOK)
+		createMetaServer := testutils.GetMockAPIServer(t, http.StatusOK, createMetaResponse)
+		defer createMetaServer.Close()
+		JIRA := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{TokenURL: &tokenServer.URL, CreateMetaURL: &createMetaServer.URL}}}}
+
+		priority := 1.0
+		_, err := JIRA.CreateNewTask(db, *userID, accountID, TaskCreationObject{Title: "new task", JIRAIssueType: "Bug", PriorityNormalized: &priority})
+		assert.Error(t, err)
+		assert.Equal(t, "JIRA issue type does not support priorities", err.Error())
+	})
+	t.Run("CreateFailed", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		tokenServer := getTokenServerForJIRA(t, http.StatusOK)
+		createMetaServer := testutils.GetMockAPIServer(t, http.StatusOK, createMetaResponse)
+		defer createMetaServer.Close()
+		userInfoServer := testutils.GetMockAPIServer(t, http.StatusOK, `{"accountId":"jira-account-id"}`)
+		defer userInfoServer.Close()
+		issueCreateServer := testutils.GetMockAPIServer(t, http.StatusBadRequest, `{}`)
+		defer issueCreateServer.Close()
+		JIRA := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{
+			TokenURL:       &tokenServer.URL,
+			CreateMetaURL:  &createMetaServer.URL,
+			UserInfoURL:    &userInfoServer.URL,
+			IssueCreateURL: &issueCreateServer.URL,
+		}}}}
+
+		_, err := JIRA.CreateNewTask(db, *userID, accountID, TaskCreationObject{Title: "new task"})
+		assert.Error(t, err)
+		assert.Equal(t, "unable to successfully make issue create request", err.Error())
+	})
+	t.Run("Success", func(t *testing.T) {
+		userID, accountID := setupJIRA(t, externalAPITokenCollection, AtlassianSiteCollection)
+		tokenServer := getTokenServerForJIRA(t, http.StatusOK)
+		createMetaServer := testutils.GetMockAPIServer(t, http.StatusOK, createMetaResponse)
+		defer createMetaServer.Close()
+		priorityServer := getJIRAPriorityServer(t, http.StatusOK, priorityResponse)
+		defer priorityServer.Close()
+		userInfoServer := testutils.GetMockAPIServer(t, http.StatusOK, `{"accountId":"jira-account-id"}`)
+		defer userInfoServer.Close()
+		statusServer := getStatusServerForJIRA(t, http.StatusOK, false)
+		defer statusServer.Close()
+		issueCreateServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/rest/api/3/issue", r.RequestURI)
+			assert.Equal(t, "POST", r.Method)
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"fields":{"project":{"key":"MOON"},"issuetype":{"id":"10001"},"summary":"new task","description":{"content":[{"content":[{"text":"new body","type":"text"}],"type":"paragraph"}],"type":"doc","version":1},"assignee":{"accountId":"jira-account-id"},"priority":{"id":"3"},"duedate":"2021-04-20"}}`, string(body))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"10042","key":"MOON-42"}`))
+		}))
+		defer issueCreateServer.Close()
+		JIRA := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{
+			TokenURL:        &tokenServer.URL,
+			CreateMetaURL:   &createMetaServer.URL,
+			PriorityListURL: &priorityServer.URL,
+			UserInfoURL:     &userInfoServer.URL,
+			StatusListURL:   &statusServer.URL,
+			IssueCreateURL:  &issueCreateServer.URL,
+		}}}}
+
+		dueDate, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, "2021-04-20")
+		priority := 4.0
+		taskID, err := JIRA.CreateNewTask(db, *userID, accountID, TaskCreationObject{
+			Title:              "new task",
+			Body:               "new body",
+			DueDate:            &dueDate,
+			PriorityNormalized: &priority,
+		})
+		assert.NoError(t, err)
+
+		task, err := database.GetTask(db, taskID, *userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "10042", task.IDExternal)
+		assert.Equal(t, "new task", *task.Title)
+		assert.Equal(t, "https://dankmemes.com/browse/MOON-42", task.Deeplink)
+		assert.Equal(t, accountID, task.SourceAccountID)
+		assert.Equal(t, primitive.NewDateTimeFromTime(dueDate), *task.DueDate)
+		assert.Equal(t, 4.0, *task.PriorityNormalized)
+		assert.Equal(t, "3", task.ExternalPriority.ExternalID)
+		assert.Equal(t, 3, len(task.AllExternalPriorities))
+		assert.Equal(t, "10000", task.Status.ExternalID)
+		assert.Equal(t, 2, len(task.AllStatuses))
+		assert.True(t, *task.JIRATaskParams.HasDueDateField)
+		assert.True(t, *task.JIRATaskParams.HasPriorityField)
+
+		var storedPriorities []database.JIRAPriority
+		err = database.FindWithCollection(database.GetJiraPrioritiesCollection(db), *userID, nil, &storedPriorities, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(storedPriorities))
+	})
+}
+
+func TestPopulateJIRATaskLabels(t *testing.T) {
+	var jiraTask JIRATask
+	err := json.Unmarshal([]byte(`{"id": "42069", "key": "MOON-1969", "fields": {"summary": "Sample Taskeroni", "labels": ["backend", "p1"]}}`), &jiraTask)
+	assert.NoError(t, err)
+	task := populateJIRATask(primitive.NewObjectID(), "example-account", &database.AtlassianSiteConfiguration{SiteURL: "https://example.atlassian.net"}, jiraTask)
+	assert.Equal(t, []database.ExternalLabel{
+		{ExternalID: "backend", Name: "backend"},
+		{ExternalID: "p1", Name: "p1"},
+	}, *task.ExternalLabels)
+}
+
+func TestGetJIRADescription(t *testing.T) {
+	assert.Equal(t, `{"content":[],"type":"doc","version":1}`, string(*getJIRADescription("")))
+	assert.Equal(t, `{"content":[{"content":[{"text":"example body","type":"text"}],"type":"paragraph"}],"type":"doc","version":1}`, string(*getJIRADescription("example body")))
+	document := `{"type": "doc", "version": 1, "content": []}`
+	assert.Equal(t, document, string(*getJIRADescription(document)))
+	// JSON which isn't an ADF document is sent as text
+	assert.Equal(t, `{"content":[{"content":[{"text":"42","type":"text"}],"type":"paragraph"}],"type":"doc","version":1}`, string(*getJIRADescription("42")))
+	assert.Equal(t, `{"content":[{"content":[{"text":"true","type":"text"}],"type":"paragraph"}],"type":"doc","version":1}`, string(*getJIRADescription("true")))
+	assert.Equal(t, `{"content":[{"content":[{"text":"{\"type\": \"paragraph\"}","type":"text"}],"type":"paragraph"}],"type":"doc","version":1}`, string(*getJIRADescription(`{"type": "paragraph"}`)))
+}
+
+func TestGetJIRADescriptionText(t *testing.T) {
+	assert.Equal(t, "", GetJIRADescriptionText(""))
+	assert.Equal(t, "", GetJIRADescriptionText(`{"type": "doc", "version": 1, "content": []}`))
+	assert.Equal(t, "example body", GetJIRADescriptionText(string(*getJIRADescription("example body"))))
+	description := `{"type": "doc", "version": 1, "content": [
+		{"type": "heading", "attrs": {"level": 1}, "content": [{"type": "text", "text": "Steps"}]},
+		{"type": "bulletList", "content": [
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "open the "}, {"type": "text", "text": "invoice", "marks": [{"type": "strong"}]}]}]},
+			{"type": "listItem", "content": [{"type": "paragraph", "content": [{"type": "text", "text": "click export"}, {"type": "hardBreak"}, {"type": "text", "text": "twice"}]}]}
+		]},
+		{"type": "paragraph", "content": []}
+	]}`
+	assert.Equal(t, "Steps\nopen the invoice\nclick export\ntwice", GetJIRADescriptionText(description))
+	// bodies which are not an ADF document are already plain text
+	assert.Equal(t, "plain text", GetJIRADescriptionText("plain text"))
+	assert.Equal(t, `{"type": "paragraph"}`, GetJIRADescriptionText(`{"type": "paragraph"}`))
+}
+
+func TestGetJIRACreateMetadata(t *testing.T) {
+	createMetaResponse := `{"projects":[{"id":"10000","key":"MOON","issuetypes":[{"id":"10001","name":"Task","subtask":false,"fields":{}}]},{"id":"20000","key":"SUN","issuetypes":[{"id":"20001","name":"Bug","subtask":false,"fields":{}}]}]}`
+	createMetaServer := testutils.GetMockAPIServer(t, http.StatusOK, createMetaResponse)
+	defer createMetaServer.Close()
+	JIRA := JIRASource{Atlassian: AtlassianService{Config: AtlassianConfig{ConfigValues: AtlassianConfigValues{CreateMetaURL: &createMetaServer.URL}}}}
+	siteConfiguration := &database.AtlassianSiteConfiguration{}
+
+	t.Run("LaterProject", func(t *testing.T) {
+		// the first project has no bugs, so the second one is used
+		project, issueType, err := JIRA.getCreateMetadata(siteConfiguration, "token", "", "Bug")
+		assert.NoError(t, err)
+		assert.Equal(t, "SUN", project.Key)
+		assert.Equal(t, "20001", issueType.ID)
+	})
+	t.Run("NoProjectHasIssueType", func(t *testing.T) {
+		_, _, err := JIRA.getCreateMetadata(siteConfiguration, "token", "", "Epic")
+		assert.EqualError(t, err, "invalid JIRA issue type for project")
+	})
+	t.Run("ExplicitProject", func(t *testing.T) {
+		_, _, err := JIRA.getCreateMetadata(siteConfiguration, "token", "MOON", "Bug")
+		assert.EqualError(t, err, "invalid JIRA issue type for project")
+	})
+}
@@ -33,6 +33,7 @@ type LinearConfigValues struct {
 	UserInfoURL    *string
 	TaskFetchURL   *string
 	TaskUpdateURL  *string
+	TaskCreateURL  *string
 	StatusFetchURL *string
 }
 
@@ -235,6 +236,12 @@ type LinearExternalUserInfoQuery struct {
 	}
 }
 
+type linearOrganizationQuery struct {
+	Organization struct {
+		Id graphql.String
+	}
+}
+
 type linearWorkflowStatesQuery struct {
 	WorkflowStates struct {
 		Nodes []struct {
@@ -244,6 +251,7 @@ type linearWorkflowStatesQuery struct {
 			Position graphql.Float
 			Color    graphql.String
 			Team     struct {
+				Id   graphql.ID
 				Name graphql.String
 			}
 		}
@@ -307,6 +315,13 @@ type linearAssignedIssuesQuery struct {
 				StartsAt graphql.String
 				EndsAt   graphql.String
 			}
+			Labels struct {
+				Nodes []struct {
+					Id    graphql.ID
+					Name  graphql.String
+					Color graphql.String
+				}
+			}
 		}
 	} `graphql:"issues(filter: {state: {type: {nin: [\"completed\", \"canceled\"]}}, assignee: {email: {eq: $email}}})"`
 	ActiveCycles   Cycles `graphql:" activeCycles: cycles (filter: {isActive: {eq: true}})"`
@@ -534,6 +549,138 @@ func addLinearComment(client *graphqlBasic.Client, issueID string, comment datab
 	return nil
 }
 
+const linearCreateIssueQueryStr = `
+		mutation IssueCreate (
+			$title: String!
+			, $teamId: String!
+			, $assigneeId: String
+			, $stateId: String
+			, $dueDate: TimelessDate
+			, $description: String
+			, $priority: Int
+		) {
+		  issueCreate(
+			input: {
+			  title: $title
+			  , teamId: $teamId
+			  , assigneeId: $assigneeId
+			  , stateId: $stateId
+			  , dueDate: $dueDate
+			  , description: $description
+			  , priority: $priority
+			}
+		  ) {
+			success
+			, issue {
+			  id
+			  , url
+			  , createdAt
+			  , updatedAt
+			  , state {
+				id
+				, name
+				, type
+			  }
+			}
+		  }
+		}`
+
+type linearCreateIssueQuery struct {
+	IssueCreate struct {
+		Success graphql.Boolean
+		Issue   struct {
+			Id        graphql.String
+			Url       graphql.String
+			CreatedAt graphql.String
+			UpdatedAt graphql.String
+			State     struct {
+				Id   graphql.String
+				Name graphql.String
+				Type graphql.String
+			}
+		}
+	}
+}
+
+func createLinearIssue(client *graphqlBasic.Client, teamID string, assigneeID string, task TaskCreationObject) (*linearCreateIssueQuery, error) {
+	request := graphqlBasic.NewRequest(linearCreateIssueQueryStr)
+	request.Var("title", task.Title)
+	request.Var("teamId", teamID)
+	if assigneeID != "" {
+		request.Var("assigneeId", assigneeID)
+	}
+	if task.LinearStatusID != "" {
+		request.Var("stateId", task.LinearStatusID)
+	}
+	if task.DueDate != nil {
+		request.Var("dueDate", task.DueDate.Format(constants.YEAR_MONTH_DAY_FORMAT))
+	}
+	if task.Body != "" {
+		request.Var("description", task.Body)
+	}
+	if task.PriorityNormalized != nil {
+		request.Var("priority", int(*task.PriorityNormalized))
+	}
+
+	log.Debug().Msgf("sending request to Linear: %+v", request)
+	var query linearCreateIssueQuery
+	logger := logging.GetSentryLogger()
+	if err := client.Run(context.Background(), request, &query); err != nil {
+		logger.Error().Err(err).Msg("failed to create linear issue")
+		return nil, err
+	}
+	if !query.IssueCreate.Success {
+		err := errors.New("linear mutation failed to create issue")
+		logger.Error().Err(err).Send()
+		return nil, err
+	}
+	return &query, nil
+}
+
+// getLinearTeamForIssueCreation returns the ID and name of the team a new issue should be created in.
+// If no team ID is requested, the team of the first workflow state is used.
+func getLinearTeamForIssueCreation(statusQuery *linearWorkflowStatesQuery, requestedTeamID string) (string, string, error) {
+	for _, node := range statusQuery.WorkflowStates.Nodes {
+		teamID, ok := node.Team.Id.(string)
+		if !ok {
+			continue
+		}
+		if requestedTeamID == "" || requestedTeamID == teamID {
+			return teamID, string(node.Team.Name), nil
+		}
+	}
+	return "", "", errors.New("could not find linear team")
+}
+
+func GetCompletedLinearStatus(teamStatuses []*database.ExternalTaskStatus) *database.ExternalTaskStatus {
+	for _, status := range teamStatuses {
+		if status.Type == LinearCompletedType {
+			return status
+		}
+	}
+	return nil
+}
+
+// Linear represents priority as an integer from 0 to 4, which we store directly in PriorityNormalized
+func GetLinearPriorities() []*database.ExternalTaskPriority {
+	return []*database.ExternalTaskPriority{
+		{ExternalID: "0", Name: "No priority", PriorityNormalized: 0.0},
+		{ExternalID: "1", Name: "Urgent", PriorityNormalized: 1.0},
+		{ExternalID: "2", Name: "High", PriorityNormalized: 2.0},
+		{ExternalID: "3", Name: "Medium", PriorityNormalized: 3.0},
+		{ExternalID: "4", Name: "Low", PriorityNormalized: 4.0},
+	}
+}
+
+func GetLinearPriorityFromNormalized(priorityNormalized float64) *database.ExternalTaskPriority {
+	for _, priority := range GetLinearPriorities() {
+		if priority.PriorityNormalized == priorityNormalized {
+			return priority
+		}
+	}
+	return nil
+}
+
 func getLinearUserInfoStruct(client *graphql.Client) (*linearUserInfoQuery, error) {
 	var query linearUserInfoQuery
 	err := client.Query(context.Background(), &query, nil)
@@ -545,6 +692,16 @@ func getLinearUserInfoStruct(client *graphql.Client) (*linearUserInfoQuery, erro
 	return &query, nil
 }
 
+func GetLinearOrganizationID(client *graphql.Client) (string, error) {
+	var query linearOrganizationQuery
+	err := client.Query(context.Background(), &query, nil)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to fetch linear organization")
+		return "", err
+	}
+	return string(query.Organization.Id), nil
+}
+
 func GetLinearUserInfoStructByID(client *graphqlBasic.Client, externalID string) (*LinearExternalUserInfoQuery, error) {
 	request := graphqlBasic.NewRequest(linearExternalUserInfoQueryString)
 	request.Var("id", externalID)
@@ -96,6 +96,7 @@ func (linearTask LinearTaskSource) GetTasks(db *mongo.Database, userID primitive
 			SourceID:           TASK_SOURCE_ID_LINEAR,
 			Title:              &stringTitle,
 			Body:               &stringBody,
+			SearchBody:         &stringBody,
 			SourceAccountID:    accountID,
 			CreatedAtExternal:  primitive.NewDateTimeFromTime(createdAt),
 			UpdatedAt:          primitive.NewDateTimeFromTime(updatedAt),
@@ -107,6 +108,8 @@ func (linearTask LinearTaskSource) GetTasks(db *mongo.Database, userID primitive
 				State:      string(linearIssue.State.Name),
 				Type:       string(linearIssue.State.Type),
 			},
+			ExternalPriority:      GetLinearPriorityFromNormalized(float64(linearIssue.Priority)),
+			AllExternalPriorities: GetLinearPriorities(),
 		}
 		if len(linearIssue.Comments.Nodes) > 0 {
 			var dbComments []database.Comment
@@ -127,6 +130,15 @@ func (linearTask LinearTaskSource) GetTasks(db *mongo.Database, userID primitive
 			}
 			task.Comments = &dbComments
 		}
+		externalLabels := []database.ExternalLabel{}
+		for _, linearLabel := range linearIssue.Labels.Nodes {
+			externalLabels = append(externalLabels, database.ExternalLabel{
+				ExternalID: linearLabel.Id.(string),
+				Name:       string(linearLabel.Name),
+				Color:      string(linearLabel.Color),
+			})
+		}
+		task.ExternalLabels = &externalLabels
 		if linearIssue.Cycle.Id != nil {
 			startsAt, _ := time.Parse("2006-01-02T15:04:05.000Z", string(linearIssue.Cycle.StartsAt))
 			endsAt, _ := time.Parse("2006-01-02T15:04:05.000Z", string(linearIssue.Cycle.EndsAt))
@@ -146,14 +158,18 @@ func (linearTask LinearTaskSource) GetTasks(db *mongo.Database, userID primitive
 		}
 
 		updateFields := database.Task{
-			Title:              task.Title,
-			Body:               task.Body,
-			Comments:           task.Comments,
-			Status:             task.Status,
-			CompletedStatus:    task.CompletedStatus,
-			IsCompleted:        task.IsCompleted,
-			PriorityNormalized: task.PriorityNormalized,
-			LinearCycle:        task.LinearCycle,
+			Title:                 task.Title,
+			Body:                  task.Body,
+			SearchBody:            task.Body,
+			Comments:              task.Comments,
+			Status:                task.Status,
+			CompletedStatus:       task.CompletedStatus,
+			IsCompleted:           task.IsCompleted,
+			PriorityNormalized:    task.PriorityNormalized,
+			ExternalPriority:      task.ExternalPriority,
+			AllExternalPriorities: task.AllExternalPriorities,
+			LinearCycle:           task.LinearCycle,
+			ExternalLabels:        task.ExternalLabels,
 		}
 
 		if linearIssue.DueDate != "" {
@@ -225,7 +241,109 @@ func (linearTask LinearTaskSource) ModifyTask(db *mongo.Database, userID primiti
 }
 
 func (linearTask LinearTaskSource) CreateNewTask(db *mongo.Database, userID primitive.ObjectID, accountID string, task TaskCreationObject) (primitive.ObjectID, error) {
-	return primitive.NilObjectID, errors.New("has not been implemented yet")
+	logger := logging.GetSentryLogger()
+	if task.PriorityNormalized != nil && GetLinearPriorityFromNormalized(*task.PriorityNormalized) == nil {
+		return primitive.NilObjectID, errors.New("invalid linear priority")
+	}
+
+	statusClient, err := GetLinearClient(linearTask.Linear.Config.ConfigValues.StatusFetchURL, db, userID, accountID)
+	if err != nil {
+		logger.Error().Err(err).Msg("unable to create linear client")
+		return primitive.NilObjectID, err
+	}
+	statuses, err := GetLinearWorkflowStates(statusClient)
+	if err != nil {
+		logger.Error().Err(err).Msg("unable to get linear workflow states")
+		return primitive.NilObjectID, err
+	}
+	teamID, teamName, err := getLinearTeamForIssueCreation(statuses, task.LinearTeamID)
+	if err != nil {
+		logger.Error().Err(err).Msg("unable to find linear team for new issue")
+		return primitive.NilObjectID, err
+	}
+	teamStatuses := ProcessLinearStatuses(statuses)[teamName]
+	if task.LinearStatusID != "" {
+		isValidStatus := false
+		for _, status := range teamStatuses {
+			if status.ExternalID == task.LinearStatusID {
+				isValidStatus = true
+			}
+		}
+		if !isValidStatus {
+			return primitive.NilObjectID, errors.New("linear status does not belong to team")
+		}
+	}
+
+	// assign the issue to the linked account so that it shows up in the user's assigned issues
+	token, err := database.GetExternalToken(db, accountID, TASK_SERVICE_ID_LINEAR)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	client, err := GetBasicLinearClient(linearTask.Linear.Config.ConfigValues.TaskCreateURL, db, userID, accountID)
+	if err != nil {
+		logger.Error().Err(err).Msg("unable to create linear client")
+		return primitive.NilObjectID, err
+	}
+	createResult, err := createLinearIssue(client, teamID, token.ExternalID, task)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	linearIssue := createResult.IssueCreate.Issue
+
+	createdAt, _ := time.Parse("2006-01-02T15:04:05.000Z", string(linearIssue.CreatedAt))
+	updatedAt, _ := time.Parse("2006-01-02T15:04:05.000Z", string(linearIssue.UpdatedAt))
+	taskSection := constants.IDTaskSectionDefault
+	if task.IDTaskSection != primitive.NilObjectID {
+		taskSection = task.IDTaskSection
+	}
+	isCompleted := false
+	isDeleted := false
+	newTask := &database.Task{
+		UserID:            userID,
+		IDExternal:        string(linearIssue.Id),
+		IDTaskSection:     taskSection,
+		Deeplink:          string(linearIssue.Url),
+		SourceID:          TASK_SOURCE_ID_LINEAR,
+		Title:             &task.Title,
+		Body:              &task.Body,
+		SearchBody:        &task.Body,
+		SourceAccountID:   accountID,
+		CreatedAtExternal: primitive.NewDateTimeFromTime(createdAt),
+		UpdatedAt:         primitive.NewDateTimeFromTime(updatedAt),
+		IsCompleted:       &isCompleted,
+		IsDeleted:         &isDeleted,
+		TimeAllocation:    task.TimeAllocation,
+		Status: &database.ExternalTaskStatus{
+			ExternalID: string(linearIssue.State.Id),
+			State:      string(linearIssue.State.Name),
+			Type:       string(linearIssue.State.Type),
+		},
+		AllStatuses:           teamStatuses,
+		CompletedStatus:       GetCompletedLinearStatus(teamStatuses),
+		AllExternalPriorities: GetLinearPriorities(),
+	}
+	priorityNormalized := 0.0
+	if task.PriorityNormalized != nil {
+		priorityNormalized = *task.PriorityNormalized
+	}
+	newTask.PriorityNormalized = &priorityNormalized
+	newTask.ExternalPriority = GetLinearPriorityFromNormalized(priorityNormalized)
+	if task.DueDate != nil {
+		dueDate, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, task.DueDate.Format(constants.YEAR_MONTH_DAY_FORMAT))
+		primitiveDueDate := primitive.NewDateTimeFromTime(dueDate)
+		newTask.DueDate = &primitiveDueDate
+	} else {
+		dueDate := primitive.NewDateTimeFromTime(time.Unix(0, 0))
+		newTask.DueDate = &dueDate
+	}
+
+	dbTask, err := database.GetOrCreateTask(db, userID, newTask.IDExternal, TASK_SOURCE_ID_LINEAR, newTask)
+	if err != nil {
+		logger.Error().Err(err).Msg("could not create task")
+		return primitive.NilObjectID, err
+	}
+	return dbTask.ID, nil
 }
 
 func (linearTask LinearTaskSource) CreateNewEvent(db *mongo.Database, userID primitive.ObjectID, accountID string, event EventCreateObject) error {
@@ -260,6 +260,7 @@ func TestLoadLinearTasks(t *testing.T) {
 			DueDate:            &dueDate,
 			CreatedAtExternal:  primitive.NewDateTimeFromTime(createdAt),
 			PriorityNormalized: &priority,
+			ExternalPriority:   &database.ExternalTaskPriority{ExternalID: "3"},
 			Status: &database.ExternalTaskStatus{
 				ExternalID: "state-id",
 				State:      "Todo",
@@ -379,6 +380,7 @@ func TestLoadLinearTasks(t *testing.T) {
 			UserID:             userID,
 			DueDate:            &dueDate,
 			PriorityNormalized: &priority,
+			ExternalPriority:   &database.ExternalTaskPriority{ExternalID: "3"},
 			CreatedAtExternal:  primitive.NewDateTimeFromTime(createdAt),
 			Status: &database.ExternalTaskStatus{
 				ExternalID: "state-id",
@@ -1061,6 +1063,135 @@ func TestModifyLinearTask(t *testing.T) {
 	})
 }
 
+func TestCreateLinearTask(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	userID := primitive.NewObjectID()
+	accountID := "sample_account@email.com"
+	_, err = database.GetExternalTokenCollection(db).InsertOne(context.Background(), database.ExternalAPIToken{
+		UserID:     userID,
+		ServiceID:  TASK_SERVICE_ID_LINEAR,
+		AccountID:  accountID,
+		ExternalID: "linear-user-id",
+	})
+	assert.NoError(t, err)
+
+	statusServer := testutils.GetMockAPIServer(t, 200, `{"data": {
+		"workflowStates": {
+			"nodes": [
+				{
+					"id": "todo-state-id",
+					"name": "Todo",
+					"type": "unstarted",
+					"team": {
+						"id": "backend-team-id",
+						"name": "Backend"
+					}
+				},
+				{
+					"id": "done-state-id",
+					"name": "Done",
+					"type": "completed",
+					"team": {
+						"id": "backend-team-id",
+						"name": "Backend"
+					}
+				},
+				{
+					"id": "frontend-todo-state-id",
+					"name": "Todo",
+					"type": "unstarted",
+					"team": {
+						"id": "frontend-team-id",
+						"name": "Frontend"
+					}
+				}
+			]
+		}
+	}}`)
+	defer statusServer.Close()
+
+	t.Run("InvalidPriority", func(t *testing.T) {
+		priority := 7.0
+		linearTask := LinearTaskSource{Linear: LinearService{Config: LinearConfig{ConfigValues: LinearConfigValues{StatusFetchURL: &statusServer.URL}}}}
+		_, err := linearTask.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue", PriorityNormalized: &priority})
+		assert.EqualError(t, err, "invalid linear priority")
+	})
+	t.Run("InvalidTeam", func(t *testing.T) {
+		linearTask := LinearTaskSource{Linear: LinearService{Config: LinearConfig{ConfigValues: LinearConfigValues{StatusFetchURL: &statusServer.URL}}}}
+		_, err := linearTask.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue", LinearTeamID: "oopsie"})
+		assert.EqualError(t, err, "could not find linear team")
+	})
+	t.Run("StatusNotInTeam", func(t *testing.T) {
+		linearTask := LinearTaskSource{Linear: LinearService{Config: LinearConfig{ConfigValues: LinearConfigValues{StatusFetchURL: &statusServer.URL}}}}
+		_, err := linearTask.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue", LinearTeamID: "backend-team-id", LinearStatusID: "frontend-todo-state-id"})
+		assert.EqualError(t, err, "linear status does not belong to team")
+	})
+	t.Run("MutationFailed", func(t *testing.T) {
+		createServer := testutils.GetMockAPIServer(t, 200, `{"data": {"issueCreate": {"success": false}}}`)
+		defer createServer.Close()
+		linearTask := LinearTaskSource{Linear: LinearService{Config: LinearConfig{ConfigValues: LinearConfigValues{
+			StatusFetchURL: &statusServer.URL,
+			TaskCreateURL:  &createServer.URL,
+		}}}}
+		_, err := linearTask.CreateNewTask(db, userID, accountID, TaskCreationObject{Title: "new issue"})
+		assert.EqualError(t, err, "linear mutation failed to create issue")
+	})
+	t.Run("Success", func(t *testing.T) {
+		createServer := testutils.GetMockAPIServer(t, 200, `{"data": {"issueCreate": {
+			"success": true,
+			"issue": {
+				"id": "new-issue-id",
+				"url": "https://linear.app/issue/new-issue-id",
+				"createdAt": "2022-06-06T23:13:24.037Z",
+				"updatedAt": "2022-06-06T23:13:24.037Z",
+				"state": {
+					"id": "todo-state-id",
+					"name": "Todo",
+					"type": "unstarted"
+				}
+			}
+		}}}`)
+		defer createServer.Close()
+		linearTask := LinearTaskSource{Linear: LinearService{Config: LinearConfig{ConfigValues: LinearConfigValues{
+			StatusFetchURL: &statusServer.URL,
+			TaskCreateURL:  &createServer.URL,
+		}}}}
+
+		priority := 2.0
+		dueDate, _ := time.Parse(constants.YEAR_MONTH_DAY_FORMAT, "2022-09-12")
+		taskID, err := linearTask.CreateNewTask(db, userID, accountID, TaskCreationObject{
+			Title:              "new issue",
+			Body:               "new issue body",
+			DueDate:            &dueDate,
+			PriorityNormalized: &priority,
+			LinearTeamID:       "backend-team-id",
+			LinearStatusID:     "todo-state-id",
+		})
+		assert.NoError(t, err)
+
+		task, err := database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, "new-issue-id", task.IDExternal)
+		assert.Equal(t, TASK_SOURCE_ID_LINEAR, task.SourceID)
+		assert.Equal(t, accountID, task.SourceAccountID)
+		assert.Equal(t, "https://linear.app/issue/new-issue-id", task.Deeplink)
+		assert.Equal(t, "new issue", *task.Title)
+		assert.Equal(t, "new issue body", *task.Body)
+		assert.Equal(t, constants.IDTaskSectionDefault, task.IDTaskSection)
+		assert.Equal(t, primitive.NewDateTimeFromTime(dueDate), *task.DueDate)
+		assert.Equal(t, 2.0, *task.PriorityNormalized)
+		assert.Equal(t, "2", task.ExternalPriority.ExternalID)
+		assert.Equal(t, 5, len(task.AllExternalPriorities))
+		assert.Equal(t, "todo-state-id", task.Status.ExternalID)
+		assert.Equal(t, "done-state-id", task.CompletedStatus.ExternalID)
+		assert.Equal(t, 2, len(task.AllStatuses))
+		assert.False(t, *task.IsCompleted)
+	})
+}
+
 func TestAddComment(t *testing.T) {
 	db, dbCleanup, err := database.GetDBConnection()
 	assert.NoError(t, err)
@@ -1176,6 +1307,7 @@ func assertTasksEqual(t *testing.T, a *database.Task, b *database.Task) {
 		}
 	}
 	assertLinearCyclesEqual(t, a.LinearCycle, b.LinearCycle)
+	assert.Equal(t, a.AsanaTaskParams, b.AsanaTaskParams)
 }
 
 func assertLinearCyclesEqual(t *testing.T, a database.LinearCycle, b database.LinearCycle) {
@@ -43,10 +43,14 @@ func emptyTaskResultWithSource(err error, sourceID string) TaskResult {
 }
 
 func emptyPullRequestResult(err error, suppressSentry bool) PullRequestResult {
+	return emptyPullRequestResultWithSource(err, TASK_SOURCE_ID_GITHUB_PR, suppressSentry)
+}
+
+func emptyPullRequestResultWithSource(err error, sourceID string, suppressSentry bool) PullRequestResult {
 	return PullRequestResult{
 		PullRequests:   []*database.PullRequest{},
 		Error:          err,
-		SourceID:       TASK_SOURCE_ID_GITHUB_PR,
+		SourceID:       sourceID,
 		SuppressSentry: suppressSentry,
 	}
 }
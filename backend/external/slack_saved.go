@@ -83,6 +83,7 @@ func (slackTask SlackSavedTaskSource) CreateNewTask(db *mongo.Database, userID p
 		SourceID:          TASK_SOURCE_ID_SLACK_SAVED,
 		Title:             &task.Title,
 		Body:              &task.Body,
+		SearchBody:        &task.Body,
 		SourceAccountID:   accountID,
 		Deeplink:          slackAdditionalInformation.Deeplink,
 		Sender:            slackAdditionalInformation.Username,
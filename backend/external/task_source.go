@@ -28,7 +28,18 @@ type TaskCreationObject struct {
 	TimeAllocation     *int64
 	IDTaskSection      primitive.ObjectID
 	ParentTaskID       primitive.ObjectID
+	PriorityNormalized *float64
 	SlackMessageParams database.SlackMessageParams
+	// used to select the team and workflow state of a new Linear issue
+	LinearTeamID   string
+	LinearStatusID string
+	// used to select the project and issue type of a new Jira issue
+	JIRAProjectKey string
+	JIRAIssueType  string
+	// used to select the project of a new Asana task
+	AsanaProjectID string
+	// used to select the repository of a new GitHub issue
+	GithubRepositoryID string
 }
 
 type Attendee struct {
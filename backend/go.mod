@@ -8,6 +8,7 @@ require (
 	github.com/chidiwilliams/flatbson v0.3.0
 	github.com/dghubble/oauth1 v0.7.0
 	github.com/gin-gonic/gin v1.7.7
+	github.com/go-co-op/gocron v1.18.1
 	github.com/golang-migrate/migrate/v4 v4.14.1
 	github.com/google/go-github/v39 v39.2.0
 	github.com/google/go-github/v45 v45.1.0
@@ -17,6 +18,7 @@ require (
 	github.com/rs/zerolog v1.26.1
 	github.com/shurcooL/graphql v0.0.0-20200928012149-18c5c3165e3a
 	github.com/slack-go/slack v0.10.3
+	github.com/square/mongo-lock v0.0.0-20220601164918-701ecf357cd7
 	github.com/stretchr/testify v1.8.2
 	github.com/swaggo/files v0.0.0-20220610200504-28940afbdbfe
 	github.com/swaggo/gin-swagger v1.5.0
@@ -27,11 +29,7 @@ require (
 	mvdan.cc/xurls/v2 v2.3.0
 )
 
-require (
-	github.com/go-co-op/gocron v1.18.1 // indirect
-	github.com/robfig/cron/v3 v3.0.1 // indirect
-	github.com/square/mongo-lock v0.0.0-20220601164918-701ecf357cd7 // indirect
-)
+require github.com/robfig/cron/v3 v3.0.1 // indirect
 
 require (
 	cloud.google.com/go v0.87.0 // indirect
@@ -82,7 +80,7 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rogpeppe/go-internal v1.8.1-0.20211023094830-115ce09fd6b4 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
-	github.com/sashabaranov/go-gpt3 v0.0.0-20221216095610-1c20931ead68 // indirect
+	github.com/sashabaranov/go-gpt3 v0.0.0-20221216095610-1c20931ead68
 	github.com/shurcooL/sanitized_anchor_name v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.0 // indirect
 	github.com/ugorji/go/codec v1.1.7 // indirect
@@ -95,7 +93,7 @@ require (
 	github.com/youmark/pkcs8 v0.0.0-20181117223130-1be2e3e5546d // indirect
 	go.opencensus.io v0.23.0 // indirect
 	golang.org/x/crypto v0.0.0-20211215165025-cf75a172585e // indirect
-	golang.org/x/exp v0.0.0-20220823124025-807a23277127 // indirect
+	golang.org/x/exp v0.0.0-20220823124025-807a23277127
 	golang.org/x/mod v0.6.0-dev.0.20220419223038-86c51ed26bb4 // indirect
 	golang.org/x/net v0.0.0-20220722155237-a158d28d115b // indirect
 	golang.org/x/sync v0.1.0 // indirect
@@ -0,0 +1,407 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"github.com/GeneralTask/task-manager/backend/settings"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// exports still processing after this long are assumed to have been abandoned and are started again
+const DATA_EXPORT_STALE_AFTER = time.Hour
+
+type dataExportSetting struct {
+	FieldKey   string `json:"field_key"`
+	FieldName  string `json:"field_name"`
+	FieldValue string `json:"field_value"`
+}
+
+// each export is claimed before it is built, so no job lock is needed and the job can run often
+func dataExportJob() {
+	db, cleanup, err := database.GetDBConnection()
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to connect to db for data export job")
+		return
+	}
+	defer cleanup()
+	err = processDataExports(db, time.Now)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to run data export job")
+	}
+	err = expireDataExports(db, time.Now())
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to expire data exports")
+	}
+}
+
+// building an export can take a while, so the time is read again for every claim
+func processDataExports(db *mongo.Database, getCurrentTime func() time.Time) error {
+	logger := logging.GetSentryLogger()
+	for {
+		now := getCurrentTime()
+		dataExport, err := database.ClaimPendingDataExport(db, now, now.Add(-DATA_EXPORT_STALE_AFTER))
+		if err == mongo.ErrNoDocuments {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		// a failed export is not retried, so that one bad account can't block the others
+		err = runDataExport(db, dataExport, now)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to build data export")
+			_, err = database.GetDataExportCollection(db).UpdateOne(
+				context.Background(),
+				bson.M{"_id": dataExport.ID},
+				bson.M{"$set": bson.M{"status": database.DataExportStatusFailed, "is_active": false}},
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func runDataExport(db *mongo.Database, dataExport *database.DataExport, now time.Time) error {
+	archive, err := buildDataExportArchive(db, dataExport.UserID, now)
+	if err != nil {
+		return err
+	}
+	bucket, err := database.GetDataExportFileBucket(db)
+	if err != nil {
+		return err
+	}
+	fileID, err := bucket.UploadFromStream(fmt.Sprintf("data_export_%s.zip", dataExport.ID.Hex()), bytes.NewReader(archive))
+	if err != nil {
+		return err
+	}
+	_, err = database.GetDataExportCollection(db).UpdateOne(
+		context.Background(),
+		bson.M{"_id": dataExport.ID},
+		bson.M{"$set": bson.M{
+			"status":       database.DataExportStatusComplete,
+			"is_active":    false,
+			"file_id":      fileID,
+			"size_bytes":   len(archive),
+			"completed_at": primitive.NewDateTimeFromTime(now),
+			"expires_at":   primitive.NewDateTimeFromTime(now.Add(constants.DATA_EXPORT_RETENTION_DAYS * 24 * time.Hour)),
+		}},
+	)
+	return err
+}
+
+// expireDataExports deletes the files of exports which can no longer be downloaded, keeping the export itself for its status
+func expireDataExports(db *mongo.Database, now time.Time) error {
+	bucket, err := database.GetDataExportFileBucket(db)
+	if err != nil {
+		return err
+	}
+	for {
+		dataExport, err := database.ClaimExpiredDataExport(db, now)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		err = bucket.Delete(dataExport.FileID)
+		if err != nil && err != gridfs.ErrFileNotFound {
+			return err
+		}
+	}
+}
+
+// buildDataExportArchive bundles everything the user has stored with us into a ZIP of JSON files,
+// along with Markdown copies of their tasks and notes which are readable without any other tools
+func buildDataExportArchive(db *mongo.Database, userID primitive.ObjectID, now time.Time) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	archive := zip.NewWriter(buffer)
+	addFile := func(name string, content []byte) error {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(content)
+		return err
+	}
+	addJSONFile := func(name string, value interface{}) error {
+		content, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return err
+		}
+		return addFile(name, content)
+	}
+
+	user, err := database.GetUser(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = addJSONFile("account.json", map[string]interface{}{
+		"id":          user.ID.Hex(),
+		"email":       user.Email,
+		"name":        user.Name,
+		"timezone":    user.Timezone,
+		"created_at":  getDataExportValue(user.CreatedAt),
+		"exported_at": now.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// tasks from every source, including completed and deleted ones
+	for _, exportedCollection := range []struct {
+		fileName   string
+		collection *mongo.Collection
+	}{
+		{"tasks.json", database.GetTaskCollection(db)},
+		{"notes.json", database.GetNoteCollection(db)},
+		{"sections.json", database.GetTaskSectionCollection(db)},
+		{"labels.json", database.GetLabelCollection(db)},
+		{"views.json", database.GetViewCollection(db)},
+		{"recurring_task_templates.json", database.GetRecurringTaskTemplateCollection(db)},
+	} {
+		documents, err := getDataExportDocuments(exportedCollection.collection, userID, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		err = addJSONFile(exportedCollection.fileName, documents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// only the links are exported, as the events themselves belong to the user's calendar
+	eventLinks, err := getDataExportDocuments(
+		database.GetCalendarEventCollection(db),
+		userID,
+		&[]bson.M{{"$or": []bson.M{
+			{"linked_task_id": bson.M{"$exists": true}},
+			{"linked_view_id": bson.M{"$exists": true}},
+			{"linked_pull_request_id": bson.M{"$exists": true}},
+		}}},
+		options.Find().SetProjection(bson.M{
+			"title":                  1,
+			"datetime_start":         1,
+			"datetime_end":           1,
+			"linked_task_id":         1,
+			"linked_view_id":         1,
+			"linked_pull_request_id": 1,
+			"linked_task_source_id":  1,
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = addJSONFile("calendar_event_links.json", eventLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	settingsOptions, err := settings.GetSettingsOptions(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	userSettings, err := settings.GetUserSettings(db, userID, settingsOptions)
+	if err != nil {
+		return nil, err
+	}
+	exportedSettings := []dataExportSetting{}
+	for _, userSetting := range userSettings {
+		exportedSettings = append(exportedSettings, dataExportSetting{
+			FieldKey:   userSetting.FieldKey,
+			FieldName:  userSetting.FieldName,
+			FieldValue: userSetting.FieldValue,
+		})
+	}
+	err = addJSONFile("settings.json", exportedSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []database.Task
+	err = database.FindWithCollection(database.GetTaskCollection(db), userID, &[]bson.M{{"is_deleted": bson.M{"$ne": true}}}, &tasks, nil)
+	if err != nil {
+		return nil, err
+	}
+	sections, err := database.GetTaskSections(db, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = addFile("tasks.md", []byte(getTasksMarkdown(tasks, *sections)))
+	if err != nil {
+		return nil, err
+	}
+
+	var notes []database.Note
+	err = database.FindWithCollection(database.GetNoteCollection(db), userID, &[]bson.M{{"is_deleted": bson.M{"$ne": true}}}, &notes, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range notes {
+		err = addFile("notes/"+note.ID.Hex()+".md", []byte(getNoteMarkdown(note)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// getDataExportDocuments returns the user's documents in a collection as plain JSON values, with "_id" renamed to "id"
+func getDataExportDocuments(collection *mongo.Collection, userID primitive.ObjectID, additionalFilters *[]bson.M, findOptions *options.FindOptions) ([]interface{}, error) {
+	var documents []bson.M
+	err := database.FindWithCollection(collection, userID, additionalFilters, &documents, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	results := []interface{}{}
+	for _, document := range documents {
+		document["id"] = document["_id"]
+		delete(document, "_id")
+		delete(document, "user_id")
+		results = append(results, getDataExportValue(document))
+	}
+	return results, nil
+}
+
+// getDataExportValue converts Mongo types into values which marshal to readable JSON
+func getDataExportValue(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case bson.M:
+		result := map[string]interface{}{}
+		for key, element := range typedValue {
+			result[key] = getDataExportValue(element)
+		}
+		return result
+	case bson.D:
+		result := map[string]interface{}{}
+		for _, element := range typedValue {
+			result[element.Key] = getDataExportValue(element.Value)
+		}
+		return result
+	case bson.A:
+		result := []interface{}{}
+		for _, element := range typedValue {
+			result = append(result, getDataExportValue(element))
+		}
+		return result
+	case primitive.ObjectID:
+		return typedValue.Hex()
+	case primitive.DateTime:
+		return typedValue.Time().UTC().Format(time.RFC3339)
+	}
+	return value
+}
+
+// getTasksMarkdown lists the tasks as checklists under their sections, with subtasks nested beneath their parent
+func getTasksMarkdown(tasks []database.Task, sections []database.TaskSection) string {
+	sectionIDs := []primitive.ObjectID{constants.IDTaskSectionDefault}
+	sectionNames := map[primitive.ObjectID]string{constants.IDTaskSectionDefault: constants.TaskSectionNameDefault}
+	for _, section := range sections {
+		sectionIDs = append(sectionIDs, section.ID)
+		sectionNames[section.ID] = section.Name
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].IDOrdering < tasks[j].IDOrdering
+	})
+	taskIDs := map[primitive.ObjectID]bool{}
+	for _, task := range tasks {
+		taskIDs[task.ID] = true
+	}
+	subtasks := map[primitive.ObjectID][]database.Task{}
+	sectionTasks := map[primitive.ObjectID][]database.Task{}
+	for _, task := range tasks {
+		if task.ParentTaskID != primitive.NilObjectID && taskIDs[task.ParentTaskID] {
+			subtasks[task.ParentTaskID] = append(subtasks[task.ParentTaskID], task)
+			continue
+		}
+		if _, ok := sectionNames[task.IDTaskSection]; !ok {
+			// tasks from external sources keep the default section
+			task.IDTaskSection = constants.IDTaskSectionDefault
+		}
+		sectionTasks[task.IDTaskSection] = append(sectionTasks[task.IDTaskSection], task)
+	}
+
+	externalConfig := external.GetConfig()
+	var builder strings.Builder
+	builder.WriteString("# Tasks\n")
+	var writeTask func(task database.Task, depth int)
+	writeTask = func(task database.Task, depth int) {
+		indent := strings.Repeat("  ", depth)
+		checkbox := "[ ]"
+		if task.IsCompleted != nil && *task.IsCompleted {
+			checkbox = "[x]"
+		}
+		title := ""
+		if task.Title != nil {
+			title = *task.Title
+		}
+		if task.Deeplink != "" {
+			title = fmt.Sprintf("[%s](%s)", title, task.Deeplink)
+		}
+		details := []string{}
+		if task.SourceID != "" && task.SourceID != external.TASK_SOURCE_ID_GT_TASK {
+			sourceName := task.SourceID
+			taskSourceResult, err := externalConfig.GetSourceResult(task.SourceID)
+			if err == nil {
+				sourceName = taskSourceResult.Details.Name
+			}
+			details = append(details, "from "+sourceName)
+		}
+		if task.DueDate != nil && task.DueDate.Time().Unix() > 0 {
+			details = append(details, "due "+task.DueDate.Time().UTC().Format(constants.YEAR_MONTH_DAY_FORMAT))
+		}
+		builder.WriteString(fmt.Sprintf("%s- %s %s", indent, checkbox, title))
+		if len(details) > 0 {
+			builder.WriteString(" (" + strings.Join(details, ", ") + ")")
+		}
+		builder.WriteString("\n")
+		if task.Body != nil && strings.TrimSpace(*task.Body) != "" {
+			for _, line := range strings.Split(strings.TrimSpace(*task.Body), "\n") {
+				builder.WriteString(indent + "  " + line + "\n")
+			}
+		}
+		for _, subtask := range subtasks[task.ID] {
+			writeTask(subtask, depth+1)
+		}
+	}
+	for _, sectionID := range sectionIDs {
+		if len(sectionTasks[sectionID]) == 0 {
+			continue
+		}
+		builder.WriteString("\n## " + sectionNames[sectionID] + "\n\n")
+		for _, task := range sectionTasks[sectionID] {
+			writeTask(task, 0)
+		}
+	}
+	return builder.String()
+}
+
+func getNoteMarkdown(note database.Note) string {
+	title := "Untitled note"
+	if note.Title != nil && *note.Title != "" {
+		title = *note.Title
+	}
+	body := ""
+	if note.Body != nil {
+		body = strings.TrimSpace(*note.Body)
+	}
+	return fmt.Sprintf("# %s\n\n%s\n", title, body)
+}
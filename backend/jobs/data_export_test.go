@@ -0,0 +1,177 @@
+package jobs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestProcessDataExports(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	now := time.Date(2023, 3, 1, 16, 0, 0, 0, time.UTC)
+	getTime := func(currentTime time.Time) func() time.Time {
+		return func() time.Time {
+			return currentTime
+		}
+	}
+
+	insertResult, err := database.GetUserCollection(db).InsertOne(context.Background(), database.User{Email: "test_data_export@generaltask.com"})
+	assert.NoError(t, err)
+	userID := insertResult.InsertedID.(primitive.ObjectID)
+	title := "write report"
+	deleted := true
+	taskID := primitive.NewObjectID()
+	_, err = database.GetTaskCollection(db).InsertMany(context.Background(), []interface{}{
+		database.Task{ID: taskID, UserID: userID, Title: &title, SourceID: external.TASK_SOURCE_ID_GT_TASK, IDTaskSection: constants.IDTaskSectionDefault},
+		database.Task{UserID: userID, Title: &title, SourceID: external.TASK_SOURCE_ID_LINEAR, IsDeleted: &deleted},
+		// tasks of other users are left out
+		database.Task{UserID: primitive.NewObjectID(), Title: &title},
+	})
+	assert.NoError(t, err)
+	noteTitle := "meeting notes"
+	noteResult, err := database.GetNoteCollection(db).InsertOne(context.Background(), database.Note{UserID: userID, Title: &noteTitle})
+	assert.NoError(t, err)
+	_, err = database.GetCalendarEventCollection(db).InsertMany(context.Background(), []interface{}{
+		database.CalendarEvent{UserID: userID, Title: "standup", Body: "agenda", LinkedTaskID: taskID},
+		database.CalendarEvent{UserID: userID, Title: "lunch"},
+	})
+	assert.NoError(t, err)
+
+	dataExport, isCreated, err := database.GetOrCreateDataExport(db, userID, now)
+	assert.NoError(t, err)
+	assert.True(t, isCreated)
+	// an unfinished export is reused
+	sameExport, isCreated, err := database.GetOrCreateDataExport(db, userID, now)
+	assert.NoError(t, err)
+	assert.False(t, isCreated)
+	assert.Equal(t, dataExport.ID, sameExport.ID)
+
+	assert.NoError(t, processDataExports(db, getTime(now)))
+	dataExport, err = database.GetDataExport(db, dataExport.ID, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, database.DataExportStatusComplete, dataExport.Status)
+	assert.False(t, dataExport.IsActive)
+	assert.Equal(t, primitive.NewDateTimeFromTime(now.Add(7*24*time.Hour)), dataExport.ExpiresAt)
+
+	bucket, err := database.GetDataExportFileBucket(db)
+	assert.NoError(t, err)
+	var archive bytes.Buffer
+	_, err = bucket.DownloadToStream(dataExport.FileID, &archive)
+	assert.NoError(t, err)
+	assert.Equal(t, dataExport.SizeBytes, int64(archive.Len()))
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	assert.NoError(t, err)
+	files := map[string][]byte{}
+	for _, file := range reader.File {
+		fileReader, err := file.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(fileReader)
+		assert.NoError(t, err)
+		files[file.Name] = content
+	}
+	for _, fileName := range []string{"account.json", "tasks.json", "notes.json", "sections.json", "labels.json", "views.json", "recurring_task_templates.json", "settings.json", "calendar_event_links.json", "tasks.md"} {
+		assert.Contains(t, files, fileName)
+	}
+	var tasks []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(files["tasks.json"], &tasks))
+	assert.Equal(t, 2, len(tasks))
+	assert.Equal(t, taskID.Hex(), tasks[0]["id"])
+	assert.NotContains(t, tasks[0], "user_id")
+	var eventLinks []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(files["calendar_event_links.json"], &eventLinks))
+	assert.Equal(t, 1, len(eventLinks))
+	assert.Equal(t, taskID.Hex(), eventLinks[0]["linked_task_id"])
+	assert.NotContains(t, eventLinks[0], "body")
+	assert.Equal(t, "# Tasks\n\n## Task Inbox\n\n- [ ] write report\n", string(files["tasks.md"]))
+	assert.Equal(t, "# meeting notes\n\n\n", string(files["notes/"+noteResult.InsertedID.(primitive.ObjectID).Hex()+".md"]))
+
+	t.Run("Expire", func(t *testing.T) {
+		assert.NoError(t, expireDataExports(db, now.Add(6*24*time.Hour)))
+		dataExport, err := database.GetDataExport(db, dataExport.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, database.DataExportStatusComplete, dataExport.Status)
+
+		assert.NoError(t, expireDataExports(db, now.Add(8*24*time.Hour)))
+		dataExport, err = database.GetDataExport(db, dataExport.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, database.DataExportStatusExpired, dataExport.Status)
+		count, err := bucket.GetFilesCollection().CountDocuments(context.Background(), bson.M{"_id": dataExport.FileID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+	t.Run("StaleExport", func(t *testing.T) {
+		staleExport, _, err := database.GetOrCreateDataExport(db, userID, now)
+		assert.NoError(t, err)
+		claimedExport, err := database.ClaimPendingDataExport(db, now, now.Add(-DATA_EXPORT_STALE_AFTER))
+		assert.NoError(t, err)
+		assert.Equal(t, staleExport.ID, claimedExport.ID)
+		// an export which is still being built isn't claimed again
+		assert.NoError(t, processDataExports(db, getTime(now.Add(30*time.Minute))))
+		staleExport, err = database.GetDataExport(db, staleExport.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, database.DataExportStatusProcessing, staleExport.Status)
+
+		assert.NoError(t, processDataExports(db, getTime(now.Add(2*time.Hour))))
+		staleExport, err = database.GetDataExport(db, staleExport.ID, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, database.DataExportStatusComplete, staleExport.Status)
+	})
+}
+
+func TestGetTasksMarkdown(t *testing.T) {
+	title := func(value string) *string {
+		return &value
+	}
+	completed := true
+	body := "first line\nsecond line"
+	dueDate := primitive.NewDateTimeFromTime(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))
+	sectionID := primitive.NewObjectID()
+	parentID := primitive.NewObjectID()
+	tasks := []database.Task{
+		{ID: primitive.NewObjectID(), IDOrdering: 2, Title: title("second"), SourceID: external.TASK_SOURCE_ID_GT_TASK, IDTaskSection: constants.IDTaskSectionDefault},
+		{ID: parentID, IDOrdering: 1, Title: title("first"), Body: &body, SourceID: external.TASK_SOURCE_ID_GT_TASK, IDTaskSection: constants.IDTaskSectionDefault},
+		{ID: primitive.NewObjectID(), ParentTaskID: parentID, Title: title("subtask"), IsCompleted: &completed, SourceID: external.TASK_SOURCE_ID_GT_TASK},
+		{ID: primitive.NewObjectID(), Title: title("linear issue"), SourceID: external.TASK_SOURCE_ID_LINEAR, Deeplink: "https://linear.app/issue", DueDate: &dueDate},
+		{ID: primitive.NewObjectID(), Title: title("planning"), SourceID: external.TASK_SOURCE_ID_GT_TASK, IDTaskSection: sectionID},
+	}
+	assert.Equal(t, "# Tasks\n"+
+		"\n## Task Inbox\n\n"+
+		"- [ ] [linear issue](https://linear.app/issue) (from Linear, due 2023-03-01)\n"+
+		"- [ ] first\n"+
+		"  first line\n"+
+		"  second line\n"+
+		"  - [x] subtask\n"+
+		"- [ ] second\n"+
+		"\n## Work\n\n"+
+		"- [ ] planning\n",
+		getTasksMarkdown(tasks, []database.TaskSection{{ID: sectionID, Name: "Work"}}))
+}
+
+func TestGetDataExportValue(t *testing.T) {
+	objectID := primitive.NewObjectID()
+	datetime := time.Date(2023, 3, 1, 16, 0, 0, 0, time.UTC)
+	assert.Equal(t, map[string]interface{}{
+		"id":      objectID.Hex(),
+		"created": "2023-03-01T16:00:00Z",
+		"nested":  map[string]interface{}{"ids": []interface{}{objectID.Hex()}},
+		"title":   "task",
+	}, getDataExportValue(bson.M{
+		"id":      objectID,
+		"created": primitive.NewDateTimeFromTime(datetime),
+		"nested":  bson.D{{Key: "ids", Value: bson.A{objectID}}},
+		"title":   "task",
+	}))
+}
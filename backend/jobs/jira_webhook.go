@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// webhooks are refreshed a week ahead of expiring so that a few failed runs don't drop events
+const JIRA_WEBHOOK_REFRESH_BEFORE = 7 * 24 * time.Hour
+
+func jiraWebhookRefreshJob() {
+	_, err := EnsureJobOnlyRunsOnceToday("jira_webhook_refresh")
+	if err != nil {
+		return
+	}
+	db, cleanup, err := database.GetDBConnection()
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to connect to db for jira webhook refresh job")
+		return
+	}
+	defer cleanup()
+	jira := external.JIRASource{Atlassian: external.AtlassianService{Config: external.GetConfig().Atlassian}}
+	err = refreshJIRAWebhooks(db, jira, time.Now())
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to run jira webhook refresh job")
+		return
+	}
+}
+
+func refreshJIRAWebhooks(db *mongo.Database, jira external.JIRASource, now time.Time) error {
+	logger := logging.GetSentryLogger()
+	siteConfigurations, err := database.GetAtlassianSiteConfigurationsWithExpiringWebhooks(db, now.Add(JIRA_WEBHOOK_REFRESH_BEFORE))
+	if err != nil {
+		return err
+	}
+	for _, siteConfiguration := range *siteConfigurations {
+		err = jira.RegisterWebhooks(db, &siteConfiguration, now)
+		if err != nil {
+			// keep going so that one unlinked site does not block the others
+			logger.Error().Err(err).Msgf("failed to refresh jira webhooks for site: %s", siteConfiguration.CloudID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRefreshJIRAWebhooks(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	now := time.Date(2023, 3, 1, 16, 0, 0, 0, time.UTC)
+
+	webhookServer := testutils.GetMockAPIServer(t, http.StatusOK, `{"expirationDate": "2023-04-01T12:00:00.000+0000"}`)
+	defer webhookServer.Close()
+	jira := external.JIRASource{Atlassian: external.AtlassianService{Config: external.AtlassianConfig{ConfigValues: external.AtlassianConfigValues{WebhookURL: &webhookServer.URL}}}}
+
+	userID := primitive.NewObjectID()
+	cloudID := primitive.NewObjectID().Hex()
+	_, err = database.GetExternalTokenCollection(db).InsertOne(context.Background(), database.ExternalAPIToken{
+		ServiceID: external.TASK_SERVICE_ID_ATLASSIAN,
+		Token:     `{"access_token":"sample-token","refresh_token":"sample-token","scope":"sample-scope","expires_in":3600,"token_type":"Bearer"}`,
+		UserID:    userID,
+		AccountID: cloudID,
+	})
+	assert.NoError(t, err)
+	insertResult, err := database.GetJiraSitesCollection(db).InsertMany(context.Background(), []interface{}{
+		database.AtlassianSiteConfiguration{UserID: userID, CloudID: cloudID, WebhookIDs: []int64{1000}, WebhookExpiresAt: primitive.NewDateTimeFromTime(now.Add(time.Hour))},
+		// webhooks which aren't close to expiring are left alone
+		database.AtlassianSiteConfiguration{UserID: primitive.NewObjectID(), CloudID: cloudID, WebhookIDs: []int64{1001}, WebhookExpiresAt: primitive.NewDateTimeFromTime(now.Add(20 * 24 * time.Hour))},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, refreshJIRAWebhooks(db, jira, now))
+	getExpiresAt := func(siteID interface{}) primitive.DateTime {
+		var siteConfiguration database.AtlassianSiteConfiguration
+		assert.NoError(t, database.GetJiraSitesCollection(db).FindOne(context.Background(), bson.M{"_id": siteID}).Decode(&siteConfiguration))
+		return siteConfiguration.WebhookExpiresAt
+	}
+	assert.Equal(t, primitive.NewDateTimeFromTime(time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)), getExpiresAt(insertResult.InsertedIDs[0]))
+	assert.Equal(t, primitive.NewDateTimeFromTime(now.Add(20*24*time.Hour)), getExpiresAt(insertResult.InsertedIDs[1]))
+}
@@ -0,0 +1,310 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"github.com/GeneralTask/task-manager/backend/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func recurringTaskJob() {
+	_, err := EnsureJobOnlyRunsOncePerHour("recurring_task")
+	if err != nil {
+		return
+	}
+	db, cleanup, err := database.GetDBConnection()
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to connect to db for recurring task job")
+		return
+	}
+	defer cleanup()
+	err = backfillAllRecurringTaskTemplates(db, time.Now())
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to run recurring task job")
+		return
+	}
+}
+
+func backfillAllRecurringTaskTemplates(db *mongo.Database, currentTime time.Time) error {
+	logger := logging.GetSentryLogger()
+	cursor, err := database.GetRecurringTaskTemplateCollection(db).Find(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"is_deleted": false},
+			{"is_enabled": true},
+		}},
+	)
+	if err != nil {
+		return err
+	}
+	var templates []database.RecurringTaskTemplate
+	err = cursor.All(context.Background(), &templates)
+	if err != nil {
+		return err
+	}
+
+	userIDToLocation := make(map[primitive.ObjectID]*time.Location)
+	for _, template := range templates {
+		location, exists := userIDToLocation[template.UserID]
+		if !exists {
+			location = getUserLocation(db, template.UserID)
+			userIDToLocation[template.UserID] = location
+		}
+		if location == nil {
+			// tasks for users without a stored timezone are backfilled when they next open the app
+			continue
+		}
+		err = BackfillRecurringTaskTemplate(db, template, currentTime.In(location))
+		if err != nil {
+			// keep going so that one bad template does not block tasks for other users
+			logger.Error().Err(err).Msgf("failed to backfill recurring task template: %s", template.ID.Hex())
+		}
+	}
+	return nil
+}
+
+func getUserLocation(db *mongo.Database, userID primitive.ObjectID) *time.Location {
+	user, err := database.GetUser(db, userID)
+	if err != nil || user.Timezone == "" {
+		return nil
+	}
+	location, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msgf("invalid timezone for user: %s", userID.Hex())
+		return nil
+	}
+	return location
+}
+
+// BackfillRecurringTaskTemplate creates the tasks which have come due since the template was last backfilled.
+// currentTime must be in the user's local timezone, as the creation time of day is relative to it.
+func BackfillRecurringTaskTemplate(db *mongo.Database, template database.RecurringTaskTemplate, currentTime time.Time) error {
+	occurrences, err := GetRecurringTaskTemplateOccurrences(template, template.LastBackfillDatetime.Time(), currentTime)
+	if err != nil {
+		return err
+	}
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	// claim the backfill before inserting so that the job and the backfill endpoint never both create the same tasks
+	claimed, err := claimRecurringTaskTemplateBackfill(db, template, currentTime)
+	if err != nil || !claimed {
+		return err
+	}
+
+	var tasks []interface{}
+	if template.ReplaceExisting != nil && *template.ReplaceExisting {
+		_, err = database.GetTaskCollection(db).UpdateMany(
+			context.Background(),
+			bson.M{"$and": []bson.M{
+				{"recurring_task_template_id": template.ID},
+				{"user_id": template.UserID},
+			}},
+			bson.M{"$set": bson.M{"is_deleted": true}},
+		)
+		if err != nil && err != mongo.ErrNoDocuments {
+			logging.GetSentryLogger().Error().Err(err).Msg("failed to update existing tasks from template")
+			return err
+		}
+		// only the most recent occurrence is kept, so that its due date is current
+		tasks = createTasksFromTemplate(template, occurrences[len(occurrences)-1], currentTime)
+	} else {
+		for _, occurrence := range occurrences {
+			tasks = append(tasks, createTasksFromTemplate(template, occurrence, currentTime)...)
+		}
+	}
+
+	// parents and their subtasks are inserted together so that subtasks are never created without a parent
+	_, err = database.GetTaskCollection(db).InsertMany(context.Background(), tasks)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("unable to insert tasks from template")
+		return err
+	}
+	return nil
+}
+
+// returns false if the template has been backfilled by someone else since it was fetched
+func claimRecurringTaskTemplateBackfill(db *mongo.Database, template database.RecurringTaskTemplate, currentTime time.Time) (bool, error) {
+	var lastBackfillDatetime interface{} = template.LastBackfillDatetime
+	if template.LastBackfillDatetime == 0 {
+		// matches templates where the field was never set
+		lastBackfillDatetime = nil
+	}
+	updateResult, err := database.GetRecurringTaskTemplateCollection(db).UpdateOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"_id": template.ID},
+			{"user_id": template.UserID},
+			{"last_backfill_datetime": lastBackfillDatetime},
+		}},
+		bson.M{"$set": bson.M{"last_backfill_datetime": primitive.NewDateTimeFromTime(currentTime)}},
+	)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to modify recurring task template trigger time")
+		return false, err
+	}
+	return updateResult.MatchedCount > 0, nil
+}
+
+// GetRecurringTaskTemplateOccurrences returns the times at or after `after` and before `before` at which tasks are due from the template.
+// The time of day to create tasks is relative to the location of `before`, which should be the user's timezone.
+func GetRecurringTaskTemplateOccurrences(template database.RecurringTaskTemplate, after time.Time, before time.Time) ([]time.Time, error) {
+	recurrence, start, err := getRecurringTaskTemplateRecurrence(template, after.In(before.Location()))
+	if err != nil {
+		return nil, err
+	}
+	var occurrences []time.Time
+	for _, occurrence := range recurrence.Occurrences(start, after, before) {
+		if !IsRecurringTaskTemplateOccurrenceSkipped(template, occurrence) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences, nil
+}
+
+// GetUpcomingRecurringTaskTemplateOccurrences returns the next `limit` times at or after `after` at which tasks are due from the template,
+// including skipped ones. The time of day to create tasks is relative to the location of `after`.
+func GetUpcomingRecurringTaskTemplateOccurrences(template database.RecurringTaskTemplate, after time.Time, limit int) ([]time.Time, error) {
+	recurrence, start, err := getRecurringTaskTemplateRecurrence(template, after)
+	if err != nil {
+		return nil, err
+	}
+	return recurrence.NextOccurrences(start, after, limit), nil
+}
+
+// IsRecurringTaskTemplateOccurrenceSkipped returns whether the user chose not to create a task on the occurrence's date.
+// Skipped dates are compared in the occurrence's location, i.e. the user's timezone.
+func IsRecurringTaskTemplateOccurrenceSkipped(template database.RecurringTaskTemplate, occurrence time.Time) bool {
+	date := occurrence.Format(constants.YEAR_MONTH_DAY_FORMAT)
+	for _, skippedDate := range template.SkippedDates {
+		if skippedDate == date {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the template's recurrence along with its start in the location of `after`
+func getRecurringTaskTemplateRecurrence(template database.RecurringTaskTemplate, after time.Time) (*utils.Recurrence, time.Time, error) {
+	if template.TimeOfDaySecondsToCreateTask == nil {
+		return nil, time.Time{}, errors.New("invalid template value")
+	}
+	location := after.Location()
+
+	// the recurrence rate shorthand does not use intervals, so it can start from the last backfill to save work
+	anchor := after
+	var rule string
+	if template.RecurrenceRule != nil && *template.RecurrenceRule != "" {
+		rule = *template.RecurrenceRule
+		if template.CreatedAt != 0 {
+			// anchor at the template's creation so that intervals such as every other week stay in step
+			anchor = template.CreatedAt.Time().In(location)
+		}
+	} else {
+		var err error
+		rule, err = getRecurrenceRuleForRecurrenceRate(template)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	recurrence, err := utils.ParseRecurrence(rule)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	timeOfDaySeconds := *template.TimeOfDaySecondsToCreateTask
+	start := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), timeOfDaySeconds/3600, timeOfDaySeconds%3600/60, timeOfDaySeconds%60, 0, location)
+	return recurrence, start, nil
+}
+
+var recurrenceRuleWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func getRecurrenceRuleForRecurrenceRate(template database.RecurringTaskTemplate) (string, error) {
+	// there are certain values that must be present depending on the recurrence type
+	if template.RecurrenceRate == nil {
+		return "", errors.New("invalid template value")
+	}
+	rate := *template.RecurrenceRate
+	if (rate == constants.RecurrenceRateWeekly || rate == constants.RecurrenceRateMonthly || rate == constants.RecurrenceRateAnnually) && template.DayToCreateTask == nil {
+		return "", errors.New("invalid template value")
+	}
+	if rate == constants.RecurrenceRateAnnually && template.MonthToCreateTask == nil {
+		return "", errors.New("invalid template value")
+	}
+
+	switch rate {
+	case constants.RecurrenceRateDaily:
+		return "FREQ=DAILY", nil
+	case constants.RecurrenceRateWeekDaily:
+		return "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", nil
+	case constants.RecurrenceRateWeekly:
+		// both 0 and 7 are accepted for Sunday
+		if *template.DayToCreateTask < 0 || *template.DayToCreateTask > 7 {
+			return "", errors.New("invalid template value")
+		}
+		return "FREQ=WEEKLY;BYDAY=" + recurrenceRuleWeekdays[*template.DayToCreateTask%7], nil
+	case constants.RecurrenceRateMonthly:
+		return fmt.Sprintf("FREQ=MONTHLY;BYMONTHDAY=%d", *template.DayToCreateTask), nil
+	case constants.RecurrenceRateAnnually:
+		return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYMONTHDAY=%d", *template.MonthToCreateTask, *template.DayToCreateTask), nil
+	}
+	return "", errors.New("unrecognized recurrence rate for template backfill")
+}
+
+// returns the parent task followed by its subtasks
+func createTasksFromTemplate(template database.RecurringTaskTemplate, occurrence time.Time, currentTime time.Time) []interface{} {
+	completed := false
+	var dueDate *primitive.DateTime
+	if template.DueDateOffsetSeconds != nil {
+		dueDateTemp := primitive.NewDateTimeFromTime(occurrence.Add(time.Duration(*template.DueDateOffsetSeconds) * time.Second))
+		dueDate = &dueDateTemp
+	}
+
+	// TODO calculate time when this task should have been created for CreatedAt and UpdatedAt
+	parentTask := database.Task{
+		ID:                      primitive.NewObjectID(),
+		UserID:                  template.UserID,
+		RecurringTaskTemplateID: template.ID,
+		SourceID:                external.TASK_SOURCE_ID_GT_TASK,
+		Title:                   template.Title,
+		Body:                    template.Body,
+		SearchBody:              template.Body,
+		IDTaskSection:           template.IDTaskSection,
+		PriorityNormalized:      template.PriorityNormalized,
+		DueDate:                 dueDate,
+		TimeAllocation:          template.TimeAllocation,
+		IsCompleted:             &completed,
+		CreatedAtExternal:       primitive.NewDateTimeFromTime(currentTime),
+		UpdatedAt:               primitive.NewDateTimeFromTime(currentTime),
+	}
+	tasks := []interface{}{parentTask}
+	for index, subtask := range template.Subtasks {
+		title := subtask.Title
+		body := subtask.Body
+		tasks = append(tasks, database.Task{
+			UserID:                  template.UserID,
+			ParentTaskID:            parentTask.ID,
+			RecurringTaskTemplateID: template.ID,
+			SourceID:                external.TASK_SOURCE_ID_GT_TASK,
+			Title:                   &title,
+			Body:                    &body,
+			SearchBody:              &body,
+			IDOrdering:              index + 1,
+			IDTaskSection:           template.IDTaskSection,
+			IsCompleted:             &completed,
+			CreatedAtExternal:       primitive.NewDateTimeFromTime(currentTime),
+			UpdatedAt:               primitive.NewDateTimeFromTime(currentTime),
+		})
+	}
+	return tasks
+}
@@ -0,0 +1,326 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func createTestRecurringTaskTemplate(t *testing.T, db *mongo.Database, userID primitive.ObjectID, recurrenceRate int, creationTimeSeconds int, lastBackfillTime time.Time) database.RecurringTaskTemplate {
+	title := "recurring task"
+	enabled := true
+	deleted := false
+	template := database.RecurringTaskTemplate{
+		UserID:                       userID,
+		Title:                        &title,
+		IsEnabled:                    &enabled,
+		IsDeleted:                    &deleted,
+		RecurrenceRate:               &recurrenceRate,
+		TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+		LastBackfillDatetime:         primitive.NewDateTimeFromTime(lastBackfillTime),
+	}
+	insertResult, err := database.GetRecurringTaskTemplateCollection(db).InsertOne(context.Background(), template)
+	assert.NoError(t, err)
+	template.ID = insertResult.InsertedID.(primitive.ObjectID)
+	return template
+}
+
+func createTestUserWithTimezone(t *testing.T, db *mongo.Database, timezone string) primitive.ObjectID {
+	insertResult, err := database.GetUserCollection(db).InsertOne(context.Background(), database.User{Timezone: timezone})
+	assert.NoError(t, err)
+	return insertResult.InsertedID.(primitive.ObjectID)
+}
+
+func TestBackfillRecurringTaskTemplate(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	// 10:10:10 on Tuesday November 15, 2022
+	currentTime := time.Date(2022, time.November, 15, 10, 10, 10, 0, time.UTC)
+
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		template := createTestRecurringTaskTemplate(t, db, primitive.NewObjectID(), constants.RecurrenceRateWeekly, 0, currentTime.Add(-time.Hour))
+		assert.Error(t, BackfillRecurringTaskTemplate(db, template, currentTime))
+	})
+	t.Run("Success", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		// 10:00:10, created once on the 13th, 14th and 15th
+		template := createTestRecurringTaskTemplate(t, db, userID, constants.RecurrenceRateDaily, 60*60*10+10, currentTime.AddDate(0, 0, -3))
+
+		assert.NoError(t, BackfillRecurringTaskTemplate(db, template, currentTime))
+		tasks, err := database.GetActiveTasks(db, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(*tasks))
+		assert.Equal(t, template.ID, (*tasks)[0].RecurringTaskTemplateID)
+
+		var updatedTemplate database.RecurringTaskTemplate
+		err = database.GetRecurringTaskTemplateCollection(db).FindOne(context.Background(), bson.M{"_id": template.ID}).Decode(&updatedTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, primitive.NewDateTimeFromTime(currentTime), updatedTemplate.LastBackfillDatetime)
+	})
+	t.Run("Subtasks", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		template := createTestRecurringTaskTemplate(t, db, userID, constants.RecurrenceRateDaily, 60*60*10+10, currentTime.AddDate(0, 0, -2))
+		template.Subtasks = []database.RecurringTaskTemplateSubtask{{Title: "first"}, {Title: "second"}}
+
+		assert.NoError(t, BackfillRecurringTaskTemplate(db, template, currentTime))
+		tasks, err := database.GetActiveTasks(db, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, len(*tasks))
+
+		parentTaskIDs := map[primitive.ObjectID]int{}
+		for _, task := range *tasks {
+			if !task.ParentTaskID.IsZero() {
+				parentTaskIDs[task.ParentTaskID]++
+			}
+		}
+		assert.Equal(t, 2, len(parentTaskIDs))
+		for parentTaskID, subtaskCount := range parentTaskIDs {
+			assert.Equal(t, 2, subtaskCount)
+			parentTask, err := database.GetTask(db, parentTaskID, userID)
+			assert.NoError(t, err)
+			assert.Equal(t, template.ID, parentTask.RecurringTaskTemplateID)
+		}
+	})
+	t.Run("AlreadyBackfilled", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		template := createTestRecurringTaskTemplate(t, db, userID, constants.RecurrenceRateDaily, 60*60*10+10, currentTime.AddDate(0, 0, -3))
+
+		assert.NoError(t, BackfillRecurringTaskTemplate(db, template, currentTime))
+		// a stale copy of the template must not create the same tasks again
+		assert.NoError(t, BackfillRecurringTaskTemplate(db, template, currentTime.Add(time.Minute)))
+		tasks, err := database.GetActiveTasks(db, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, len(*tasks))
+	})
+}
+
+func TestCreateTasksFromTemplate(t *testing.T) {
+	title := "weekly release"
+	dueDateOffsetSeconds := 60 * 60 * 24
+	timeAllocation := (30 * time.Minute).Nanoseconds()
+	template := database.RecurringTaskTemplate{
+		ID:                   primitive.NewObjectID(),
+		UserID:               primitive.NewObjectID(),
+		Title:                &title,
+		IDTaskSection:        primitive.NewObjectID(),
+		DueDateOffsetSeconds: &dueDateOffsetSeconds,
+		TimeAllocation:       &timeAllocation,
+		Subtasks: []database.RecurringTaskTemplateSubtask{
+			{Title: "cut release branch"},
+			{Title: "write release notes", Body: "include migrations"},
+		},
+	}
+	occurrence := time.Date(2022, time.November, 14, 10, 0, 0, 0, time.UTC)
+	currentTime := occurrence.Add(time.Hour)
+
+	tasks := createTasksFromTemplate(template, occurrence, currentTime)
+	assert.Equal(t, 3, len(tasks))
+	parentTask := tasks[0].(database.Task)
+	assert.False(t, parentTask.ID.IsZero())
+	assert.Equal(t, primitive.NilObjectID, parentTask.ParentTaskID)
+	assert.Equal(t, "weekly release", *parentTask.Title)
+	assert.Equal(t, primitive.NewDateTimeFromTime(occurrence.AddDate(0, 0, 1)), *parentTask.DueDate)
+	assert.Equal(t, timeAllocation, *parentTask.TimeAllocation)
+	assert.Equal(t, primitive.NewDateTimeFromTime(currentTime), parentTask.CreatedAtExternal)
+
+	for index, subtaskTemplate := range template.Subtasks {
+		subtask := tasks[index+1].(database.Task)
+		assert.Equal(t, parentTask.ID, subtask.ParentTaskID)
+		assert.Equal(t, template.ID, subtask.RecurringTaskTemplateID)
+		assert.Equal(t, template.IDTaskSection, subtask.IDTaskSection)
+		assert.Equal(t, subtaskTemplate.Title, *subtask.Title)
+		assert.Equal(t, subtaskTemplate.Body, *subtask.Body)
+		assert.Equal(t, index+1, subtask.IDOrdering)
+		assert.Nil(t, subtask.DueDate)
+	}
+
+	t.Run("NoDueDate", func(t *testing.T) {
+		template.DueDateOffsetSeconds = nil
+		template.Subtasks = nil
+		tasks := createTasksFromTemplate(template, occurrence, currentTime)
+		assert.Equal(t, 1, len(tasks))
+		assert.Nil(t, tasks[0].(database.Task).DueDate)
+	})
+}
+
+func TestBackfillAllRecurringTaskTemplates(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+
+	// 10:10:10 UTC on Tuesday November 15, 2022, which is 02:10:10 in Los Angeles
+	currentTime := time.Date(2022, time.November, 15, 10, 10, 10, 0, time.UTC)
+	// 09:00:00 on Monday November 14, 2022 in Los Angeles
+	lastBackfillTime := time.Date(2022, time.November, 14, 17, 0, 0, 0, time.UTC)
+	// 08:00:00, which has passed in UTC but not yet in Los Angeles
+	creationTimeSeconds := 60 * 60 * 8
+
+	losAngelesUserID := createTestUserWithTimezone(t, db, "America/Los_Angeles")
+	createTestRecurringTaskTemplate(t, db, losAngelesUserID, constants.RecurrenceRateDaily, creationTimeSeconds, lastBackfillTime)
+	utcUserID := createTestUserWithTimezone(t, db, "UTC")
+	createTestRecurringTaskTemplate(t, db, utcUserID, constants.RecurrenceRateDaily, creationTimeSeconds, lastBackfillTime)
+	noTimezoneUserID := createTestUserWithTimezone(t, db, "")
+	createTestRecurringTaskTemplate(t, db, noTimezoneUserID, constants.RecurrenceRateDaily, creationTimeSeconds, lastBackfillTime)
+
+	assert.NoError(t, backfillAllRecurringTaskTemplates(db, currentTime))
+
+	tasks, err := database.GetActiveTasks(db, losAngelesUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(*tasks))
+	tasks, err = database.GetActiveTasks(db, utcUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*tasks))
+	tasks, err = database.GetActiveTasks(db, noTimezoneUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(*tasks))
+}
+
+func TestGetRecurringTaskTemplateOccurrences(t *testing.T) {
+	// 10:10:10 on Tuesday November 15, 2022
+	currentTime := time.Date(2022, time.November, 15, 10, 10, 10, 0, time.UTC)
+	// 10:00:10
+	creationTimeSeconds := 60*60*10 + 10
+	intPointer := func(value int) *int { return &value }
+	stringPointer := func(value string) *string { return &value }
+
+	for _, testCase := range []struct {
+		name             string
+		template         database.RecurringTaskTemplate
+		lastBackfillTime time.Time
+		expected         int
+	}{
+		{
+			name:             "Daily",
+			template:         database.RecurringTaskTemplate{RecurrenceRate: intPointer(constants.RecurrenceRateDaily)},
+			lastBackfillTime: time.Date(2022, time.November, 15, 9, 0, 0, 0, time.UTC),
+			expected:         1,
+		},
+		{
+			name:             "WeekDaily",
+			template:         database.RecurringTaskTemplate{RecurrenceRate: intPointer(constants.RecurrenceRateWeekDaily)},
+			lastBackfillTime: time.Date(2022, time.November, 11, 9, 0, 0, 0, time.UTC),
+			expected:         3,
+		},
+		{
+			name:             "Weekly",
+			template:         database.RecurringTaskTemplate{RecurrenceRate: intPointer(constants.RecurrenceRateWeekly), DayToCreateTask: intPointer(int(time.Monday))},
+			lastBackfillTime: time.Date(2022, time.November, 6, 9, 0, 0, 0, time.UTC),
+			expected:         2,
+		},
+		{
+			name:             "WeeklySunday",
+			template:         database.RecurringTaskTemplate{RecurrenceRate: intPointer(constants.RecurrenceRateWeekly), DayToCreateTask: intPointer(7)},
+			lastBackfillTime: time.Date(2022, time.November, 6, 9, 0, 0, 0, time.UTC),
+			expected:         2,
+		},
+		{
+			name:             "Monthly",
+			template:         database.RecurringTaskTemplate{RecurrenceRate: intPointer(constants.RecurrenceRateMonthly), DayToCreateTask: intPointer(14)},
+			lastBackfillTime: time.Date(2022, time.September, 13, 4, 0, 0, 0, time.UTC),
+			expected:         3,
+		},
+		{
+			name:             "Annually",
+			template:         database.RecurringTaskTemplate{RecurrenceRate: intPointer(constants.RecurrenceRateAnnually), DayToCreateTask: intPointer(14), MonthToCreateTask: intPointer(11)},
+			lastBackfillTime: time.Date(2021, time.September, 12, 9, 0, 0, 0, time.UTC),
+			expected:         2,
+		},
+		{
+			name: "RecurrenceRule",
+			// every other Tuesday, counting from the template's creation on Tuesday November 1
+			template: database.RecurringTaskTemplate{
+				RecurrenceRule: stringPointer("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"),
+				CreatedAt:      primitive.NewDateTimeFromTime(time.Date(2022, time.November, 1, 8, 0, 0, 0, time.UTC)),
+			},
+			lastBackfillTime: time.Date(2022, time.November, 7, 9, 0, 0, 0, time.UTC),
+			expected:         1,
+		},
+		{
+			name: "RecurrenceRuleOffWeek",
+			template: database.RecurringTaskTemplate{
+				RecurrenceRule: stringPointer("RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"),
+				CreatedAt:      primitive.NewDateTimeFromTime(time.Date(2022, time.November, 8, 8, 0, 0, 0, time.UTC)),
+			},
+			lastBackfillTime: time.Date(2022, time.November, 9, 9, 0, 0, 0, time.UTC),
+			expected:         0,
+		},
+		{
+			name: "RecurrenceRuleWithExceptions",
+			template: database.RecurringTaskTemplate{
+				RecurrenceRate: intPointer(constants.RecurrenceRateMonthly),
+				RecurrenceRule: stringPointer("RRULE:FREQ=DAILY\nEXDATE;VALUE=DATE:20221114"),
+				CreatedAt:      primitive.NewDateTimeFromTime(time.Date(2022, time.November, 1, 8, 0, 0, 0, time.UTC)),
+			},
+			lastBackfillTime: time.Date(2022, time.November, 12, 9, 0, 0, 0, time.UTC),
+			expected:         3,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.template.TimeOfDaySecondsToCreateTask = &creationTimeSeconds
+			occurrences, err := GetRecurringTaskTemplateOccurrences(testCase.template, testCase.lastBackfillTime, currentTime)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, len(occurrences))
+		})
+	}
+	t.Run("SkippedDates", func(t *testing.T) {
+		occurrences, err := GetRecurringTaskTemplateOccurrences(database.RecurringTaskTemplate{
+			RecurrenceRate:               intPointer(constants.RecurrenceRateDaily),
+			TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+			SkippedDates:                 []string{"2022-11-14"},
+		}, currentTime.AddDate(0, 0, -3), currentTime)
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{
+			time.Date(2022, time.November, 13, 10, 0, 10, 0, time.UTC),
+			time.Date(2022, time.November, 15, 10, 0, 10, 0, time.UTC),
+		}, occurrences)
+	})
+	t.Run("UpcomingOccurrences", func(t *testing.T) {
+		occurrences, err := GetUpcomingRecurringTaskTemplateOccurrences(database.RecurringTaskTemplate{
+			RecurrenceRate:               intPointer(constants.RecurrenceRateMonthly),
+			DayToCreateTask:              intPointer(1),
+			TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+			SkippedDates:                 []string{"2022-12-01"},
+		}, currentTime, 2)
+		assert.NoError(t, err)
+		// skipped occurrences are still included so that they can be shown
+		assert.Equal(t, []time.Time{
+			time.Date(2022, time.December, 1, 10, 0, 10, 0, time.UTC),
+			time.Date(2023, time.January, 1, 10, 0, 10, 0, time.UTC),
+		}, occurrences)
+	})
+	t.Run("InvalidRecurrenceRule", func(t *testing.T) {
+		_, err := GetRecurringTaskTemplateOccurrences(database.RecurringTaskTemplate{
+			RecurrenceRule:               stringPointer("FREQ=HOURLY"),
+			TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+		}, currentTime.Add(-time.Hour), currentTime)
+		assert.Error(t, err)
+	})
+	t.Run("InvalidWeekday", func(t *testing.T) {
+		_, err := GetRecurringTaskTemplateOccurrences(database.RecurringTaskTemplate{
+			RecurrenceRate:               intPointer(constants.RecurrenceRateWeekly),
+			DayToCreateTask:              intPointer(8),
+			TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+		}, currentTime.Add(-time.Hour), currentTime)
+		assert.Error(t, err)
+	})
+	t.Run("LocalTimezone", func(t *testing.T) {
+		losAngeles, err := time.LoadLocation("America/Los_Angeles")
+		assert.NoError(t, err)
+		occurrences, err := GetRecurringTaskTemplateOccurrences(database.RecurringTaskTemplate{
+			RecurrenceRate:               intPointer(constants.RecurrenceRateDaily),
+			TimeOfDaySecondsToCreateTask: &creationTimeSeconds,
+		}, currentTime.AddDate(0, 0, -1), currentTime.In(losAngeles))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(occurrences))
+		assert.Equal(t, time.Date(2022, time.November, 14, 10, 0, 10, 0, losAngeles), occurrences[0])
+	})
+}
@@ -20,5 +20,31 @@ func GetScheduler() (*gocron.Scheduler, error) {
 		return nil, err
 	}
 
+	_, err = s.Every(1).Day().At("08:00").Do(trashPurgeJob)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Every(1).Day().At("08:00").Do(jiraWebhookRefreshJob)
+	if err != nil {
+		return nil, err
+	}
+
+	// run at the top of each hour so recurring tasks are created close to their scheduled time
+	_, err = s.Cron("0 * * * *").Do(recurringTaskJob)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Cron("*/5 * * * *").Do(taskReminderJob)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Every(1).Minute().Do(dataExportJob)
+	if err != nil {
+		return nil, err
+	}
+
 	return s, nil
 }
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"github.com/GeneralTask/task-manager/backend/notifications"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type getNotifierFunc func(db *mongo.Database, userID primitive.ObjectID) (notifications.Notifier, error)
+
+// each reminder is claimed before it is sent, so no job lock is needed and the job can run often
+func taskReminderJob() {
+	db, cleanup, err := database.GetDBConnection()
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to connect to db for task reminder job")
+		return
+	}
+	defer cleanup()
+	err = sendDueReminders(db, time.Now(), notifications.GetNotifier)
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to run task reminder job")
+	}
+}
+
+func sendDueReminders(db *mongo.Database, now time.Time, getNotifier getNotifierFunc) error {
+	logger := logging.GetSentryLogger()
+	for {
+		task, err := database.ClaimDueTaskReminder(db, now)
+		if err == mongo.ErrNoDocuments {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		// a reminder which fails to send is not retried, so that a bad notifier can't block the others
+		user, err := database.GetUser(db, task.UserID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to load user for task reminder")
+			continue
+		}
+		notifier, err := getNotifier(db, task.UserID)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to load notifier for task reminder")
+			continue
+		}
+		err = notifier.SendTaskReminder(user, task)
+		if err != nil {
+			logger.Error().Err(err).Msg("failed to send task reminder")
+		}
+	}
+}
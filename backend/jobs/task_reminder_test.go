@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/notifications"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type fakeNotifier struct {
+	sentTaskIDs []primitive.ObjectID
+	err         error
+}
+
+func (notifier *fakeNotifier) SendTaskReminder(user *database.User, task *database.Task) error {
+	notifier.sentTaskIDs = append(notifier.sentTaskIDs, task.ID)
+	return notifier.err
+}
+
+func TestSendDueReminders(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	now := time.Now()
+
+	insertUser, err := database.GetUserCollection(db).InsertOne(context.Background(), database.User{Email: "test_reminders@generaltask.com"})
+	assert.NoError(t, err)
+	userID := insertUser.InsertedID.(primitive.ObjectID)
+	insertTask := func(remindAt time.Time, isCompleted bool, isReminderSent bool) primitive.ObjectID {
+		remindAtDateTime := primitive.NewDateTimeFromTime(remindAt)
+		insertResult, err := database.GetTaskCollection(db).InsertOne(context.Background(), database.Task{
+			UserID:         userID,
+			RemindAt:       &remindAtDateTime,
+			IsCompleted:    &isCompleted,
+			IsReminderSent: &isReminderSent,
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	dueTaskID := insertTask(now.Add(-time.Minute), false, false)
+	insertTask(now.Add(time.Hour), false, false)
+	insertTask(now.Add(-time.Minute), true, false)
+	insertTask(now.Add(-time.Minute), false, true)
+	insertTask(time.Unix(0, 0), false, false)
+
+	notifier := &fakeNotifier{err: errors.New("send failed")}
+	getNotifier := func(db *mongo.Database, userID primitive.ObjectID) (notifications.Notifier, error) {
+		return notifier, nil
+	}
+	assert.NoError(t, sendDueReminders(db, now, getNotifier))
+	assert.Equal(t, []primitive.ObjectID{dueTaskID}, notifier.sentTaskIDs)
+	task, err := database.GetTask(db, dueTaskID, userID)
+	assert.NoError(t, err)
+	assert.True(t, *task.IsReminderSent)
+
+	// reminders are only sent once, even when sending failed
+	assert.NoError(t, sendDueReminders(db, now, getNotifier))
+	assert.Equal(t, []primitive.ObjectID{dueTaskID}, notifier.sentTaskIDs)
+}
@@ -0,0 +1,137 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"github.com/GeneralTask/task-manager/backend/settings"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UpdateTimeTrackingTeamData saves the focus time graph's data points from the time team members tracked on their tasks.
+// Team members are matched to users by email, and members without an account, or who have not opted in to sharing
+// their tracked time, are left out of the team average. The team owner's own time is always included.
+func UpdateTimeTrackingTeamData(userID primitive.ObjectID, endCutoff time.Time, lookbackDays int) error {
+	logger := logging.GetSentryLogger()
+	db, cleanup, err := database.GetDBConnection()
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	team, err := database.GetOrCreateDashboardTeam(db, userID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get dashboard team")
+		return err
+	}
+	teamMembers, err := database.GetDashboardTeamMembers(db, team.ID)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to get dashboard team members")
+		return err
+	}
+	if teamMembers == nil {
+		return nil
+	}
+
+	startCutoff := endCutoff.Add(-time.Hour * 24 * time.Duration(lookbackDays))
+	teamDailyTotals := make(map[time.Time]time.Duration)
+	trackedMemberCount := 0
+	for _, teamMember := range *teamMembers {
+		if teamMember.Email == "" {
+			continue
+		}
+		var user database.User
+		err = database.GetUserCollection(db).FindOne(context.Background(), bson.M{"email": teamMember.Email}).Decode(&user)
+		if err == mongo.ErrNoDocuments {
+			continue
+		} else if err != nil {
+			logger.Error().Err(err).Msg("failed to load team member user")
+			return err
+		}
+		// anyone can be added to a team by email, so their tracked time is only shown if they allow it
+		if user.ID != userID {
+			isSharing, err := isSharingTrackedTime(db, user.ID)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to load team member settings")
+				return err
+			}
+			if !isSharing {
+				continue
+			}
+		}
+		sessions, err := database.GetTimeSessions(db, user.ID, startCutoff, endCutoff)
+		if err != nil {
+			return err
+		}
+		dailyTotals := database.GetTimeSessionDailyTotals(*sessions, startCutoff, endCutoff, endCutoff)
+		err = saveFocusTimeDataPoints(db, dailyTotals, 1, team.ID, teamMember.ID)
+		if err != nil {
+			logger.Error().Err(err).Msgf("failed to save team %s member %s data points", team.ID, teamMember.ID)
+			return err
+		}
+		for day, duration := range dailyTotals {
+			teamDailyTotals[day] += duration
+		}
+		trackedMemberCount += 1
+	}
+	if trackedMemberCount == 0 {
+		return nil
+	}
+	err = saveFocusTimeDataPoints(db, teamDailyTotals, trackedMemberCount, team.ID, primitive.NilObjectID)
+	if err != nil {
+		logger.Error().Err(err).Msgf("failed to save team %s data points", team.ID)
+		return err
+	}
+	return nil
+}
+
+func isSharingTrackedTime(db *mongo.Database, userID primitive.ObjectID) (bool, error) {
+	var userSettings []database.UserSetting
+	err := database.FindWithCollection(database.GetUserSettingsCollection(db), userID, nil, &userSettings, nil)
+	if err != nil {
+		return false, err
+	}
+	return settings.GetSettingValue(userSettings, settings.ShareTrackedTimeSetting) == "true", nil
+}
+
+func saveFocusTimeDataPoints(db *mongo.Database, dailyTotals map[time.Time]time.Duration, memberCount int, teamID primitive.ObjectID, individualID primitive.ObjectID) error {
+	dataPointCollection := database.GetDashboardDataPointCollection(db)
+	for day, duration := range dailyTotals {
+		date := primitive.NewDateTimeFromTime(day)
+		dashboardDataPoint := database.DashboardDataPoint{
+			TeamID:    teamID,
+			GraphType: constants.DashboardGraphTypeFocusTime,
+			Value:     int(duration.Minutes()) / memberCount,
+			Date:      date,
+			CreatedAt: primitive.NewDateTimeFromTime(time.Now()),
+		}
+		filters := []bson.M{
+			{"date": date},
+			{"graph_type": constants.DashboardGraphTypeFocusTime},
+			{"team_id": teamID},
+		}
+		if individualID != primitive.NilObjectID {
+			dashboardDataPoint.IndividualID = individualID
+			filters = append(filters, bson.M{"individual_id": individualID})
+		} else {
+			filters = append(filters, bson.M{"individual_id": bson.M{"$exists": false}})
+		}
+		// tracked time replaces any focus time estimated from the calendar for the same day
+		_, err := dataPointCollection.UpdateOne(
+			context.Background(),
+			bson.M{"$and": filters},
+			bson.M{"$set": dashboardDataPoint},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			logging.GetSentryLogger().Error().Err(err).Msg("failed to update data point")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUpdateTimeTrackingTeamData(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	ownerResult, err := database.GetUserCollection(db).InsertOne(context.Background(), database.User{Email: "test_focus_time_owner@generaltask.com"})
+	assert.NoError(t, err)
+	ownerID := ownerResult.InsertedID.(primitive.ObjectID)
+	team, err := database.GetOrCreateDashboardTeam(db, ownerID)
+	assert.NoError(t, err)
+
+	insertMember := func(email string, isSharing bool) (primitive.ObjectID, primitive.ObjectID) {
+		userID := ownerID
+		if email != "test_focus_time_owner@generaltask.com" {
+			insertResult, err := database.GetUserCollection(db).InsertOne(context.Background(), database.User{Email: email})
+			assert.NoError(t, err)
+			userID = insertResult.InsertedID.(primitive.ObjectID)
+		}
+		if isSharing {
+			assert.NoError(t, database.UpdateUserSetting(db, userID, constants.SettingFieldShareTrackedTime, "true"))
+		}
+		memberResult, err := database.GetDashboardTeamMemberCollection(db).InsertOne(context.Background(), database.DashboardTeamMember{TeamID: team.ID, Email: email})
+		assert.NoError(t, err)
+		return userID, memberResult.InsertedID.(primitive.ObjectID)
+	}
+	// the owner's own time is shown without opting in
+	_, ownerMemberID := insertMember("test_focus_time_owner@generaltask.com", false)
+	firstUserID, firstMemberID := insertMember("test_focus_time_1@generaltask.com", true)
+	secondUserID, _ := insertMember("test_focus_time_2@generaltask.com", true)
+	// members who have not opted in are left out
+	privateUserID, _ := insertMember("test_focus_time_private@generaltask.com", false)
+	// members without an account are left out
+	_, err = database.GetDashboardTeamMemberCollection(db).InsertOne(context.Background(), database.DashboardTeamMember{TeamID: team.ID, Email: "test_focus_time_3@generaltask.com"})
+	assert.NoError(t, err)
+
+	now := time.Date(2023, 3, 2, 20, 0, 0, 0, time.UTC)
+	insertSession := func(userID primitive.ObjectID, start time.Time, duration time.Duration) {
+		_, err := database.GetTaskTimeSessionCollection(db).InsertOne(context.Background(), database.TaskTimeSession{
+			UserID:    userID,
+			TaskID:    primitive.NewObjectID(),
+			StartedAt: primitive.NewDateTimeFromTime(start),
+			EndedAt:   primitive.NewDateTimeFromTime(start.Add(duration)),
+		})
+		assert.NoError(t, err)
+	}
+	insertSession(firstUserID, time.Date(2023, 3, 2, 16, 0, 0, 0, time.UTC), 2*time.Hour)
+	insertSession(secondUserID, time.Date(2023, 3, 2, 10, 0, 0, 0, time.UTC), time.Hour)
+	insertSession(ownerID, time.Date(2023, 3, 2, 12, 0, 0, 0, time.UTC), 3*time.Hour)
+	insertSession(privateUserID, time.Date(2023, 3, 2, 12, 0, 0, 0, time.UTC), 5*time.Hour)
+
+	assert.NoError(t, UpdateTimeTrackingTeamData(ownerID, now, DEFAULT_LOOKBACK_DAYS))
+
+	var dataPoints []database.DashboardDataPoint
+	cursor, err := database.GetDashboardDataPointCollection(db).Find(context.Background(), bson.M{"team_id": team.ID, "graph_type": constants.DashboardGraphTypeFocusTime})
+	assert.NoError(t, err)
+	assert.NoError(t, cursor.All(context.Background(), &dataPoints))
+	assert.Equal(t, 4, len(dataPoints))
+	day := primitive.NewDateTimeFromTime(time.Date(2023, 3, 2, constants.UTC_OFFSET, 0, 0, 0, time.UTC))
+	for _, dataPoint := range dataPoints {
+		assert.Equal(t, day, dataPoint.Date)
+		if dataPoint.IndividualID == firstMemberID {
+			assert.Equal(t, 120, dataPoint.Value)
+		} else if dataPoint.IndividualID == ownerMemberID {
+			assert.Equal(t, 180, dataPoint.Value)
+		} else if dataPoint.IndividualID == primitive.NilObjectID {
+			assert.Equal(t, 120, dataPoint.Value)
+		} else {
+			assert.Equal(t, 60, dataPoint.Value)
+		}
+	}
+
+	// running the update again replaces the existing data points
+	assert.NoError(t, UpdateTimeTrackingTeamData(ownerID, now, DEFAULT_LOOKBACK_DAYS))
+	count, err := database.GetDashboardDataPointCollection(db).CountDocuments(context.Background(), bson.M{"team_id": team.ID, "graph_type": constants.DashboardGraphTypeFocusTime})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), count)
+}
@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func trashPurgeJob() {
+	_, err := EnsureJobOnlyRunsOnceToday("trash_purge")
+	if err != nil {
+		return
+	}
+	db, cleanup, err := database.GetDBConnection()
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to connect to db for trash purge job")
+		return
+	}
+	defer cleanup()
+	err = purgeTrash(db, time.Now().Add(-getTrashRetention(config.GetConfigValue("TRASH_RETENTION_DAYS"))))
+	if err != nil {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to run trash purge job")
+		return
+	}
+}
+
+func getTrashRetention(retentionDays string) time.Duration {
+	days, err := strconv.Atoi(retentionDays)
+	if err != nil || days < 1 {
+		days = constants.DEFAULT_TRASH_RETENTION_DAYS
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func purgeTrash(db *mongo.Database, deletedBefore time.Time) error {
+	logger := logging.GetSentryLogger()
+	// tasks from external sources are kept as a record of the deletion, as the next sync would otherwise recreate them
+	purgedTasks, err := database.PurgeDeletedTasks(db, external.TASK_SOURCE_ID_GT_TASK, deletedBefore)
+	if err != nil {
+		return err
+	}
+	purgedNotes, err := database.PurgeDeletedNotes(db, deletedBefore)
+	if err != nil {
+		return err
+	}
+	logger.Info().Msgf("purged %d tasks and %d notes from the trash", purgedTasks, purgedNotes)
+	return nil
+}
@@ -0,0 +1,71 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetTrashRetention(t *testing.T) {
+	assert.Equal(t, 7*24*time.Hour, getTrashRetention("7"))
+	assert.Equal(t, 30*24*time.Hour, getTrashRetention(""))
+	assert.Equal(t, 30*24*time.Hour, getTrashRetention("0"))
+	assert.Equal(t, 30*24*time.Hour, getTrashRetention("a week"))
+}
+
+func TestPurgeTrash(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	userID := primitive.NewObjectID()
+	now := time.Now()
+	deleted := true
+	notDeleted := false
+
+	insertTask := func(sourceID string, isDeleted *bool, deletedAt time.Time) primitive.ObjectID {
+		insertResult, err := database.GetTaskCollection(db).InsertOne(context.Background(), database.Task{
+			UserID:    userID,
+			SourceID:  sourceID,
+			IsDeleted: isDeleted,
+			DeletedAt: primitive.NewDateTimeFromTime(deletedAt),
+		})
+		assert.NoError(t, err)
+		return insertResult.InsertedID.(primitive.ObjectID)
+	}
+	expiredTaskID := insertTask(external.TASK_SOURCE_ID_GT_TASK, &deleted, now.Add(-40*24*time.Hour))
+	recentTaskID := insertTask(external.TASK_SOURCE_ID_GT_TASK, &deleted, now.Add(-time.Hour))
+	notDeletedTaskID := insertTask(external.TASK_SOURCE_ID_GT_TASK, &notDeleted, now.Add(-40*24*time.Hour))
+	// the deleted state of external tasks must survive the next sync
+	expiredLinearTaskID := insertTask(external.TASK_SOURCE_ID_LINEAR, &deleted, now.Add(-40*24*time.Hour))
+	_, err = database.GetTaskHistoryCollection(db).InsertOne(context.Background(), database.TaskHistoryEvent{UserID: userID, TaskID: expiredTaskID, Field: "status"})
+	assert.NoError(t, err)
+
+	// notes deleted before deleted_at was recorded are kept
+	_, err = database.GetNoteCollection(db).InsertMany(context.Background(), []interface{}{
+		database.Note{UserID: userID, IsDeleted: &deleted, DeletedAt: primitive.NewDateTimeFromTime(now.Add(-40 * 24 * time.Hour))},
+		database.Note{UserID: userID, IsDeleted: &deleted},
+	})
+	assert.NoError(t, err)
+
+	err = purgeTrash(db, now.Add(-30*24*time.Hour))
+	assert.NoError(t, err)
+
+	_, err = database.GetTask(db, expiredTaskID, userID)
+	assert.Error(t, err)
+	for _, taskID := range []primitive.ObjectID{recentTaskID, notDeletedTaskID, expiredLinearTaskID} {
+		_, err = database.GetTask(db, taskID, userID)
+		assert.NoError(t, err)
+	}
+	count, err := database.GetTaskHistoryCollection(db).CountDocuments(context.Background(), bson.M{"task_id": expiredTaskID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	count, err = database.GetNoteCollection(db).CountDocuments(context.Background(), bson.M{"user_id": userID})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
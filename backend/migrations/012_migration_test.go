@@ -0,0 +1,58 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMigrate012(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	migrate, err := getMigrate("")
+	assert.NoError(t, err)
+	err = migrate.Steps(1)
+	assert.NoError(t, err)
+
+	collections := []*mongo.Collection{
+		database.GetTaskCollection(db),
+		database.GetNoteCollection(db),
+		database.GetPullRequestCollection(db),
+		database.GetCalendarEventCollection(db),
+	}
+	hasSearchIndex := func(collection *mongo.Collection) bool {
+		cursor, err := collection.Indexes().List(context.Background())
+		if err != nil {
+			// the collection may not exist yet
+			return false
+		}
+		var indexes []bson.M
+		assert.NoError(t, cursor.All(context.Background(), &indexes))
+		for _, index := range indexes {
+			if index["name"] == "search_text" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("MigrateUp", func(t *testing.T) {
+		err = migrate.Steps(1)
+		assert.NoError(t, err)
+		for _, collection := range collections {
+			assert.True(t, hasSearchIndex(collection), collection.Name())
+		}
+	})
+	t.Run("MigrateDown", func(t *testing.T) {
+		err = migrate.Steps(-1)
+		assert.NoError(t, err)
+		for _, collection := range collections {
+			assert.False(t, hasSearchIndex(collection), collection.Name())
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMigrate013(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	migrate, err := getMigrate("")
+	assert.NoError(t, err)
+	err = migrate.Steps(1)
+	assert.NoError(t, err)
+
+	hasHistoryIndex := func() bool {
+		cursor, err := database.GetTaskHistoryCollection(db).Indexes().List(context.Background())
+		if err != nil {
+			// the collection may not exist yet
+			return false
+		}
+		var indexes []bson.M
+		assert.NoError(t, cursor.All(context.Background(), &indexes))
+		for _, index := range indexes {
+			if index["name"] == "user_id_1_task_id_1_created_at_1" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("MigrateUp", func(t *testing.T) {
+		err = migrate.Steps(1)
+		assert.NoError(t, err)
+		assert.True(t, hasHistoryIndex())
+	})
+	t.Run("MigrateDown", func(t *testing.T) {
+		err = migrate.Steps(-1)
+		assert.NoError(t, err)
+		assert.False(t, hasHistoryIndex())
+	})
+}
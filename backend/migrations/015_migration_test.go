@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMigrate015(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	migrate, err := getMigrate("")
+	assert.NoError(t, err)
+	err = migrate.Steps(1)
+	assert.NoError(t, err)
+
+	hasDataExportIndexes := func() bool {
+		cursor, err := database.GetDataExportCollection(db).Indexes().List(context.Background())
+		if err != nil {
+			// the collection may not exist yet
+			return false
+		}
+		var indexes []bson.M
+		assert.NoError(t, cursor.All(context.Background(), &indexes))
+		indexNames := map[interface{}]bool{}
+		for _, index := range indexes {
+			indexNames[index["name"]] = true
+		}
+		return indexNames["status_1_created_at_1"] && indexNames["user_id_1_status_1"]
+	}
+
+	t.Run("MigrateUp", func(t *testing.T) {
+		err = migrate.Steps(1)
+		assert.NoError(t, err)
+		assert.True(t, hasDataExportIndexes())
+	})
+	t.Run("MigrateDown", func(t *testing.T) {
+		err = migrate.Steps(-1)
+		assert.NoError(t, err)
+		assert.False(t, hasDataExportIndexes())
+	})
+}
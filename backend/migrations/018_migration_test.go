@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMigrate018(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	migrate, err := getMigrate("")
+	assert.NoError(t, err)
+	err = migrate.Steps(1)
+	assert.NoError(t, err)
+
+	taskCollection := database.GetTaskCollection(db)
+	// tasks written before this migration have a body but no search body
+	_, err = taskCollection.InsertMany(context.Background(), []interface{}{
+		bson.M{"source_id": "gt_task", "title": "task", "body": "task body"},
+		bson.M{"source_id": "jira", "title": "issue", "body": `{"type": "doc", "version": 1, "content": []}`},
+	})
+	assert.NoError(t, err)
+
+	getSearchIndexKey := func() bson.M {
+		cursor, err := taskCollection.Indexes().List(context.Background())
+		assert.NoError(t, err)
+		var indexes []bson.M
+		assert.NoError(t, cursor.All(context.Background(), &indexes))
+		for _, index := range indexes {
+			if index["name"] == "search_text" {
+				// text indexes list their fields as weights rather than keys
+				return index["weights"].(bson.M)
+			}
+		}
+		return nil
+	}
+	getSearchBody := func(sourceID string) interface{} {
+		var task bson.M
+		assert.NoError(t, taskCollection.FindOne(context.Background(), bson.M{"source_id": sourceID}).Decode(&task))
+		return task["search_body"]
+	}
+
+	t.Run("MigrateUp", func(t *testing.T) {
+		err = migrate.Steps(1)
+		assert.NoError(t, err)
+		weights := getSearchIndexKey()
+		assert.Contains(t, weights, "search_body")
+		assert.NotContains(t, weights, "body")
+		assert.Equal(t, "task body", getSearchBody("gt_task"))
+		// JIRA descriptions are indexed once their text is extracted by the next sync
+		assert.Nil(t, getSearchBody("jira"))
+	})
+	t.Run("MigrateDown", func(t *testing.T) {
+		err = migrate.Steps(-1)
+		assert.NoError(t, err)
+		weights := getSearchIndexKey()
+		assert.Contains(t, weights, "body")
+		assert.NotContains(t, weights, "search_body")
+		assert.Nil(t, getSearchBody("gt_task"))
+	})
+}
@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMigrate019(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	migrate, err := getMigrate("")
+	assert.NoError(t, err)
+	err = migrate.Steps(1)
+	assert.NoError(t, err)
+
+	dataExportCollection := database.GetDataExportCollection(db)
+	userID := primitive.NewObjectID()
+	_, err = dataExportCollection.InsertMany(context.Background(), []interface{}{
+		bson.M{"user_id": userID, "status": database.DataExportStatusPending},
+		bson.M{"user_id": userID, "status": database.DataExportStatusComplete},
+	})
+	assert.NoError(t, err)
+
+	hasActiveIndex := func() bool {
+		cursor, err := dataExportCollection.Indexes().List(context.Background())
+		assert.NoError(t, err)
+		var indexes []bson.M
+		assert.NoError(t, cursor.All(context.Background(), &indexes))
+		for _, index := range indexes {
+			if index["name"] == "user_id_1_active" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("MigrateUp", func(t *testing.T) {
+		err = migrate.Steps(1)
+		assert.NoError(t, err)
+		assert.True(t, hasActiveIndex())
+		count, err := dataExportCollection.CountDocuments(context.Background(), bson.M{"user_id": userID, "is_active": true})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		// a user can only have one unfinished export
+		_, err = dataExportCollection.InsertOne(context.Background(), database.DataExport{UserID: userID, Status: database.DataExportStatusPending, IsActive: true})
+		assert.True(t, mongo.IsDuplicateKeyError(err))
+	})
+	t.Run("MigrateDown", func(t *testing.T) {
+		err = migrate.Steps(-1)
+		assert.NoError(t, err)
+		assert.False(t, hasActiveIndex())
+	})
+}
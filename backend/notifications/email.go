@@ -0,0 +1,81 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/utils"
+)
+
+const ReminderFromEmail = "reminders@generaltask.com"
+
+type EmailNotifier struct {
+	SendURL string
+	APIKey  string
+}
+
+type mandrillSendRequest struct {
+	Key     string          `json:"key"`
+	Message mandrillMessage `json:"message"`
+}
+
+type mandrillMessage struct {
+	FromEmail string              `json:"from_email"`
+	Subject   string              `json:"subject"`
+	Text      string              `json:"text"`
+	To        []mandrillRecipient `json:"to"`
+}
+
+type mandrillRecipient struct {
+	Email string `json:"email"`
+	Type  string `json:"type"`
+}
+
+func NewEmailNotifier() *EmailNotifier {
+	return &EmailNotifier{
+		SendURL: utils.MANDRILL_SEND_URL,
+		APIKey:  config.GetConfigValue("MANDRILL_CLIENT_SECRET"),
+	}
+}
+
+func (notifier *EmailNotifier) SendTaskReminder(user *database.User, task *database.Task) error {
+	if user.Email == "" {
+		return ErrNotLinked
+	}
+	subject := "Reminder"
+	if task.Title != nil {
+		subject = "Reminder: " + *task.Title
+	}
+	body, err := json.Marshal(mandrillSendRequest{
+		Key: notifier.APIKey,
+		Message: mandrillMessage{
+			FromEmail: ReminderFromEmail,
+			Subject:   subject,
+			Text:      getReminderText(task),
+			To:        []mandrillRecipient{{Email: user.Email, Type: "to"}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest("POST", notifier.SendURL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Content-Type", "application/json")
+	client := &http.Client{Timeout: constants.ExternalTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return errors.New("email send failed")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/testutils"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestEmailNotifier(t *testing.T) {
+	title := "Renew passport"
+	task := &database.Task{ID: primitive.NewObjectID(), Title: &title}
+	user := &database.User{ID: primitive.NewObjectID(), Email: "test_reminder@generaltask.com"}
+
+	t.Run("NoEmail", func(t *testing.T) {
+		notifier := EmailNotifier{}
+		assert.Equal(t, ErrNotLinked, notifier.SendTaskReminder(&database.User{}, task))
+	})
+	t.Run("SendFailed", func(t *testing.T) {
+		server := testutils.GetMockAPIServer(t, http.StatusInternalServerError, `{}`)
+		defer server.Close()
+		notifier := EmailNotifier{SendURL: server.URL, APIKey: "key"}
+		assert.EqualError(t, notifier.SendTaskReminder(user, task), "email send failed")
+	})
+	t.Run("Success", func(t *testing.T) {
+		var sentRequest mandrillSendRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&sentRequest))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		notifier := EmailNotifier{SendURL: server.URL, APIKey: "key"}
+		assert.NoError(t, notifier.SendTaskReminder(user, task))
+		assert.Equal(t, "key", sentRequest.Key)
+		assert.Equal(t, "Reminder: Renew passport", sentRequest.Message.Subject)
+		assert.Equal(t, []mandrillRecipient{{Email: "test_reminder@generaltask.com", Type: "to"}}, sentRequest.Message.To)
+		assert.Contains(t, sentRequest.Message.Text, "task/"+task.ID.Hex())
+	})
+}
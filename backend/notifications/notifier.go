@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/GeneralTask/task-manager/backend/config"
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"github.com/GeneralTask/task-manager/backend/settings"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrNotLinked = errors.New("user has not linked an account for this notifier")
+var ErrRelinkRequired = errors.New("user must relink their account for this notifier")
+
+// Notifier delivers task reminders to a user through a single channel
+type Notifier interface {
+	SendTaskReminder(user *database.User, task *database.Task) error
+}
+
+// GetNotifier returns the notifier the user picked in their settings
+func GetNotifier(db *mongo.Database, userID primitive.ObjectID) (Notifier, error) {
+	var userSettings []database.UserSetting
+	err := database.FindWithCollection(database.GetUserSettingsCollection(db), userID, nil, &userSettings, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch settings.GetSettingValue(userSettings, settings.ReminderNotifierSetting) {
+	case constants.ChoiceKeySlack:
+		return &FallbackNotifier{Primary: NewSlackNotifier(db), Fallback: NewEmailNotifier()}, nil
+	default:
+		return NewEmailNotifier(), nil
+	}
+}
+
+// FallbackNotifier sends reminders through the fallback notifier when the primary notifier can't deliver them
+type FallbackNotifier struct {
+	Primary  Notifier
+	Fallback Notifier
+}
+
+func (notifier *FallbackNotifier) SendTaskReminder(user *database.User, task *database.Task) error {
+	err := notifier.Primary.SendTaskReminder(user, task)
+	if err == nil {
+		return nil
+	}
+	if err != ErrNotLinked {
+		logging.GetSentryLogger().Error().Err(err).Msg("failed to send task reminder, falling back")
+	}
+	return notifier.Fallback.SendTaskReminder(user, task)
+}
+
+func getReminderText(task *database.Task) string {
+	title := ""
+	if task.Title != nil {
+		title = *task.Title
+	}
+	return fmt.Sprintf("Reminder: %s\n%stask/%s", title, config.GetConfigValue("HOME_URL"), task.ID.Hex())
+}
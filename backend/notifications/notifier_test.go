@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeNotifier struct {
+	err   error
+	sends int
+}
+
+func (notifier *fakeNotifier) SendTaskReminder(user *database.User, task *database.Task) error {
+	notifier.sends++
+	return notifier.err
+}
+
+func TestFallbackNotifier(t *testing.T) {
+	user := &database.User{ID: primitive.NewObjectID()}
+	task := &database.Task{ID: primitive.NewObjectID()}
+
+	t.Run("PrimarySent", func(t *testing.T) {
+		primary, fallback := &fakeNotifier{}, &fakeNotifier{}
+		notifier := FallbackNotifier{Primary: primary, Fallback: fallback}
+		assert.NoError(t, notifier.SendTaskReminder(user, task))
+		assert.Equal(t, 1, primary.sends)
+		assert.Equal(t, 0, fallback.sends)
+	})
+	t.Run("PrimaryNotLinked", func(t *testing.T) {
+		primary, fallback := &fakeNotifier{err: ErrNotLinked}, &fakeNotifier{}
+		notifier := FallbackNotifier{Primary: primary, Fallback: fallback}
+		assert.NoError(t, notifier.SendTaskReminder(user, task))
+		assert.Equal(t, 1, fallback.sends)
+	})
+	t.Run("PrimaryFailed", func(t *testing.T) {
+		primary, fallback := &fakeNotifier{err: errors.New("send failed")}, &fakeNotifier{}
+		notifier := FallbackNotifier{Primary: primary, Fallback: fallback}
+		assert.NoError(t, notifier.SendTaskReminder(user, task))
+		assert.Equal(t, 1, fallback.sends)
+	})
+	t.Run("FallbackFailed", func(t *testing.T) {
+		primary, fallback := &fakeNotifier{err: ErrRelinkRequired}, &fakeNotifier{err: ErrNotLinked}
+		notifier := FallbackNotifier{Primary: primary, Fallback: fallback}
+		assert.Equal(t, ErrNotLinked, notifier.SendTaskReminder(user, task))
+	})
+}
@@ -0,0 +1,87 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/GeneralTask/task-manager/backend/constants"
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/GeneralTask/task-manager/backend/logging"
+	"github.com/slack-go/slack"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/oauth2"
+)
+
+// Slack errors which mean the token can't post messages until the user links their account again
+var slackRelinkErrors = map[string]bool{
+	"missing_scope":    true,
+	"not_authed":       true,
+	"invalid_auth":     true,
+	"token_revoked":    true,
+	"account_inactive": true,
+}
+
+// SlackNotifier sends reminders as a direct message from the user's own linked Slack account
+type SlackNotifier struct {
+	DB          *mongo.Database
+	OverrideURL *string
+}
+
+func NewSlackNotifier(db *mongo.Database) *SlackNotifier {
+	return &SlackNotifier{DB: db}
+}
+
+func (notifier *SlackNotifier) SendTaskReminder(user *database.User, task *database.Task) error {
+	var externalToken database.ExternalAPIToken
+	err := database.GetExternalTokenCollection(notifier.DB).FindOne(
+		context.Background(),
+		bson.M{"$and": []bson.M{
+			{"user_id": user.ID},
+			{"service_id": external.TASK_SERVICE_ID_SLACK},
+		}},
+	).Decode(&externalToken)
+	if err == mongo.ErrNoDocuments {
+		return ErrNotLinked
+	} else if err != nil {
+		return err
+	}
+	if externalToken.IsBadToken {
+		return ErrRelinkRequired
+	}
+
+	var oauthToken oauth2.Token
+	err = json.Unmarshal([]byte(externalToken.Token), &oauthToken)
+	if err != nil {
+		return err
+	}
+	options := []slack.Option{slack.OptionHTTPClient(&http.Client{Timeout: constants.ExternalTimeout})}
+	if notifier.OverrideURL != nil {
+		options = append(options, slack.OptionAPIURL(*notifier.OverrideURL))
+	}
+	client := slack.New(oauthToken.AccessToken, options...)
+	_, _, err = client.PostMessage(getSlackUserID(externalToken.AccountID), slack.MsgOptionText(getReminderText(task), false))
+	var slackError slack.SlackErrorResponse
+	if errors.As(err, &slackError) && slackRelinkErrors[slackError.Err] {
+		// accounts linked before reminders needed the chat:write scope must be linked again
+		_, updateErr := database.GetExternalTokenCollection(notifier.DB).UpdateOne(
+			context.Background(),
+			bson.M{"_id": externalToken.ID},
+			bson.M{"$set": bson.M{"is_bad_token": true}},
+		)
+		if updateErr != nil {
+			logging.GetSentryLogger().Error().Err(updateErr).Msg("failed to mark Slack token as bad")
+		}
+		return ErrRelinkRequired
+	}
+	return err
+}
+
+// Slack account IDs are stored as <team ID>-<user ID>
+func getSlackUserID(accountID string) string {
+	return accountID[strings.LastIndex(accountID, "-")+1:]
+}
@@ -0,0 +1,96 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GeneralTask/task-manager/backend/database"
+	"github.com/GeneralTask/task-manager/backend/external"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetSlackUserID(t *testing.T) {
+	assert.Equal(t, "U123", getSlackUserID("T456-U123"))
+	assert.Equal(t, "U123", getSlackUserID("U123"))
+}
+
+func TestSlackNotifier(t *testing.T) {
+	db, dbCleanup, err := database.GetDBConnection()
+	assert.NoError(t, err)
+	defer dbCleanup()
+	title := "Renew passport"
+	task := &database.Task{ID: primitive.NewObjectID(), Title: &title}
+
+	t.Run("NotLinked", func(t *testing.T) {
+		notifier := NewSlackNotifier(db)
+		assert.Equal(t, ErrNotLinked, notifier.SendTaskReminder(&database.User{ID: primitive.NewObjectID()}, task))
+	})
+	t.Run("BadToken", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		_, err := database.GetExternalTokenCollection(db).InsertOne(context.Background(), database.ExternalAPIToken{
+			UserID:     userID,
+			ServiceID:  external.TASK_SERVICE_ID_SLACK,
+			AccountID:  "T456-U123",
+			Token:      `{"access_token":"slack-token"}`,
+			IsBadToken: true,
+		})
+		assert.NoError(t, err)
+		notifier := NewSlackNotifier(db)
+		assert.Equal(t, ErrRelinkRequired, notifier.SendTaskReminder(&database.User{ID: userID}, task))
+	})
+	t.Run("MissingScope", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		insertResult, err := database.GetExternalTokenCollection(db).InsertOne(context.Background(), database.ExternalAPIToken{
+			UserID:    userID,
+			ServiceID: external.TASK_SERVICE_ID_SLACK,
+			AccountID: "T456-U123",
+			Token:     `{"access_token":"slack-token"}`,
+		})
+		assert.NoError(t, err)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{"ok":false,"error":"missing_scope"}`))
+			assert.NoError(t, err)
+		}))
+		defer server.Close()
+		overrideURL := server.URL + "/"
+		notifier := SlackNotifier{DB: db, OverrideURL: &overrideURL}
+		assert.Equal(t, ErrRelinkRequired, notifier.SendTaskReminder(&database.User{ID: userID}, task))
+
+		// the linked accounts list asks the user to link the account again
+		var externalToken database.ExternalAPIToken
+		err = database.GetExternalTokenCollection(db).FindOne(context.Background(), bson.M{"_id": insertResult.InsertedID}).Decode(&externalToken)
+		assert.NoError(t, err)
+		assert.True(t, externalToken.IsBadToken)
+	})
+	t.Run("Success", func(t *testing.T) {
+		userID := primitive.NewObjectID()
+		_, err := database.GetExternalTokenCollection(db).InsertOne(context.Background(), database.ExternalAPIToken{
+			UserID:    userID,
+			ServiceID: external.TASK_SERVICE_ID_SLACK,
+			AccountID: "T456-U123",
+			Token:     `{"access_token":"slack-token"}`,
+		})
+		assert.NoError(t, err)
+
+		var channel string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/chat.postMessage", r.URL.Path)
+			assert.NoError(t, r.ParseForm())
+			channel = r.Form.Get("channel")
+			w.Header().Add("Content-Type", "application/json")
+			_, err := w.Write([]byte(`{"ok":true,"channel":"D123","ts":"1"}`))
+			assert.NoError(t, err)
+		}))
+		defer server.Close()
+		overrideURL := server.URL + "/"
+		notifier := SlackNotifier{DB: db, OverrideURL: &overrideURL}
+		assert.NoError(t, notifier.SendTaskReminder(&database.User{ID: userID}, task))
+		assert.Equal(t, "U123", channel)
+	})
+}
@@ -191,6 +191,25 @@ var HasDismissedMulticalPromptSetting = SettingDefinition{
 	},
 }
 
+var ReminderNotifierSetting = SettingDefinition{
+	FieldKey:      constants.SettingFieldReminderNotifier,
+	DefaultChoice: constants.ChoiceKeyEmail,
+	Choices: []SettingChoice{
+		{Key: constants.ChoiceKeyEmail},
+		{Key: constants.ChoiceKeySlack},
+	},
+}
+
+// tracked time is private, so it is only shown on the dashboards of other users' teams once the user opts in
+var ShareTrackedTimeSetting = SettingDefinition{
+	FieldKey:      constants.SettingFieldShareTrackedTime,
+	DefaultChoice: "false",
+	Choices: []SettingChoice{
+		{Key: "true"},
+		{Key: "false"},
+	},
+}
+
 var LinearTaskFilteringSetting = SettingDefinition{
 	DefaultChoice: "all_cycles",
 	Choices: []SettingChoice{
@@ -227,6 +246,10 @@ var hardcodedSettings = []SettingDefinition{
 	LabSmartPrioritizeEnabledSetting,
 	// multical settings
 	HasDismissedMulticalPromptSetting,
+	// reminder settings
+	ReminderNotifierSetting,
+	// dashboard settings
+	ShareTrackedTimeSetting,
 }
 
 func GetSettingsOptions(db *mongo.Database, userID primitive.ObjectID) (*[]SettingDefinition, error) {
@@ -102,7 +102,7 @@ func TestGetSettingsOptions(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		settings, err := GetSettingsOptions(db, userID)
 		assert.NoError(t, err)
-		assert.Equal(t, 30, len(*settings))
+		assert.Equal(t, 32, len(*settings))
 		assert.Equal(t, "sidebar_linear_preference", (*settings)[3].FieldKey)
 		assert.Equal(t, "sidebar_jira_preference", (*settings)[4].FieldKey)
 		assert.Equal(t, "sidebar_github_preference", (*settings)[5].FieldKey)
@@ -115,18 +115,20 @@ func TestGetSettingsOptions(t *testing.T) {
 		assert.Equal(t, "move_empty_lists_to_bottom", (*settings)[12].FieldKey)
 		assert.Equal(t, "lab_smart_prioritize_enabled", (*settings)[13].FieldKey)
 		assert.Equal(t, "has_dismissed_multical_prompt", (*settings)[14].FieldKey)
-		assert.Equal(t, insertedViewID+"_github_filtering_preference", (*settings)[15].FieldKey)
-		assert.Equal(t, insertedViewID+"_github_sorting_preference", (*settings)[16].FieldKey)
-		assert.Equal(t, insertedViewID+"_github_sorting_direction", (*settings)[17].FieldKey)
-		assert.Equal(t, insertedSectionID+"_task_sorting_preference_main", (*settings)[18].FieldKey)
-		assert.Equal(t, insertedSectionID+"_task_sorting_direction_main", (*settings)[19].FieldKey)
-		assert.Equal(t, insertedSectionID+"_task_sorting_preference_overview", (*settings)[20].FieldKey)
-		assert.Equal(t, insertedSectionID+"_task_sorting_direction_overview", (*settings)[21].FieldKey)
-		assert.Equal(t, "000000000000000000000001_task_sorting_preference_main", (*settings)[22].FieldKey)
-		assert.Equal(t, "000000000000000000000001_task_sorting_direction_main", (*settings)[23].FieldKey)
-		assert.Equal(t, "000000000000000000000001_task_sorting_preference_overview", (*settings)[24].FieldKey)
-		assert.Equal(t, "000000000000000000000001_task_sorting_direction_overview", (*settings)[25].FieldKey)
-		calendarSetting := (*settings)[26]
+		assert.Equal(t, "reminder_notifier_preference", (*settings)[15].FieldKey)
+		assert.Equal(t, "share_tracked_time_with_teams", (*settings)[16].FieldKey)
+		assert.Equal(t, insertedViewID+"_github_filtering_preference", (*settings)[17].FieldKey)
+		assert.Equal(t, insertedViewID+"_github_sorting_preference", (*settings)[18].FieldKey)
+		assert.Equal(t, insertedViewID+"_github_sorting_direction", (*settings)[19].FieldKey)
+		assert.Equal(t, insertedSectionID+"_task_sorting_preference_main", (*settings)[20].FieldKey)
+		assert.Equal(t, insertedSectionID+"_task_sorting_direction_main", (*settings)[21].FieldKey)
+		assert.Equal(t, insertedSectionID+"_task_sorting_preference_overview", (*settings)[22].FieldKey)
+		assert.Equal(t, insertedSectionID+"_task_sorting_direction_overview", (*settings)[23].FieldKey)
+		assert.Equal(t, "000000000000000000000001_task_sorting_preference_main", (*settings)[24].FieldKey)
+		assert.Equal(t, "000000000000000000000001_task_sorting_direction_main", (*settings)[25].FieldKey)
+		assert.Equal(t, "000000000000000000000001_task_sorting_preference_overview", (*settings)[26].FieldKey)
+		assert.Equal(t, "000000000000000000000001_task_sorting_direction_overview", (*settings)[27].FieldKey)
+		calendarSetting := (*settings)[28]
 		assert.Equal(t, constants.SettingFieldCalendarForNewTasks, calendarSetting.FieldKey)
 		assert.Equal(t, "a", calendarSetting.DefaultChoice)
 		assert.Equal(t, []SettingChoice{
@@ -134,7 +136,7 @@ func TestGetSettingsOptions(t *testing.T) {
 			{Key: "b", Name: "oof 2"},
 			{Key: "", Name: ""},
 		}, calendarSetting.Choices)
-		calendarIDSetting := (*settings)[27]
+		calendarIDSetting := (*settings)[29]
 		assert.Equal(t, constants.SettingFieldCalendarIDForNewTasks, calendarIDSetting.FieldKey)
 		assert.Equal(t, []SettingChoice{
 			{Key: "cal1", Name: "title1"},
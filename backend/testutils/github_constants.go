@@ -6,6 +6,8 @@ const (
 	UserResponsePayload                string = `{"id": 1, "login": "chad1616"}`
 	UserRepositoriesPayload            string = `[{"id": 1234, "name": "ExampleRepository", "full_name": "dankmemes/ExampleRepository", "owner": {"login": "gigaChad123"}}]`
 	UserPullRequestsPayload            string = `[{"id": 1, "number": 420, "commits": 777, "title": "Fix big oopsie", "body": "the oopsie must be fixed", "created_at": "2011-01-26T19:01:12Z", "updated_at": "2011-01-26T19:01:12Z", "html_url": "github.com", "user": {"login": "chad1616", "id": 1}, "requested_reviewers": [], "head": {"sha": "abc123", "ref": "ExampleBranch"}, "base": {"sha": "def456", "ref": "BaseExampleBranch"}}]`
+	PullRequestPayload                 string = `{"id": 1, "number": 420, "state": "open", "commits": 777, "title": "Fix big oopsie", "body": "the oopsie must be fixed", "created_at": "2011-01-26T19:01:12Z", "updated_at": "2011-01-26T19:01:12Z", "html_url": "github.com", "user": {"login": "chad1616", "id": 1}, "requested_reviewers": [], "head": {"sha": "abc123", "ref": "ExampleBranch"}, "base": {"sha": "def456", "ref": "BaseExampleBranch"}}`
+	ClosedPullRequestPayload           string = `{"id": 1, "number": 420, "state": "closed", "commits": 777, "title": "Fix big oopsie", "body": "the oopsie must be fixed", "created_at": "2011-01-26T19:01:12Z", "updated_at": "2011-01-26T19:01:12Z", "html_url": "github.com", "user": {"login": "chad1616", "id": 1}, "requested_reviewers": [], "head": {"sha": "abc123", "ref": "ExampleBranch"}, "base": {"sha": "def456", "ref": "BaseExampleBranch"}}`
 	UserNotRelevantPullRequestsPayload string = `[{"id": 2, "number": 42069, "title": "Fix big oopsie 2", "created_at": "2011-01-26T19:01:12Z", "updated_at": "2011-01-26T19:01:12Z", "html_url": "github.com", "user": {"login": "gigachad", "id": 2}, "requested_reviewers": [], "head": {"sha": "abc1234", "ref": "ExampleBranch2"}}]`
 	PullRequestReviewersPayload        string = `{"users": [{"login": "goodTeamMember"}]}`
 	PullRequestTeamReviewersPayload    string = `{"teams": [{"name": "goodTeam"}]}`
@@ -0,0 +1,499 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence is a subset of RFC 5545 recurrence properties: a single RRULE, optionally with DTSTART and EXDATE.
+// Only the DAILY, WEEKLY, MONTHLY and YEARLY frequencies are supported, as tasks are created at most once a day.
+type Recurrence struct {
+	frequency      string
+	interval       int
+	count          int
+	until          *recurrenceDateTime
+	byDay          []recurrenceWeekday
+	byMonthDay     []int
+	byMonth        []int
+	bySetPos       []int
+	weekStart      time.Weekday
+	start          *recurrenceDateTime
+	exceptionDates []recurrenceDateTime
+}
+
+const (
+	RecurrenceFrequencyDaily   = "DAILY"
+	RecurrenceFrequencyWeekly  = "WEEKLY"
+	RecurrenceFrequencyMonthly = "MONTHLY"
+	RecurrenceFrequencyYearly  = "YEARLY"
+)
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+type recurrenceWeekday struct {
+	weekday time.Weekday
+	// nth occurrence of the weekday within the month or year, counting from the end if negative; 0 for every occurrence
+	ordinal int
+}
+
+// date-times without a location are floating, and are resolved in the user's timezone
+type recurrenceDateTime struct {
+	year     int
+	month    time.Month
+	day      int
+	hour     int
+	minute   int
+	second   int
+	isDate   bool
+	location *time.Location
+}
+
+func (dateTime recurrenceDateTime) in(location *time.Location) time.Time {
+	if dateTime.location != nil {
+		location = dateTime.location
+	}
+	return time.Date(dateTime.year, dateTime.month, dateTime.day, dateTime.hour, dateTime.minute, dateTime.second, 0, location)
+}
+
+// ParseRecurrence parses newline separated RRULE, DTSTART and EXDATE properties, e.g.
+// "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU\nEXDATE;VALUE=DATE:20230103". A bare rule without the "RRULE:" prefix is also accepted.
+func ParseRecurrence(value string) (*Recurrence, error) {
+	recurrence := Recurrence{interval: 1, weekStart: time.Monday}
+	hasRule := false
+	for _, line := range strings.FieldsFunc(value, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, params, content := splitRecurrenceContentLine(line)
+		switch name {
+		case "RRULE":
+			if hasRule {
+				return nil, errors.New("only one RRULE is supported")
+			}
+			err := recurrence.parseRule(content)
+			if err != nil {
+				return nil, err
+			}
+			hasRule = true
+		case "DTSTART":
+			if recurrence.start != nil {
+				return nil, errors.New("only one DTSTART is supported")
+			}
+			start, err := parseRecurrenceDateTime(content, params)
+			if err != nil {
+				return nil, err
+			}
+			recurrence.start = &start
+		case "EXDATE":
+			for _, dateValue := range strings.Split(content, ",") {
+				exceptionDate, err := parseRecurrenceDateTime(dateValue, params)
+				if err != nil {
+					return nil, err
+				}
+				recurrence.exceptionDates = append(recurrence.exceptionDates, exceptionDate)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence property: %s", name)
+		}
+	}
+	if !hasRule {
+		return nil, errors.New("missing RRULE")
+	}
+	return &recurrence, nil
+}
+
+func splitRecurrenceContentLine(line string) (string, map[string]string, string) {
+	params := map[string]string{}
+	separatorIndex := strings.Index(line, ":")
+	if separatorIndex == -1 {
+		return "RRULE", params, line
+	}
+	nameAndParams := strings.Split(line[:separatorIndex], ";")
+	for _, param := range nameAndParams[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = value
+	}
+	return strings.ToUpper(nameAndParams[0]), params, line[separatorIndex+1:]
+}
+
+func parseRecurrenceDateTime(value string, params map[string]string) (recurrenceDateTime, error) {
+	value = strings.TrimSpace(value)
+	var location *time.Location
+	if tzid, exists := params["TZID"]; exists {
+		var err error
+		location, err = time.LoadLocation(tzid)
+		if err != nil {
+			return recurrenceDateTime{}, err
+		}
+	}
+
+	layout := "20060102T150405"
+	isDate := params["VALUE"] == "DATE" || len(value) == len("20060102")
+	if isDate {
+		layout = "20060102"
+	} else if strings.HasSuffix(value, "Z") {
+		value = strings.TrimSuffix(value, "Z")
+		location = time.UTC
+	}
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		return recurrenceDateTime{}, fmt.Errorf("invalid recurrence date: %s", value)
+	}
+	return recurrenceDateTime{
+		year:     parsed.Year(),
+		month:    parsed.Month(),
+		day:      parsed.Day(),
+		hour:     parsed.Hour(),
+		minute:   parsed.Minute(),
+		second:   parsed.Second(),
+		isDate:   isDate,
+		location: location,
+	}, nil
+}
+
+func (recurrence *Recurrence) parseRule(rule string) error {
+	seenParts := map[string]bool{}
+	for _, part := range strings.Split(rule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		key = strings.ToUpper(key)
+		value = strings.ToUpper(value)
+		if !found || value == "" {
+			return fmt.Errorf("invalid RRULE part: %s", part)
+		}
+		if seenParts[key] {
+			return fmt.Errorf("duplicate RRULE part: %s", key)
+		}
+		seenParts[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			switch value {
+			case RecurrenceFrequencyDaily, RecurrenceFrequencyWeekly, RecurrenceFrequencyMonthly, RecurrenceFrequencyYearly:
+				recurrence.frequency = value
+			default:
+				return fmt.Errorf("unsupported FREQ: %s", value)
+			}
+		case "INTERVAL":
+			recurrence.interval, err = parseRecurrenceInt(value, 1, 1<<16)
+		case "COUNT":
+			recurrence.count, err = parseRecurrenceInt(value, 1, 1<<16)
+		case "UNTIL":
+			var until recurrenceDateTime
+			until, err = parseRecurrenceDateTime(value, map[string]string{})
+			recurrence.until = &until
+		case "BYDAY":
+			for _, dayValue := range strings.Split(value, ",") {
+				var weekday recurrenceWeekday
+				weekday, err = parseRecurrenceWeekday(dayValue)
+				if err != nil {
+					break
+				}
+				recurrence.byDay = append(recurrence.byDay, weekday)
+			}
+		case "BYMONTHDAY":
+			recurrence.byMonthDay, err = parseRecurrenceIntList(value, 31)
+		case "BYMONTH":
+			recurrence.byMonth, err = parseRecurrenceIntList(value, 12)
+			for _, month := range recurrence.byMonth {
+				if month < 0 {
+					err = fmt.Errorf("invalid BYMONTH: %s", value)
+				}
+			}
+		case "BYSETPOS":
+			recurrence.bySetPos, err = parseRecurrenceIntList(value, 366)
+		case "WKST":
+			weekStart, exists := recurrenceWeekdays[value]
+			if !exists {
+				return fmt.Errorf("invalid WKST: %s", value)
+			}
+			recurrence.weekStart = weekStart
+		default:
+			return fmt.Errorf("unsupported RRULE part: %s", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if recurrence.frequency == "" {
+		return errors.New("RRULE is missing FREQ")
+	}
+	if recurrence.count > 0 && recurrence.until != nil {
+		return errors.New("RRULE cannot have both COUNT and UNTIL")
+	}
+	if recurrence.frequency == RecurrenceFrequencyWeekly && len(recurrence.byMonthDay) > 0 {
+		return errors.New("BYMONTHDAY is not supported for WEEKLY rules")
+	}
+	for _, weekday := range recurrence.byDay {
+		if weekday.ordinal != 0 && recurrence.frequency != RecurrenceFrequencyMonthly && recurrence.frequency != RecurrenceFrequencyYearly {
+			return errors.New("BYDAY ordinals are only supported for MONTHLY and YEARLY rules")
+		}
+	}
+	return nil
+}
+
+func parseRecurrenceInt(value string, min int, max int) (int, error) {
+	result, err := strconv.Atoi(value)
+	if err != nil || result < min || result > max {
+		return 0, fmt.Errorf("invalid RRULE value: %s", value)
+	}
+	return result, nil
+}
+
+// values may be negative to count from the end, but never zero
+func parseRecurrenceIntList(value string, max int) ([]int, error) {
+	var results []int
+	for _, item := range strings.Split(value, ",") {
+		result, err := parseRecurrenceInt(strings.TrimPrefix(item, "+"), -max, max)
+		if err != nil || result == 0 {
+			return nil, fmt.Errorf("invalid RRULE value: %s", value)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func parseRecurrenceWeekday(value string) (recurrenceWeekday, error) {
+	if len(value) < 2 {
+		return recurrenceWeekday{}, fmt.Errorf("invalid BYDAY: %s", value)
+	}
+	weekday, exists := recurrenceWeekdays[value[len(value)-2:]]
+	if !exists {
+		return recurrenceWeekday{}, fmt.Errorf("invalid BYDAY: %s", value)
+	}
+	ordinal := 0
+	if len(value) > 2 {
+		var err error
+		ordinal, err = parseRecurrenceInt(strings.TrimPrefix(value[:len(value)-2], "+"), -53, 53)
+		if err != nil || ordinal == 0 {
+			return recurrenceWeekday{}, fmt.Errorf("invalid BYDAY: %s", value)
+		}
+	}
+	return recurrenceWeekday{weekday: weekday, ordinal: ordinal}, nil
+}
+
+// Occurrences returns the occurrences at or after `after` and before `before`.
+// The recurrence is anchored at its DTSTART if present, and at `start` otherwise; floating times use the location of `start`.
+func (recurrence *Recurrence) Occurrences(start time.Time, after time.Time, before time.Time) []time.Time {
+	return recurrence.occurrences(start, after, before, 0)
+}
+
+// NextOccurrences returns up to `limit` occurrences at or after `after`, anchored the same way as Occurrences.
+func (recurrence *Recurrence) NextOccurrences(start time.Time, after time.Time, limit int) []time.Time {
+	// rules such as the 30th of February never match, so the search has to stop somewhere
+	return recurrence.occurrences(start, after, after.AddDate(maxRecurrenceSearchYears, 0, 0), limit)
+}
+
+const maxRecurrenceSearchYears = 100
+
+func (recurrence *Recurrence) occurrences(start time.Time, after time.Time, before time.Time, limit int) []time.Time {
+	location := start.Location()
+	if recurrence.start != nil {
+		start = recurrence.start.in(location)
+	}
+	var until *time.Time
+	if recurrence.until != nil {
+		untilTime := recurrence.until.in(location)
+		if recurrence.until.isDate {
+			// a date UNTIL includes occurrences on that day
+			untilTime = untilTime.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		until = &untilTime
+	}
+
+	occurrences := []time.Time{}
+	count := 0
+	// periods are tracked as UTC dates so that daylight savings does not affect the date arithmetic
+	before = before.In(location)
+	lastDay := time.Date(before.Year(), before.Month(), before.Day(), 0, 0, 0, 0, time.UTC)
+	for period := recurrence.firstPeriod(start); !period.After(lastDay); period = recurrence.nextPeriod(period) {
+		for _, day := range recurrence.expandPeriod(period, start) {
+			occurrence := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), start.Second(), 0, location)
+			if occurrence.Before(start) {
+				continue
+			}
+			if (until != nil && occurrence.After(*until)) || (recurrence.count > 0 && count >= recurrence.count) || !occurrence.Before(before) {
+				return occurrences
+			}
+			// excluded occurrences still count towards COUNT
+			count++
+			if !occurrence.Before(after) && !recurrence.isException(occurrence) {
+				occurrences = append(occurrences, occurrence)
+				if limit > 0 && len(occurrences) >= limit {
+					return occurrences
+				}
+			}
+		}
+	}
+	return occurrences
+}
+
+func (recurrence *Recurrence) isException(occurrence time.Time) bool {
+	for _, exceptionDate := range recurrence.exceptionDates {
+		if exceptionDate.isDate {
+			if occurrence.Year() == exceptionDate.year && occurrence.Month() == exceptionDate.month && occurrence.Day() == exceptionDate.day {
+				return true
+			}
+		} else if occurrence.Equal(exceptionDate.in(occurrence.Location())) {
+			return true
+		}
+	}
+	return false
+}
+
+func (recurrence *Recurrence) firstPeriod(start time.Time) time.Time {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	switch recurrence.frequency {
+	case RecurrenceFrequencyWeekly:
+		return day.AddDate(0, 0, -((int(day.Weekday()) - int(recurrence.weekStart) + 7) % 7))
+	case RecurrenceFrequencyMonthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case RecurrenceFrequencyYearly:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func (recurrence *Recurrence) nextPeriod(period time.Time) time.Time {
+	switch recurrence.frequency {
+	case RecurrenceFrequencyWeekly:
+		return period.AddDate(0, 0, 7*recurrence.interval)
+	case RecurrenceFrequencyMonthly:
+		return period.AddDate(0, recurrence.interval, 0)
+	case RecurrenceFrequencyYearly:
+		return period.AddDate(recurrence.interval, 0, 0)
+	}
+	return period.AddDate(0, 0, recurrence.interval)
+}
+
+// returns the days in the period which match the rule, in order
+func (recurrence *Recurrence) expandPeriod(period time.Time, start time.Time) []time.Time {
+	end := period.AddDate(0, 0, 1)
+	switch recurrence.frequency {
+	case RecurrenceFrequencyWeekly:
+		end = period.AddDate(0, 0, 7)
+	case RecurrenceFrequencyMonthly:
+		end = period.AddDate(0, 1, 0)
+	case RecurrenceFrequencyYearly:
+		end = period.AddDate(1, 0, 0)
+	}
+
+	byDay := recurrence.byDay
+	byMonthDay := recurrence.byMonthDay
+	byMonth := recurrence.byMonth
+	// without any BY* parts, the day, weekday and month are taken from the start of the recurrence
+	switch recurrence.frequency {
+	case RecurrenceFrequencyWeekly:
+		if len(byDay) == 0 {
+			byDay = []recurrenceWeekday{{weekday: start.Weekday()}}
+		}
+	case RecurrenceFrequencyMonthly:
+		if len(byDay) == 0 && len(byMonthDay) == 0 {
+			byMonthDay = []int{start.Day()}
+		}
+	case RecurrenceFrequencyYearly:
+		if len(byDay) == 0 && len(byMonthDay) == 0 {
+			byMonthDay = []int{start.Day()}
+			if len(byMonth) == 0 {
+				byMonth = []int{int(start.Month())}
+			}
+		}
+	}
+	// for YEARLY rules BYDAY ordinals count within the year, unless limited to specific months
+	ordinalWithinYear := recurrence.frequency == RecurrenceFrequencyYearly && len(byMonth) == 0
+
+	var days []time.Time
+	for day := period; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if len(byMonth) > 0 && !containsInt(byMonth, int(day.Month())) {
+			continue
+		}
+		if len(byMonthDay) > 0 && !matchesMonthDay(day, byMonthDay) {
+			continue
+		}
+		if len(byDay) > 0 && !matchesWeekday(day, byDay, ordinalWithinYear) {
+			continue
+		}
+		days = append(days, day)
+	}
+
+	if len(recurrence.bySetPos) == 0 {
+		return days
+	}
+	var selectedDays []time.Time
+	for _, position := range recurrence.bySetPos {
+		index := position - 1
+		if position < 0 {
+			index = len(days) + position
+		}
+		if index >= 0 && index < len(days) && !containsTime(selectedDays, days[index]) {
+			selectedDays = append(selectedDays, days[index])
+		}
+	}
+	sort.Slice(selectedDays, func(i, j int) bool { return selectedDays[i].Before(selectedDays[j]) })
+	return selectedDays
+}
+
+func matchesMonthDay(day time.Time, byMonthDay []int) bool {
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, monthDay := range byMonthDay {
+		if monthDay == day.Day() || (monthDay < 0 && daysInMonth+monthDay+1 == day.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesWeekday(day time.Time, byDay []recurrenceWeekday, ordinalWithinYear bool) bool {
+	index := day.Day() - 1
+	length := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if ordinalWithinYear {
+		index = day.YearDay() - 1
+		length = time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	}
+	for _, weekday := range byDay {
+		if weekday.weekday != day.Weekday() {
+			continue
+		}
+		if weekday.ordinal == 0 ||
+			(weekday.ordinal > 0 && index/7+1 == weekday.ordinal) ||
+			(weekday.ordinal < 0 && (length-1-index)/7+1 == -weekday.ordinal) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsTime(values []time.Time, value time.Time) bool {
+	for _, item := range values {
+		if item.Equal(value) {
+			return true
+		}
+	}
+	return false
+}
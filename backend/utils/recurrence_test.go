@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getRecurrenceOccurrences(t *testing.T, value string, start time.Time, after time.Time, before time.Time) []string {
+	recurrence, err := ParseRecurrence(value)
+	assert.NoError(t, err)
+	var results []string
+	for _, occurrence := range recurrence.Occurrences(start, after, before) {
+		results = append(results, occurrence.Format("2006-01-02 15:04"))
+	}
+	return results
+}
+
+func TestParseRecurrence(t *testing.T) {
+	for _, value := range []string{
+		"FREQ=DAILY",
+		"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+		"RRULE:FREQ=MONTHLY;BYDAY=-1FR",
+		"RRULE:FREQ=MONTHLY;BYMONTHDAY=1,15\nEXDATE;VALUE=DATE:20230101,20230115",
+		"DTSTART;TZID=America/Los_Angeles:20230101T090000\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=2SU;UNTIL=20300101T000000Z",
+		"rrule:freq=weekly;byday=mo,we,fr;wkst=su;count=10",
+	} {
+		_, err := ParseRecurrence(value)
+		assert.NoError(t, err, value)
+	}
+	for _, value := range []string{
+		"",
+		"EXDATE:20230101",
+		"FREQ=HOURLY",
+		"FREQ=SOMETIMES",
+		"INTERVAL=2",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20230101",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=YEARLY;BYMONTH=13",
+		"RRULE:FREQ=DAILY\nRRULE:FREQ=WEEKLY",
+		"RRULE:FREQ=DAILY\nEXDATE:tomorrow",
+		"RRULE:FREQ=DAILY\nDTSTART;TZID=Mars/Olympus_Mons:20230101T090000",
+		"RRULE:FREQ=DAILY\nRDATE:20230101",
+	} {
+		_, err := ParseRecurrence(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	// 09:00 on Sunday January 1, 2023
+	start := time.Date(2023, time.January, 1, 9, 0, 0, 0, time.UTC)
+	after := start
+	before := time.Date(2023, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Daily", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00", "2023-01-02 09:00", "2023-01-03 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=DAILY", start, after, time.Date(2023, time.January, 3, 10, 0, 0, 0, time.UTC)))
+	})
+	t.Run("Window", func(t *testing.T) {
+		// occurrences exactly at the end of the window are excluded, and those at the start are included
+		assert.Equal(t,
+			[]string{"2023-01-02 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=DAILY", start, start.AddDate(0, 0, 1), start.AddDate(0, 0, 2)))
+	})
+	t.Run("EveryOtherTuesday", func(t *testing.T) {
+		// the Tuesday in the first week is before the start
+		assert.Equal(t,
+			[]string{"2023-01-10 09:00", "2023-01-24 09:00", "2023-02-07 09:00"},
+			getRecurrenceOccurrences(t, "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", start, after, time.Date(2023, time.February, 10, 0, 0, 0, 0, time.UTC)))
+	})
+	t.Run("WeekStart", func(t *testing.T) {
+		// with weeks starting on Monday, the Sunday start is the end of the first week
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00", "2023-01-09 09:00", "2023-01-15 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU", start, after, time.Date(2023, time.January, 20, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00", "2023-01-02 09:00", "2023-01-15 09:00", "2023-01-16 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,SU;WKST=SU", start, after, time.Date(2023, time.January, 20, 0, 0, 0, 0, time.UTC)))
+	})
+	t.Run("LastFridayOfTheMonth", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-27 09:00", "2023-02-24 09:00", "2023-03-31 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=MONTHLY;BYDAY=-1FR", start, after, before))
+	})
+	t.Run("FirstAndFifteenth", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00", "2023-01-15 09:00", "2023-02-01 09:00", "2023-02-15 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=MONTHLY;BYMONTHDAY=1,15", start, after, time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC)))
+	})
+	t.Run("LastDayOfTheMonth", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-31 09:00", "2023-02-28 09:00", "2023-03-31 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=MONTHLY;BYMONTHDAY=-1", start, after, before))
+	})
+	t.Run("MissingMonthDay", func(t *testing.T) {
+		// months without a 30th are skipped
+		assert.Equal(t,
+			[]string{"2023-01-30 09:00", "2023-03-30 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=MONTHLY;BYMONTHDAY=30", start, after, before))
+	})
+	t.Run("LastWeekdayOfTheMonth", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-31 09:00", "2023-02-28 09:00", "2023-03-31 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", start, after, before))
+	})
+	t.Run("Yearly", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00", "2024-01-01 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=YEARLY", start, after, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)))
+		// second Sunday of March
+		assert.Equal(t,
+			[]string{"2023-03-12 09:00", "2024-03-10 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=YEARLY;BYMONTH=3;BYDAY=2SU", start, after, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)))
+		// first Monday of the year
+		assert.Equal(t,
+			[]string{"2023-01-02 09:00", "2024-01-01 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=YEARLY;BYDAY=1MO", start, after, time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)))
+	})
+	t.Run("Count", func(t *testing.T) {
+		// earlier occurrences count towards COUNT even when outside the window
+		assert.Equal(t,
+			[]string{"2023-01-02 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=DAILY;COUNT=2", start, start.AddDate(0, 0, 1), before))
+	})
+	t.Run("Until", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00", "2023-01-02 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=DAILY;UNTIL=20230102", start, after, before))
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00"},
+			getRecurrenceOccurrences(t, "FREQ=DAILY;UNTIL=20230102T085959Z", start, after, before))
+	})
+	t.Run("ExceptionDates", func(t *testing.T) {
+		assert.Equal(t,
+			[]string{"2023-01-01 09:00", "2023-01-04 09:00"},
+			getRecurrenceOccurrences(t, "RRULE:FREQ=DAILY;COUNT=4\nEXDATE;VALUE=DATE:20230102\nEXDATE:20230103T090000", start, after, before))
+	})
+	t.Run("DTSTART", func(t *testing.T) {
+		losAngeles, err := time.LoadLocation("America/Los_Angeles")
+		assert.NoError(t, err)
+		assert.Equal(t,
+			[]string{"2023-01-10 18:00", "2023-01-11 18:00"},
+			getRecurrenceOccurrences(t, "DTSTART:20230110T180000\nRRULE:FREQ=DAILY", start.In(losAngeles), after, time.Date(2023, time.January, 12, 12, 0, 0, 0, losAngeles)))
+	})
+	t.Run("DaylightSavings", func(t *testing.T) {
+		losAngeles, err := time.LoadLocation("America/Los_Angeles")
+		assert.NoError(t, err)
+		localStart := time.Date(2023, time.March, 11, 9, 0, 0, 0, losAngeles)
+		recurrence, err := ParseRecurrence("FREQ=DAILY")
+		assert.NoError(t, err)
+		occurrences := recurrence.Occurrences(localStart, localStart, localStart.AddDate(0, 0, 2))
+		assert.Equal(t, 2, len(occurrences))
+		assert.Equal(t, 9, occurrences[1].Hour())
+		assert.Equal(t, 23*time.Hour, occurrences[1].Sub(occurrences[0]))
+	})
+	t.Run("NextOccurrences", func(t *testing.T) {
+		recurrence, err := ParseRecurrence("FREQ=MONTHLY;BYDAY=-1FR")
+		assert.NoError(t, err)
+		occurrences := recurrence.NextOccurrences(start, start.AddDate(0, 1, 0), 2)
+		assert.Equal(t, []time.Time{
+			time.Date(2023, time.February, 24, 9, 0, 0, 0, time.UTC),
+			time.Date(2023, time.March, 31, 9, 0, 0, 0, time.UTC),
+		}, occurrences)
+	})
+	t.Run("NextOccurrencesNeverMatches", func(t *testing.T) {
+		recurrence, err := ParseRecurrence("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Time{}, recurrence.NextOccurrences(start, start, 2))
+	})
+}